@@ -0,0 +1,138 @@
+package mux
+
+import (
+	"log"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// AccessEntry describes a served request. It is passed to AccessLogger.
+type AccessEntry struct {
+	// Time is the moment the request was received.
+	Time time.Time
+
+	// Method is the method of the request.
+	Method string
+
+	// Path is the path of the request as it was received.
+	Path string
+
+	// Route is the template of the route that served the request (e.g.
+	// "/users/{id:int}"). It is empty if no route did.
+	Route string
+
+	// Status is the status code of the response.
+	Status int
+
+	// Bytes is the size of the response body.
+	Bytes int64
+
+	// Latency is the time it took to serve the request.
+	Latency time.Duration
+
+	// RemoteIP is the IP address of the client (see ClientIP).
+	RemoteIP string
+
+	// Experiments maps the experiments the request took part in to the
+	// variants it was assigned to (see Router.Experiment).
+	Experiments map[string]string
+}
+
+// AccessLogger is the sink of access log entries.
+type AccessLogger interface {
+	LogAccess(r *http.Request, entry *AccessEntry)
+}
+
+// AccessLoggerFunc is a function that implements AccessLogger. It makes it
+// easy to plug in any logging library, e.g. zap:
+//
+//	mux.AccessLoggerFunc(func(r *http.Request, e *mux.AccessEntry) {
+//	    logger.Info("request",
+//	        zap.String("method", e.Method),
+//	        zap.String("route", e.Route),
+//	        zap.Int("status", e.Status),
+//	        zap.Duration("latency", e.Latency))
+//	})
+type AccessLoggerFunc func(r *http.Request, entry *AccessEntry)
+
+// LogAccess method ensures that AccessLoggerFunc implements AccessLogger.
+func (f AccessLoggerFunc) LogAccess(r *http.Request, entry *AccessEntry) {
+	f(r, entry)
+}
+
+// StdAccessLogger returns AccessLogger that writes entries to l (the standard
+// logger if nil) as lines of key=value pairs.
+func StdAccessLogger(l *log.Logger) AccessLogger {
+	if l == nil {
+		l = log.Default()
+	}
+	return AccessLoggerFunc(func(r *http.Request, e *AccessEntry) {
+		var experiments string
+		if len(e.Experiments) > 0 {
+			experiments = " experiments=" + formatExperiments(e.Experiments)
+		}
+		l.Printf("method=%s path=%q route=%q status=%d bytes=%d "+
+			"latency=%s ip=%s%s",
+			e.Method, e.Path, e.Route, e.Status, e.Bytes, e.Latency,
+			e.RemoteIP, experiments)
+	})
+}
+
+// SlogAccessLogger returns AccessLogger that writes entries to l (the default
+// slog logger if nil) at info level with the request context.
+func SlogAccessLogger(l *slog.Logger) AccessLogger {
+	if l == nil {
+		l = slog.Default()
+	}
+	return AccessLoggerFunc(func(r *http.Request, e *AccessEntry) {
+		attrs := []slog.Attr{
+			slog.String("method", e.Method),
+			slog.String("path", e.Path),
+			slog.String("route", e.Route),
+			slog.Int("status", e.Status),
+			slog.Int64("bytes", e.Bytes),
+			slog.Duration("latency", e.Latency),
+			slog.String("ip", e.RemoteIP),
+		}
+		if len(e.Experiments) > 0 {
+			attrs = append(attrs, slog.String("experiments",
+				formatExperiments(e.Experiments)))
+		}
+		l.LogAttrs(r.Context(), slog.LevelInfo, "request", attrs...)
+	})
+}
+
+// AccessLog returns Middleware that passes an AccessEntry to the logger once
+// every request is served:
+//
+//	rtr := mux.New().Wrap(mux.AccessLog(mux.SlogAccessLogger(nil)))
+//
+// Register it on the root Router, so that it sees the original path and the
+// requests that don't match any route.
+func AccessLog(logger AccessLogger) Middleware {
+	return func(next http.Handler) http.Handler {
+		return View(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rw := NewResponseWriter(w)
+			r = RecordRoute(r)
+			next.ServeHTTP(rw, r)
+
+			entry := &AccessEntry{
+				Time:     start,
+				Method:   r.Method,
+				Path:     originalPath(r),
+				Route:    "",
+				Status:   rw.Status(),
+				Bytes:    rw.Size(),
+				Latency:  time.Since(start),
+				RemoteIP: ClientIP(r),
+			}
+			if route := MatchedRoute(r); route != nil {
+				entry.Route = route.Template()
+			}
+			entry.Experiments = recordedExperiments(r)
+			logger.LogAccess(r, entry)
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package mux
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccessLog(t *testing.T) {
+	var entries []*AccessEntry
+	logger := AccessLoggerFunc(func(r *http.Request, e *AccessEntry) {
+		entries = append(entries, e)
+	})
+
+	rtr := New().Wrap(AccessLog(logger))
+	api := rtr.Subrouter().PathPrefix("/api")
+	api.Get("/users/{id:int}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte("hello"))
+	})
+
+	rec, req, err := request(http.MethodGet, "/api/users/42", nil)
+	assert.NoError(t, err)
+	req.RemoteAddr = "10.0.0.1:5555"
+	rtr.ServeHTTP(rec, req)
+	rec, req, err = request(http.MethodGet, "/missing", nil)
+	assert.NoError(t, err)
+	rtr.ServeHTTP(rec, req)
+
+	if assert.Len(t, entries, 2) {
+		e := entries[0]
+		assert.Equal(t, http.MethodGet, e.Method)
+		assert.Equal(t, "/api/users/42", e.Path)
+		assert.Equal(t, "/api/users/{id:int}", e.Route)
+		assert.Equal(t, http.StatusAccepted, e.Status)
+		assert.Equal(t, int64(5), e.Bytes)
+		assert.Equal(t, "10.0.0.1", e.RemoteIP)
+		assert.False(t, e.Time.IsZero())
+
+		e = entries[1]
+		assert.Equal(t, "/missing", e.Path)
+		assert.Equal(t, "", e.Route)
+		assert.Equal(t, http.StatusNotFound, e.Status)
+	}
+}
+
+func TestStdAccessLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := StdAccessLogger(log.New(&buf, "", 0))
+	logger.LogAccess(nil, &AccessEntry{
+		Method:   http.MethodGet,
+		Path:     "/users/42",
+		Route:    "/users/{id:int}",
+		Status:   http.StatusOK,
+		Bytes:    5,
+		RemoteIP: "10.0.0.1",
+	})
+	assert.Equal(t,
+		`method=GET path="/users/42" route="/users/{id:int}" status=200 `+
+			`bytes=5 latency=0s ip=10.0.0.1`,
+		strings.TrimSpace(buf.String()))
+	//-------------------- Another Test Case --------------------
+	buf.Reset()
+	logger.LogAccess(nil, &AccessEntry{
+		Method:      http.MethodGet,
+		Path:        "/checkout",
+		Status:      http.StatusOK,
+		Experiments: map[string]string{"search": "b", "checkout": "a"},
+	})
+	assert.Equal(t,
+		`method=GET path="/checkout" route="" status=200 bytes=0 `+
+			`latency=0s ip= experiments=checkout:a,search:b`,
+		strings.TrimSpace(buf.String()))
+}
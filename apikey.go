@@ -0,0 +1,105 @@
+package mux
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// APIKeyLookup returns the identity of the client that owns the API key, or
+// nil if the key is unknown. Errors are reported for lookups that failed,
+// e.g. because the database is down.
+type APIKeyLookup func(ctx context.Context, key string) (*Identity, error)
+
+// APIKeyFilter takes care of filtering requests by API key. The key is taken
+// from the Header or, if it is not there, from the Query parameter. If Header
+// is "Authorization", the key must be sent with "Bearer" scheme.
+//
+// Used as a filter (see Router.Filter), it hides the subtree from requests
+// without a valid key, so they get "404 Not Found" or match other routes. Use
+// APIKey middleware to reject them with "401 Unauthorized" instead and let
+// handlers know the identity of the client.
+type APIKeyFilter struct {
+	// Header is the name of the header that carries the key.
+	Header string
+
+	// Query is the name of the query parameter that carries the key. Empty
+	// means the key is only accepted in the Header. Keys in URLs tend to end
+	// up in logs, so prefer headers.
+	Query string
+
+	// Lookup resolves the key.
+	Lookup APIKeyLookup
+}
+
+// NewAPIKeyFilter returns pointer to a newly created APIKeyFilter that takes
+// the key from the header.
+func NewAPIKeyFilter(header string, lookup APIKeyLookup) *APIKeyFilter {
+	return &APIKeyFilter{header, "", lookup}
+}
+
+// Match method returns boolean value that tells you whether given request
+// passed the filter. Also, *APIKeyFilter implements the Filter interface
+// since it has this method.
+func (fil *APIKeyFilter) Match(r *http.Request) bool {
+	id, err := fil.identify(r)
+	return id != nil && err == nil
+}
+
+// identify method looks up the identity of the client by the key sent with
+// the request. It returns nil if there is no key or it is unknown.
+func (fil *APIKeyFilter) identify(r *http.Request) (*Identity, error) {
+	key := fil.key(r)
+	if key == "" {
+		return nil, nil
+	}
+	return fil.Lookup(r.Context(), key)
+}
+
+// key method returns the key sent with the request.
+func (fil *APIKeyFilter) key(r *http.Request) string {
+	if fil.Header != "" {
+		key := r.Header.Get(fil.Header)
+		if strings.EqualFold(fil.Header, "Authorization") {
+			scheme, token, _ := strings.Cut(key, " ")
+			key = ""
+			if strings.EqualFold(scheme, "Bearer") {
+				key = strings.TrimSpace(token)
+			}
+		}
+		if key != "" {
+			return key
+		}
+	}
+	if fil.Query != "" {
+		return r.URL.Query().Get(fil.Query)
+	}
+	return ""
+}
+
+// APIKey returns Middleware that protects the subtree with API keys checked by
+// the filter:
+//
+//	partners := rtr.Subrouter().PathPrefix("/partners").
+//	    Wrap(mux.APIKey(mux.NewAPIKeyFilter("X-API-Key", db.LookupKey)))
+//
+// Requests without a valid key get "401 Unauthorized" through Error; failed
+// lookups are reported through Error as they are. Handlers can get the
+// identity of the client with IdentityOf.
+func APIKey(fil *APIKeyFilter) Middleware {
+	return func(next http.Handler) http.Handler {
+		return View(func(w http.ResponseWriter, r *http.Request) {
+			id, err := fil.identify(r)
+			if err != nil {
+				Error(w, r, err)
+				return
+			}
+			if id == nil {
+				Error(w, r, NewHTTPError(http.StatusUnauthorized,
+					"invalid API key"))
+				return
+			}
+			next.ServeHTTP(w, WithIdentity(r, id))
+		})
+	}
+}
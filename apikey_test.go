@@ -0,0 +1,67 @@
+package mux
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIKey(t *testing.T) {
+	lookup := func(ctx context.Context, key string) (*Identity, error) {
+		switch key {
+		case "k1":
+			return &Identity{Subject: "acme"}, nil
+		case "broken":
+			return nil, errors.New("db is down")
+		}
+		return nil, nil
+	}
+	hello := func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("hello, " + Username(r)))
+	}
+
+	rtr := New()
+	fil := &APIKeyFilter{"Authorization", "api_key", lookup}
+	rtr.Subrouter().PathPrefix("/partners").Wrap(APIKey(fil)).Get("/", hello)
+	rtr.Subrouter().PathPrefix("/hidden").
+		Filter(NewAPIKeyFilter("X-API-Key", lookup)).
+		Get("/", hello)
+
+	cases := []struct {
+		path   string
+		header map[string]string
+		code   int
+		body   string
+	}{
+		{"/partners/", nil, http.StatusUnauthorized, "invalid API key\n"},
+		{"/partners/", map[string]string{"Authorization": "Bearer k1"},
+			http.StatusOK, "hello, acme"},
+		{"/partners/", map[string]string{"Authorization": "Basic k1"},
+			http.StatusUnauthorized, "invalid API key\n"},
+		{"/partners/?api_key=k1", nil, http.StatusOK, "hello, acme"},
+		{"/partners/?api_key=k2", nil, http.StatusUnauthorized,
+			"invalid API key\n"},
+		{"/partners/?api_key=broken", nil, http.StatusInternalServerError,
+			"Internal Server Error\n"},
+		// Filters don't set the identity.
+		{"/hidden/", map[string]string{"X-API-Key": "k1"},
+			http.StatusOK, "hello, "},
+		{"/hidden/", map[string]string{"X-API-Key": "k2"},
+			http.StatusNotFound, "404 page not found\n"},
+		{"/hidden/?api_key=k1", nil,
+			http.StatusNotFound, "404 page not found\n"},
+	}
+	for _, c := range cases {
+		rec, req, err := request(http.MethodGet, c.path, nil)
+		assert.NoError(t, err)
+		for k, v := range c.header {
+			req.Header.Set(k, v)
+		}
+		rtr.ServeHTTP(rec, req)
+		assert.Equal(t, c.code, rec.Code, c.path)
+		assert.Equal(t, c.body, rec.Body.String(), c.path)
+	}
+}
@@ -0,0 +1,126 @@
+package mux
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"net/http"
+	"strconv"
+)
+
+// BasicAuth returns Middleware that protects the subtree with HTTP Basic
+// authentication. The check function tells whether the credentials are valid;
+// use BasicAuthUsers for a fixed set of users:
+//
+//	admin := rtr.Subrouter().PathPrefix("/admin").
+//	    Wrap(mux.BasicAuth("admin", mux.BasicAuthUsers(map[string]string{
+//	        "alice": os.Getenv("ALICE_PASSWORD"),
+//	    })))
+//
+// Requests without valid credentials get "401 Unauthorized" with the
+// WWW-Authenticate header through Error. Handlers can get the name of the
+// authenticated user with Username or IdentityOf. Serve it over HTTPS only,
+// since the credentials are sent in clear text.
+func BasicAuth(realm string, check func(user, pass string) bool) Middleware {
+	challenge := "Basic realm=" + strconv.Quote(realm) + `, charset="UTF-8"`
+	return func(next http.Handler) http.Handler {
+		return View(func(w http.ResponseWriter, r *http.Request) {
+			user, pass, ok := r.BasicAuth()
+			if !ok || !check(user, pass) {
+				w.Header().Set("WWW-Authenticate", challenge)
+				Error(w, r, NewHTTPError(http.StatusUnauthorized,
+					"invalid credentials"))
+				return
+			}
+			id := &Identity{Subject: user}
+			next.ServeHTTP(w, WithIdentity(r, id))
+		})
+	}
+}
+
+// BasicAuthUsers returns a check function for BasicAuth that accepts the given
+// users with their passwords. Passwords are compared in constant time, so that
+// response times don't reveal how much of a password was guessed right.
+func BasicAuthUsers(users map[string]string) func(user, pass string) bool {
+	hashes := make(map[string][sha256.Size]byte, len(users))
+	for user, pass := range users {
+		hashes[user] = sha256.Sum256([]byte(pass))
+	}
+	// Unknown users are compared with a dummy hash, so that they take as
+	// long as known ones.
+	var dummy [sha256.Size]byte
+	return func(user, pass string) bool {
+		want, known := hashes[user]
+		if !known {
+			want = dummy
+		}
+		got := sha256.Sum256([]byte(pass))
+		return subtle.ConstantTimeCompare(got[:], want[:]) == 1 && known
+	}
+}
+
+// Identity describes the authenticated client of the request. It is placed in
+// the request context by authentication middleware, like BasicAuth or APIKey,
+// so that handlers and authorization checks don't depend on the way the client
+// was authenticated.
+type Identity struct {
+	// Subject identifies the client, e.g. the name of the user or the ID of
+	// the API client.
+	Subject string
+
+	// Scopes are the permissions granted to the client, e.g. "orders:write".
+	Scopes []string
+
+	// Roles are the roles the client has, e.g. "admin".
+	Roles []string
+}
+
+// HasScope method tells whether the scope was granted to the client.
+func (id *Identity) HasScope(scope string) bool {
+	if id == nil {
+		return false
+	}
+	for _, s := range id.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// HasRole method tells whether the client has the role.
+func (id *Identity) HasRole(role string) bool {
+	if id == nil {
+		return false
+	}
+	for _, r := range id.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// WithIdentity returns a copy of request that carries the identity of the
+// client. Custom authentication middleware should use it, so that IdentityOf
+// works with them as well.
+func WithIdentity(r *http.Request, id *Identity) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), identityKey, id))
+}
+
+// IdentityOf returns the identity of the authenticated client, or nil if the
+// request is not authenticated.
+func IdentityOf(r *http.Request) *Identity {
+	id, _ := r.Context().Value(identityKey).(*Identity)
+	return id
+}
+
+// Username returns the subject of the authenticated client (see Identity),
+// e.g. the name of the user authenticated by BasicAuth, or an empty string if
+// the request is not authenticated.
+func Username(r *http.Request) string {
+	if id := IdentityOf(r); id != nil {
+		return id.Subject
+	}
+	return ""
+}
@@ -0,0 +1,54 @@
+package mux
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBasicAuth(t *testing.T) {
+	rtr := New()
+	rtr.Get("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("public " + Username(r)))
+	})
+	rtr.Subrouter().PathPrefix("/admin").
+		Wrap(BasicAuth("admin area", BasicAuthUsers(map[string]string{
+			"alice": "secret",
+			"bob":   "",
+		}))).
+		Get("/", func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("hello, " + Username(r)))
+		})
+
+	cases := []struct {
+		path string
+		user string
+		pass string
+		code int
+		body string
+	}{
+		{"/", "", "", http.StatusOK, "public "},
+		{"/admin/", "", "", http.StatusUnauthorized, "invalid credentials\n"},
+		{"/admin/", "alice", "secret", http.StatusOK, "hello, alice"},
+		{"/admin/", "alice", "secrets", http.StatusUnauthorized,
+			"invalid credentials\n"},
+		{"/admin/", "bob", "", http.StatusOK, "hello, bob"},
+		{"/admin/", "eve", "", http.StatusUnauthorized,
+			"invalid credentials\n"},
+	}
+	for _, c := range cases {
+		rec, req, err := request(http.MethodGet, c.path, nil)
+		assert.NoError(t, err)
+		if c.user != "" {
+			req.SetBasicAuth(c.user, c.pass)
+		}
+		rtr.ServeHTTP(rec, req)
+		assert.Equal(t, c.code, rec.Code, c.user)
+		assert.Equal(t, c.body, rec.Body.String(), c.user)
+		if c.code == http.StatusUnauthorized {
+			assert.Equal(t, `Basic realm="admin area", charset="UTF-8"`,
+				rec.Header().Get("WWW-Authenticate"))
+		}
+	}
+}
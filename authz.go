@@ -0,0 +1,77 @@
+package mux
+
+import (
+	"net/http"
+	"strings"
+)
+
+// RequireScopes returns Middleware that lets through only the clients that
+// were granted all of the scopes. It relies on the Identity placed in the
+// request context by authentication middleware registered before it (see
+// BasicAuth, APIKey).
+//
+// Unauthenticated requests get "401 Unauthorized" and those that lack a scope
+// get "403 Forbidden" through Error.
+func RequireScopes(scopes ...string) Middleware {
+	return authorize(func(id *Identity) string {
+		for _, scope := range scopes {
+			if !id.HasScope(scope) {
+				return "missing scope " + scope
+			}
+		}
+		return ""
+	})
+}
+
+// RequireRoles returns Middleware that lets through only the clients that
+// have at least one of the roles. It relies on the Identity placed in the
+// request context by authentication middleware registered before it (see
+// BasicAuth, APIKey).
+//
+// Unauthenticated requests get "401 Unauthorized" and those that have none of
+// the roles get "403 Forbidden" through Error.
+func RequireRoles(roles ...string) Middleware {
+	return authorize(func(id *Identity) string {
+		for _, role := range roles {
+			if id.HasRole(role) {
+				return ""
+			}
+		}
+		return "one of roles required: " + strings.Join(roles, ", ")
+	})
+}
+
+// RequireScopes method registers RequireScopes middleware on the Router, so
+// that the authorization policy is declared next to the route:
+//
+//	api.Subrouter().Methods(http.MethodPost).Path("/orders").
+//	    RequireScopes("orders:write").
+//	    HandleFunc(createOrder)
+func (rtr *Router) RequireScopes(scopes ...string) *Router {
+	return rtr.Wrap(RequireScopes(scopes...))
+}
+
+// RequireRoles method registers RequireRoles middleware on the Router.
+func (rtr *Router) RequireRoles(roles ...string) *Router {
+	return rtr.Wrap(RequireRoles(roles...))
+}
+
+// authorize returns Middleware that rejects requests of clients that were
+// not authenticated or for which check returns a reason to deny access.
+func authorize(check func(id *Identity) (denied string)) Middleware {
+	return func(next http.Handler) http.Handler {
+		return View(func(w http.ResponseWriter, r *http.Request) {
+			id := IdentityOf(r)
+			if id == nil {
+				Error(w, r, NewHTTPError(http.StatusUnauthorized,
+					"authentication required"))
+				return
+			}
+			if reason := check(id); reason != "" {
+				Error(w, r, NewHTTPError(http.StatusForbidden, "%s", reason))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package mux
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequire(t *testing.T) {
+	// The "X-Test-Identity" header holds "subject;scope,scope;role,role".
+	authenticate := func(next http.Handler) http.Handler {
+		return View(func(w http.ResponseWriter, r *http.Request) {
+			header := r.Header.Get("X-Test-Identity")
+			if header == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			parts := strings.Split(header, ";")
+			next.ServeHTTP(w, WithIdentity(r, &Identity{
+				Subject: parts[0],
+				Scopes:  strings.Split(parts[1], ","),
+				Roles:   strings.Split(parts[2], ","),
+			}))
+		})
+	}
+	ok := func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}
+
+	rtr := New().Wrap(authenticate)
+	rtr.Subrouter().Methods(http.MethodGet).Path("/orders").
+		RequireScopes("orders:read").
+		HandleFunc(ok)
+	rtr.Subrouter().Methods(http.MethodPost).Path("/orders").
+		RequireScopes("orders:read", "orders:write").
+		HandleFunc(ok)
+	rtr.Subrouter().PathPrefix("/admin").
+		RequireRoles("admin", "ops").
+		Get("/", ok)
+
+	cases := []struct {
+		method   string
+		path     string
+		identity string
+		code     int
+		body     string
+	}{
+		{http.MethodGet, "/orders", "", http.StatusUnauthorized,
+			"authentication required\n"},
+		{http.MethodGet, "/orders", "ann;orders:read;", http.StatusOK, "ok"},
+		{http.MethodPost, "/orders", "ann;orders:read;", http.StatusForbidden,
+			"missing scope orders:write\n"},
+		{http.MethodPost, "/orders", "bob;orders:write,orders:read;",
+			http.StatusOK, "ok"},
+		{http.MethodGet, "/admin/", "ann;orders:read;user",
+			http.StatusForbidden, "one of roles required: admin, ops\n"},
+		{http.MethodGet, "/admin/", "bob;;user,ops", http.StatusOK, "ok"},
+	}
+	for _, c := range cases {
+		rec, req, err := request(c.method, c.path, nil)
+		assert.NoError(t, err)
+		if c.identity != "" {
+			req.Header.Set("X-Test-Identity", c.identity)
+		}
+		rtr.ServeHTTP(rec, req)
+		assert.Equal(t, c.code, rec.Code, c.identity)
+		assert.Equal(t, c.body, rec.Body.String(), c.identity)
+	}
+}
+
+func TestIdentityHas(t *testing.T) {
+	id := &Identity{"ann", []string{"a", "b"}, []string{"admin"}}
+	assert.True(t, id.HasScope("b"))
+	assert.False(t, id.HasScope("admin"))
+	assert.True(t, id.HasRole("admin"))
+	assert.False(t, id.HasRole("a"))
+
+	var none *Identity
+	assert.False(t, none.HasScope("a"))
+	assert.False(t, none.HasRole("admin"))
+}
@@ -0,0 +1,98 @@
+// Use of this source code is governed by the Mozilla Public License Version 2.0
+// that can be found in the LICENSE file.
+
+/*
+Package autocert makes mux.ServeTLS obtain and renew certificates from Let's
+Encrypt (or another ACME server) for the hosts served by a mux routing tree:
+
+	rtr.Subrouter().Host("example.com").HandleFunc(home)
+	cfg, err := autocert.Config(rtr, &autocert.Options{
+	    Email: "admin@example.com",
+	})
+	if err != nil {
+	    log.Fatal(err)
+	}
+	mux.ServeTLS(":443", rtr, &mux.TLSOptions{Config: cfg})
+
+Certificates are verified with the TLS-ALPN-01 challenge, so nothing has to
+listen on port 80.
+*/
+package autocert
+
+import (
+	"crypto/tls"
+	"errors"
+	"net/http"
+
+	"github.com/sharpvik/mux"
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// Options configures certificates obtained with ACME.
+type Options struct {
+	// Email is the contact address of the account, so that the certificate
+	// authority can send notices about the certificates. It may be empty.
+	Email string
+
+	// Hosts are the hosts certificates are obtained for. If nil, they are
+	// collected from the Host filters of the mux.Router (see
+	// mux.Router.Hosts). Certificates are never requested for other hosts,
+	// so that clients can't exhaust the rate limits of the certificate
+	// authority.
+	Hosts []string
+
+	// Cache stores certificates and the account key between restarts. Nil
+	// means autocert.DirCache("autocert") of golang.org/x/crypto.
+	Cache autocert.Cache
+
+	// DirectoryURL is the URL of the ACME directory. Empty means the
+	// production directory of Let's Encrypt.
+	DirectoryURL string
+}
+
+// Config returns function for mux.TLSOptions.Config that makes the server
+// obtain certificates for the hosts served by h. It returns an error if there
+// are no hosts. If opts is nil, defaults are used.
+func Config(h http.Handler, opts *Options) (func(cfg *tls.Config), error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+	m, err := opts.manager(h)
+	if err != nil {
+		return nil, err
+	}
+	return func(cfg *tls.Config) {
+		cfg.GetCertificate = m.GetCertificate
+		cfg.NextProtos = []string{"h2", "http/1.1", acme.ALPNProto}
+	}, nil
+}
+
+// manager method returns autocert.Manager for the hosts served by h. It
+// returns an error if there are no hosts.
+func (opts *Options) manager(h http.Handler) (*autocert.Manager, error) {
+	hosts := opts.Hosts
+	if hosts == nil {
+		if rtr, ok := h.(*mux.Router); ok {
+			hosts = rtr.Hosts()
+		}
+	}
+	if len(hosts) == 0 {
+		return nil, errors.New("autocert: no hosts to obtain certificates " +
+			"for; set them with mux.Router.Host or Options.Hosts")
+	}
+	cache := opts.Cache
+	if cache == nil {
+		cache = autocert.DirCache("autocert")
+	}
+	m := &autocert.Manager{
+		Prompt:     autocert.AcceptTOS,
+		Cache:      cache,
+		HostPolicy: autocert.HostWhitelist(hosts...),
+		Email:      opts.Email,
+	}
+	if opts.DirectoryURL != "" {
+		m.Client = &acme.Client{DirectoryURL: opts.DirectoryURL}
+	}
+	return m, nil
+}
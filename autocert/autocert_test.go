@@ -0,0 +1,37 @@
+package autocert
+
+import (
+	"context"
+	"crypto/tls"
+	"testing"
+
+	"github.com/sharpvik/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig(t *testing.T) {
+	rtr := mux.New()
+	rtr.Subrouter().Host("Example.com", "www.example.com")
+	rtr.Pattern("GET api.example.com/users/{id}")
+
+	configure, err := Config(rtr, nil)
+	assert.NoError(t, err)
+	cfg := &tls.Config{}
+	configure(cfg)
+	assert.NotNil(t, cfg.GetCertificate)
+	assert.Contains(t, cfg.NextProtos, "acme-tls/1")
+
+	m, err := (&Options{}).manager(rtr)
+	assert.NoError(t, err)
+	ctx := context.Background()
+	assert.NoError(t, m.HostPolicy(ctx, "api.example.com"))
+	assert.Error(t, m.HostPolicy(ctx, "evil.com"))
+
+	m, err = (&Options{Hosts: []string{"evil.com"}}).manager(rtr)
+	assert.NoError(t, err)
+	assert.NoError(t, m.HostPolicy(ctx, "evil.com"))
+	//-------------------- Another Test Case --------------------
+	_, err = Config(mux.New(), nil)
+	assert.EqualError(t, err, "autocert: no hosts to obtain certificates "+
+		"for; set them with mux.Router.Host or Options.Hosts")
+}
@@ -0,0 +1,223 @@
+package mux
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FieldError describes a failure to bind a single struct field.
+type FieldError struct {
+	// Field is the name of the struct field.
+	Field string
+
+	// Param is the name of the path variable or query parameter.
+	Param string
+
+	// Err is the conversion error.
+	Err error
+}
+
+// Error method ensures that FieldError implements the error interface.
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Param, e.Err)
+}
+
+// Unwrap method returns the underlying error.
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// BindError is returned by Bind when some of the fields could not be bound. It
+// lists all failures at once, so that clients can fix them in one go.
+type BindError struct {
+	Fields []*FieldError
+}
+
+// Error method ensures that BindError implements the error interface.
+func (e *BindError) Error() string {
+	msgs := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		msgs[i] = f.Error()
+	}
+	return "bind: " + strings.Join(msgs, "; ")
+}
+
+// Bind populates the struct pointed to by dst from path variables and query
+// parameters of the request. Fields are bound according to their `mux` tags;
+// untagged fields and fields tagged with "-" are left intact. Path variables
+// take precedence over query parameters with the same name:
+//
+//	var params struct {
+//	    ID    int      `mux:"id"`
+//	    Page  int      `mux:"page"`
+//	    Tags  []string `mux:"tag"`
+//	    Since time.Time `mux:"since"`
+//	}
+//	err := mux.Bind(r, &params)
+//
+// Supported field types are strings, booleans, integers, unsigned integers,
+// floats, time.Time (RFC 3339 or "2006-01-02") and slices of those; slices are
+// filled with all values of repeated query parameters. Conversion errors for
+// all fields are collected into a single *BindError, which is returned wrapped
+// into *HTTPError with "400 Bad Request" status code, ready to be passed to
+// Error. Bound values are validated afterwards (see Validator).
+func Bind(r *http.Request, dst interface{}) error {
+	if err := bindParams(r, dst); err != nil {
+		return err
+	}
+	return validate(r, dst)
+}
+
+// bindParams populates the struct pointed to by dst like Bind, but doesn't
+// validate it.
+func bindParams(r *http.Request, dst interface{}) error {
+	vars, _ := Vars(r)
+	query := r.URL.Query()
+	return bindStruct(dst, func(field reflect.Value, param string) (
+		bool, error,
+	) {
+		if value, ok := vars[param]; ok {
+			return true, bindVar(field, value)
+		}
+		if values, ok := query[param]; ok {
+			return true, bindStrings(field, values)
+		}
+		return false, nil
+	})
+}
+
+// binder is a function that binds a single struct field to the value of the
+// named parameter. It returns false if there is no such parameter.
+type binder func(field reflect.Value, param string) (bool, error)
+
+// bindStruct calls bind for every tagged field of the struct pointed to by
+// dst. Binding errors are collected into *BindError which is returned as
+// *HTTPError with "400 Bad Request" status code.
+func bindStruct(dst interface{}, bind binder) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Ptr || v.IsNil() ||
+		v.Elem().Kind() != reflect.Struct {
+		return errors.New("bind: destination must be a non-nil struct pointer")
+	}
+	v = v.Elem()
+	t := v.Type()
+	var failed []*FieldError
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		param := field.Tag.Get("mux")
+		if param == "" || param == "-" || field.PkgPath != "" {
+			continue
+		}
+		if _, err := bind(v.Field(i), param); err != nil {
+			var herr *HTTPError
+			if errors.As(err, &herr) {
+				return err
+			}
+			failed = append(failed, &FieldError{field.Name, param, err})
+		}
+	}
+
+	if len(failed) > 0 {
+		return &HTTPError{http.StatusBadRequest, &BindError{failed}}
+	}
+	return nil
+}
+
+// bindVar sets field to the value of a path variable.
+func bindVar(field reflect.Value, value interface{}) error {
+	v := reflect.ValueOf(value)
+	if v.Type().AssignableTo(field.Type()) {
+		field.Set(v)
+		return nil
+	}
+	if t, ok := value.(time.Time); ok {
+		return bindStrings(field, []string{t.Format(time.RFC3339Nano)})
+	}
+	return bindStrings(field, []string{fmt.Sprint(value)})
+}
+
+// bindStrings sets field to the given values. Non-slice fields receive the
+// first one.
+func bindStrings(field reflect.Value, values []string) error {
+	if field.Kind() == reflect.Slice &&
+		field.Type().Elem().Kind() != reflect.Uint8 {
+		slice := reflect.MakeSlice(field.Type(), len(values), len(values))
+		for i, s := range values {
+			if err := bindString(slice.Index(i), s); err != nil {
+				return err
+			}
+		}
+		field.Set(slice)
+		return nil
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	return bindString(field, values[0])
+}
+
+// bindString converts s to the type of field and sets it.
+func bindString(field reflect.Value, s string) error {
+	if field.Type() == reflect.TypeOf(time.Time{}) {
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			t, err = time.Parse("2006-01-02", s)
+		}
+		if err != nil {
+			return fmt.Errorf("invalid time %q", s)
+		}
+		field.Set(reflect.ValueOf(t))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(s)
+
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", s)
+		}
+		field.SetBool(b)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, field.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", s)
+		}
+		field.SetInt(n)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, field.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid unsigned integer %q", s)
+		}
+		field.SetUint(n)
+
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, field.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid number %q", s)
+		}
+		field.SetFloat(f)
+
+	case reflect.Ptr:
+		ptr := reflect.New(field.Type().Elem())
+		if err := bindString(ptr.Elem(), s); err != nil {
+			return err
+		}
+		field.Set(ptr)
+
+	default:
+		return fmt.Errorf("unsupported field type %s", field.Type())
+	}
+	return nil
+}
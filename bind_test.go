@@ -0,0 +1,57 @@
+package mux
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBind(t *testing.T) {
+	type params struct {
+		ID     uint      `mux:"id"`
+		Page   int       `mux:"page"`
+		Tags   []string  `mux:"tag"`
+		Since  time.Time `mux:"since"`
+		Draft  *bool     `mux:"draft"`
+		Ignore string
+	}
+
+	var p params
+	var bindErr error
+	rtr := New()
+	rtr.Get("/posts/{id:nat}", func(w http.ResponseWriter, r *http.Request) {
+		p = params{}
+		bindErr = Bind(r, &p)
+	})
+
+	rec, req, err := request(http.MethodGet,
+		"/posts/42?page=3&tag=go&tag=mux&since=2021-03-14&draft=true&id=7", nil)
+	assert.NoError(t, err)
+	rtr.ServeHTTP(rec, req)
+	assert.NoError(t, bindErr)
+	assert.Equal(t, uint(42), p.ID)
+	assert.Equal(t, 3, p.Page)
+	assert.Equal(t, []string{"go", "mux"}, p.Tags)
+	assert.Equal(t, time.Date(2021, 3, 14, 0, 0, 0, 0, time.UTC), p.Since)
+	if assert.NotNil(t, p.Draft) {
+		assert.True(t, *p.Draft)
+	}
+	//-------------------- Another Test Case --------------------
+	rec, req, err = request(http.MethodGet,
+		"/posts/42?page=three&since=yesterday", nil)
+	assert.NoError(t, err)
+	rtr.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, StatusOf(bindErr))
+	var be *BindError
+	if assert.True(t, errors.As(bindErr, &be)) {
+		assert.Len(t, be.Fields, 2)
+		assert.Equal(t, "Page", be.Fields[0].Field)
+		assert.Equal(t, "since", be.Fields[1].Param)
+	}
+	//-------------------- Another Test Case --------------------
+	assert.Error(t, Bind(req, p))
+}
@@ -0,0 +1,147 @@
+package mux
+
+import (
+	"sync"
+	"time"
+)
+
+// BreakerOptions configures the circuit breaker that stops Proxy from sending
+// requests to a failing backend. The breaker opens once the backend fails a
+// number of times in a row, so that the backend gets no requests for a while.
+// Then it lets a single trial request through: if it succeeds, the breaker
+// closes; otherwise it stays open for another while.
+//
+// The backend fails if it can't be reached or if it responds with "502 Bad
+// Gateway", "503 Service Unavailable" or "504 Gateway Timeout".
+type BreakerOptions struct {
+	// Failures is the number of failures in a row that open the breaker.
+	// Zero means 5.
+	Failures int
+
+	// Cooldown is how long the breaker stays open before it lets a trial
+	// request through. Zero means 30 seconds.
+	Cooldown time.Duration
+}
+
+// breakerState is the state of the circuit breaker.
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+// breaker is a circuit breaker of a backend. Its methods may be called on nil
+// breaker, which always lets requests through.
+type breaker struct {
+	failures int
+	cooldown time.Duration
+	now      func() time.Time
+
+	mu       sync.Mutex
+	state    breakerState
+	failed   int       // failures in a row
+	openedAt time.Time // when the breaker opened last
+	trial    bool      // whether the trial request is in flight
+}
+
+// newBreaker returns pointer to a new closed breaker. It returns nil if opts
+// is nil.
+func newBreaker(opts *BreakerOptions) *breaker {
+	if opts == nil {
+		return nil
+	}
+	cb := &breaker{
+		failures: opts.Failures,
+		cooldown: opts.Cooldown,
+		now:      time.Now,
+	}
+	if cb.failures <= 0 {
+		cb.failures = 5
+	}
+	if cb.cooldown <= 0 {
+		cb.cooldown = 30 * time.Second
+	}
+	return cb
+}
+
+// ready method tells whether the breaker would let a request through. Unlike
+// acquire, it doesn't start the trial.
+func (cb *breaker) ready() bool {
+	if cb == nil {
+		return true
+	}
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	switch cb.state {
+	case breakerOpen:
+		return cb.now().Sub(cb.openedAt) >= cb.cooldown
+	case breakerHalfOpen:
+		return !cb.trial
+	}
+	return true
+}
+
+// acquire method tells whether the breaker lets the request through. Once
+// the cooldown is over, the first request acquired is the trial one.
+func (cb *breaker) acquire() bool {
+	if cb == nil {
+		return true
+	}
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	switch cb.state {
+	case breakerOpen:
+		if cb.now().Sub(cb.openedAt) < cb.cooldown {
+			return false
+		}
+		cb.state = breakerHalfOpen
+		fallthrough
+	case breakerHalfOpen:
+		if cb.trial {
+			return false
+		}
+		cb.trial = true
+	}
+	return true
+}
+
+// success method records the request that succeeded and closes the breaker.
+func (cb *breaker) success() {
+	if cb == nil {
+		return
+	}
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.state = breakerClosed
+	cb.failed = 0
+	cb.trial = false
+}
+
+// failure method records the request that failed and opens the breaker if
+// there were too many failures in a row or if it was the trial request.
+func (cb *breaker) failure() {
+	if cb == nil {
+		return
+	}
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.failed++
+	if cb.state == breakerHalfOpen || cb.failed >= cb.failures {
+		cb.state = breakerOpen
+		cb.openedAt = cb.now()
+		cb.trial = false
+	}
+}
+
+// release method records the request whose outcome is unknown, e.g. because
+// the client went away, so that another trial request may be let through.
+func (cb *breaker) release() {
+	if cb == nil {
+		return
+	}
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.trial = false
+}
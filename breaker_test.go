@@ -0,0 +1,48 @@
+package mux
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBreaker(t *testing.T) {
+	now := time.Now()
+	cb := newBreaker(&BreakerOptions{Failures: 2, Cooldown: time.Minute})
+	cb.now = func() time.Time { return now }
+
+	assert.True(t, cb.acquire())
+	cb.failure()
+	assert.True(t, cb.acquire())
+	cb.success()
+	cb.failure()
+	assert.True(t, cb.ready(), "success resets failures")
+	cb.failure()
+	assert.False(t, cb.ready())
+	assert.False(t, cb.acquire())
+
+	// The trial request fails and the breaker opens again.
+	now = now.Add(time.Minute)
+	assert.True(t, cb.ready())
+	assert.True(t, cb.acquire())
+	assert.False(t, cb.ready(), "one trial at a time")
+	assert.False(t, cb.acquire())
+	cb.failure()
+	assert.False(t, cb.acquire())
+
+	// The trial is released, so another one starts and succeeds.
+	now = now.Add(time.Minute)
+	assert.True(t, cb.acquire())
+	cb.release()
+	assert.True(t, cb.acquire())
+	cb.success()
+	assert.True(t, cb.acquire())
+	assert.True(t, cb.acquire())
+	//-------------------- Another Test Case --------------------
+	var nilBreaker *breaker
+	assert.Nil(t, newBreaker(nil))
+	assert.True(t, nilBreaker.acquire())
+	nilBreaker.failure()
+	assert.True(t, nilBreaker.ready())
+}
@@ -0,0 +1,497 @@
+package mux
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultCacheTTL is how long cached responses are fresh unless specified
+// otherwise.
+const DefaultCacheTTL = time.Minute
+
+// DefaultMaxCacheEntryBytes is the maximum size of the body of a cached
+// response unless specified otherwise.
+const DefaultMaxCacheEntryBytes = 1 << 20
+
+// CachedResponse is a response kept in CacheStore.
+type CachedResponse struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+
+	// Created is when the response was served.
+	Created time.Time
+
+	// Expires is when the response becomes stale. Stale responses are still
+	// served until StaleUntil while they are being revalidated.
+	Expires    time.Time
+	StaleUntil time.Time
+}
+
+// CacheStore keeps cached responses. The default one, MemoryCache, keeps them
+// in memory, so each instance of the server has its own cache. Deployments
+// with several instances may share responses in a store like Redis by
+// implementing this interface.
+type CacheStore interface {
+	// Get returns the response stored under the key, or nil if there is
+	// none.
+	Get(ctx context.Context, key string) (*CachedResponse, error)
+
+	// Set stores the response under the key for the time to live.
+	Set(ctx context.Context, key string, resp *CachedResponse,
+		ttl time.Duration) error
+
+	// DeletePrefix deletes the responses stored under the keys that start
+	// with the prefix.
+	DeletePrefix(ctx context.Context, prefix string) error
+}
+
+// CacheOptions configures Cache.
+type CacheOptions struct {
+	// Store keeps the responses. If nil, a new MemoryCache is used.
+	Store CacheStore
+
+	// Name is prepended to the keys, so that caches that share the Store
+	// have separate responses.
+	Name string
+
+	// TTL is how long responses are fresh. Zero means DefaultCacheTTL.
+	TTL time.Duration
+
+	// StaleWhileRevalidate is how long stale responses are still served
+	// after they expire, while a fresh one is being made in the background.
+	// Zero means that stale responses aren't served.
+	StaleWhileRevalidate time.Duration
+
+	// MaxEntryBytes is the maximum size of the body of a cached response.
+	// Larger responses aren't cached. Zero means DefaultMaxCacheEntryBytes.
+	MaxEntryBytes int
+
+	// Headers are the request headers that select the response in addition
+	// to its host, path and query, e.g. "Accept" or "Accept-Language".
+	// Requests with Authorization header aren't cached, unless it is listed
+	// here.
+	Headers []string
+
+	// Key, if set, returns additional part of the key that selects the
+	// response, e.g. the tenant. Requests with an empty key aren't cached.
+	Key KeyFunc
+}
+
+// Cache keeps responses of GET requests and serves them again while they are
+// fresh, so that read-heavy routes don't run their handlers every time.
+// Responses are cached if they are "200 OK", don't set cookies, aren't encoded
+// (see Compress) and don't forbid caching with Cache-Control header
+// (no-store, no-cache or private). Only the headers set by the handlers are
+// cached, so register Compress and middleware that sets per-request headers
+// around the cache rather than within it.
+// Responses served from the cache carry Age header; X-Cache header tells
+// whether the response was a HIT, a MISS or a STALE one. HEAD requests are
+// served from cached GET responses.
+type Cache struct {
+	store      CacheStore
+	name       string
+	ttl, stale time.Duration
+	maxBytes   int
+	headers    []string
+	key        KeyFunc
+	now        func() time.Time
+
+	// revalidating holds the keys of stale responses that are being
+	// revalidated.
+	revalidating sync.Map
+}
+
+// NewCache returns pointer to a new Cache. If opts is nil, defaults are used.
+func NewCache(opts *CacheOptions) *Cache {
+	if opts == nil {
+		opts = &CacheOptions{}
+	}
+	c := &Cache{
+		store:    opts.Store,
+		name:     opts.Name,
+		ttl:      opts.TTL,
+		stale:    opts.StaleWhileRevalidate,
+		maxBytes: opts.MaxEntryBytes,
+		headers:  opts.Headers,
+		key:      opts.Key,
+		now:      time.Now,
+	}
+	if c.store == nil {
+		c.store = NewMemoryCache()
+	}
+	if c.ttl == 0 {
+		c.ttl = DefaultCacheTTL
+	}
+	if c.maxBytes == 0 {
+		c.maxBytes = DefaultMaxCacheEntryBytes
+	}
+	return c
+}
+
+// Middleware method returns Middleware that serves requests from the cache:
+//
+//	cache := mux.NewCache(&mux.CacheOptions{
+//	    TTL:                  30 * time.Second,
+//	    StaleWhileRevalidate: time.Minute,
+//	    Headers:              []string{"Accept-Language"},
+//	})
+//	rtr.Subrouter().PathPrefix("/products").Cache(cache)
+//	...
+//	cache.Invalidate(ctx, "shop.example.com", "/products/42")
+func (c *Cache) Middleware() Middleware {
+	return func(next http.Handler) http.Handler {
+		return View(func(w http.ResponseWriter, r *http.Request) {
+			key, ok := c.keyOf(r)
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+			resp, err := c.store.Get(r.Context(), key)
+			if err != nil {
+				logCacheError(r, "get", key, err)
+			}
+			now := c.now()
+			switch {
+			case resp != nil && now.Before(resp.Expires):
+				c.serve(w, r, resp, "HIT")
+				return
+			case resp != nil && now.Before(resp.StaleUntil):
+				c.serve(w, r, resp, "STALE")
+				c.revalidate(next, r, key)
+				return
+			}
+
+			w.Header().Set("X-Cache", "MISS")
+			if r.Method != http.MethodGet {
+				next.ServeHTTP(w, r)
+				return
+			}
+			cw := newCacheWriter(w, c.maxBytes)
+			next.ServeHTTP(cw, r)
+			cw.copyHeader()
+			c.keep(r, key, cw)
+		})
+	}
+}
+
+// Cache method registers the Middleware of the Cache on the Router. See
+// Cache.Middleware.
+func (rtr *Router) Cache(c *Cache) *Router {
+	return rtr.Wrap(c.Middleware())
+}
+
+// Invalidate method drops the responses cached for the host and path,
+// whatever their query and headers. The host is compared case-insensitively
+// and without port. The path is the full path of the request, even if the
+// cache is registered on a sub-router with a prefix. Use Purge to drop the
+// responses of every host.
+func (c *Cache) Invalidate(ctx context.Context, host, path string) error {
+	return c.store.DeletePrefix(ctx,
+		c.name+"\x00"+cacheHost(host)+"\x00"+path+"\x00")
+}
+
+// Purge method drops all the responses cached by the Cache.
+func (c *Cache) Purge(ctx context.Context) error {
+	return c.store.DeletePrefix(ctx, c.name+"\x00")
+}
+
+// keyOf method returns the key of the response to the request. It returns
+// false if the request mustn't be cached.
+func (c *Cache) keyOf(r *http.Request) (string, bool) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return "", false
+	}
+	// Routers may serve different hosts with the same paths, so the host is
+	// part of the key.
+	var key strings.Builder
+	key.WriteString(c.name + "\x00" + cacheHost(r.Host) + "\x00")
+	key.WriteString(originalPath(r) + "\x00")
+	key.WriteString(r.URL.Query().Encode())
+	cacheable := r.Header.Get("Authorization") == ""
+	for _, h := range c.headers {
+		if http.CanonicalHeaderKey(h) == "Authorization" {
+			cacheable = true
+		}
+		key.WriteString("\x00" + strings.Join(r.Header.Values(h), ","))
+	}
+	if !cacheable {
+		return "", false
+	}
+	if c.key != nil {
+		k := c.key(r)
+		if k == "" {
+			return "", false
+		}
+		key.WriteString("\x00" + k)
+	}
+	return key.String(), true
+}
+
+// cacheHost returns the host in the form used in the cache keys: lower-cased
+// and without port.
+func cacheHost(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return strings.ToLower(host)
+}
+
+// serve method writes the cached response.
+func (c *Cache) serve(
+	w http.ResponseWriter, r *http.Request, resp *CachedResponse, state string,
+) {
+	h := w.Header()
+	for k, v := range resp.Header {
+		h[k] = append([]string(nil), v...)
+	}
+	age := c.now().Sub(resp.Created) / time.Second
+	h.Set("Age", strconv.FormatInt(int64(age), 10))
+	h.Set("X-Cache", state)
+	h.Set("Content-Length", strconv.Itoa(len(resp.Body)))
+	w.WriteHeader(resp.StatusCode)
+	if r.Method != http.MethodHead {
+		w.Write(resp.Body)
+	}
+}
+
+// revalidate method serves a copy of the request in the background to replace
+// the stale response, unless that is being done already.
+func (c *Cache) revalidate(next http.Handler, r *http.Request, key string) {
+	if _, busy := c.revalidating.LoadOrStore(key, true); busy {
+		return
+	}
+	// Don't let the copy overwrite what is recorded for the access log.
+	ctx := context.WithValue(context.WithoutCancel(r.Context()), routeKey, nil)
+	get := r.Clone(ctx)
+	get.Method = http.MethodGet
+	go func() {
+		defer func() {
+			recover()
+			c.revalidating.Delete(key)
+		}()
+		cw := newCacheWriter(discardWriter{make(http.Header)}, c.maxBytes)
+		next.ServeHTTP(cw, get)
+		c.keep(get, key, cw)
+	}()
+}
+
+// keep method stores the response recorded by the writer, if it may be
+// cached.
+func (c *Cache) keep(r *http.Request, key string, cw *cacheWriter) {
+	if !cw.cacheable() {
+		return
+	}
+	now := c.now()
+	resp := &CachedResponse{
+		StatusCode: cw.code,
+		Header:     cw.header.Clone(),
+		Body:       cw.body.Bytes(),
+		Created:    now,
+		Expires:    now.Add(c.ttl),
+		StaleUntil: now.Add(c.ttl + c.stale),
+	}
+	err := c.store.Set(r.Context(), key, resp, c.ttl+c.stale)
+	if err != nil {
+		logCacheError(r, "set", key, err)
+	}
+}
+
+// logCacheError reports the failure of the cache store to the logger of the
+// router, if there is one.
+func logCacheError(r *http.Request, op, key string, err error) {
+	if l := logger(r); l != nil {
+		l.ErrorContext(r.Context(), "mux: cache store failed", "op", op,
+			"key", key, "err", err)
+	}
+}
+
+// cacheWriter is http.ResponseWriter that records the response, so that it
+// can be cached, while writing it. The handler gets a header of its own, so
+// that only the headers it sets are cached, and not those set for the request
+// by the middleware around the cache, such as Content-Encoding or request IDs.
+type cacheWriter struct {
+	http.ResponseWriter
+	header   http.Header
+	copied   bool
+	code     int
+	body     bytes.Buffer
+	limit    int
+	tooLarge bool
+	flushed  bool
+}
+
+// newCacheWriter returns pointer to a new cacheWriter that wraps w and records
+// bodies up to limit bytes.
+func newCacheWriter(w http.ResponseWriter, limit int) *cacheWriter {
+	return &cacheWriter{ResponseWriter: w, header: make(http.Header),
+		limit: limit}
+}
+
+// Header method returns the header of the handler, which is copied to the
+// underlying http.ResponseWriter when the status code is written.
+func (cw *cacheWriter) Header() http.Header {
+	return cw.header
+}
+
+// copyHeader method copies the header of the handler to the underlying
+// http.ResponseWriter, unless it was copied already.
+func (cw *cacheWriter) copyHeader() {
+	if cw.copied {
+		return
+	}
+	cw.copied = true
+	h := cw.ResponseWriter.Header()
+	for k, v := range cw.header {
+		h[k] = v
+	}
+}
+
+// WriteHeader method records the status code and writes it along with the
+// header.
+func (cw *cacheWriter) WriteHeader(code int) {
+	if cw.code == 0 {
+		cw.code = code
+	}
+	cw.copyHeader()
+	cw.ResponseWriter.WriteHeader(code)
+}
+
+// Write method records the data, unless the response is too large to be
+// cached, and writes it.
+func (cw *cacheWriter) Write(p []byte) (int, error) {
+	if cw.code == 0 {
+		cw.WriteHeader(http.StatusOK)
+	}
+	if !cw.tooLarge {
+		if cw.body.Len()+len(p) > cw.limit {
+			cw.tooLarge = true
+			cw.body = bytes.Buffer{}
+		} else {
+			cw.body.Write(p)
+		}
+	}
+	return cw.ResponseWriter.Write(p)
+}
+
+// Flush method flushes the underlying http.ResponseWriter. Flushed responses
+// are streamed, so they aren't cached.
+func (cw *cacheWriter) Flush() {
+	cw.flushed = true
+	if cw.code == 0 {
+		cw.WriteHeader(http.StatusOK)
+	}
+	if f, ok := cw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap method returns the underlying http.ResponseWriter, so that
+// http.ResponseController can reach it.
+func (cw *cacheWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
+// cacheable method tells whether the recorded response may be cached.
+func (cw *cacheWriter) cacheable() bool {
+	if cw.code != http.StatusOK || cw.tooLarge || cw.flushed {
+		return false
+	}
+	// Encoded responses only suit the clients that accept the encoding.
+	h := cw.header
+	if len(h.Values("Set-Cookie")) > 0 || h.Get("Content-Encoding") != "" {
+		return false
+	}
+	for _, v := range h.Values("Cache-Control") {
+		for _, d := range strings.Split(v, ",") {
+			d, _, _ = strings.Cut(strings.TrimSpace(d), "=")
+			switch strings.ToLower(d) {
+			case "no-store", "no-cache", "private":
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// MemoryCache is CacheStore that keeps responses in memory. Expired responses
+// are dropped from time to time, so that the memory used is proportional to
+// the number of responses that are still fresh or may be served stale.
+type MemoryCache struct {
+	mu      sync.Mutex
+	entries map[string]memoryCacheEntry
+	swept   time.Time
+	now     func() time.Time
+}
+
+// memoryCacheEntry is a response kept by MemoryCache until it expires.
+type memoryCacheEntry struct {
+	resp    *CachedResponse
+	expires time.Time
+}
+
+// NewMemoryCache returns pointer to an empty MemoryCache.
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{
+		entries: make(map[string]memoryCacheEntry),
+		swept:   time.Now(),
+		now:     time.Now,
+	}
+}
+
+// Get method ensures that MemoryCache implements the CacheStore interface.
+func (s *MemoryCache) Get(
+	ctx context.Context, key string,
+) (*CachedResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[key]
+	if !ok || !s.now().Before(e.expires) {
+		return nil, nil
+	}
+	return e.resp, nil
+}
+
+// Set method ensures that MemoryCache implements the CacheStore interface.
+func (s *MemoryCache) Set(
+	ctx context.Context, key string, resp *CachedResponse, ttl time.Duration,
+) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	s.sweep(now)
+	s.entries[key] = memoryCacheEntry{resp, now.Add(ttl)}
+	return nil
+}
+
+// DeletePrefix method ensures that MemoryCache implements the CacheStore
+// interface.
+func (s *MemoryCache) DeletePrefix(ctx context.Context, prefix string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key := range s.entries {
+		if strings.HasPrefix(key, prefix) {
+			delete(s.entries, key)
+		}
+	}
+	return nil
+}
+
+// sweep method drops expired responses, at most once a minute.
+func (s *MemoryCache) sweep(now time.Time) {
+	if now.Sub(s.swept) < time.Minute {
+		return
+	}
+	s.swept = now
+	for key, e := range s.entries {
+		if !now.Before(e.expires) {
+			delete(s.entries, key)
+		}
+	}
+}
@@ -0,0 +1,245 @@
+package mux
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache(t *testing.T) {
+	now := time.Unix(0, 0)
+	store := NewMemoryCache()
+	store.now = func() time.Time { return now }
+	cache := NewCache(&CacheOptions{
+		Store:                store,
+		TTL:                  time.Minute,
+		StaleWhileRevalidate: time.Minute,
+		MaxEntryBytes:        16,
+		Headers:              []string{"Accept-Language"},
+	})
+	cache.now = store.now
+
+	var calls atomic.Int32
+	counter := func(w http.ResponseWriter, r *http.Request) {
+		n := calls.Add(1)
+		Text(w, http.StatusOK, fmt.Sprintf("%s %d", r.URL.Path, n))
+	}
+	rtr := New().HeadFallback(true)
+	rtr.Route("/products", func(r *Router) {
+		r.Cache(cache)
+		r.Get("/{id:int}", counter)
+		r.Post("/{id:int}", counter)
+		r.Get("/large", func(w http.ResponseWriter, r *http.Request) {
+			Text(w, http.StatusOK, strings.Repeat("x", 17))
+		})
+		r.Get("/private", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Cache-Control", "private, max-age=60")
+			counter(w, r)
+		})
+		r.Get("/missing", func(w http.ResponseWriter, r *http.Request) {
+			Text(w, http.StatusNotFound, "missing")
+		})
+	})
+	serve := func(method, url string, header ...string) (string, string) {
+		rec, req, err := request(method, url, nil)
+		assert.NoError(t, err)
+		for i := 0; i < len(header); i += 2 {
+			req.Header.Set(header[i], header[i+1])
+		}
+		rtr.ServeHTTP(rec, req)
+		return rec.Body.String(), rec.Header().Get("X-Cache")
+	}
+
+	body, state := serve(http.MethodGet, "/products/1")
+	assert.Equal(t, "/1 1", body)
+	assert.Equal(t, "MISS", state)
+	now = now.Add(30 * time.Second)
+	rec, req, err := request(http.MethodGet, "/products/1", nil)
+	assert.NoError(t, err)
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, "/1 1", rec.Body.String())
+	assert.Equal(t, "HIT", rec.Header().Get("X-Cache"))
+	assert.Equal(t, "30", rec.Header().Get("Age"))
+	assert.Equal(t, "text/plain; charset=utf-8",
+		rec.Header().Get("Content-Type"))
+	rec, req, err = request(http.MethodHead, "/products/1", nil)
+	assert.NoError(t, err)
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, "", rec.Body.String())
+	assert.Equal(t, "HIT", rec.Header().Get("X-Cache"))
+	//-------------------- Another Test Case --------------------
+	body, _ = serve(http.MethodGet, "/products/1?b=2&a=1")
+	assert.Equal(t, "/1 2", body)
+	body, state = serve(http.MethodGet, "/products/1?a=1&b=2")
+	assert.Equal(t, "/1 2", body)
+	assert.Equal(t, "HIT", state)
+	body, _ = serve(http.MethodGet, "/products/1", "Accept-Language", "de")
+	assert.Equal(t, "/1 3", body)
+	body, _ = serve(http.MethodGet, "/products/1", "Authorization", "secret")
+	assert.Equal(t, "/1 4", body)
+	body, _ = serve(http.MethodGet, "/products/1", "Authorization", "secret")
+	assert.Equal(t, "/1 5", body)
+	body, _ = serve(http.MethodPost, "/products/1")
+	assert.Equal(t, "/1 6", body)
+	//-------------------- Another Test Case --------------------
+	for i := 0; i < 2; i++ {
+		_, state = serve(http.MethodGet, "/products/large")
+		assert.Equal(t, "MISS", state)
+		_, state = serve(http.MethodGet, "/products/private")
+		assert.Equal(t, "MISS", state)
+		_, state = serve(http.MethodGet, "/products/missing")
+		assert.Equal(t, "MISS", state)
+	}
+	//-------------------- Another Test Case --------------------
+	calls.Store(10)
+	now = now.Add(45 * time.Second)
+	body, state = serve(http.MethodGet, "/products/1")
+	assert.Equal(t, "/1 1", body)
+	assert.Equal(t, "STALE", state)
+	assert.Eventually(t, func() bool {
+		body, state = serve(http.MethodGet, "/products/1")
+		return state == "HIT" && body == "/1 11"
+	}, time.Second, time.Millisecond)
+	now = now.Add(3 * time.Minute)
+	body, state = serve(http.MethodGet, "/products/1")
+	assert.Equal(t, "/1 12", body)
+	assert.Equal(t, "MISS", state)
+	//-------------------- Another Test Case --------------------
+	assert.NoError(t, cache.Invalidate(context.Background(), "",
+		"/products/1"))
+	body, state = serve(http.MethodGet, "/products/1")
+	assert.Equal(t, "/1 13", body)
+	assert.Equal(t, "MISS", state)
+	serve(http.MethodGet, "/products/2")
+	assert.NoError(t, cache.Purge(context.Background()))
+	assert.Empty(t, store.entries)
+}
+
+func TestCacheOuterHeaders(t *testing.T) {
+	var ids atomic.Int32
+	requestID := func(next http.Handler) http.Handler {
+		return View(func(w http.ResponseWriter, r *http.Request) {
+			id := fmt.Sprint(ids.Add(1))
+			w.Header().Set("X-Request-Id", id)
+			next.ServeHTTP(w, r)
+		})
+	}
+	page := strings.Repeat("lorem ipsum ", 200)
+	rtr := New()
+	rtr.Wrap(requestID)
+	rtr.Wrap(Compress(&CompressOptions{Encoders: []Encoder{GzipEncoder(-1)}}))
+	rtr.Route("/pages", func(r *Router) {
+		r.Cache(NewCache(&CacheOptions{TTL: time.Minute}))
+		r.Get("/about", func(w http.ResponseWriter, r *http.Request) {
+			Text(w, http.StatusOK, page)
+		})
+	})
+	serve := func(encoding string) *httptest.ResponseRecorder {
+		rec, req, err := request(http.MethodGet, "/pages/about", nil)
+		assert.NoError(t, err)
+		if encoding != "" {
+			req.Header.Set("Accept-Encoding", encoding)
+		}
+		rtr.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := serve("gzip")
+	assert.Equal(t, "MISS", rec.Header().Get("X-Cache"))
+	assert.Equal(t, "gzip", rec.Header().Get("Content-Encoding"))
+	assert.Equal(t, "1", rec.Header().Get("X-Request-Id"))
+	rec = serve("")
+	assert.Equal(t, "HIT", rec.Header().Get("X-Cache"))
+	assert.Empty(t, rec.Header().Get("Content-Encoding"))
+	assert.Equal(t, "2", rec.Header().Get("X-Request-Id"))
+	assert.Equal(t, page, rec.Body.String())
+	rec = serve("gzip")
+	assert.Equal(t, "HIT", rec.Header().Get("X-Cache"))
+	assert.Equal(t, "gzip", rec.Header().Get("Content-Encoding"))
+	assert.Equal(t, "3", rec.Header().Get("X-Request-Id"))
+	zr, err := gzip.NewReader(rec.Body)
+	assert.NoError(t, err)
+	body, err := io.ReadAll(zr)
+	assert.NoError(t, err)
+	assert.Equal(t, page, string(body))
+	//-------------------- Another Test Case --------------------
+	rtr.Get("/encoded", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "br")
+		Text(w, http.StatusOK, "not really brotli")
+	}).Cache(NewCache(nil))
+	for i := 0; i < 2; i++ {
+		rec, req, err := request(http.MethodGet, "/encoded", nil)
+		assert.NoError(t, err)
+		rtr.ServeHTTP(rec, req)
+		assert.Equal(t, "MISS", rec.Header().Get("X-Cache"))
+	}
+}
+
+func TestCacheHosts(t *testing.T) {
+	cache := NewCache(nil)
+	text := func(s string) View {
+		return func(w http.ResponseWriter, r *http.Request) {
+			Text(w, http.StatusOK, s)
+		}
+	}
+	rtr := New().Cache(cache)
+	rtr.Get("/p", text("a")).Host("a.example")
+	rtr.Get("/p", text("b")).Host("b.example")
+	serve := func(host string) (string, string) {
+		rec, req, err := request(http.MethodGet, "/p", nil)
+		assert.NoError(t, err)
+		req.Host = host
+		rtr.ServeHTTP(rec, req)
+		return rec.Body.String(), rec.Header().Get("X-Cache")
+	}
+
+	for _, c := range []struct{ host, body, state string }{
+		{"a.example", "a", "MISS"},
+		{"b.example", "b", "MISS"},
+		{"A.Example:8080", "a", "HIT"},
+		{"b.example", "b", "HIT"},
+	} {
+		body, state := serve(c.host)
+		assert.Equal(t, c.body, body, c.host)
+		assert.Equal(t, c.state, state, c.host)
+	}
+	//-------------------- Another Test Case --------------------
+	ctx := context.Background()
+	assert.NoError(t, cache.Invalidate(ctx, "B.example", "/p"))
+	_, state := serve("a.example")
+	assert.Equal(t, "HIT", state)
+	_, state = serve("b.example")
+	assert.Equal(t, "MISS", state)
+}
+
+func TestMemoryCache(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(0, 0)
+	store := NewMemoryCache()
+	store.now = func() time.Time { return now }
+	store.swept = now
+
+	resp := &CachedResponse{StatusCode: http.StatusOK}
+	assert.NoError(t, store.Set(ctx, "a", resp, time.Second))
+	assert.NoError(t, store.Set(ctx, "b", resp, time.Hour))
+	got, err := store.Get(ctx, "a")
+	assert.NoError(t, err)
+	assert.Same(t, resp, got)
+	now = now.Add(time.Second)
+	got, err = store.Get(ctx, "a")
+	assert.NoError(t, err)
+	assert.Nil(t, got)
+	//-------------------- Another Test Case --------------------
+	now = now.Add(time.Minute)
+	assert.NoError(t, store.Set(ctx, "c", resp, time.Hour))
+	assert.Len(t, store.entries, 2)
+}
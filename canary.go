@@ -0,0 +1,110 @@
+package mux
+
+import (
+	"hash/fnv"
+	"math"
+	"math/rand"
+	"net/http"
+	"sync/atomic"
+)
+
+// canaryBuckets is the number of buckets requests are hashed into, so that
+// percentages have two decimal places.
+const canaryBuckets = 10000
+
+// CanaryOptions configures CanaryFilter.
+type CanaryOptions struct {
+	// Percent is the share of traffic sent to the canary, from 0 to 100.
+	Percent float64
+
+	// Cookie is the name of the cookie that identifies the client, e.g. the
+	// session cookie. Clients with the same value always get the same
+	// assignment, as long as the percentage doesn't shrink.
+	Cookie string
+
+	// Header is the name of the header that identifies the client when it
+	// has no cookie, e.g. "X-User-Id".
+	Header string
+}
+
+// CanaryFilter takes care of filtering the share of requests that goes to the
+// canary version of a handler. Requests are assigned by the hash of the
+// cookie or the header that identifies the client; requests that have neither
+// are assigned at random.
+//
+// A client assigned to the canary stays there while the percentage grows, so
+// the rollout may be made gradual with SetPercent.
+type CanaryFilter struct {
+	opts CanaryOptions
+
+	// buckets is the number of buckets out of canaryBuckets that go to the
+	// canary.
+	buckets atomic.Int64
+}
+
+// NewCanaryFilter returns pointer to a new CanaryFilter. If opts is nil,
+// defaults are used, so no requests go to the canary until SetPercent is
+// called.
+func NewCanaryFilter(opts *CanaryOptions) *CanaryFilter {
+	fil := new(CanaryFilter)
+	if opts != nil {
+		fil.opts = *opts
+	}
+	fil.SetPercent(fil.opts.Percent)
+	return fil
+}
+
+// SetPercent method changes the share of traffic sent to the canary. Values
+// outside of the range from 0 to 100 are clamped. It is safe to call while
+// requests are served.
+func (fil *CanaryFilter) SetPercent(percent float64) {
+	percent = math.Max(0, math.Min(100, percent))
+	fil.buckets.Store(int64(math.Round(percent * canaryBuckets / 100)))
+}
+
+// Percent method returns the share of traffic sent to the canary.
+func (fil *CanaryFilter) Percent() float64 {
+	return float64(fil.buckets.Load()) * 100 / canaryBuckets
+}
+
+// Match method returns boolean value that tells you whether given request
+// passed the filter. Also, *CanaryFilter implements the Filter interface since
+// it has this method.
+func (fil *CanaryFilter) Match(r *http.Request) bool {
+	return canaryBucket(r, &fil.opts) < fil.buckets.Load()
+}
+
+// canaryBucket returns the bucket the request is assigned to.
+func canaryBucket(r *http.Request, opts *CanaryOptions) int64 {
+	var key string
+	if opts.Cookie != "" {
+		if c, err := r.Cookie(opts.Cookie); err == nil {
+			key = c.Value
+		}
+	}
+	if key == "" && opts.Header != "" {
+		key = r.Header.Get(opts.Header)
+	}
+	if key == "" {
+		return rand.Int63n(canaryBuckets)
+	}
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return int64(h.Sum32() % canaryBuckets)
+}
+
+// Canary method creates a sub-router that sends the share of requests chosen
+// by the filter to h. The rest go on to the other routes, so the canary must
+// be registered before the stable version:
+//
+//	canary := mux.NewCanaryFilter(&mux.CanaryOptions{
+//	    Percent: 5,
+//	    Cookie:  "session",
+//	})
+//	rtr.Canary(newCheckout, canary).PathPrefix("/checkout")
+//	rtr.Mount("/checkout", checkout)
+//	...
+//	canary.SetPercent(25)
+func (rtr *Router) Canary(h http.Handler, fil *CanaryFilter) *Router {
+	return rtr.Subrouter().Filter(fil).Handler(h)
+}
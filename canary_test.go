@@ -0,0 +1,78 @@
+package mux
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanaryFilter(t *testing.T) {
+	fil := NewCanaryFilter(&CanaryOptions{
+		Percent: 30,
+		Cookie:  "session",
+		Header:  "X-User-Id",
+	})
+	assert.Equal(t, 30.0, fil.Percent())
+
+	canary := 0
+	assigned := make(map[string]bool)
+	for i := 0; i < 1000; i++ {
+		r, _ := http.NewRequest(http.MethodGet, "/", nil)
+		id := fmt.Sprintf("user-%d", i)
+		if i%2 == 0 {
+			r.AddCookie(&http.Cookie{Name: "session", Value: id})
+		} else {
+			r.Header.Set("X-User-Id", id)
+		}
+		match := fil.Match(r)
+		assigned[id] = match
+		if match {
+			canary++
+		}
+		assert.Equal(t, match, fil.Match(r), "assignment must be sticky")
+	}
+	assert.InDelta(t, 300, canary, 60)
+	//-------------------- Another Test Case --------------------
+	fil.SetPercent(60)
+	for id, match := range assigned {
+		r, _ := http.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("X-User-Id", id)
+		if match {
+			assert.True(t, fil.Match(r), "canary clients must stay")
+		}
+	}
+	//-------------------- Another Test Case --------------------
+	fil.SetPercent(150)
+	assert.Equal(t, 100.0, fil.Percent())
+	r, _ := http.NewRequest(http.MethodGet, "/", nil)
+	assert.True(t, fil.Match(r))
+	fil.SetPercent(-1)
+	assert.False(t, fil.Match(r))
+	assert.False(t, NewCanaryFilter(nil).Match(r))
+}
+
+func TestCanary(t *testing.T) {
+	text := func(s string) View {
+		return func(w http.ResponseWriter, r *http.Request) {
+			Text(w, http.StatusOK, s)
+		}
+	}
+	canary := NewCanaryFilter(&CanaryOptions{Header: "X-User-Id"})
+	rtr := New()
+	rtr.Canary(text("canary"), canary).PathPrefix("/checkout")
+	rtr.Mount("/checkout", text("stable"))
+	rtr.Get("/home", text("home"))
+
+	serve := func(path string) string {
+		rec, req, _ := request(http.MethodGet, path, nil)
+		req.Header.Set("X-User-Id", "42")
+		rtr.ServeHTTP(rec, req)
+		return rec.Body.String()
+	}
+	assert.Equal(t, "stable", serve("/checkout"))
+	canary.SetPercent(100)
+	assert.Equal(t, "canary", serve("/checkout"))
+	assert.Equal(t, "home", serve("/home"))
+}
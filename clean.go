@@ -0,0 +1,81 @@
+package mux
+
+import (
+	"net/http"
+	"path"
+	"strings"
+)
+
+// Path cleaning modes used by Router.CleanPath.
+const (
+	cleanNone = iota
+	cleanRewrite
+	cleanRedirect
+)
+
+// CleanPath method enables an opt-in pre-routing step that cleans request paths
+// by eliminating repeated slashes as well as "." and ".." elements (see
+// path.Clean), so that "//a/../b" becomes "/b". Trailing slash is preserved.
+//
+// If redirect is false, the cleaned path is routed right away. Otherwise,
+// the client is redirected to the cleaned URL ("301 Moved Permanently" for GET
+// and HEAD, "308 Permanent Redirect" for other methods).
+func (rtr *Router) CleanPath(redirect bool) *Router {
+	rtr.clean = cleanRewrite
+	if redirect {
+		rtr.clean = cleanRedirect
+	}
+	return rtr
+}
+
+// cleanPath method cleans request path according to the mode set by
+// CleanPath. If the path is rewritten, a copy of the request is returned, so
+// that the request of the caller stays intact. It returns false if the request
+// was answered with a redirect and must not be routed any further.
+func (rtr *Router) cleanPath(
+	w http.ResponseWriter, r *http.Request,
+) (*http.Request, bool) {
+	if rtr.clean == cleanNone {
+		return r, true
+	}
+
+	clean := cleanPath(r.URL.Path)
+	if clean == r.URL.Path {
+		return r, true
+	}
+
+	if rtr.clean == cleanRedirect {
+		code := http.StatusPermanentRedirect
+		if r.Method == http.MethodGet || r.Method == http.MethodHead {
+			code = http.StatusMovedPermanently
+		}
+		// Parent routers may have cut their prefixes already; restore them.
+		orig := originalPath(r)
+		if strings.HasSuffix(orig, r.URL.Path) {
+			clean = orig[:len(orig)-len(r.URL.Path)] + clean
+		}
+		http.Redirect(w, r, requestURI(r, clean), code)
+		return r, false
+	}
+
+	rawPath := ""
+	if r.URL.RawPath != "" {
+		rawPath = cleanPath(r.URL.RawPath)
+	}
+	return withPath(r, clean, rawPath), true
+}
+
+// cleanPath returns the canonical form of p, preserving its trailing slash.
+func cleanPath(p string) string {
+	if p == "" {
+		return "/"
+	}
+	if p[0] != '/' {
+		p = "/" + p
+	}
+	clean := path.Clean(p)
+	if strings.HasSuffix(p, "/") && clean != "/" {
+		clean += "/"
+	}
+	return clean
+}
@@ -0,0 +1,45 @@
+package mux
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCleanPath(t *testing.T) {
+	cases := map[string]string{
+		"":          "/",
+		"/":         "/",
+		"//a/../b":  "/b",
+		"/a/./b/":   "/a/b/",
+		"a//b":      "/a/b",
+		"/../../x/": "/x/",
+	}
+	for in, out := range cases {
+		assert.Equal(t, out, cleanPath(in), in)
+	}
+}
+
+func TestRouterCleanPath(t *testing.T) {
+	rtr := New().CleanPath(false)
+	rtr.Get("/b", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, r.URL.Path)
+	})
+
+	rec, req, err := request(http.MethodGet, "/", nil)
+	assert.NoError(t, err)
+	req.URL.Path = "//a/../b"
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, "/b", rec.Body.String())
+	assert.Equal(t, "//a/../b", req.URL.Path, "caller's request changed")
+	//-------------------- Another Test Case --------------------
+	rtr.CleanPath(true)
+	rec, req, err = request(http.MethodGet, "/?q=1", nil)
+	assert.NoError(t, err)
+	req.URL.Path = "//a/../b"
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusMovedPermanently, rec.Code)
+	assert.Equal(t, "/b?q=1", rec.Header().Get("Location"))
+}
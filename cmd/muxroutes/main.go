@@ -0,0 +1,156 @@
+// Use of this source code is governed by the Mozilla Public License Version 2.0
+// that can be found in the LICENSE file.
+
+/*
+Command muxroutes prints the routing table of a package that builds its routes
+with github.com/sharpvik/mux.
+
+The package must export a function that returns the root Router:
+
+	func Routes() *mux.Router
+
+Usage:
+
+	muxroutes [-func Routes] [-tree] [package]
+
+The package defaults to the one in the current directory. muxroutes generates a
+tiny program that calls the function and walks the returned Router, and runs it
+with "go run" inside the package's module, so the module must be buildable.
+By default, routes with handlers are printed as a table of methods, path
+templates, names and handlers; -tree prints the whole tree instead.
+*/
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+func main() {
+	fn := flag.String("func", "Routes", "exported function returning *mux.Router")
+	tree := flag.Bool("tree", false, "print the routing tree instead of table")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: muxroutes [-func Routes] [-tree] [package]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	pkg := "."
+	if flag.NArg() > 1 {
+		flag.Usage()
+		os.Exit(2)
+	} else if flag.NArg() == 1 {
+		pkg = flag.Arg(0)
+	}
+
+	if err := run(pkg, *fn, *tree); err != nil {
+		fmt.Fprintln(os.Stderr, "muxroutes:", err)
+		os.Exit(1)
+	}
+}
+
+// run generates the program that prints routes of the package and runs it.
+func run(pkg string, fn string, tree bool) error {
+	out, err := exec.Command(
+		"go", "list", "-f", "{{.ImportPath}}\t{{.Module.Dir}}", pkg,
+	).Output()
+	if err != nil {
+		return fmt.Errorf("can't find package %s: %v", pkg, err)
+	}
+	fields := strings.Split(strings.TrimSpace(string(out)), "\t")
+	if len(fields) != 2 || fields[1] == "" {
+		return fmt.Errorf("package %s is not in a module", pkg)
+	}
+	importPath, moduleDir := fields[0], fields[1]
+
+	src, err := program(importPath, fn, tree)
+	if err != nil {
+		return err
+	}
+
+	// The program must live inside the module to import the package.
+	dir, err := os.MkdirTemp(moduleDir, ".muxroutes-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), src, 0644); err != nil {
+		return err
+	}
+
+	cmd := exec.Command("go", "run", "./"+filepath.Base(dir))
+	cmd.Dir = moduleDir
+	cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+	return cmd.Run()
+}
+
+// program returns the source code of the program that prints routes returned
+// by the function of the package.
+func program(importPath string, fn string, tree bool) ([]byte, error) {
+	var buf bytes.Buffer
+	err := programTemplate.Execute(&buf, struct {
+		Package string
+		Func    string
+		Tree    bool
+	}{importPath, fn, tree})
+	if err != nil {
+		return nil, err
+	}
+	return format.Source(buf.Bytes())
+}
+
+var programTemplate = template.Must(template.New("program").Parse(`
+// Code generated by muxroutes. DO NOT EDIT.
+
+package main
+
+import (
+	"os"
+{{- if not .Tree}}
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/sharpvik/mux"
+{{- end}}
+
+	target {{printf "%q" .Package}}
+)
+
+func main() {
+	rtr := target.{{.Func}}()
+{{- if .Tree}}
+	rtr.PrintTree(os.Stdout)
+{{- else}}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "METHODS\tPATH\tNAME\tHANDLER\tSOURCE")
+	rtr.Walk(func(route *mux.RouteInfo) error {
+		if route.Handler == nil {
+			return nil
+		}
+		methods := "*"
+		if route.Methods != nil {
+			methods = strings.Join(route.Methods, ",")
+		}
+		path := route.Template()
+		if path == "" {
+			path = "/"
+		}
+		if route.Disabled {
+			path += " (disabled)"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", methods, path, route.Name,
+			mux.HandlerName(route.Handler), route.Source)
+		return nil
+	})
+	w.Flush()
+{{- end}}
+}
+`))
@@ -0,0 +1,22 @@
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProgram(t *testing.T) {
+	for _, tree := range []bool{false, true} {
+		src, err := program("example.com/app/routes", "Routes", tree)
+		assert.NoError(t, err)
+
+		file, err := parser.ParseFile(token.NewFileSet(), "main.go", src, 0)
+		assert.NoError(t, err)
+		assert.Equal(t, "main", file.Name.Name)
+		assert.Contains(t, string(src), `target "example.com/app/routes"`)
+		assert.Contains(t, string(src), "target.Routes()")
+	}
+}
@@ -0,0 +1,112 @@
+package mux
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RouteError describes a problem with a single route found by Compile.
+type RouteError struct {
+	// Route is the route in question.
+	Route *RouteInfo
+
+	// Err is the problem.
+	Err error
+}
+
+// Error method ensures that RouteError implements the error interface.
+func (e *RouteError) Error() string {
+	return fmt.Sprintf("%s: %v", describe(e.Route), e.Err)
+}
+
+// Unwrap method returns the underlying error.
+func (e *RouteError) Unwrap() error {
+	return e.Err
+}
+
+// CompileError is returned by Compile when some of the routes are invalid. It
+// lists all problems at once.
+type CompileError struct {
+	Errors []*RouteError
+}
+
+// Error method ensures that CompileError implements the error interface.
+func (e *CompileError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
+	}
+	return "invalid routes:\n\t" + strings.Join(msgs, "\n\t")
+}
+
+// Compile method prepares the whole routing tree for serving, so that no work
+// is left for the first requests: it builds route indices and compiles path
+// filters that were constructed by hand (e.g. &mux.PathFilter{Path: "/x"}),
+// which would otherwise panic upon the first request. Problems are reported
+// all at once instead of one by one:
+//
+//	if err := rtr.Compile(); err != nil {
+//	    log.Fatal(err)
+//	}
+//
+// Path prefixes with variables are reported as well, since prefixes are matched
+// literally. Compile may be called again after new routes are added. It returns
+// *CompileError if some routes are invalid and nil otherwise.
+func (rtr *Router) Compile() error {
+	var errs []*RouteError
+	rtr.compile(nil, &errs)
+	if len(errs) > 0 {
+		return &CompileError{errs}
+	}
+	return nil
+}
+
+// compile method compiles this Router and its sub-routers recursively,
+// collecting errors.
+func (rtr *Router) compile(prefixes []string, errs *[]*RouteError) {
+	info := rtr.info(prefixes, 0)
+	fail := func(err error) {
+		*errs = append(*errs, &RouteError{info, err})
+	}
+
+	fils := []Filter{}
+	if rtr.filters.Path != nil {
+		fils = append(fils, rtr.filters.Path)
+	}
+	for _, fil := range append(fils, rtr.filters.Custom...) {
+		if fil, ok := fil.(*PathFilter); ok {
+			if err := fil.compile(); err != nil {
+				fail(err)
+			}
+		}
+	}
+	if prefix := rtr.filters.PathPrefix; prefix != nil {
+		for _, seg := range strings.Split(string(*prefix), "/") {
+			if isVar(seg) {
+				fail(fmt.Errorf("variable %s in path prefix %s", seg, *prefix))
+			}
+		}
+	}
+
+	for _, route := range rtr.ordered() {
+		route.compile(info.Prefixes, errs)
+	}
+}
+
+// compile method completes the PathFilter that was constructed by hand. Filters
+// created by NewPathFilter are left intact. A Regexp that was set by hand is
+// kept as is.
+func (fil *PathFilter) compile() error {
+	if fil.segments != nil {
+		return nil
+	}
+	compiled, err := compilePathFilter(fil.Path, fil.Strict)
+	if err != nil {
+		return err
+	}
+	if fil.Regexp != nil {
+		compiled.Regexp = fil.Regexp
+	}
+	*fil = *compiled
+	return nil
+}
@@ -0,0 +1,52 @@
+package mux
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompile(t *testing.T) {
+	rtr := New()
+	rtr.Subrouter().
+		Filter(&PathFilter{Path: "/users/{id:int}", Strict: true}).
+		HandleFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	rec, req, err := request(http.MethodGet, "/users/42", nil)
+	assert.NoError(t, err)
+	assert.Panics(t, func() { rtr.ServeHTTP(rec, req) })
+	assert.NoError(t, rtr.Compile())
+
+	rec, req, err = request(http.MethodGet, "/users/42", nil)
+	assert.NoError(t, err)
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec, req, err = request(http.MethodGet, "/users/john", nil)
+	assert.NoError(t, err)
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+//-------------------- Another Test Case --------------------
+
+func TestCompileErrors(t *testing.T) {
+	rtr := New()
+	rtr.Subrouter().Filter(&PathFilter{Path: "/a/{x:*}/b"})
+	api := rtr.Subrouter().PathPrefix("/api/{version:int}")
+	api.Subrouter().Filter(&PathFilter{Path: "/b/{x:int}/{x:int}"})
+
+	err := rtr.Compile()
+	var cerr *CompileError
+	assert.True(t, errors.As(err, &cerr))
+	assert.Len(t, cerr.Errors, 3)
+	assert.Contains(t, cerr.Errors[0].Error(), "catch-all variable")
+	assert.Contains(t, cerr.Errors[1].Error(), "in path prefix")
+	assert.Equal(t, "/api/{version:int}", cerr.Errors[2].Route.Template())
+	assert.Contains(t, cerr.Errors[2].Error(), "duplicate variable x")
+
+	assert.Panics(t, func() { NewPathFilter("/{x:int}/{x:int}") })
+	assert.Panics(t, func() { NewPathFilter("") })
+}
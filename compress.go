@@ -0,0 +1,380 @@
+package mux
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"errors"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Encoder compresses response bodies with a content coding. Writers that have
+// a Reset(io.Writer) method are reused across responses. Package encoding
+// provides Encoders for "br" and "zstd" content codings.
+type Encoder interface {
+	// Encoding returns the name of the content coding as used by the
+	// Accept-Encoding and Content-Encoding headers, e.g. "gzip".
+	Encoding() string
+
+	// NewWriter returns a writer that compresses the data written to it
+	// into w. The data must be flushed to w when the writer is closed.
+	NewWriter(w io.Writer) io.WriteCloser
+}
+
+// GzipEncoder returns Encoder for "gzip" content coding with the compression
+// level of the compress/gzip package. Invalid levels fall back to the default
+// one.
+func GzipEncoder(level int) Encoder {
+	if level < gzip.HuffmanOnly || level > gzip.BestCompression {
+		level = gzip.DefaultCompression
+	}
+	return &encoder{"gzip", func(w io.Writer) io.WriteCloser {
+		gw, _ := gzip.NewWriterLevel(w, level)
+		return gw
+	}}
+}
+
+// DeflateEncoder returns Encoder for "deflate" content coding with the
+// compression level of the compress/flate package. Invalid levels fall back to
+// the default one.
+func DeflateEncoder(level int) Encoder {
+	if level < flate.HuffmanOnly || level > flate.BestCompression {
+		level = flate.DefaultCompression
+	}
+	return &encoder{"deflate", func(w io.Writer) io.WriteCloser {
+		fw, _ := flate.NewWriter(w, level)
+		return fw
+	}}
+}
+
+// resetWriter is a compressing writer that can be reused.
+type resetWriter interface {
+	io.WriteCloser
+	Reset(w io.Writer)
+}
+
+// encoder is a simple implementation of Encoder.
+type encoder struct {
+	encoding  string
+	newWriter func(w io.Writer) io.WriteCloser
+}
+
+// Encoding method ensures that encoder implements the Encoder interface.
+func (e *encoder) Encoding() string {
+	return e.encoding
+}
+
+// NewWriter method ensures that encoder implements the Encoder interface.
+func (e *encoder) NewWriter(w io.Writer) io.WriteCloser {
+	return e.newWriter(w)
+}
+
+// CompressOptions configures Compress.
+type CompressOptions struct {
+	// Encoders are the content codings the server offers, in order of its
+	// preference, which is used when the client likes several of them
+	// equally. If nil, gzip is offered with default level.
+	Encoders []Encoder
+
+	// MinSize is the size of the body (in bytes) below which responses are
+	// sent uncompressed, since compression would not pay off. Zero means
+	// 1024; use a negative value to compress every response.
+	MinSize int
+
+	// Types is a list of media types that are compressed. Types ending with
+	// "/*" match every subtype, e.g. "text/*". If nil, text, JSON,
+	// JavaScript, XML, SVG and WebAssembly are compressed.
+	Types []string
+}
+
+// defaultCompressTypes are the media types compressed by default.
+var defaultCompressTypes = []string{
+	"text/*",
+	"application/json",
+	"application/javascript",
+	"application/xml",
+	"application/wasm",
+	"image/svg+xml",
+}
+
+// Compress returns Middleware that compresses responses with the content
+// coding negotiated via the Accept-Encoding header of the request:
+//
+//	rtr.Wrap(mux.Compress(&mux.CompressOptions{
+//	    Encoders: []mux.Encoder{encoding.Brotli(5), mux.GzipEncoder(6)},
+//	}))
+//
+// Responses are compressed only if their media type is listed in Types and
+// their body is at least MinSize bytes long. Responses to HEAD requests,
+// partial responses and responses that have Content-Encoding set by the
+// handler are left as is. If opts is nil, defaults are used.
+func Compress(opts *CompressOptions) Middleware {
+	c := &compressor{minSize: 1024}
+	if opts != nil {
+		c.encoders, c.types = opts.Encoders, opts.Types
+		if opts.MinSize != 0 {
+			c.minSize = opts.MinSize
+		}
+	}
+	if c.encoders == nil {
+		c.encoders = []Encoder{GzipEncoder(-1)}
+	}
+	if c.types == nil {
+		c.types = defaultCompressTypes
+	}
+	c.pools = make([]sync.Pool, len(c.encoders))
+
+	return func(next http.Handler) http.Handler {
+		return View(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Accept-Encoding")
+			i := c.negotiate(r.Header.Values("Accept-Encoding"))
+			if i < 0 || r.Method == http.MethodHead {
+				next.ServeHTTP(w, r)
+				return
+			}
+			cw := &compressWriter{ResponseWriter: w, c: c, enc: i}
+			defer cw.Close()
+			next.ServeHTTP(cw, r)
+		})
+	}
+}
+
+// compressor holds the configuration of Compress.
+type compressor struct {
+	encoders []Encoder
+	pools    []sync.Pool
+	minSize  int
+	types    []string
+}
+
+// negotiate method returns index of the encoder that suits the Accept-Encoding
+// header best, or -1 if none is acceptable.
+func (c *compressor) negotiate(accept []string) int {
+	if len(accept) == 0 {
+		return -1
+	}
+	qs := make(map[string]float64)
+	for _, part := range strings.Split(strings.Join(accept, ","), ",") {
+		params := strings.Split(part, ";")
+		coding := strings.ToLower(strings.TrimSpace(params[0]))
+		if coding == "" {
+			continue
+		}
+		q := 1.0
+		for _, p := range params[1:] {
+			p = strings.TrimSpace(p)
+			if strings.HasPrefix(p, "q=") {
+				if v, err := strconv.ParseFloat(p[2:], 64); err == nil {
+					q = v
+				}
+			}
+		}
+		qs[coding] = q
+	}
+
+	best, bestQ := -1, 0.0
+	for i, enc := range c.encoders {
+		q, ok := qs[enc.Encoding()]
+		if !ok {
+			q = qs["*"]
+		}
+		if q > bestQ {
+			best, bestQ = i, q
+		}
+	}
+	return best
+}
+
+// compressible method tells whether responses of the media type should be
+// compressed.
+func (c *compressor) compressible(contentType string) bool {
+	mt, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, t := range c.types {
+		if t == mt || strings.HasSuffix(t, "/*") &&
+			strings.HasPrefix(mt, strings.TrimSuffix(t, "*")) {
+			return true
+		}
+	}
+	return false
+}
+
+// compressWriter is an http.ResponseWriter that buffers the beginning of the
+// body until it is known whether the response should be compressed.
+type compressWriter struct {
+	http.ResponseWriter
+	c   *compressor
+	enc int
+
+	// status is the status code passed to WriteHeader, if any.
+	status int
+
+	// buf holds the beginning of the body until the decision is made.
+	buf []byte
+
+	// decided tells whether the header was written to the underlying
+	// writer, and zw is the compressing writer if compression is on.
+	decided bool
+	zw      io.WriteCloser
+}
+
+// WriteHeader method records the status code. It is written to the underlying
+// writer once it is known whether the response is compressed.
+func (cw *compressWriter) WriteHeader(code int) {
+	if cw.decided || cw.status != 0 {
+		return
+	}
+	if code < 200 && code != http.StatusSwitchingProtocols {
+		cw.ResponseWriter.WriteHeader(code)
+		return
+	}
+	cw.status = code
+	if !bodyAllowed(code) {
+		cw.decide(false)
+	}
+}
+
+// Write method buffers the data until MinSize bytes are collected, then
+// passes it on compressed or as is.
+func (cw *compressWriter) Write(b []byte) (int, error) {
+	if cw.status == 0 {
+		cw.status = http.StatusOK
+	}
+	if !cw.decided {
+		if h := cw.Header(); h.Get("Content-Type") == "" {
+			sniff := append(cw.buf[:len(cw.buf):len(cw.buf)], b...)
+			h.Set("Content-Type", http.DetectContentType(sniff))
+		}
+		if len(cw.buf)+len(b) < cw.c.minSize {
+			cw.buf = append(cw.buf, b...)
+			return len(b), nil
+		}
+		if err := cw.decide(true); err != nil {
+			return 0, err
+		}
+	}
+	if cw.zw != nil {
+		return cw.zw.Write(b)
+	}
+	return cw.ResponseWriter.Write(b)
+}
+
+// decide method writes the header, compressing the response if it is large
+// enough and suitable, and flushes the buffered data.
+func (cw *compressWriter) decide(large bool) error {
+	cw.decided = true
+	h := cw.Header()
+	if cw.status == 0 {
+		cw.status = http.StatusOK
+	}
+	if large && cw.compressible() {
+		enc := cw.c.encoders[cw.enc]
+		h.Set("Content-Encoding", enc.Encoding())
+		h.Del("Content-Length")
+		// The compressed body is not byte-for-byte equal to the original.
+		if etag := h.Get("ETag"); strings.HasPrefix(etag, `"`) {
+			h.Set("ETag", "W/"+etag)
+		}
+		if zw, ok := cw.c.pools[cw.enc].Get().(resetWriter); ok {
+			zw.Reset(cw.ResponseWriter)
+			cw.zw = zw
+		} else {
+			cw.zw = enc.NewWriter(cw.ResponseWriter)
+		}
+	}
+	cw.ResponseWriter.WriteHeader(cw.status)
+
+	buf := cw.buf
+	cw.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	var err error
+	if cw.zw != nil {
+		_, err = cw.zw.Write(buf)
+	} else {
+		_, err = cw.ResponseWriter.Write(buf)
+	}
+	return err
+}
+
+// compressible method tells whether the response may be compressed given its
+// status code and headers.
+func (cw *compressWriter) compressible() bool {
+	h := cw.Header()
+	return bodyAllowed(cw.status) &&
+		cw.status != http.StatusPartialContent &&
+		h.Get("Content-Encoding") == "" &&
+		h.Get("Content-Range") == "" &&
+		cw.c.compressible(h.Get("Content-Type"))
+}
+
+// Close method writes the rest of the response and releases the compressing
+// writer.
+func (cw *compressWriter) Close() error {
+	if !cw.decided {
+		if cw.status == 0 && len(cw.buf) == 0 {
+			// The handler wrote nothing; let the server respond.
+			return nil
+		}
+		if err := cw.decide(false); err != nil {
+			return err
+		}
+	}
+	if cw.zw == nil {
+		return nil
+	}
+	err := cw.zw.Close()
+	if zw, ok := cw.zw.(resetWriter); ok {
+		zw.Reset(io.Discard)
+		cw.c.pools[cw.enc].Put(zw)
+	}
+	cw.zw = nil
+	return err
+}
+
+// Flush method ensures that compressWriter implements the http.Flusher
+// interface. Flushing makes the decision right away, so that streamed
+// responses are compressed even if the first chunk is small.
+func (cw *compressWriter) Flush() {
+	if !cw.decided {
+		cw.decide(true)
+	}
+	if f, ok := cw.zw.(interface{ Flush() error }); ok {
+		f.Flush()
+	}
+	if f, ok := cw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack method ensures that compressWriter implements the http.Hijacker
+// interface.
+func (cw *compressWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := cw.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("mux: response can't be hijacked")
+	}
+	cw.decided = true
+	return h.Hijack()
+}
+
+// Unwrap method returns the underlying writer. It is used by
+// http.ResponseController.
+func (cw *compressWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
+// bodyAllowed tells whether a response with the status code may have a body.
+func bodyAllowed(code int) bool {
+	return code >= 200 && code != http.StatusNoContent &&
+		code != http.StatusNotModified
+}
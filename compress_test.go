@@ -0,0 +1,136 @@
+package mux
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompress(t *testing.T) {
+	long := strings.Repeat("hello, world! ", 200)
+	text := func(s string) View {
+		return func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			w.Header().Set("ETag", `"v1"`)
+			w.Write([]byte(s))
+		}
+	}
+
+	rtr := New().Wrap(Compress(&CompressOptions{
+		Encoders: []Encoder{GzipEncoder(-1), DeflateEncoder(-1)},
+	}))
+	rtr.Get("/long", text(long))
+	rtr.Get("/short", text("hello"))
+	rtr.Get("/image", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		w.Write([]byte(long))
+	})
+	rtr.Get("/sniff", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("<!DOCTYPE html><html>" + long + "</html>"))
+	})
+	rtr.Get("/empty", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	decode := func(encoding string, body io.Reader) string {
+		var r io.Reader
+		switch encoding {
+		case "gzip":
+			gr, err := gzip.NewReader(body)
+			assert.NoError(t, err)
+			r = gr
+		case "deflate":
+			fr := flate.NewReader(body)
+			defer fr.Close()
+			r = fr
+		default:
+			r = body
+		}
+		b, err := io.ReadAll(r)
+		assert.NoError(t, err)
+		return string(b)
+	}
+
+	cases := []struct {
+		path     string
+		accept   string
+		encoding string
+	}{
+		{"/long", "gzip", "gzip"},
+		{"/long", "br, deflate", "deflate"},
+		{"/long", "deflate, gzip", "gzip"},
+		{"/long", "deflate;q=1, gzip;q=0.5", "deflate"},
+		{"/long", "*", "gzip"},
+		{"/long", "*, gzip;q=0", "deflate"},
+		{"/long", "identity", ""},
+		{"/long", "", ""},
+		{"/short", "gzip", ""},
+		{"/image", "gzip", ""},
+		{"/sniff", "gzip", "gzip"},
+	}
+	for i := 0; i < 2; i++ {
+		// Repeat to use pooled writers.
+		for _, c := range cases {
+			rec, req, err := request(http.MethodGet, c.path, nil)
+			assert.NoError(t, err)
+			if c.accept != "" {
+				req.Header.Set("Accept-Encoding", c.accept)
+			}
+			rtr.ServeHTTP(rec, req)
+			assert.Equal(t, c.encoding, rec.Header().Get("Content-Encoding"),
+				c.path+" "+c.accept)
+			assert.Equal(t, "Accept-Encoding", rec.Header().Get("Vary"))
+			body := decode(c.encoding, rec.Body)
+			switch c.path {
+			case "/long":
+				assert.Equal(t, long, body)
+			case "/short":
+				assert.Equal(t, "hello", body)
+			}
+			if c.encoding != "" {
+				assert.Less(t, rec.Body.Len(), len(long))
+			}
+			if c.path == "/long" && c.encoding != "" {
+				assert.Equal(t, `W/"v1"`, rec.Header().Get("ETag"))
+			}
+		}
+	}
+
+	//-------------------- Another Test Case --------------------
+
+	rec, req, err := request(http.MethodGet, "/empty", nil)
+	assert.NoError(t, err)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Empty(t, rec.Header().Get("Content-Encoding"))
+
+	//-------------------- Another Test Case --------------------
+
+	rtr = New().Wrap(Compress(&CompressOptions{
+		Encoders: []Encoder{DeflateEncoder(9)},
+		MinSize:  -1,
+		Types:    []string{"text/*"},
+	}))
+	rtr.Get("/", text("hi"))
+	rec, req, err = request(http.MethodGet, "/", nil)
+	assert.NoError(t, err)
+	req.Header.Set("Accept-Encoding", "gzip, deflate")
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, "deflate", rec.Header().Get("Content-Encoding"))
+
+	//-------------------- Another Test Case --------------------
+
+	rtr = New().Wrap(Compress(nil))
+	rtr.Get("/", text(long))
+	rec, req, err = request(http.MethodGet, "/", nil)
+	assert.NoError(t, err)
+	req.Header.Set("Accept-Encoding", "br, zstd, deflate, gzip")
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, "gzip", rec.Header().Get("Content-Encoding"))
+}
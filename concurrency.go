@@ -0,0 +1,115 @@
+package mux
+
+import (
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// ConcurrencyOptions configures ConcurrencyLimit.
+type ConcurrencyOptions struct {
+	// Queue is the number of requests that may wait for a slot when all of
+	// them are taken. Zero means requests are rejected right away.
+	Queue int
+
+	// Timeout is the time a request may wait in the queue. Zero means it
+	// waits until a slot is free or the client goes away.
+	Timeout time.Duration
+
+	// RetryAfter is the value of Retry-After header sent with rejections.
+	// Zero means one second.
+	RetryAfter time.Duration
+}
+
+// ConcurrencyLimit returns Middleware that lets at most n requests be served
+// at the same time, so that a slow backend isn't overloaded by requests piling
+// up. Requests beyond the limit wait in a queue if opts allow it; those that
+// don't fit into the queue or wait too long get "503 Service Unavailable" with
+// Retry-After header through Error. If opts is nil, defaults are used.
+//
+// The limit covers the subtree of the Router it is registered on, see
+// Router.ConcurrencyLimit. It panics if n is not positive.
+func ConcurrencyLimit(n int, opts *ConcurrencyOptions) Middleware {
+	if n <= 0 {
+		panic("concurrency limit must be positive")
+	}
+	cl := &concurrencyLimiter{
+		slots:      make(chan struct{}, n),
+		retryAfter: time.Second,
+	}
+	if opts != nil {
+		cl.queue, cl.timeout = int64(opts.Queue), opts.Timeout
+		if opts.RetryAfter > 0 {
+			cl.retryAfter = opts.RetryAfter
+		}
+	}
+	return func(next http.Handler) http.Handler {
+		return View(func(w http.ResponseWriter, r *http.Request) {
+			if !cl.acquire(r) {
+				w.Header().Set("Retry-After", ceilSeconds(cl.retryAfter))
+				Error(w, r, NewHTTPError(http.StatusServiceUnavailable,
+					"server is busy"))
+				return
+			}
+			defer cl.release()
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ConcurrencyLimit method registers ConcurrencyLimit middleware on the
+// Router, so that at most n requests are served by its subtree at the same
+// time:
+//
+//	rtr.Subrouter().PathPrefix("/reports").ConcurrencyLimit(4,
+//	    &mux.ConcurrencyOptions{Queue: 16, Timeout: 5 * time.Second})
+func (rtr *Router) ConcurrencyLimit(
+	n int, opts *ConcurrencyOptions,
+) *Router {
+	return rtr.Wrap(ConcurrencyLimit(n, opts))
+}
+
+// concurrencyLimiter is a semaphore with a bounded queue.
+type concurrencyLimiter struct {
+	slots      chan struct{}
+	waiting    atomic.Int64
+	queue      int64
+	timeout    time.Duration
+	retryAfter time.Duration
+}
+
+// acquire method takes a slot for the request, waiting in the queue if
+// needed. It returns false if the request is rejected.
+func (cl *concurrencyLimiter) acquire(r *http.Request) bool {
+	select {
+	case cl.slots <- struct{}{}:
+		return true
+	default:
+	}
+
+	if cl.waiting.Add(1) > cl.queue {
+		cl.waiting.Add(-1)
+		return false
+	}
+	defer cl.waiting.Add(-1)
+
+	var expired <-chan time.Time
+	if cl.timeout > 0 {
+		timer := time.NewTimer(cl.timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+	select {
+	case cl.slots <- struct{}{}:
+		return true
+	case <-expired:
+		return false
+	case <-r.Context().Done():
+		return false
+	}
+}
+
+// release method frees the slot taken by acquire.
+func (cl *concurrencyLimiter) release() {
+	<-cl.slots
+}
@@ -0,0 +1,97 @@
+package mux
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConcurrencyLimit(t *testing.T) {
+	started := make(chan struct{}, 10)
+	unblock := make(chan struct{})
+
+	rtr := New()
+	rtr.Get("/fast", func(w http.ResponseWriter, r *http.Request) {})
+	rtr.Subrouter().PathPrefix("/slow").
+		ConcurrencyLimit(1, &ConcurrencyOptions{
+			Queue:      1,
+			Timeout:    time.Minute,
+			RetryAfter: 3 * time.Second,
+		}).
+		Get("/", func(w http.ResponseWriter, r *http.Request) {
+			started <- struct{}{}
+			<-unblock
+		})
+
+	serve := func(path string) *httptest.ResponseRecorder {
+		rec, req, err := request(http.MethodGet, path, nil)
+		assert.NoError(t, err)
+		rtr.ServeHTTP(rec, req)
+		return rec
+	}
+
+	var wg sync.WaitGroup
+	codes := make(chan int, 2)
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			codes <- serve("/slow/").Code
+		}()
+	}
+	<-started
+
+	// One request is served, one is queued, so the third is rejected.
+	assert.Eventually(t, func() bool {
+		rec := serve("/slow/")
+		if rec.Code != http.StatusServiceUnavailable {
+			return false
+		}
+		return rec.Header().Get("Retry-After") == "3"
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, http.StatusOK, serve("/fast").Code)
+
+	close(unblock)
+	wg.Wait()
+	close(codes)
+	for code := range codes {
+		assert.Equal(t, http.StatusOK, code)
+	}
+
+	//-------------------- Another Test Case --------------------
+
+	assert.Panics(t, func() { ConcurrencyLimit(0, nil) })
+}
+
+func TestConcurrencyTimeout(t *testing.T) {
+	unblock := make(chan struct{})
+	defer close(unblock)
+
+	rtr := New().ConcurrencyLimit(1, &ConcurrencyOptions{
+		Queue:   1,
+		Timeout: 10 * time.Millisecond,
+	})
+	rtr.Get("/", func(w http.ResponseWriter, r *http.Request) {
+		<-unblock
+	})
+
+	started := make(chan struct{})
+	go func() {
+		rec, req, _ := request(http.MethodGet, "/", nil)
+		close(started)
+		rtr.ServeHTTP(rec, req)
+	}()
+	<-started
+
+	assert.Eventually(t, func() bool {
+		rec, req, err := request(http.MethodGet, "/", nil)
+		assert.NoError(t, err)
+		rtr.ServeHTTP(rec, req)
+		return rec.Code == http.StatusServiceUnavailable &&
+			rec.Header().Get("Retry-After") == "1"
+	}, time.Second, time.Millisecond)
+}
@@ -0,0 +1,161 @@
+package mux
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config is a declarative description of a routing tree.
+type Config struct {
+	// Middleware are the names of the root Router's middleware handlers.
+	Middleware []string `json:"middleware,omitempty" yaml:"middleware"`
+
+	// Routes are the sub-routers of the root Router.
+	Routes []RouteConfig `json:"routes" yaml:"routes"`
+}
+
+// RouteConfig is a declarative description of a single route. Handlers are
+// referred to by their names, which are resolved when the tree is built.
+type RouteConfig struct {
+	// Name is the name of the route (see Router.Name).
+	Name string `json:"name,omitempty" yaml:"name"`
+
+	// Methods is the list of accepted methods; empty means any.
+	Methods []string `json:"methods,omitempty" yaml:"methods"`
+
+	// Path is the path template (e.g. "/users/{id:int}").
+	Path string `json:"path,omitempty" yaml:"path"`
+
+	// Prefix is the path prefix cut from the path of matching requests
+	// before they are passed to Routes. It can't be set along with Path.
+	Prefix string `json:"prefix,omitempty" yaml:"prefix"`
+
+	// Middleware are the names of the route's middleware handlers (see
+	// Router.Use).
+	Middleware []string `json:"middleware,omitempty" yaml:"middleware"`
+
+	// Handler is the name of the route's handler.
+	Handler string `json:"handler,omitempty" yaml:"handler"`
+
+	// Routes are the sub-routers of the route.
+	Routes []RouteConfig `json:"routes,omitempty" yaml:"routes"`
+}
+
+// FromConfig constructs a new Router from a YAML or JSON document describing
+// the routing tree (see Config). Handlers and middleware are referred to by
+// names, which are resolved with the map:
+//
+//	middleware: [logger]
+//	routes:
+//	  - prefix: /api
+//	    middleware: [auth]
+//	    routes:
+//	      - {methods: [GET], path: /users, handler: listUsers}
+//	      - {methods: [GET], path: "/users/{id:int}", handler: showUser}
+//	  - {prefix: /static, handler: assets}
+//
+// Unlike the builder methods, it does not panic: malformed documents, unknown
+// fields and names, and invalid path templates are reported as errors.
+func FromConfig(
+	r io.Reader, handlers map[string]http.Handler,
+) (*Router, error) {
+	conf, err := parseConfig(r)
+	if err != nil {
+		return nil, err
+	}
+	return conf.Build(handlers)
+}
+
+// parseConfig decodes Config from YAML or JSON, which is a subset of YAML.
+func parseConfig(r io.Reader) (*Config, error) {
+	var conf Config
+	dec := yaml.NewDecoder(r)
+	dec.KnownFields(true)
+	if err := dec.Decode(&conf); err != nil {
+		return nil, fmt.Errorf("config: %v", err)
+	}
+	return &conf, nil
+}
+
+// Build method constructs a new Router from the configuration, resolving
+// handler and middleware names with the map. Unlike the builder methods, it
+// does not panic: unknown names and invalid path templates are reported as
+// errors.
+func (conf *Config) Build(handlers map[string]http.Handler) (*Router, error) {
+	rtr := New()
+	if err := use(rtr, conf.Middleware, handlers); err != nil {
+		return nil, fmt.Errorf("config: %v", err)
+	}
+	for i := range conf.Routes {
+		if err := conf.Routes[i].build(rtr, handlers); err != nil {
+			return nil, err
+		}
+	}
+	return rtr, nil
+}
+
+// build method adds the route described by the configuration to the parent.
+func (conf *RouteConfig) build(
+	parent *Router, handlers map[string]http.Handler,
+) error {
+	fail := func(format string, v ...interface{}) error {
+		route := conf.Path
+		if conf.Name != "" {
+			route = conf.Name
+		}
+		return fmt.Errorf(
+			"config: route %s: %s", route, fmt.Sprintf(format, v...),
+		)
+	}
+
+	if conf.Path != "" && conf.Prefix != "" {
+		return fail("path and prefix are mutually exclusive")
+	}
+
+	rtr := parent.detached().Name(conf.Name)
+	if len(conf.Methods) > 0 {
+		rtr.Methods(conf.Methods...)
+	}
+	if conf.Path != "" {
+		fil, err := compilePathFilter(conf.Path, rtr.strictPath)
+		if err != nil {
+			return fail("%v", err)
+		}
+		rtr.filters.Path = fil
+	}
+	if conf.Prefix != "" {
+		rtr.PathPrefix(conf.Prefix)
+	}
+	if err := use(rtr, conf.Middleware, handlers); err != nil {
+		return fail("%v", err)
+	}
+	if conf.Handler != "" {
+		h, ok := handlers[conf.Handler]
+		if !ok {
+			return fail("unknown handler %q", conf.Handler)
+		}
+		rtr.Handler(h)
+	}
+	for i := range conf.Routes {
+		if err := conf.Routes[i].build(rtr, handlers); err != nil {
+			return err
+		}
+	}
+	parent.attach(rtr)
+	return nil
+}
+
+// use registers named middleware handlers on the router.
+func use(rtr *Router, names []string, handlers map[string]http.Handler) error {
+	for _, name := range names {
+		h, ok := handlers[name]
+		if !ok {
+			return fmt.Errorf("unknown middleware %q", name)
+		}
+		rtr.Use(h)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package mux
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromConfig(t *testing.T) {
+	var log []string
+	logger := func(name string) http.Handler {
+		return View(func(w http.ResponseWriter, r *http.Request) {
+			log = append(log, name)
+		})
+	}
+	text := func(s string) http.Handler {
+		return View(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(s))
+		})
+	}
+	handlers := map[string]http.Handler{
+		"logger":    logger("logger"),
+		"auth":      logger("auth"),
+		"listUsers": text("users"),
+		"showUser":  text("user"),
+		"assets":    text("assets"),
+	}
+
+	rtr, err := FromConfig(strings.NewReader(`
+middleware: [logger]
+routes:
+  - prefix: /api
+    middleware: [auth]
+    routes:
+      - {methods: [GET], path: /users, handler: listUsers}
+      - {methods: [GET], path: "/users/{id:int}", handler: showUser}
+  - {name: static, prefix: /static, handler: assets}
+`), handlers)
+	assert.NoError(t, err)
+
+	cases := []struct {
+		method string
+		path   string
+		code   int
+		body   string
+		log    []string
+	}{
+		{http.MethodGet, "/api/users", http.StatusOK, "users",
+			[]string{"logger", "auth"}},
+		{http.MethodGet, "/api/users/42", http.StatusOK, "user",
+			[]string{"logger", "auth"}},
+		{http.MethodPost, "/api/users", http.StatusMethodNotAllowed, "",
+			[]string{"logger", "auth"}},
+		{http.MethodGet, "/static/app.js", http.StatusOK, "assets",
+			[]string{"logger"}},
+	}
+	for _, c := range cases {
+		log = nil
+		rec, req, err := request(c.method, c.path, nil)
+		assert.NoError(t, err)
+		rtr.ServeHTTP(rec, req)
+		assert.Equal(t, c.code, rec.Code, c.path)
+		if c.body != "" {
+			assert.Equal(t, c.body, rec.Body.String(), c.path)
+		}
+		assert.Equal(t, c.log, log, c.path)
+	}
+
+	// JSON is accepted as well.
+	rtr, err = FromConfig(strings.NewReader(
+		`{"routes": [{"path": "/users", "handler": "listUsers"}]}`,
+	), handlers)
+	assert.NoError(t, err)
+	rec, req, err := request(http.MethodGet, "/users", nil)
+	assert.NoError(t, err)
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, "users", rec.Body.String())
+}
+
+//-------------------- Another Test Case --------------------
+
+func TestFromConfigErrors(t *testing.T) {
+	cases := map[string]string{
+		`{"routes": [{"path": "/a/{x:*}/b"}]}`:        "catch-all variable",
+		`{"routes": [{"path": "/a", "unknown": 1}]}`:  "field unknown not found",
+		`{"routes": [{"name": "u", "handler": "x"}]}`: `route u: unknown handler "x"`,
+		`{"middleware": ["x"]}`:                       `unknown middleware "x"`,
+		"routes: [":                                   "config: yaml",
+	}
+	for doc, msg := range cases {
+		_, err := FromConfig(strings.NewReader(doc), nil)
+		if assert.Error(t, err, doc) {
+			assert.Contains(t, err.Error(), msg, doc)
+		}
+	}
+	//-------------------- Another Test Case --------------------
+	_, err := FromConfig(strings.NewReader(
+		`{"routes": [{"path": "/a", "prefix": "/a"}]}`), nil)
+	assert.EqualError(t, err,
+		"config: route /a: path and prefix are mutually exclusive")
+}
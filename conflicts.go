@@ -0,0 +1,193 @@
+package mux
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// Conflict describes a route that can never be matched because a sibling
+// route that is checked before it accepts every request it accepts.
+type Conflict struct {
+	// Route is the unreachable route.
+	Route *RouteInfo
+
+	// By is the route that shadows it.
+	By *RouteInfo
+
+	// Duplicate is true if both routes have identical methods and paths.
+	Duplicate bool
+}
+
+// String method describes the conflict in a human-readable way.
+func (c *Conflict) String() string {
+	how := "shadowed by"
+	if c.Duplicate {
+		how = "duplicate of"
+	}
+	return fmt.Sprintf("%s is %s %s", describe(c.Route), how, describe(c.By))
+}
+
+// ConflictError is returned by Validate when some routes are unreachable.
+type ConflictError struct {
+	Conflicts []*Conflict
+}
+
+// Error method ensures that ConflictError implements the error interface.
+func (e *ConflictError) Error() string {
+	msgs := make([]string, len(e.Conflicts))
+	for i, c := range e.Conflicts {
+		msgs[i] = c.String()
+	}
+	return "route conflicts:\n\t" + strings.Join(msgs, "\n\t")
+}
+
+// Validate method checks the routing tree for routes that can never be
+// matched: duplicates (e.g. two siblings with identical methods and paths) and
+// routes shadowed by siblings that are checked before them (e.g. "/users/new"
+// registered after "/users/{name:str}", or anything under "/static" after
+// PathPrefix("/static")). Routes are considered in the order they are matched
+// in, so conflicts resolved with Priority or SortBySpecificity are not
+// reported.
+//
+// The check is conservative: it only reports routes that are unreachable for
+// sure, so routes with custom filters never shadow others. The error lists
+// the locations where conflicting routes were registered:
+//
+//	if err := rtr.Validate(); err != nil {
+//	    log.Fatal(err)
+//	}
+//
+// It returns *ConflictError if there are conflicts and nil otherwise.
+func (rtr *Router) Validate() error {
+	var conflicts []*Conflict
+	rtr.validateRoutes(nil, &conflicts)
+	if len(conflicts) > 0 {
+		return &ConflictError{conflicts}
+	}
+	return nil
+}
+
+// validateRoutes method collects conflicts among routes of this Router and of
+// its sub-routers recursively.
+func (rtr *Router) validateRoutes(prefixes []string, conflicts *[]*Conflict) {
+	info := rtr.info(prefixes, 0)
+	routes := rtr.ordered()
+	for j, b := range routes {
+		for _, a := range routes[:j] {
+			if !a.shadows(b) {
+				continue
+			}
+			*conflicts = append(*conflicts, &Conflict{
+				Route:     b.info(info.Prefixes, 0),
+				By:        a.info(info.Prefixes, 0),
+				Duplicate: a.duplicates(b),
+			})
+			break
+		}
+		b.validateRoutes(info.Prefixes, conflicts)
+	}
+}
+
+// shadows method tells whether this Router accepts every request that the
+// other one accepts.
+func (rtr *Router) shadows(other *Router) bool {
+	a, b := rtr.filters, other.filters
+	if !rtr.Enabled() || rtr.group && rtr.handler == nil || len(a.Custom) > 0 {
+		return false
+	}
+	if a.Schemes != nil && !reflect.DeepEqual(a.Schemes, b.Schemes) ||
+		a.Host != nil && !reflect.DeepEqual(a.Host, b.Host) ||
+		a.UserAgent != nil && !reflect.DeepEqual(a.UserAgent, b.UserAgent) ||
+		a.ClientCert != nil && !reflect.DeepEqual(a.ClientCert, b.ClientCert) {
+		return false
+	}
+	if a.Methods != nil {
+		if b.Methods == nil {
+			return false
+		}
+		for _, m := range b.Methods.Methods.Items() {
+			if !a.Methods.Methods.Has(m) {
+				return false
+			}
+		}
+	}
+	if a.PathPrefix != nil {
+		prefix := other.literalPrefix()
+		if other.filters.Path != nil && !other.filters.Path.hasVars &&
+			other.filters.Path.Strict {
+			prefix = other.filters.Path.Path
+		}
+		if !strings.HasPrefix(prefix, string(*a.PathPrefix)) {
+			return false
+		}
+	}
+	return a.Path == nil || a.Path.covers(b.Path)
+}
+
+// duplicates method tells whether this Router has the same methods and path
+// as the other one.
+func (rtr *Router) duplicates(other *Router) bool {
+	a, b := rtr.info(nil, 0), other.info(nil, 0)
+	return reflect.DeepEqual(a.Methods, b.Methods) && a.Path == b.Path &&
+		reflect.DeepEqual(a.Prefixes, b.Prefixes)
+}
+
+// covers method tells whether the filter accepts every path that the other
+// filter accepts. Only identical templates and static paths are compared.
+func (fil *PathFilter) covers(other *PathFilter) bool {
+	if other == nil {
+		return false
+	}
+	if fil.Path == other.Path && fil.Regexp.String() == other.Regexp.String() {
+		return true
+	}
+	if other.hasVars || !other.Strict {
+		return false
+	}
+	return fil.Match(&http.Request{URL: &url.URL{Path: other.Path}})
+}
+
+// describe returns short description of the route for error messages.
+func describe(route *RouteInfo) string {
+	methods := "*"
+	if route.Methods != nil {
+		methods = strings.Join(route.Methods, ",")
+	}
+	desc := methods + " " + route.Template()
+	if route.Template() == "" {
+		desc = methods + " /*"
+	}
+	if route.Source != "" {
+		desc += " (" + route.Source + ")"
+	}
+	return desc
+}
+
+// packageDir is the directory with source files of this package. Frames from
+// those files are skipped when looking for the caller, except for tests.
+var packageDir = func() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Dir(file)
+}()
+
+// caller returns location (file:line) of the first caller outside of this
+// package.
+func caller() string {
+	pcs := make([]uintptr, 16)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+	for {
+		frame, more := frames.Next()
+		if filepath.Dir(frame.File) != packageDir ||
+			strings.HasSuffix(frame.File, "_test.go") {
+			return fmt.Sprintf("%s:%d", filepath.Base(frame.File), frame.Line)
+		}
+		if !more {
+			return ""
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package mux
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+
+	rtr := New()
+	rtr.Get("/users", noop)
+	rtr.Get("/users/{name:str}", noop)
+	rtr.Post("/users", noop)
+	rtr.Get("/users/{id:int}", noop)
+	rtr.Get("/users/new", noop) // Shadowed by "/users/{name:str}".
+	rtr.Get("/users", noop)     // Duplicate.
+	rtr.Subrouter().PathPrefix("/static").HandleFunc(noop)
+	rtr.Get("/static/app.js", noop) // Shadowed by the prefix.
+	rtr.Get("/news/{id:int}", noop)
+	rtr.Subrouter().Methods(http.MethodGet, http.MethodPost).
+		Path("/news/{id:int}").HandleFunc(noop)
+	rtr.Get("/news/42", noop).Priority(1)
+	rtr.Group(func(r *Router) {
+		r.Get("/about", noop)
+	})
+	rtr.Get("/about", noop)
+
+	err := rtr.Validate()
+	var conflicts *ConflictError
+	assert.True(t, errors.As(err, &conflicts))
+	assert.Len(t, conflicts.Conflicts, 3)
+
+	c := conflicts.Conflicts[0]
+	assert.Equal(t, "/users/new", c.Route.Template())
+	assert.Equal(t, "/users/{name:str}", c.By.Template())
+	assert.False(t, c.Duplicate)
+	assert.Regexp(t, `^GET /users/new \(conflicts_test\.go:\d+\) is shadowed by `+
+		`GET /users/\{name:str\} \(conflicts_test\.go:\d+\)$`, c.String())
+
+	c = conflicts.Conflicts[1]
+	assert.Equal(t, "/users", c.Route.Template())
+	assert.True(t, c.Duplicate)
+
+	c = conflicts.Conflicts[2]
+	assert.Equal(t, "/static/app.js", c.Route.Template())
+	assert.Equal(t, "/static", c.By.Template())
+
+	// Nested routers are validated as well.
+	rtr = New()
+	rtr.Route("/api", func(r *Router) {
+		r.Get("/{rest:*}", noop)
+		r.Get("/users", noop)
+	})
+	err = rtr.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "GET /api/users")
+
+	rtr = New().SortBySpecificity(true)
+	rtr.Get("/users/{name:str}", noop)
+	rtr.Get("/users/new", noop)
+	assert.NoError(t, rtr.Validate())
+}
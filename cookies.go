@@ -0,0 +1,171 @@
+package mux
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// maxCookieSize is the size of the largest cookie browsers are required to
+// accept.
+const maxCookieSize = 4096
+
+// ErrInvalidCookie is returned by Cookies when the value of a cookie is
+// malformed, was tampered with or was made with an unknown key.
+var ErrInvalidCookie = errors.New("mux: invalid cookie")
+
+// Cookies signs cookie values with HMAC-SHA256, so that clients can't forge
+// them, and optionally encrypts them with AES-GCM, so that clients can't read
+// them either. Create it with SecureCookies.
+//
+// Cookies are always made with the first key, while any of the keys is
+// accepted. Keys are rotated by putting a new key first and dropping the old
+// one once cookies made with it have expired:
+//
+//	cookies := mux.SecureCookies(newKey, oldKey)
+type Cookies struct {
+	keys    []cookieKey
+	encrypt bool
+}
+
+// cookieKey is a set of keys derived from a secret given to SecureCookies.
+type cookieKey struct {
+	mac  []byte
+	aead cipher.AEAD
+}
+
+// SecureCookies returns pointer to Cookies that sign values with the keys.
+// Keys should be at least 32 random bytes long. It panics if no keys are
+// given.
+func SecureCookies(keys ...[]byte) *Cookies {
+	if len(keys) == 0 {
+		panic("can't secure cookies without keys")
+	}
+	c := &Cookies{make([]cookieKey, len(keys)), false}
+	for i, secret := range keys {
+		block, _ := aes.NewCipher(deriveKey(secret, "mux cookie encryption"))
+		aead, _ := cipher.NewGCM(block)
+		c.keys[i] = cookieKey{deriveKey(secret, "mux cookie signing"), aead}
+	}
+	return c
+}
+
+// Encrypted method returns pointer to a copy of Cookies that also encrypt
+// the values.
+func (c *Cookies) Encrypted() *Cookies {
+	return &Cookies{c.keys, true}
+}
+
+// Set method sets the cookie with the value signed (and encrypted, if
+// needed). The cookie given is not modified. An error is returned if the
+// cookie ends up larger than 4KB, which browsers may reject.
+func (c *Cookies) Set(w http.ResponseWriter, cookie *http.Cookie) error {
+	signed := *cookie
+	signed.Value = c.Encode(cookie.Name, []byte(cookie.Value))
+	if len(signed.String()) > maxCookieSize {
+		return fmt.Errorf("mux: cookie %s is too large", cookie.Name)
+	}
+	http.SetCookie(w, &signed)
+	return nil
+}
+
+// Get method returns the value of the cookie set by Set. It returns
+// http.ErrNoCookie if the request has no such cookie and ErrInvalidCookie if
+// its value can't be trusted.
+func (c *Cookies) Get(r *http.Request, name string) (string, error) {
+	cookie, err := r.Cookie(name)
+	if err != nil {
+		return "", err
+	}
+	value, err := c.Decode(name, cookie.Value)
+	return string(value), err
+}
+
+// Encode method returns the value signed (and encrypted, if needed) to be
+// stored in the cookie with the name. The name is signed together with the
+// value, so that values can't be moved from one cookie to another.
+func (c *Cookies) Encode(name string, value []byte) string {
+	key := c.keys[0]
+	if c.encrypt {
+		size := key.aead.NonceSize()
+		nonce := make([]byte, size, size+len(value)+key.aead.Overhead())
+		if _, err := rand.Read(nonce); err != nil {
+			panic(err)
+		}
+		sealed := key.aead.Seal(nonce, nonce, value, []byte(name))
+		return base64.RawURLEncoding.EncodeToString(sealed)
+	}
+	payload := base64.RawURLEncoding.EncodeToString(value)
+	return payload + "." + base64.RawURLEncoding.EncodeToString(
+		key.sign(name, payload),
+	)
+}
+
+// Decode method returns the value encoded by Encode for the cookie with the
+// name. It returns ErrInvalidCookie if the value can't be trusted.
+func (c *Cookies) Decode(name, value string) ([]byte, error) {
+	if c.encrypt {
+		return c.open(name, value)
+	}
+	payload, sig, ok := strings.Cut(value, ".")
+	if !ok {
+		return nil, ErrInvalidCookie
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil {
+		return nil, ErrInvalidCookie
+	}
+	for _, key := range c.keys {
+		if hmac.Equal(mac, key.sign(name, payload)) {
+			b, err := base64.RawURLEncoding.DecodeString(payload)
+			if err != nil {
+				return nil, ErrInvalidCookie
+			}
+			return b, nil
+		}
+	}
+	return nil, ErrInvalidCookie
+}
+
+// open method decrypts the value encrypted by Encode.
+func (c *Cookies) open(name, value string) ([]byte, error) {
+	sealed, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, ErrInvalidCookie
+	}
+	for _, key := range c.keys {
+		n := key.aead.NonceSize()
+		if len(sealed) < n {
+			break
+		}
+		plain, err := key.aead.Open(nil, sealed[:n], sealed[n:], []byte(name))
+		if err == nil {
+			return plain, nil
+		}
+	}
+	return nil, ErrInvalidCookie
+}
+
+// sign method returns HMAC of the payload of the cookie with the name.
+func (key cookieKey) sign(name, payload string) []byte {
+	mac := hmac.New(sha256.New, key.mac)
+	mac.Write([]byte(name))
+	mac.Write([]byte{0})
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
+
+// deriveKey derives a 32 bytes long key for the purpose from the secret, so
+// that the same secret can be used for signing and encryption.
+func deriveKey(secret []byte, purpose string) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(purpose))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,69 @@
+package mux
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSecureCookies(t *testing.T) {
+	oldKey, newKey := []byte("old key"), []byte("new key")
+
+	for _, encrypted := range []bool{false, true} {
+		cookies := SecureCookies(oldKey)
+		rotated := SecureCookies(newKey, oldKey)
+		other := SecureCookies(newKey)
+		if encrypted {
+			cookies, rotated = cookies.Encrypted(), rotated.Encrypted()
+			other = other.Encrypted()
+		}
+
+		rec := httptest.NewRecorder()
+		err := cookies.Set(rec, &http.Cookie{Name: "user", Value: "ann"})
+		assert.NoError(t, err)
+		set := rec.Result().Cookies()[0]
+		assert.NotEqual(t, "ann", set.Value)
+		assert.Equal(t, !encrypted, strings.HasPrefix(set.Value, "YW5u."))
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.AddCookie(set)
+		value, err := cookies.Get(req, "user")
+		assert.NoError(t, err)
+		assert.Equal(t, "ann", value)
+
+		// Cookies made with the old key are accepted after rotation.
+		value, err = rotated.Get(req, "user")
+		assert.NoError(t, err)
+		assert.Equal(t, "ann", value)
+		_, err = other.Get(req, "user")
+		assert.Equal(t, ErrInvalidCookie, err)
+
+		// Values can't be moved to other cookies or tampered with.
+		_, err = cookies.Decode("admin", set.Value)
+		assert.Equal(t, ErrInvalidCookie, err)
+		tampered := []byte(set.Value)
+		tampered[len(tampered)/2] ^= 1
+		_, err = cookies.Decode("user", string(tampered))
+		assert.Equal(t, ErrInvalidCookie, err)
+
+		_, err = cookies.Get(req, "missing")
+		assert.Equal(t, http.ErrNoCookie, err)
+	}
+}
+
+func TestSecureCookiesSize(t *testing.T) {
+	rec := httptest.NewRecorder()
+	err := SecureCookies([]byte("key")).Set(rec, &http.Cookie{
+		Name:  "big",
+		Value: strings.Repeat("a", maxCookieSize),
+	})
+	assert.EqualError(t, err, "mux: cookie big is too large")
+	assert.Empty(t, rec.Result().Cookies())
+
+	assert.PanicsWithValue(t, "can't secure cookies without keys", func() {
+		SecureCookies()
+	})
+}
@@ -0,0 +1,97 @@
+package mux
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// debugRoute is a JSON-friendly description of a routing tree node used by
+// the DebugHandler.
+type debugRoute struct {
+	Name       string        `json:"name,omitempty"`
+	Template   string        `json:"template,omitempty"`
+	Filters    debugFilters  `json:"filters"`
+	Handler    string        `json:"handler,omitempty"`
+	Middleware int           `json:"middleware"`
+	Disabled   bool          `json:"disabled,omitempty"`
+	Routes     []*debugRoute `json:"routes,omitempty"`
+}
+
+// debugFilters is a JSON-friendly description of router's Filters.
+type debugFilters struct {
+	Schemes    []string `json:"schemes,omitempty"`
+	Hosts      []string `json:"hosts,omitempty"`
+	Methods    []string `json:"methods,omitempty"`
+	Path       string   `json:"path,omitempty"`
+	PathRegexp string   `json:"pathRegexp,omitempty"`
+	PathPrefix string   `json:"pathPrefix,omitempty"`
+	UserAgent  []string `json:"userAgent,omitempty"`
+	ClientCert []string `json:"clientCert,omitempty"`
+	Custom     int      `json:"custom,omitempty"`
+}
+
+// DebugHandler method returns an http.Handler that responds with the routing
+// tree of this Router encoded as JSON. It is meant to be mounted in
+// development only, e.g.
+//
+//	rtr.Get("/debug/routes", rtr.DebugHandler().ServeHTTP)
+//
+// The tree is inspected upon every request, so routes added later are shown.
+func (rtr *Router) DebugHandler() http.Handler {
+	return View(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		enc.Encode(rtr.debug(nil))
+	})
+}
+
+// debug method recursively builds debugRoute for this Router given the
+// prefixes of its parents.
+func (rtr *Router) debug(prefixes []string) *debugRoute {
+	info := rtr.info(prefixes, 0)
+	fils := rtr.filters
+	route := &debugRoute{
+		Name:       info.Name,
+		Template:   info.Template(),
+		Middleware: len(rtr.middleware) + len(rtr.wrappers),
+		Disabled:   info.Disabled,
+	}
+	if rtr.handler != nil {
+		route.Handler = fmt.Sprintf("%T", rtr.handler)
+	}
+	if fils.Schemes != nil {
+		route.Filters.Schemes = fils.Schemes.Schemes.Items()
+	}
+	route.Filters.Hosts = info.Hosts
+	route.Filters.Methods = info.Methods
+	if fils.Path != nil {
+		route.Filters.Path = fils.Path.Path
+		route.Filters.PathRegexp = fils.Path.Regexp.String()
+	}
+	if fils.PathPrefix != nil {
+		route.Filters.PathPrefix = string(*fils.PathPrefix)
+	}
+	if fils.UserAgent != nil {
+		for _, regex := range fils.UserAgent.Patterns {
+			route.Filters.UserAgent = append(
+				route.Filters.UserAgent, regex.String(),
+			)
+		}
+	}
+	if fils.ClientCert != nil {
+		route.Filters.ClientCert = []string{}
+		for _, regex := range fils.ClientCert.Patterns {
+			route.Filters.ClientCert = append(
+				route.Filters.ClientCert, regex.String(),
+			)
+		}
+	}
+	route.Filters.Custom = len(fils.Custom)
+	// List the routes in the order they are matched, like Walk does.
+	for _, sub := range rtr.ordered() {
+		route.Routes = append(route.Routes, sub.debug(info.Prefixes))
+	}
+	return route
+}
@@ -0,0 +1,73 @@
+// Use of this source code is governed by the Mozilla Public License Version 2.0
+// that can be found in the LICENSE file.
+
+/*
+Package debug serves the profiles of net/http/pprof and the variables of
+expvar from mux routing trees:
+
+	admin := rtr.Subrouter().Wrap(mux.BasicAuth("debug", check))
+	admin.Mount("/debug/pprof", debug.Pprof())
+	admin.Get("/debug/vars", debug.Expvar().ServeHTTP)
+
+Profiles and variables reveal a lot about the server, so keep them away from
+the public. Note that importing this package registers the handlers of
+net/http/pprof and expvar on http.DefaultServeMux as well, which should not be
+exposed either. That is why they live apart from package mux.
+*/
+package debug
+
+import (
+	"expvar"
+	"net/http"
+	"net/http/pprof"
+	"strings"
+
+	"github.com/sharpvik/mux"
+)
+
+// Pprof returns http.Handler that serves the handlers of net/http/pprof. It
+// is meant to be mounted with mux.Router.Mount, e.g. at "/debug/pprof", and
+// lists the profiles at the mount point itself.
+//
+// The handlers of net/http/pprof expect to be served under "/debug/pprof/",
+// so Pprof adjusts the path for them.
+func Pprof() http.Handler {
+	return mux.View(servePprof)
+}
+
+// Expvar returns http.Handler that serves the variables published with expvar
+// as JSON.
+func Expvar() http.Handler {
+	return expvar.Handler()
+}
+
+// servePprof dispatches requests under the mount point of Pprof to the
+// handlers of net/http/pprof.
+func servePprof(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/cmdline":
+		pprof.Cmdline(w, r)
+	case "/profile":
+		pprof.Profile(w, r)
+	case "/symbol":
+		pprof.Symbol(w, r)
+	case "/trace":
+		pprof.Trace(w, r)
+	case "/":
+		// The index links to the profiles with relative URLs, which only
+		// work if the index is served under a path with trailing slash.
+		if path := mux.OriginalPath(r); !strings.HasSuffix(path, "/") {
+			http.Redirect(w, r, path+"/", http.StatusMovedPermanently)
+			return
+		}
+		fallthrough
+	default:
+		// Index serves the named profiles as well. It finds their names by
+		// cutting "/debug/pprof/" from the path.
+		u := *r.URL
+		u.Path, u.RawPath = "/debug/pprof"+r.URL.Path, ""
+		r2 := r.WithContext(r.Context())
+		r2.URL = &u
+		pprof.Index(w, r2)
+	}
+}
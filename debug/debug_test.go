@@ -0,0 +1,39 @@
+package debug
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sharpvik/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDebug(t *testing.T) {
+	rtr := mux.New()
+	admin := rtr.Subrouter().PathPrefix("/admin")
+	admin.Mount("/pprof", Pprof())
+	admin.Get("/vars", Expvar().ServeHTTP)
+
+	cases := []struct {
+		path     string
+		code     int
+		contains string
+	}{
+		{"/admin/pprof", http.StatusMovedPermanently, ""},
+		{"/admin/pprof/", http.StatusOK, "Types of profiles available"},
+		{"/admin/pprof/goroutine?debug=1", http.StatusOK, "goroutine profile"},
+		{"/admin/pprof/cmdline", http.StatusOK, ""},
+		{"/admin/pprof/nope", http.StatusNotFound, "Unknown profile"},
+		{"/admin/vars", http.StatusOK, `"memstats"`},
+	}
+	for _, c := range cases {
+		rec := httptest.NewRecorder()
+		rtr.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, c.path, nil))
+		assert.Equal(t, c.code, rec.Code, c.path)
+		assert.Contains(t, rec.Body.String(), c.contains, c.path)
+		if c.code == http.StatusMovedPermanently {
+			assert.Equal(t, "/admin/pprof/", rec.Header().Get("Location"))
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package mux
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDebugHandler(t *testing.T) {
+	rtr := New()
+	api := rtr.Subrouter().PathPrefix("/api").Name("api").
+		UseFunc(func(w http.ResponseWriter, r *http.Request) {})
+	api.Get("/users/{id:int}", func(w http.ResponseWriter, r *http.Request) {})
+	rtr.Subrouter().Path("/debug/routes").Handler(rtr.DebugHandler())
+
+	rec, req, err := request(http.MethodGet, "/debug/routes", nil)
+	assert.NoError(t, err)
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	var tree debugRoute
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &tree))
+	assert.Len(t, tree.Routes, 2)
+	assert.Equal(t, "api", tree.Routes[0].Name)
+	assert.Equal(t, 1, tree.Routes[0].Middleware)
+	user := tree.Routes[0].Routes[0]
+	assert.Equal(t, "/api/users/{id:int}", user.Template)
+	assert.Equal(t, []string{"GET"}, user.Filters.Methods)
+	assert.Equal(t, "mux.View", user.Handler)
+	//-------------------- Another Test Case --------------------
+	rtr = New()
+	rtr.Get("/{page:segment}", func(w http.ResponseWriter, r *http.Request) {})
+	rtr.Get("/about", func(w http.ResponseWriter, r *http.Request) {}).
+		Priority(1)
+	rec, req, err = request(http.MethodGet, "/", nil)
+	assert.NoError(t, err)
+	rtr.DebugHandler().ServeHTTP(rec, req)
+	tree = debugRoute{}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &tree))
+	if assert.Len(t, tree.Routes, 2) {
+		assert.Equal(t, "/about", tree.Routes[0].Template)
+		assert.Equal(t, "/{page:segment}", tree.Routes[1].Template)
+	}
+}
@@ -0,0 +1,114 @@
+package mux
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DeprecationOptions describes the retirement of deprecated endpoints.
+type DeprecationOptions struct {
+	// Since is when the endpoints were deprecated. Zero means that they are
+	// deprecated without a known date.
+	Since time.Time
+
+	// Sunset is when the endpoints stop working (see RFC 8594). Zero means
+	// that the date is unknown.
+	Sunset time.Time
+
+	// Link is the URL of a document that describes the deprecation, e.g. a
+	// migration guide.
+	Link string
+
+	// SunsetLink is the URL of a document that describes the sunset policy.
+	SunsetLink string
+
+	// Successor is the URL of the endpoints that replace the deprecated ones,
+	// e.g. "/v2/users".
+	Successor string
+
+	// Gone makes the endpoints respond with "410 Gone" through Error once the
+	// sunset date has passed.
+	Gone bool
+}
+
+// Deprecation returns Middleware that marks responses as deprecated with the
+// Deprecation header (see RFC 9745), as well as Sunset and Link headers if the
+// options have them:
+//
+//	v1.Deprecated(&mux.DeprecationOptions{
+//	    Since:     time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+//	    Sunset:    time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+//	    Successor: "/v2",
+//	})
+//	v1.Get("/users", listUsers).Deprecated(&mux.DeprecationOptions{
+//	    Sunset: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC),
+//	})
+//
+// Middleware of nested routers runs later, so the options of a route replace
+// those of its subtree. If opts is nil, defaults are used.
+func Deprecation(opts *DeprecationOptions) Middleware {
+	if opts == nil {
+		opts = &DeprecationOptions{}
+	}
+	deprecation := "true"
+	if !opts.Since.IsZero() {
+		deprecation = "@" + strconv.FormatInt(opts.Since.Unix(), 10)
+	}
+	var sunset string
+	if !opts.Sunset.IsZero() {
+		sunset = opts.Sunset.UTC().Format(http.TimeFormat)
+	}
+	var links []string
+	for _, l := range []struct{ url, rel string }{
+		{opts.Link, "deprecation"},
+		{opts.SunsetLink, "sunset"},
+		{opts.Successor, "successor-version"},
+	} {
+		if l.url != "" {
+			links = append(links, "<"+l.url+`>; rel="`+l.rel+`"`)
+		}
+	}
+
+	return func(next http.Handler) http.Handler {
+		return View(func(w http.ResponseWriter, r *http.Request) {
+			h := w.Header()
+			h.Set("Deprecation", deprecation)
+			h.Del("Sunset")
+			if sunset != "" {
+				h.Set("Sunset", sunset)
+			}
+			h["Link"] = append(withoutDeprecationLinks(h["Link"]), links...)
+			if len(h["Link"]) == 0 {
+				h.Del("Link")
+			}
+			if opts.Gone && sunset != "" && !time.Now().Before(opts.Sunset) {
+				Error(w, r, NewHTTPError(http.StatusGone,
+					"endpoint was retired on %s", sunset))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Deprecated method registers Deprecation middleware on the Router. See
+// Deprecation.
+func (rtr *Router) Deprecated(opts *DeprecationOptions) *Router {
+	return rtr.Wrap(Deprecation(opts))
+}
+
+// withoutDeprecationLinks returns the values of Link header without the links
+// set by Deprecation, so that nested Deprecation middleware replaces them.
+func withoutDeprecationLinks(links []string) []string {
+	var kept []string
+	for _, l := range links {
+		if !strings.Contains(l, `rel="deprecation"`) &&
+			!strings.Contains(l, `rel="sunset"`) &&
+			!strings.Contains(l, `rel="successor-version"`) {
+			kept = append(kept, l)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,62 @@
+package mux
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeprecation(t *testing.T) {
+	since := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	sunset := time.Now().Add(24 * time.Hour)
+	ok := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}
+	rtr := New()
+	v1 := rtr.Subrouter().PathPrefix("/v1").Deprecated(&DeprecationOptions{
+		Since:     since,
+		Sunset:    sunset,
+		Link:      "https://example.com/migration",
+		Successor: "/v2",
+	})
+	v1.Get("/users", ok)
+	v1.Get("/posts", ok).Deprecated(&DeprecationOptions{
+		Sunset:    time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC),
+		Successor: "/v2/articles",
+		Gone:      true,
+	})
+	rtr.Get("/legacy", ok).Deprecated(nil)
+	rtr.Get("/current", ok)
+
+	rec, req, _ := request(http.MethodGet, "/v1/users", nil)
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "@1704067200", rec.Header().Get("Deprecation"))
+	assert.Equal(t, sunset.UTC().Format(http.TimeFormat),
+		rec.Header().Get("Sunset"))
+	assert.Equal(t, []string{
+		`<https://example.com/migration>; rel="deprecation"`,
+		`</v2>; rel="successor-version"`,
+	}, rec.Header().Values("Link"))
+	//-------------------- Another Test Case --------------------
+	rec, req, _ = request(http.MethodGet, "/v1/posts", nil)
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusGone, rec.Code)
+	assert.Equal(t, "true", rec.Header().Get("Deprecation"))
+	assert.Equal(t, "Sat, 01 Jun 2024 00:00:00 GMT", rec.Header().Get("Sunset"))
+	assert.Equal(t, []string{`</v2/articles>; rel="successor-version"`},
+		rec.Header().Values("Link"))
+	//-------------------- Another Test Case --------------------
+	rec, req, _ = request(http.MethodGet, "/legacy", nil)
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "true", rec.Header().Get("Deprecation"))
+	assert.Empty(t, rec.Header().Get("Sunset"))
+	assert.Empty(t, rec.Header().Values("Link"))
+	//-------------------- Another Test Case --------------------
+	rec, req, _ = request(http.MethodGet, "/current", nil)
+	rtr.ServeHTTP(rec, req)
+	assert.Empty(t, rec.Header().Get("Deprecation"))
+}
@@ -0,0 +1,52 @@
+package mux
+
+import (
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Download sends content as an attachment named filename, so that browsers
+// save it instead of displaying it. Content type is detected from the file
+// extension or, failing that, from the content itself.
+//
+// Range requests are supported: clients may fetch parts of the content with
+// "206 Partial Content" responses to seek in media or resume downloads. Pass
+// modification time of the content, if known, to let clients make sure the
+// parts they combine come from the same version (If-Range) and to support
+// conditional requests.
+func Download(
+	w http.ResponseWriter,
+	r *http.Request,
+	filename string,
+	modtime time.Time,
+	content io.ReadSeeker,
+) {
+	w.Header().Set("Content-Disposition",
+		mime.FormatMediaType("attachment", map[string]string{
+			"filename": filename,
+		}))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	http.ServeContent(w, r, filename, modtime, content)
+}
+
+// DownloadFile sends the named file from disk as an attachment (see Download).
+// Missing files and directories are reported as "404 Not Found" through Error.
+func DownloadFile(w http.ResponseWriter, r *http.Request, name string) {
+	f, err := os.Open(name)
+	if err != nil {
+		Error(w, r, NewHTTPError(http.StatusNotFound, "file not found"))
+		return
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil || info.IsDir() {
+		Error(w, r, NewHTTPError(http.StatusNotFound, "file not found"))
+		return
+	}
+	Download(w, r, filepath.Base(name), info.ModTime(), f)
+}
@@ -0,0 +1,80 @@
+package mux
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDownload(t *testing.T) {
+	modtime := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+	rtr := New().HandleFunc(func(w http.ResponseWriter, r *http.Request) {
+		Download(w, r, "report 2021.csv", modtime,
+			strings.NewReader("id,name\n1,Viktor\n"))
+	})
+
+	rec, req, err := request(http.MethodGet, "/", nil)
+	assert.NoError(t, err)
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `attachment; filename="report 2021.csv"`,
+		rec.Header().Get("Content-Disposition"))
+	assert.Equal(t, "text/csv; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "bytes", rec.Header().Get("Accept-Ranges"))
+	assert.Equal(t, "id,name\n1,Viktor\n", rec.Body.String())
+
+	rec, req, err = request(http.MethodGet, "/", nil)
+	assert.NoError(t, err)
+	req.Header.Set("Range", "bytes=8-")
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusPartialContent, rec.Code)
+	assert.Equal(t, "bytes 8-16/17", rec.Header().Get("Content-Range"))
+	assert.Equal(t, "1,Viktor\n", rec.Body.String())
+
+	// Stale If-Range gets the whole content.
+	rec, req, err = request(http.MethodGet, "/", nil)
+	assert.NoError(t, err)
+	req.Header.Set("Range", "bytes=8-")
+	req.Header.Set("If-Range",
+		modtime.Add(-time.Hour).Format(http.TimeFormat))
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec, req, err = request(http.MethodGet, "/", nil)
+	assert.NoError(t, err)
+	req.Header.Set("Range", "bytes=100-")
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusRequestedRangeNotSatisfiable, rec.Code)
+}
+
+func TestDownloadFile(t *testing.T) {
+	dir := staticDir(t, map[string]string{"video.mp4": "0123456789"})
+	defer os.RemoveAll(dir)
+
+	rtr := New()
+	rtr.Get("/video", func(w http.ResponseWriter, r *http.Request) {
+		DownloadFile(w, r, filepath.Join(dir, "video.mp4"))
+	})
+	rtr.Get("/missing", func(w http.ResponseWriter, r *http.Request) {
+		DownloadFile(w, r, filepath.Join(dir, "missing.mp4"))
+	})
+
+	rec, req, err := request(http.MethodGet, "/video", nil)
+	assert.NoError(t, err)
+	req.Header.Set("Range", "bytes=0-3")
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusPartialContent, rec.Code)
+	assert.Equal(t, "0123", rec.Body.String())
+	assert.Equal(t, `attachment; filename=video.mp4`,
+		rec.Header().Get("Content-Disposition"))
+
+	rec, req, err = request(http.MethodGet, "/missing", nil)
+	assert.NoError(t, err)
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
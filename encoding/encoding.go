@@ -0,0 +1,69 @@
+// Use of this source code is governed by the Mozilla Public License Version 2.0
+// that can be found in the LICENSE file.
+
+/*
+Package encoding provides mux.Encoders for content codings that aren't
+supported by the standard library, so that only the servers that offer them
+depend on their implementations:
+
+	rtr := mux.New().Wrap(mux.Compress(&mux.CompressOptions{
+	    Encoders: []mux.Encoder{
+	        encoding.Zstd(0), encoding.Brotli(-1), mux.GzipEncoder(-1),
+	    },
+	}))
+*/
+package encoding
+
+import (
+	"io"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+	"github.com/sharpvik/mux"
+)
+
+// Brotli returns mux.Encoder for "br" content coding with given quality from
+// 0 (fastest) to 11 (best). Invalid qualities fall back to 4, which is
+// comparable to gzip in speed, but compresses better.
+func Brotli(quality int) mux.Encoder {
+	if quality < brotli.BestSpeed || quality > brotli.BestCompression {
+		quality = 4
+	}
+	return &encoder{"br", func(w io.Writer) io.WriteCloser {
+		return brotli.NewWriterLevel(w, quality)
+	}}
+}
+
+// Zstd returns mux.Encoder for "zstd" content coding with given level from 1
+// (fastest) to 22 (best), as used by the zstd command. Invalid levels fall back
+// to 3. The window is limited to 8MB, as required by RFC 9659.
+func Zstd(level int) mux.Encoder {
+	if level < 1 || level > 22 {
+		level = 3
+	}
+	opts := []zstd.EOption{
+		zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(level)),
+		zstd.WithEncoderConcurrency(1),
+		zstd.WithWindowSize(8 << 20),
+	}
+	return &encoder{"zstd", func(w io.Writer) io.WriteCloser {
+		zw, _ := zstd.NewWriter(w, opts...)
+		return zw
+	}}
+}
+
+// encoder is a simple implementation of mux.Encoder.
+type encoder struct {
+	encoding  string
+	newWriter func(w io.Writer) io.WriteCloser
+}
+
+// Encoding method ensures that encoder implements the mux.Encoder interface.
+func (e *encoder) Encoding() string {
+	return e.encoding
+}
+
+// NewWriter method ensures that encoder implements the mux.Encoder interface.
+func (e *encoder) NewWriter(w io.Writer) io.WriteCloser {
+	return e.newWriter(w)
+}
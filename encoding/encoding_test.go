@@ -0,0 +1,70 @@
+package encoding
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+	"github.com/sharpvik/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncoders(t *testing.T) {
+	long := strings.Repeat("hello, world! ", 200)
+	rtr := mux.New().Wrap(mux.Compress(&mux.CompressOptions{
+		Encoders: []mux.Encoder{Zstd(0), Brotli(-1), mux.GzipEncoder(-1)},
+	}))
+	rtr.Get("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Write([]byte(long))
+	})
+
+	decode := func(encoding string, body io.Reader) string {
+		var r io.Reader
+		switch encoding {
+		case "gzip":
+			gr, err := gzip.NewReader(body)
+			assert.NoError(t, err)
+			r = gr
+		case "br":
+			r = brotli.NewReader(body)
+		case "zstd":
+			zr, err := zstd.NewReader(body)
+			assert.NoError(t, err)
+			defer zr.Close()
+			r = zr
+		}
+		b, err := io.ReadAll(r)
+		assert.NoError(t, err)
+		return string(b)
+	}
+
+	cases := []struct {
+		accept   string
+		encoding string
+	}{
+		{"gzip, br", "br"},
+		{"gzip, br, zstd", "zstd"},
+		{"gzip;q=1, br;q=0.5", "gzip"},
+		{"*", "zstd"},
+		{"*, zstd;q=0", "br"},
+	}
+	for i := 0; i < 2; i++ {
+		// Repeat to use pooled writers.
+		for _, c := range cases {
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Accept-Encoding", c.accept)
+			rtr.ServeHTTP(rec, req)
+			assert.Equal(t, c.encoding, rec.Header().Get("Content-Encoding"),
+				c.accept)
+			assert.Less(t, rec.Body.Len(), len(long))
+			assert.Equal(t, long, decode(c.encoding, rec.Body), c.accept)
+		}
+	}
+}
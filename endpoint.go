@@ -0,0 +1,105 @@
+package mux
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"net/http"
+	"reflect"
+)
+
+// StatusCoder is implemented by responses of Endpoint that are sent with
+// status code other than "200 OK", e.g. "201 Created".
+type StatusCoder interface {
+	StatusCode() int
+}
+
+// Endpoint adapts a typed function to View. The View binds the request into
+// Req, calls fn with it and renders the response as JSON:
+//
+//	type getUser struct {
+//	    ID     int  `mux:"id"`
+//	    Detail bool `mux:"detail"`
+//	}
+//
+//	rtr.Get("/users/{id:int}", mux.Endpoint(
+//	    func(ctx context.Context, req getUser) (*User, error) {
+//	        return users.Get(ctx, req.ID, req.Detail)
+//	    },
+//	))
+//
+// Request body, if any, is decoded into Req as JSON (see DecodeJSON); then
+// path variables and query parameters are bound to the fields with `mux` tags
+// (see Bind), so they take precedence over the body. Req may also be a pointer
+// to a struct, or any other type that the body is decoded into. Bound values
+// are validated (see Validator).
+//
+// The response is sent with "200 OK", unless Resp implements StatusCoder.
+// Binding errors and errors returned by fn are reported through Error with
+// status codes of their *HTTPError, if any; otherwise fs.ErrNotExist maps to
+// "404 Not Found", fs.ErrPermission to "403 Forbidden",
+// context.DeadlineExceeded to "504 Gateway Timeout", and the rest to "500
+// Internal Server Error".
+func Endpoint[Req, Resp any](
+	fn func(ctx context.Context, req Req) (Resp, error),
+) View {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req Req
+		if err := bindEndpoint(r, &req); err != nil {
+			Error(w, r, err)
+			return
+		}
+		resp, err := fn(r.Context(), req)
+		if err != nil {
+			Error(w, r, endpointError(err))
+			return
+		}
+		code := http.StatusOK
+		if sc, ok := any(resp).(StatusCoder); ok {
+			code = sc.StatusCode()
+		}
+		if err := JSON(w, code, resp); err != nil {
+			Error(w, r, err)
+		}
+	}
+}
+
+// bindEndpoint decodes the body of the request and binds its parameters into
+// the value pointed to by req, then validates it. If req points to a nil
+// pointer, the pointer is set to a new value first.
+func bindEndpoint(r *http.Request, req interface{}) error {
+	dst := req
+	if v := reflect.ValueOf(req).Elem(); v.Kind() == reflect.Ptr {
+		v.Set(reflect.New(v.Type().Elem()))
+		dst = v.Interface()
+	}
+	if r.Body != nil && r.Body != http.NoBody && r.ContentLength != 0 {
+		if err := decodeJSON(r, dst, nil); err != nil {
+			return err
+		}
+	}
+	if reflect.ValueOf(dst).Elem().Kind() == reflect.Struct {
+		if err := bindParams(r, dst); err != nil {
+			return err
+		}
+	}
+	return validate(r, dst)
+}
+
+// endpointError attaches status code to the error returned by Endpoint
+// function unless it already has one. Client errors drop the original error,
+// since its message (e.g. a file path) is shown to the client.
+func endpointError(err error) error {
+	var herr *HTTPError
+	switch {
+	case errors.As(err, &herr):
+		return err
+	case errors.Is(err, fs.ErrNotExist):
+		return &HTTPError{Code: http.StatusNotFound}
+	case errors.Is(err, fs.ErrPermission):
+		return &HTTPError{Code: http.StatusForbidden}
+	case errors.Is(err, context.DeadlineExceeded):
+		return &HTTPError{http.StatusGatewayTimeout, err}
+	}
+	return err
+}
@@ -0,0 +1,104 @@
+package mux
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type article struct {
+	ID    int    `json:"id" mux:"id"`
+	Title string `json:"title"`
+	Draft bool   `json:"draft,omitempty" mux:"draft"`
+}
+
+func (a *article) Validate() error {
+	if a.Title == "forbidden" {
+		return errors.New("title is forbidden")
+	}
+	return nil
+}
+
+type created struct {
+	*article
+}
+
+func (created) StatusCode() int {
+	return http.StatusCreated
+}
+
+func TestEndpoint(t *testing.T) {
+	rtr := New()
+	rtr.Get("/articles/{id:int}", Endpoint(
+		func(ctx context.Context, req article) (article, error) {
+			switch req.ID {
+			case 404:
+				return article{}, fmt.Errorf("open /data/404: %w",
+					fs.ErrNotExist)
+			case 500:
+				return article{}, errors.New("database is down")
+			case 504:
+				return article{}, context.DeadlineExceeded
+			case 409:
+				return article{}, NewHTTPError(http.StatusConflict,
+					"article is locked")
+			}
+			req.Title = "hello"
+			return req, nil
+		},
+	))
+	rtr.Post("/articles", Endpoint(
+		func(ctx context.Context, req *article) (created, error) {
+			return created{req}, nil
+		},
+	))
+	rtr.Post("/tags", Endpoint(
+		func(ctx context.Context, tags []string) (int, error) {
+			return len(tags), nil
+		},
+	))
+
+	cases := []struct {
+		method, path, body string
+		code               int
+		res                string
+	}{
+		{http.MethodGet, "/articles/1?draft=true", "", http.StatusOK,
+			`{"id":1,"title":"hello","draft":true}`},
+		{http.MethodGet, "/articles/1?draft=maybe", "",
+			http.StatusBadRequest, ""},
+		{http.MethodGet, "/articles/404", "", http.StatusNotFound,
+			"Not Found\n"},
+		{http.MethodGet, "/articles/409", "", http.StatusConflict,
+			"article is locked\n"},
+		{http.MethodGet, "/articles/500", "", http.StatusInternalServerError,
+			"Internal Server Error\n"},
+		{http.MethodGet, "/articles/504", "", http.StatusGatewayTimeout, ""},
+		{http.MethodPost, "/articles?id=7", `{"id":1,"title":"new"}`,
+			http.StatusCreated, `{"id":7,"title":"new"}`},
+		{http.MethodPost, "/articles", `{"title":"forbidden"}`,
+			http.StatusUnprocessableEntity, ""},
+		{http.MethodPost, "/articles", `{"title":`, http.StatusBadRequest, ""},
+		{http.MethodPost, "/tags", `["a","b"]`, http.StatusOK, "2\n"},
+	}
+	for _, c := range cases {
+		rec, req, err := request(c.method, c.path, strings.NewReader(c.body))
+		assert.NoError(t, err)
+		if c.body == "" {
+			req.Body, req.ContentLength = http.NoBody, 0
+		}
+		rtr.ServeHTTP(rec, req)
+		assert.Equal(t, c.code, rec.Code, c.path)
+		if strings.HasPrefix(c.res, "{") {
+			assert.JSONEq(t, c.res, rec.Body.String(), c.path)
+		} else if c.res != "" {
+			assert.Equal(t, c.res, rec.Body.String(), c.path)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package mux
+
+import (
+	"net/http"
+	"strings"
+)
+
+// EnvOptions configures EnvFilter.
+type EnvOptions struct {
+	// Header is the header that selects the environment. Empty means
+	// "X-Env".
+	Header string
+
+	// Env is the value of the header that selects the alternate environment,
+	// compared case-insensitively. Empty means "green".
+	Env string
+
+	// Trusted tells which requests may select the environment, e.g.
+	// NewIPFilter("10.0.0.0/8") for the office network, or a filter that
+	// checks the identity of testers. It is required, since anyone could
+	// send the header otherwise.
+	Trusted Filter
+}
+
+// EnvFilter takes care of filtering requests that select an alternate
+// environment (e.g. the green deployment of blue/green) with a header. Only
+// requests from trusted sources may do that.
+type EnvFilter struct {
+	header, env string
+	trusted     Filter
+}
+
+// NewEnvFilter returns pointer to a new EnvFilter. It panics if there is no
+// trusted filter in the options.
+func NewEnvFilter(opts *EnvOptions) *EnvFilter {
+	if opts == nil || opts.Trusted == nil {
+		panic("can't select environment by header without trusted sources")
+	}
+	fil := &EnvFilter{opts.Header, opts.Env, opts.Trusted}
+	if fil.header == "" {
+		fil.header = "X-Env"
+	}
+	if fil.env == "" {
+		fil.env = "green"
+	}
+	return fil
+}
+
+// Match method returns boolean value that tells you whether given request
+// passed the filter. Also, *EnvFilter implements the Filter interface since it
+// has this method.
+func (fil *EnvFilter) Match(r *http.Request) bool {
+	env := strings.TrimSpace(r.Header.Get(fil.header))
+	return strings.EqualFold(env, fil.env) && fil.trusted.Match(r)
+}
+
+// Env method creates a sub-router that sends requests selecting the alternate
+// environment with EnvFilter to h, e.g. a Proxy of the new deployment, so that
+// testers can reach it through the same host. The rest go on to the other
+// routes, so it must be registered before them:
+//
+//	rtr.Env(greenProxy, &mux.EnvOptions{
+//	    Trusted: mux.NewIPFilter("10.0.0.0/8"),
+//	})
+//	rtr.Mount("/", blueProxy)
+//
+// It panics if there is no trusted filter in the options.
+func (rtr *Router) Env(h http.Handler, opts *EnvOptions) *Router {
+	return rtr.Subrouter().Filter(NewEnvFilter(opts)).Handler(h)
+}
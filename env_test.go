@@ -0,0 +1,49 @@
+package mux
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnv(t *testing.T) {
+	text := func(s string) View {
+		return func(w http.ResponseWriter, r *http.Request) {
+			Text(w, http.StatusOK, s)
+		}
+	}
+	rtr := New()
+	rtr.Env(text("green"), &EnvOptions{Trusted: NewIPFilter("10.0.0.0/8")})
+	rtr.Env(text("canary"), &EnvOptions{
+		Header:  "X-Deployment",
+		Env:     "canary",
+		Trusted: MatcherFunc(func(r *http.Request) bool { return true }),
+	})
+	rtr.Get("/", text("blue"))
+
+	cases := []struct {
+		ip, header, value string
+		body              string
+	}{
+		{"10.1.2.3", "", "", "blue"},
+		{"10.1.2.3", "X-Env", "green", "green"},
+		{"10.1.2.3", "X-Env", " Green ", "green"},
+		{"10.1.2.3", "X-Env", "blue", "blue"},
+		{"192.0.2.1", "X-Env", "green", "blue"},
+		{"192.0.2.1", "X-Deployment", "canary", "canary"},
+	}
+	for _, c := range cases {
+		rec, req, err := request(http.MethodGet, "/", nil)
+		assert.NoError(t, err)
+		req.RemoteAddr = c.ip + ":1234"
+		if c.header != "" {
+			req.Header.Set(c.header, c.value)
+		}
+		rtr.ServeHTTP(rec, req)
+		assert.Equal(t, c.body, rec.Body.String(), c)
+	}
+	//-------------------- Another Test Case --------------------
+	assert.Panics(t, func() { rtr.Env(text("green"), nil) })
+	assert.Panics(t, func() { NewEnvFilter(&EnvOptions{Env: "green"}) })
+}
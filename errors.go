@@ -0,0 +1,105 @@
+package mux
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// HTTPError is an error that carries the HTTP status code it should be
+// reported with.
+type HTTPError struct {
+	// Code is the HTTP status code, e.g. http.StatusBadRequest.
+	Code int
+
+	// Err is the underlying error.
+	Err error
+}
+
+// NewHTTPError returns pointer to an HTTPError with given status code and
+// message formatted according to the format specifier.
+func NewHTTPError(code int, format string, a ...interface{}) *HTTPError {
+	return &HTTPError{code, fmt.Errorf(format, a...)}
+}
+
+// Error method ensures that HTTPError implements the error interface.
+func (e *HTTPError) Error() string {
+	if e.Err == nil {
+		return http.StatusText(e.Code)
+	}
+	return e.Err.Error()
+}
+
+// Unwrap method returns the underlying error.
+func (e *HTTPError) Unwrap() error {
+	return e.Err
+}
+
+// StatusOf returns HTTP status code that err should be reported with: the
+// code of the first HTTPError in its chain or 500 if there is none.
+func StatusOf(err error) int {
+	var herr *HTTPError
+	if errors.As(err, &herr) {
+		return herr.Code
+	}
+	return http.StatusInternalServerError
+}
+
+// ErrorHandlerFunc is a function that reports errors to the client.
+type ErrorHandlerFunc func(http.ResponseWriter, *http.Request, error)
+
+// DefaultErrorHandler responds with the status code of the error (see
+// StatusOf). The body contains error message for client errors (4xx) and
+// status text for all others, so that internal details don't leak.
+func DefaultErrorHandler(w http.ResponseWriter, r *http.Request, err error) {
+	code := StatusOf(err)
+	msg := http.StatusText(code)
+	if code >= 400 && code < 500 {
+		msg = err.Error()
+	}
+	http.Error(w, msg, code)
+}
+
+// ErrorHandler method sets the function used by Error to report errors that
+// occur within this Router and its sub-routers (unless they have their own
+// error handler set).
+func (rtr *Router) ErrorHandler(h ErrorHandlerFunc) *Router {
+	rtr.errorHandler = h
+	return rtr
+}
+
+// Error reports err to the client using the status handler registered for its
+// code (see StatusHandler), the error handler of the closest router that has
+// one, or DefaultErrorHandler. Handlers are advised to use it
+// for all errors, so that error responses look the same across the app:
+//
+//	if err := mux.DecodeJSON(r, &user, nil); err != nil {
+//	    mux.Error(w, r, err)
+//	    return
+//	}
+func Error(w http.ResponseWriter, r *http.Request, err error) {
+	logError(r, err)
+	reported(r, err)
+	if h, ok := statusHandler(r, StatusOf(err)); ok {
+		h.ServeHTTP(w, r.WithContext(
+			context.WithValue(r.Context(), errorKey, err)))
+		return
+	}
+	if h, ok := r.Context().Value(errorHandlerKey).(ErrorHandlerFunc); ok {
+		h(w, r, err)
+		return
+	}
+	DefaultErrorHandler(w, r, err)
+}
+
+// withErrorHandler returns a copy of request that carries Router's error
+// handler in case it is set.
+func (rtr *Router) withErrorHandler(r *http.Request) *http.Request {
+	if rtr.errorHandler == nil {
+		return r
+	}
+	return r.WithContext(
+		context.WithValue(r.Context(), errorHandlerKey, rtr.errorHandler),
+	)
+}
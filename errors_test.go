@@ -0,0 +1,49 @@
+package mux
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusOf(t *testing.T) {
+	err := NewHTTPError(http.StatusTeapot, "short and stout")
+	assert.Equal(t, http.StatusTeapot, StatusOf(err))
+	assert.Equal(t, http.StatusTeapot, StatusOf(fmt.Errorf("wrap: %w", err)))
+	assert.Equal(t, http.StatusInternalServerError, StatusOf(errors.New("x")))
+}
+
+func TestErrorPipeline(t *testing.T) {
+	rtr := New()
+	rtr.Get("/client", func(w http.ResponseWriter, r *http.Request) {
+		Error(w, r, NewHTTPError(http.StatusBadRequest, "bad id %d", 42))
+	})
+	rtr.Get("/server", func(w http.ResponseWriter, r *http.Request) {
+		Error(w, r, errors.New("database is on fire"))
+	})
+
+	rec, req, err := request(http.MethodGet, "/client", nil)
+	assert.NoError(t, err)
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, "bad id 42\n", rec.Body.String())
+
+	rec, req, err = request(http.MethodGet, "/server", nil)
+	assert.NoError(t, err)
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "fire")
+	//-------------------- Another Test Case --------------------
+	rtr.ErrorHandler(func(w http.ResponseWriter, r *http.Request, err error) {
+		w.WriteHeader(StatusOf(err))
+		fmt.Fprintf(w, `{"error":%q}`, err.Error())
+	})
+	rec, req, err = request(http.MethodGet, "/client", nil)
+	assert.NoError(t, err)
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, `{"error":"bad id 42"}`, rec.Body.String())
+}
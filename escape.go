@@ -0,0 +1,117 @@
+package mux
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// EncodedSlashes is a policy that tells Router how to treat encoded slashes
+// ("%2F") in request paths.
+type EncodedSlashes int
+
+const (
+	// DecodedPaths is the default policy: requests are routed by their
+	// decoded path (http.Request.URL.Path), so "%2F" is indistinguishable
+	// from "/" and splits path variables in two.
+	DecodedPaths EncodedSlashes = iota
+
+	// KeepEncodedSlashes policy routes requests by their escaped path
+	// (http.Request.URL.EscapedPath), so "%2F" is allowed inside path
+	// variables. Variable values are kept escaped, e.g. "a%2Fb".
+	KeepEncodedSlashes
+
+	// DecodeEncodedSlashes policy routes requests by their escaped path just
+	// like KeepEncodedSlashes, but variable values are unescaped, e.g. "a/b".
+	DecodeEncodedSlashes
+
+	// RejectEncodedSlashes policy responds "400 Bad Request" to any request
+	// whose path contains "%2F". Other requests are routed by decoded path.
+	RejectEncodedSlashes
+)
+
+// EncodedSlashes method sets the encoded slashes policy of the Router. It is
+// meant to be set on the root of the routing tree, since the policy determines
+// the form of the path that all sub-routers match against.
+func (rtr *Router) EncodedSlashes(policy EncodedSlashes) *Router {
+	rtr.encoded = policy
+	return rtr
+}
+
+// escapePath method applies the encoded slashes policy to the request. In
+// escaped modes, it returns a copy of the request with the path replaced by
+// its escaped form, which is used for routing until the request is passed to a
+// handler (see unescaped). It returns false if the request was rejected.
+func (rtr *Router) escapePath(
+	w http.ResponseWriter, r *http.Request,
+) (*http.Request, bool) {
+	switch rtr.encoded {
+	case KeepEncodedSlashes, DecodeEncodedSlashes:
+		if encodedSlashes(r) != DecodedPaths {
+			return r, true // Already escaped by one of the parents.
+		}
+		r = withPath(r, r.URL.EscapedPath(), "")
+		return r.WithContext(
+			context.WithValue(r.Context(), encodedKey, rtr.encoded),
+		), true
+
+	case RejectEncodedSlashes:
+		if strings.Contains(strings.ToUpper(r.URL.EscapedPath()), "%2F") {
+			http.Error(w, "encoded slash in path", http.StatusBadRequest)
+			return r, false
+		}
+	}
+	return r, true
+}
+
+// encodedSlashes returns the escaped mode the request is routed in or
+// DecodedPaths if it is routed by decoded path.
+func encodedSlashes(r *http.Request) EncodedSlashes {
+	policy, _ := r.Context().Value(encodedKey).(EncodedSlashes)
+	return policy
+}
+
+// unescaped returns the request as it should be seen by handlers: if it is
+// routed by escaped path, a copy with properly decoded URL.Path and URL.RawPath
+// is returned.
+func unescaped(r *http.Request) *http.Request {
+	if encodedSlashes(r) == DecodedPaths {
+		return r
+	}
+	path, err := url.PathUnescape(r.URL.Path)
+	if err != nil {
+		return r
+	}
+	u := *r.URL
+	u.Path, u.RawPath = path, r.URL.Path
+	r2 := r.WithContext(r.Context())
+	r2.URL = &u
+	return r2
+}
+
+// varValue converts raw path variable value according to the escaped mode the
+// request is routed in.
+func varValue(r *http.Request, value string) string {
+	if encodedSlashes(r) != DecodeEncodedSlashes {
+		return value
+	}
+	if unescaped, err := url.PathUnescape(value); err == nil {
+		return unescaped
+	}
+	return value
+}
+
+// requestURI returns request URI for the redirect to given path that keeps
+// request's query intact. The path is treated as escaped if the request is
+// routed by escaped path.
+func requestURI(r *http.Request, path string) string {
+	u := *r.URL
+	u.Path, u.RawPath = path, ""
+	if encodedSlashes(r) != DecodedPaths {
+		if unescaped, err := url.PathUnescape(path); err == nil {
+			u.Path, u.RawPath = unescaped, path
+		}
+	}
+	return u.RequestURI()
+}
@@ -0,0 +1,62 @@
+package mux
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodedSlashes(t *testing.T) {
+	var got, path string
+	rtr := New()
+	rtr.Subrouter().PathPrefix("/files").
+		Get(`/{name:[\w%]+}`, func(w http.ResponseWriter, r *http.Request) {
+			vars, _ := Vars(r)
+			got = vars["name"].(string)
+			path = r.URL.Path
+		})
+
+	// Decoded paths are the default, so "%2F" splits the variable.
+	rec, req, err := request(http.MethodGet, "/files/a%2Fb", nil)
+	assert.NoError(t, err)
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	//-------------------- Another Test Case --------------------
+	rtr.EncodedSlashes(KeepEncodedSlashes)
+	rec, req, err = request(http.MethodGet, "/files/a%2Fb", nil)
+	assert.NoError(t, err)
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "a%2Fb", got)
+	assert.Equal(t, "/a/b", path)
+	assert.Equal(t, "/files/a/b", req.URL.Path, "caller's request changed")
+	assert.Equal(t, "/files/a%2Fb", req.URL.RawPath)
+	//-------------------- Another Test Case --------------------
+	rtr.EncodedSlashes(DecodeEncodedSlashes)
+	rec, req, err = request(http.MethodGet, "/files/a%2Fb%20c", nil)
+	assert.NoError(t, err)
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "a/b c", got)
+	//-------------------- Another Test Case --------------------
+	rtr.EncodedSlashes(RejectEncodedSlashes)
+	rec, req, err = request(http.MethodGet, "/files/a%2fb", nil)
+	assert.NoError(t, err)
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestEncodedSlashesRedirect(t *testing.T) {
+	rtr := New().EncodedSlashes(KeepEncodedSlashes).TrailingSlash(RedirectSlash)
+	rtr.Get(`/files/{name:[\w%]+}`, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "file")
+	})
+
+	rec, req, err := request(http.MethodGet, "/files/a%2Fb/", nil)
+	assert.NoError(t, err)
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusMovedPermanently, rec.Code)
+	assert.Equal(t, "/files/a%2Fb", rec.Header().Get("Location"))
+}
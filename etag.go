@@ -0,0 +1,193 @@
+package mux
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// DefaultMaxETagBytes is the maximum size of the response that ETag middleware
+// buffers unless specified otherwise.
+const DefaultMaxETagBytes = 1 << 20
+
+// ETagOptions configures ETag.
+type ETagOptions struct {
+	// Weak makes ETags weak (W/"..."), which tells clients that responses
+	// with the same ETag are equivalent rather than byte-for-byte equal.
+	Weak bool
+
+	// MaxBytes is the maximum size of the response that is buffered to
+	// compute its ETag. Larger responses are sent without ETag as they are
+	// written. Zero means DefaultMaxETagBytes.
+	MaxBytes int
+}
+
+// ETag returns Middleware that sets ETag header of "200 OK" responses to GET
+// requests to the hash of their body and answers "304 Not Modified" to
+// requests whose If-None-Match header has that ETag, so that clients don't
+// download dynamic responses they already have:
+//
+//	rtr.ETag(nil)
+//	rtr.Get("/users/{id:int}", showUser)
+//	rtr.Get("/events", events).NoETag()
+//
+// Responses are buffered until the handler returns, so routes that stream
+// their responses should opt out with NoETag; responses are also sent as they
+// are written once the handler flushes them. ETags set by handlers are kept.
+// If opts is nil, defaults are used.
+func ETag(opts *ETagOptions) Middleware {
+	if opts == nil {
+		opts = &ETagOptions{}
+	}
+	limit := opts.MaxBytes
+	if limit == 0 {
+		limit = DefaultMaxETagBytes
+	}
+
+	return func(next http.Handler) http.Handler {
+		return View(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet {
+				next.ServeHTTP(w, r)
+				return
+			}
+			skip := new(bool)
+			r = r.WithContext(context.WithValue(r.Context(), noETagKey, skip))
+			ew := &etagWriter{ResponseWriter: w, skip: skip, limit: limit}
+			next.ServeHTTP(ew, r)
+			if ew.passed {
+				return
+			}
+			if ew.code == 0 {
+				ew.code = http.StatusOK
+			}
+
+			h := w.Header()
+			if ew.code == http.StatusOK && h.Get("ETag") == "" {
+				sum := sha256.Sum256(ew.body.Bytes())
+				etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+				if opts.Weak {
+					etag = "W/" + etag
+				}
+				h.Set("ETag", etag)
+			}
+			if ew.code == http.StatusOK &&
+				etagMatch(r.Header.Get("If-None-Match"), h.Get("ETag")) {
+				h.Del("Content-Type")
+				h.Del("Content-Length")
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			if h.Get("Content-Length") == "" && bodyAllowed(ew.code) {
+				h.Set("Content-Length", strconv.Itoa(ew.body.Len()))
+			}
+			ew.pass()
+		})
+	}
+}
+
+// ETag method registers ETag middleware on the Router. See ETag.
+func (rtr *Router) ETag(opts *ETagOptions) *Router {
+	return rtr.Wrap(ETag(opts))
+}
+
+// NoETag method opts the routes of the Router out of ETag middleware of its
+// parents, so that their responses are sent as they are written, e.g. for
+// server-sent events.
+func (rtr *Router) NoETag() *Router {
+	return rtr.Wrap(func(next http.Handler) http.Handler {
+		return View(func(w http.ResponseWriter, r *http.Request) {
+			if skip, ok := r.Context().Value(noETagKey).(*bool); ok {
+				*skip = true
+			}
+			next.ServeHTTP(w, r)
+		})
+	})
+}
+
+// etagMatch tells whether the value of If-None-Match header lists the ETag.
+// ETags are compared weakly, as RFC 9110 requires for If-None-Match.
+func etagMatch(header, etag string) bool {
+	if header == "" || etag == "" {
+		return false
+	}
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// etagWriter is http.ResponseWriter that buffers the response, so that its
+// ETag can be set before it's sent. It stops buffering if the route opted out
+// of ETag middleware, the response is too large, or the handler flushes it.
+type etagWriter struct {
+	http.ResponseWriter
+	skip   *bool
+	limit  int
+	code   int
+	body   bytes.Buffer
+	passed bool
+}
+
+// WriteHeader method records the status code, unless the response is passed
+// through already.
+func (ew *etagWriter) WriteHeader(code int) {
+	if !ew.passed && !*ew.skip {
+		if ew.code == 0 {
+			ew.code = code
+		}
+		return
+	}
+	ew.pass()
+	ew.ResponseWriter.WriteHeader(code)
+}
+
+// Write method buffers the data, unless the response is passed through.
+func (ew *etagWriter) Write(p []byte) (int, error) {
+	if ew.code == 0 {
+		ew.code = http.StatusOK
+	}
+	if !ew.passed && !*ew.skip && ew.body.Len()+len(p) <= ew.limit {
+		return ew.body.Write(p)
+	}
+	if err := ew.pass(); err != nil {
+		return 0, err
+	}
+	return ew.ResponseWriter.Write(p)
+}
+
+// Flush method passes the response through and flushes it.
+func (ew *etagWriter) Flush() {
+	ew.pass()
+	if f, ok := ew.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap method returns the underlying http.ResponseWriter, so that
+// http.ResponseController can reach it.
+func (ew *etagWriter) Unwrap() http.ResponseWriter {
+	return ew.ResponseWriter
+}
+
+// pass method writes the status code and the buffered data, if there are
+// any, and makes the writer pass the rest of the response through.
+func (ew *etagWriter) pass() error {
+	if ew.passed {
+		return nil
+	}
+	ew.passed = true
+	if ew.code == 0 {
+		return nil
+	}
+	ew.ResponseWriter.WriteHeader(ew.code)
+	_, err := ew.ResponseWriter.Write(ew.body.Bytes())
+	return err
+}
@@ -0,0 +1,79 @@
+package mux
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestETag(t *testing.T) {
+	text := func(s string) View {
+		return func(w http.ResponseWriter, r *http.Request) {
+			Text(w, http.StatusOK, s)
+		}
+	}
+	rtr := New().ETag(&ETagOptions{MaxBytes: 16})
+	rtr.Get("/user", text("alice"))
+	rtr.Get("/large", text(strings.Repeat("x", 17)))
+	rtr.Get("/created", func(w http.ResponseWriter, r *http.Request) {
+		Text(w, http.StatusCreated, "created")
+	})
+	rtr.Get("/versioned", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"v2"`)
+		Text(w, http.StatusOK, "v2")
+	})
+	rtr.Get("/events", text("event")).NoETag()
+	rtr.Get("/flushed", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("chunk"))
+		w.(http.Flusher).Flush()
+	})
+	serve := func(path, inm string) *httptest.ResponseRecorder {
+		rec, req, err := request(http.MethodGet, path, nil)
+		assert.NoError(t, err)
+		if inm != "" {
+			req.Header.Set("If-None-Match", inm)
+		}
+		rtr.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := serve("/user", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "alice", rec.Body.String())
+	assert.Equal(t, "5", rec.Header().Get("Content-Length"))
+	etag := rec.Header().Get("ETag")
+	assert.Regexp(t, `^"[0-9a-f]{32}"$`, etag)
+	//-------------------- Another Test Case --------------------
+	for _, inm := range []string{etag, `"other", ` + etag, "W/" + etag, "*"} {
+		rec = serve("/user", inm)
+		assert.Equal(t, http.StatusNotModified, rec.Code, inm)
+		assert.Equal(t, "", rec.Body.String())
+		assert.Equal(t, etag, rec.Header().Get("ETag"))
+		assert.Empty(t, rec.Header().Get("Content-Type"))
+	}
+	rec = serve("/user", `"other"`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	//-------------------- Another Test Case --------------------
+	rec = serve("/versioned", `"v2"`)
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+	rec = serve("/created", "*")
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Empty(t, rec.Header().Get("ETag"))
+	//-------------------- Another Test Case --------------------
+	for _, path := range []string{"/large", "/events", "/flushed"} {
+		rec = serve(path, "*")
+		assert.Equal(t, http.StatusOK, rec.Code, path)
+		assert.NotEmpty(t, rec.Body.String(), path)
+		assert.Empty(t, rec.Header().Get("ETag"), path)
+	}
+	//-------------------- Another Test Case --------------------
+	weak := New().ETag(&ETagOptions{Weak: true})
+	weak.Get("/", text("alice"))
+	rw, req, err := request(http.MethodGet, "/", nil)
+	assert.NoError(t, err)
+	weak.ServeHTTP(rw, req)
+	assert.Equal(t, "W/"+etag, rw.Header().Get("ETag"))
+}
@@ -0,0 +1,210 @@
+package mux
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultExperimentMaxAge is how long clients keep their variants unless
+// specified otherwise.
+const DefaultExperimentMaxAge = 30 * 24 * time.Hour
+
+// Variant is a variant of an experiment.
+type Variant struct {
+	// Name identifies the variant, e.g. "control". It is stored in the
+	// cookie of the experiment.
+	Name string
+
+	// Weight is the relative share of clients assigned to the variant. Zero
+	// means 1.
+	Weight int
+
+	// Handler serves the requests of clients assigned to the variant.
+	Handler http.Handler
+}
+
+// weight method returns the weight of the variant, which is at least 1.
+func (v *Variant) weight() int {
+	if v.Weight <= 0 {
+		return 1
+	}
+	return v.Weight
+}
+
+// ExperimentOptions configures the cookie that keeps the variants assigned to
+// clients.
+type ExperimentOptions struct {
+	// CookieName is the name of the cookie. Empty means "exp_" followed by
+	// the name of the experiment.
+	CookieName string
+
+	// MaxAge is how long the client keeps its variant. Zero means
+	// DefaultExperimentMaxAge.
+	MaxAge time.Duration
+
+	// Path and Domain are the attributes of the cookie. Empty Path means
+	// "/".
+	Path   string
+	Domain string
+
+	// Secure makes the cookie HTTPS only.
+	Secure bool
+}
+
+// Experiment method creates a sub-router that runs an A/B experiment: every
+// client is assigned to one of the variants at random according to their
+// weights, and its requests are served by the handler of that variant. The
+// assignment is kept in a cookie, so the client keeps seeing the same variant:
+//
+//	rtr.Experiment("checkout", nil,
+//	    mux.Variant{Name: "control", Weight: 9, Handler: checkout},
+//	    mux.Variant{Name: "one-page", Weight: 1, Handler: onePageCheckout},
+//	).PathPrefix("/checkout")
+//
+// Handlers get the variant with ExperimentVariant; AccessLog reports it in
+// AccessEntry.Experiments. Clients whose cookie names an unknown variant are
+// assigned again. If opts is nil, defaults are used.
+//
+// It panics if there are no variants or their names repeat.
+func (rtr *Router) Experiment(
+	name string, opts *ExperimentOptions, variants ...Variant,
+) *Router {
+	if len(variants) == 0 {
+		panic(fmt.Sprintf("can't run experiment %s without variants", name))
+	}
+	names := newSet()
+	total := 0
+	for _, v := range variants {
+		if names.Has(v.Name) {
+			panic(fmt.Sprintf("can't run experiment %s with variant %s twice",
+				name, v.Name))
+		}
+		names.Add(v.Name)
+		total += v.weight()
+	}
+	if opts == nil {
+		opts = &ExperimentOptions{}
+	}
+	cookie := opts.CookieName
+	if cookie == "" {
+		cookie = "exp_" + name
+	}
+	maxAge := opts.MaxAge
+	if maxAge == 0 {
+		maxAge = DefaultExperimentMaxAge
+	}
+	path := opts.Path
+	if path == "" {
+		path = "/"
+	}
+
+	return rtr.Subrouter().HandleFunc(func(
+		w http.ResponseWriter, r *http.Request,
+	) {
+		var variant *Variant
+		if c, err := r.Cookie(cookie); err == nil {
+			variant = findVariant(variants, c.Value)
+		}
+		if variant == nil {
+			variant = pickVariant(variants, total)
+			http.SetCookie(w, &http.Cookie{
+				Name:     cookie,
+				Value:    variant.Name,
+				Path:     path,
+				Domain:   opts.Domain,
+				MaxAge:   int(maxAge / time.Second),
+				Secure:   opts.Secure,
+				HttpOnly: true,
+				SameSite: http.SameSiteLaxMode,
+			})
+		}
+		variant.Handler.ServeHTTP(w, withVariant(r, name, variant.Name))
+	})
+}
+
+// ExperimentVariant returns the name of the variant of the experiment that
+// the request was assigned to, or an empty string if it wasn't.
+func ExperimentVariant(r *http.Request, experiment string) string {
+	variants, _ := r.Context().Value(experimentKey).(map[string]string)
+	return variants[experiment]
+}
+
+// withVariant returns a copy of request that carries the variant of the
+// experiment. The variant is also recorded for AccessLog.
+func withVariant(r *http.Request, experiment, variant string) *http.Request {
+	if rec, ok := r.Context().Value(routeKey).(*routeRecord); ok {
+		rec.mu.Lock()
+		if rec.experiments == nil {
+			rec.experiments = make(map[string]string)
+		}
+		rec.experiments[experiment] = variant
+		rec.mu.Unlock()
+	}
+	old, _ := r.Context().Value(experimentKey).(map[string]string)
+	variants := make(map[string]string, len(old)+1)
+	for k, v := range old {
+		variants[k] = v
+	}
+	variants[experiment] = variant
+	return r.WithContext(
+		context.WithValue(r.Context(), experimentKey, variants),
+	)
+}
+
+// recordedExperiments returns the variants recorded for the request returned
+// by RecordRoute, or nil if there are none.
+func recordedExperiments(r *http.Request) map[string]string {
+	rec, ok := r.Context().Value(routeKey).(*routeRecord)
+	if !ok {
+		return nil
+	}
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	if len(rec.experiments) == 0 {
+		return nil
+	}
+	variants := make(map[string]string, len(rec.experiments))
+	for k, v := range rec.experiments {
+		variants[k] = v
+	}
+	return variants
+}
+
+// formatExperiments formats the variants as "experiment:variant" pairs
+// separated by commas and sorted by experiment.
+func formatExperiments(variants map[string]string) string {
+	pairs := make([]string, 0, len(variants))
+	for k, v := range variants {
+		pairs = append(pairs, k+":"+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// findVariant returns the variant with the name, or nil if there is none.
+func findVariant(variants []Variant, name string) *Variant {
+	for i := range variants {
+		if variants[i].Name == name {
+			return &variants[i]
+		}
+	}
+	return nil
+}
+
+// pickVariant picks a variant at random according to their weights, which
+// add up to total.
+func pickVariant(variants []Variant, total int) *Variant {
+	n := rand.Intn(total)
+	for i := range variants {
+		n -= variants[i].weight()
+		if n < 0 {
+			return &variants[i]
+		}
+	}
+	return &variants[len(variants)-1]
+}
@@ -0,0 +1,75 @@
+package mux
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExperiment(t *testing.T) {
+	variant := func(w http.ResponseWriter, r *http.Request) {
+		Text(w, http.StatusOK, ExperimentVariant(r, "checkout"))
+	}
+	var entries []*AccessEntry
+	rtr := New().Wrap(AccessLog(AccessLoggerFunc(
+		func(r *http.Request, e *AccessEntry) {
+			entries = append(entries, e)
+		},
+	)))
+	rtr.Experiment("checkout", &ExperimentOptions{Secure: true},
+		Variant{Name: "a", Weight: 3, Handler: View(variant)},
+		Variant{Name: "b", Handler: View(variant)},
+	).PathPrefix("/checkout")
+	rtr.Get("/home", variant)
+
+	counts := make(map[string]int)
+	for i := 0; i < 400; i++ {
+		rec, req, _ := request(http.MethodGet, "/checkout", nil)
+		rtr.ServeHTTP(rec, req)
+		v := rec.Body.String()
+		counts[v]++
+
+		res := http.Response{Header: rec.Header()}
+		cookies := res.Cookies()
+		if assert.Len(t, cookies, 1) {
+			assert.Equal(t, "exp_checkout", cookies[0].Name)
+			assert.Equal(t, v, cookies[0].Value)
+			assert.True(t, cookies[0].Secure)
+			assert.Equal(t, int(DefaultExperimentMaxAge.Seconds()),
+				cookies[0].MaxAge)
+		}
+	}
+	assert.Len(t, counts, 2)
+	assert.InDelta(t, 300, counts["a"], 50)
+	assert.Len(t, entries[0].Experiments, 1)
+	assert.NotEmpty(t, entries[0].Experiments["checkout"])
+	//-------------------- Another Test Case --------------------
+	for _, v := range []string{"a", "b"} {
+		rec, req, _ := request(http.MethodGet, "/checkout", nil)
+		req.AddCookie(&http.Cookie{Name: "exp_checkout", Value: v})
+		rtr.ServeHTTP(rec, req)
+		assert.Equal(t, v, rec.Body.String())
+		assert.Empty(t, rec.Header().Get("Set-Cookie"))
+		e := entries[len(entries)-1]
+		assert.Equal(t, map[string]string{"checkout": v}, e.Experiments)
+	}
+	//-------------------- Another Test Case --------------------
+	rec, req, _ := request(http.MethodGet, "/checkout", nil)
+	req.AddCookie(&http.Cookie{Name: "exp_checkout", Value: "c"})
+	rtr.ServeHTTP(rec, req)
+	assert.Contains(t, []string{"a", "b"}, rec.Body.String())
+	assert.NotEmpty(t, rec.Header().Get("Set-Cookie"))
+	//-------------------- Another Test Case --------------------
+	rec, req, _ = request(http.MethodGet, "/home", nil)
+	rtr.ServeHTTP(rec, req)
+	assert.Empty(t, rec.Body.String())
+	assert.Nil(t, entries[len(entries)-1].Experiments)
+	//-------------------- Another Test Case --------------------
+	assert.Panics(t, func() { rtr.Experiment("empty", nil) })
+	assert.Panics(t, func() {
+		rtr.Experiment("twice", nil,
+			Variant{Name: "a", Handler: View(variant)},
+			Variant{Name: "a", Handler: View(variant)})
+	})
+}
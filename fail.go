@@ -0,0 +1,47 @@
+package mux
+
+import (
+	"context"
+	"net/http"
+)
+
+// withFailHandlers method returns a copy of request that carries the fail
+// and "405 Method Not Allowed" handlers of this Router, so that sub-routers
+// without their own ones can use them.
+func (rtr *Router) withFailHandlers(r *http.Request) *http.Request {
+	ctx := r.Context()
+	if rtr.fail != nil {
+		ctx = context.WithValue(ctx, failKey, rtr.fail)
+	}
+	if rtr.methodNotAllowed != nil {
+		ctx = context.WithValue(ctx, methodNotAllowedKey, rtr.methodNotAllowed)
+	}
+	if ctx == r.Context() {
+		return r
+	}
+	return r.WithContext(ctx)
+}
+
+// failHandler method returns the fail handler of this Router, the inherited
+// one, or DefaultFailHandler.
+func (rtr *Router) failHandler(r *http.Request) http.Handler {
+	if rtr.fail != nil {
+		return rtr.fail
+	}
+	if h, ok := r.Context().Value(failKey).(http.Handler); ok {
+		return h
+	}
+	return DefaultFailHandler
+}
+
+// methodNotAllowedHandler method returns the "405 Method Not Allowed" handler
+// of this Router, the inherited one, or DefaultMethodNotAllowedHandler.
+func (rtr *Router) methodNotAllowedHandler(r *http.Request) http.Handler {
+	if rtr.methodNotAllowed != nil {
+		return rtr.methodNotAllowed
+	}
+	if h, ok := r.Context().Value(methodNotAllowedKey).(http.Handler); ok {
+		return h
+	}
+	return DefaultMethodNotAllowedHandler
+}
@@ -0,0 +1,55 @@
+package mux
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInheritedFail(t *testing.T) {
+	text := func(s string) View {
+		return func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(s))
+		}
+	}
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+
+	rtr := New().FailFunc(text("root 404")).
+		MethodNotAllowedFunc(text("root 405"))
+	api := rtr.Subrouter().PathPrefix("/api").FailFunc(text("api 404"))
+	api.Get("/users", noop)
+	v1 := api.Subrouter().PathPrefix("/v1")
+	v1.Get("/users", noop)
+	docs := rtr.Subrouter().PathPrefix("/docs").Fail(DefaultFailHandler)
+	docs.Get("/", noop)
+	admin := rtr.Subrouter().PathPrefix("/admin")
+	admin.Get("/", noop)
+
+	cases := []struct {
+		method string
+		path   string
+		body   string
+	}{
+		{http.MethodGet, "/missing", "root 404"},
+		{http.MethodGet, "/api/missing", "api 404"},
+		{http.MethodGet, "/api/v1/missing", "api 404"},
+		{http.MethodPost, "/api/v1/users", "root 405"},
+		{http.MethodGet, "/docs/missing", "404 page not found\n"},
+		{http.MethodGet, "/admin/missing", "root 404"},
+	}
+	for _, c := range cases {
+		rec, req, err := request(c.method, c.path, nil)
+		assert.NoError(t, err)
+		rtr.ServeHTTP(rec, req)
+		assert.Equal(t, c.body, rec.Body.String(), c.path)
+	}
+
+	// Nil restores inheritance.
+	api.FailFunc(nil)
+	rec, req, err := request(http.MethodGet, "/api/missing", nil)
+	assert.NoError(t, err)
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, "root 404", rec.Body.String())
+}
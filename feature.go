@@ -0,0 +1,141 @@
+package mux
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// FlagProvider tells whether feature flags are on for requests. Providers may
+// turn flags on for some tenants or users only, e.g. by IdentityOf.
+type FlagProvider interface {
+	FlagEnabled(r *http.Request, flag string) bool
+}
+
+// FlagProviderFunc is an adapter that allows the use of ordinary functions as
+// FlagProvider.
+type FlagProviderFunc func(r *http.Request, flag string) bool
+
+// FlagEnabled method calls the function itself. It ensures that
+// FlagProviderFunc implements the FlagProvider interface.
+func (f FlagProviderFunc) FlagEnabled(r *http.Request, flag string) bool {
+	return f(r, flag)
+}
+
+// StaticFlags is FlagProvider of flags that are on or off for all requests.
+// Missing flags are off.
+type StaticFlags map[string]bool
+
+// FlagEnabled method ensures that StaticFlags implements the FlagProvider
+// interface.
+func (flags StaticFlags) FlagEnabled(r *http.Request, flag string) bool {
+	return flags[flag]
+}
+
+// EnvFlags returns FlagProvider of flags set by environment variables named
+// after them: the prefix followed by the flag in upper case with characters
+// other than letters and digits replaced with underscores. For example, with
+// prefix "FEATURE_" flag "new-checkout" is on if FEATURE_NEW_CHECKOUT is
+// "true" or "1" (see strconv.ParseBool).
+func EnvFlags(prefix string) FlagProvider {
+	return FlagProviderFunc(func(r *http.Request, flag string) bool {
+		on, _ := strconv.ParseBool(os.Getenv(prefix + envName(flag)))
+		return on
+	})
+}
+
+// envName converts the flag to the name of environment variable.
+func envName(flag string) string {
+	return strings.Map(func(c rune) rune {
+		switch {
+		case c >= 'a' && c <= 'z':
+			return c - 'a' + 'A'
+		case c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+			return c
+		}
+		return '_'
+	}, flag)
+}
+
+// FlagEvaluator evaluates flags for a context, e.g. a user or a tenant, in the
+// manner of feature management services like LaunchDarkly. Adapt their
+// clients to it and pass them to EvaluatorFlags.
+type FlagEvaluator interface {
+	// BoolVariation returns the value of the flag for the context key, or
+	// the default value along with the error if the flag can't be
+	// evaluated.
+	BoolVariation(ctx context.Context, flag, key string, def bool) (bool,
+		error)
+}
+
+// EvaluatorFlags returns FlagProvider that evaluates flags with the evaluator
+// for the context key of the request returned by key. If key is nil, the
+// subject of the authenticated client is used (see IdentityOf). Flags that
+// can't be evaluated are off.
+func EvaluatorFlags(
+	ev FlagEvaluator, key func(r *http.Request) string,
+) FlagProvider {
+	if key == nil {
+		key = Username
+	}
+	return FlagProviderFunc(func(r *http.Request, flag string) bool {
+		on, err := ev.BoolVariation(r.Context(), flag, key(r), false)
+		return err == nil && on
+	})
+}
+
+// Flags method sets the provider of feature flags consulted by FeatureFilter
+// and FeatureEnabled within this Router and its sub-routers (unless they have
+// their own provider set).
+func (rtr *Router) Flags(p FlagProvider) *Router {
+	rtr.flags = p
+	return rtr
+}
+
+// withFlags returns a copy of request that carries Router's flag provider in
+// case it is set.
+func (rtr *Router) withFlags(r *http.Request) *http.Request {
+	if rtr.flags == nil {
+		return r
+	}
+	return r.WithContext(context.WithValue(r.Context(), flagsKey, rtr.flags))
+}
+
+// FeatureEnabled tells whether the feature flag is on for the request
+// according to the flag provider of the closest router that has one. Flags
+// are off if there is no provider.
+func FeatureEnabled(r *http.Request, flag string) bool {
+	p, ok := r.Context().Value(flagsKey).(FlagProvider)
+	return ok && p.FlagEnabled(r, flag)
+}
+
+// FeatureFilter takes care of filtering requests for which the feature flag is
+// on (see FeatureEnabled), so that routes can be turned on for some tenants or
+// users without code changes.
+type FeatureFilter struct {
+	Flag string
+}
+
+// NewFeatureFilter returns pointer to a new FeatureFilter of the flag.
+func NewFeatureFilter(flag string) *FeatureFilter {
+	return &FeatureFilter{flag}
+}
+
+// Match method returns boolean value that tells you whether given request
+// passed the filter. Also, *FeatureFilter implements the Filter interface
+// since it has this method.
+func (fil *FeatureFilter) Match(r *http.Request) bool {
+	return FeatureEnabled(r, fil.Flag)
+}
+
+// Feature method adds a FeatureFilter to the Router, so that it only accepts
+// requests for which the flag is on:
+//
+//	rtr.Flags(mux.EnvFlags("FEATURE_"))
+//	rtr.Get("/checkout", newCheckout).Feature("new-checkout")
+//	rtr.Get("/checkout", checkout)
+func (rtr *Router) Feature(flag string) *Router {
+	return rtr.Filter(NewFeatureFilter(flag))
+}
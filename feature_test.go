@@ -0,0 +1,76 @@
+package mux
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// betaEvaluator turns flags on for the "beta" user and fails for "broken"
+// flag.
+type betaEvaluator struct{}
+
+func (betaEvaluator) BoolVariation(
+	ctx context.Context, flag, key string, def bool,
+) (bool, error) {
+	if flag == "broken" {
+		return true, errors.New("flag is broken")
+	}
+	return key == "beta", nil
+}
+
+func TestFeature(t *testing.T) {
+	text := func(s string) View {
+		return func(w http.ResponseWriter, r *http.Request) {
+			Text(w, http.StatusOK, s)
+		}
+	}
+	rtr := New().Flags(StaticFlags{"new-checkout": true})
+	rtr.Get("/checkout", text("new")).Feature("new-checkout")
+	rtr.Get("/checkout", text("old"))
+	rtr.Get("/search", text("new")).Feature("new-search")
+	rtr.Get("/search", text("old"))
+	users := rtr.Subrouter().PathPrefix("/users").
+		Flags(EvaluatorFlags(betaEvaluator{}, func(r *http.Request) string {
+			return r.Header.Get("X-User")
+		}))
+	users.Get("/profile", text("new")).Feature("new-profile")
+	users.Get("/profile", text("old"))
+	users.Get("/broken", text("new")).Feature("broken")
+	users.Get("/broken", text("old"))
+
+	cases := []struct {
+		path, user, body string
+	}{
+		{"/checkout", "", "new"},
+		{"/search", "", "old"},
+		{"/users/profile", "beta", "new"},
+		{"/users/profile", "alice", "old"},
+		{"/users/broken", "beta", "old"},
+	}
+	for _, c := range cases {
+		rec, req, err := request(http.MethodGet, c.path, nil)
+		assert.NoError(t, err)
+		req.Header.Set("X-User", c.user)
+		rtr.ServeHTTP(rec, req)
+		assert.Equal(t, c.body, rec.Body.String(), c.path+" "+c.user)
+	}
+	//-------------------- Another Test Case --------------------
+	r, _ := http.NewRequest(http.MethodGet, "/", nil)
+	assert.False(t, FeatureEnabled(r, "new-checkout"))
+	assert.False(t, NewFeatureFilter("new-checkout").Match(r))
+}
+
+func TestEnvFlags(t *testing.T) {
+	t.Setenv("FEATURE_NEW_CHECKOUT", "true")
+	t.Setenv("FEATURE_DARK_MODE", "0")
+	flags := EnvFlags("FEATURE_")
+	r, _ := http.NewRequest(http.MethodGet, "/", nil)
+	assert.True(t, flags.FlagEnabled(r, "new-checkout"))
+	assert.True(t, flags.FlagEnabled(r, "New.Checkout"))
+	assert.False(t, flags.FlagEnabled(r, "dark-mode"))
+	assert.False(t, flags.FlagEnabled(r, "missing"))
+}
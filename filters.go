@@ -13,19 +13,66 @@ type Filter interface {
 	Match(*http.Request) bool
 }
 
+// MatcherFunc is an adapter that allows the use of ordinary functions as
+// filters.
+type MatcherFunc func(*http.Request) bool
+
+// Match method calls the function itself. It ensures that MatcherFunc
+// implements the Filter interface.
+func (f MatcherFunc) Match(r *http.Request) bool {
+	return f(r)
+}
+
+// And returns a filter that matches only if all of the given filters match.
+// An empty And is all-permissive.
+func And(filters ...Filter) Filter {
+	return MatcherFunc(func(r *http.Request) bool {
+		for _, f := range filters {
+			if !f.Match(r) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// Or returns a filter that matches if at least one of the given filters
+// matches. An empty Or never matches.
+func Or(filters ...Filter) Filter {
+	return MatcherFunc(func(r *http.Request) bool {
+		for _, f := range filters {
+			if f.Match(r) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// Not returns a filter that inverts the result of the given one.
+func Not(filter Filter) Filter {
+	return MatcherFunc(func(r *http.Request) bool {
+		return !filter.Match(r)
+	})
+}
+
 // Filters is a concrete type that contains fields for every possible filter
 // allowed on a Router. It ensures that only one filter of each type is used per
 // Router instance.
 type Filters struct {
 	Schemes    *SchemesFilter    // e.g. "http" or "https".
+	Host       *HostFilter       // e.g. "example.com".
 	Methods    *MethodsFilter    // e.g. "GET", "POST", "PUT", "DELETE", etc.
 	Path       *PathFilter       // e.g. "/home" or "/r/{sub:str}/{id:int}".
 	PathPrefix *PathPrefixFilter // e.g. "/api".
+	UserAgent  *UserAgentFilter  // e.g. "(?i)bot|crawler|spider".
+	ClientCert *ClientCertFilter // e.g. "^billing\.internal$".
+	Custom     []Filter          // Arbitrary user-defined filters.
 }
 
 // NewFilters returns pointer to an empty set of filters.
 func NewFilters() *Filters {
-	return &Filters{nil, nil, nil, nil}
+	return &Filters{nil, nil, nil, nil, nil, nil, nil, nil}
 }
 
 // Match method returns boolean value that tells you whether given request
@@ -44,6 +91,16 @@ func (fils *Filters) Match(r *http.Request) bool {
 			continue
 		}
 
+		// Slices of filters (e.g. Custom) must have every element matched.
+		if field.Kind() == reflect.Slice {
+			for _, filter := range field.Interface().([]Filter) {
+				if !filter.Match(r) {
+					return false
+				}
+			}
+			continue
+		}
+
 		// Type assertion to the Filter interface is needed.
 		filter := field.Interface().(Filter)
 
@@ -107,69 +164,208 @@ type PathFilter struct {
 	// matches the PathFilter.
 	Regexp *regexp.Regexp
 
+	// Strict tells whether Regexp is anchored to both ends of the path, so that
+	// only full paths match. For example, strict "/user" matches nothing but
+	// "/user", while the loose one also matches "/users" or "/log/user/thing".
+	Strict bool
+
 	// hasVars is a boolean flag that tells us whether this PathFilter had path
 	// variables in its template path.
 	hasVars bool
+
+	// validate is a boolean flag that tells us whether some of the variables
+	// can't be fully validated by Regexp, so they have to be parsed in order
+	// for the request to match (e.g. "2021-02-30" is not a valid date).
+	validate bool
+
+	// segments is the path template split by "/" with the leading slash
+	// dropped. It is precomputed so that requests are parsed without
+	// inspecting the template.
+	segments []pathSegment
 }
 
-// NewPathFilter returns pointer to a newly created PathFilter. It also ensures
-// that the first character in the uri is a forward-slash -- if it isn't there,
-// it will be inserted.
+// pathSegment is a segment of the PathFilter template.
+type pathSegment struct {
+	// name is the name of the variable; it is empty for static segments.
+	name string
+
+	// typ is the type of the variable (e.g. "int", "*" or a regex).
+	typ string
+
+	// convert converts values of the variable; nil means no conversion.
+	convert VarConverter
+}
+
+// NewPathFilter returns pointer to a newly created strict PathFilter that only
+// matches full paths. It also ensures that the first character in the uri is a
+// forward-slash -- if it isn't there, it will be inserted.
 func NewPathFilter(path string) *PathFilter {
+	return newPathFilter(path, true)
+}
+
+// NewLoosePathFilter returns pointer to a newly created PathFilter that is not
+// anchored, so it matches any path that contains a match of the template. This
+// is an escape hatch for those who rely on the legacy behaviour.
+func NewLoosePathFilter(path string) *PathFilter {
+	return newPathFilter(path, false)
+}
+
+// newPathFilter builds the PathFilter with given strictness. It panics if the
+// path template is invalid.
+func newPathFilter(path string, strict bool) *PathFilter {
+	fil, err := compilePathFilter(path, strict)
+	if err != nil {
+		panic(err.Error())
+	}
+	return fil
+}
+
+// compilePathFilter builds the PathFilter with given strictness. It returns an
+// error if the path template is invalid.
+func compilePathFilter(path string, strict bool) (*PathFilter, error) {
 	// Create a dummy PathFilter.
-	fil := &PathFilter{"", nil, false}
+	fil := &PathFilter{"", nil, strict, false, false, nil}
 
 	// Ensure that the leading slash is present in the path.
-	if []byte(path)[0] != '/' {
+	if path == "" {
+		return nil, fmt.Errorf("empty path")
+	}
+	if path[0] != '/' {
 		path = "/" + path
 	}
 	fil.Path = path
 
 	// Split path template by "/" and build an appropriate regular expression.
 	split := strings.Split(path, "/")[1:]
+	fil.segments = make([]pathSegment, len(split))
+	names := newSet()
 	var exp string
 
-	for _, e := range split {
-		if isVar(e) {
-			fil.hasVars = true
-
-			_, typ := varData(e)
-			sub := "/"
-			switch typ {
-			case "int":
-				sub = sub + `(-?[1-9]\d*|0)`
-
-			case "str":
-				sub = sub + `[a-zA-Z_]+`
-
-			case "nat":
-				sub = sub + `([1-9]\d*|0)`
+	for i, e := range split {
+		if !isVar(e) {
+			exp = exp + "/" + e
+			continue
+		}
+		fil.hasVars = true
 
-			default: // regex type
-				sub = sub + typ
+		name, typ, err := parseVar(e)
+		if err != nil {
+			return nil, err
+		}
+		if names.Has(name) {
+			return nil, fmt.Errorf("duplicate variable %s in path %s", name, path)
+		}
+		names.Add(name)
+		seg := pathSegment{name: name, typ: typ}
+
+		sub := "/"
+		switch typ {
+		case "*":
+			// Catch-all variable captures the rest of the path.
+			if i != len(split)-1 {
+				return nil, fmt.Errorf(
+					"catch-all variable must be the last in path %s", path,
+				)
+			}
+			sub = sub + `(.*)`
+
+		default: // named, enum or regex type
+			if vt, ok := lookupVarType(typ); ok {
+				fil.validate = fil.validate || vt.validate
+				seg.convert = vt.convert
+				sub = sub + "(" + vt.pattern + ")"
+			} else if values, ok := enumValues(typ); ok {
+				for i, v := range values {
+					values[i] = regexp.QuoteMeta(v)
+				}
+				sub = sub + "(" + strings.Join(values, "|") + ")"
+			} else {
+				// Group the regex, so that alternation stays within the
+				// segment and the anchors.
+				sub = sub + "(?:" + typ + ")"
 			}
-
-			exp = exp + sub
-		} else {
-			exp = exp + "/" + e
 		}
+
+		fil.segments[i] = seg
+		exp = exp + sub
 	}
 
-	// Try to compile generated regular expression. Panic if that fails.
+	// Anchor the expression so that it only matches full paths.
+	if strict {
+		exp = "^" + exp + "$"
+	}
+
+	// Try to compile generated regular expression.
 	regex, err := regexp.Compile(exp)
 	if err != nil {
-		panic(fmt.Sprintf("can't compile regex %s: %v", exp, err))
+		return nil, fmt.Errorf("can't compile regex %s: %v", exp, err)
 	}
 	fil.Regexp = regex
 
-	return fil
+	return fil, nil
 }
 
 // Match method returns boolean value that tells you whether given request
 // passed the filter. Also, *PathFilter implements the Filter interface since
 // it has this method.
 func (fil *PathFilter) Match(r *http.Request) bool {
-	return fil.Regexp.MatchString(r.URL.Path)
+	if !fil.Regexp.MatchString(r.URL.Path) {
+		return false
+	}
+	if fil.validate {
+		_, _, err := fil.parse(r)
+		return err == nil
+	}
+	return true
+}
+
+// parse method extracts path variables from the request and converts them to
+// their types. Raw values are returned as well. It returns an error if some of
+// the values can't be converted.
+func (fil *PathFilter) parse(
+	r *http.Request,
+) (vars map[string]interface{}, raw map[string]string, err error) {
+	vars = make(map[string]interface{})
+	raw = make(map[string]string)
+
+	// Slicing the first element away because it is always going to be an empty
+	// string since the first character is always a slash.
+	rsplit := strings.Split(r.URL.Path, "/")[1:]
+
+	// Linear pattern matching against the precomputed template segments. The
+	// exp is a request path field we want to match towards (e.g. "42").
+	for i, seg := range fil.segments {
+		// Skip all static segments. No need to validate them.
+		if seg.name == "" {
+			continue
+		}
+		if i >= len(rsplit) {
+			return nil, nil, fmt.Errorf("path %s is too short", r.URL.Path)
+		}
+		exp := rsplit[i]
+
+		switch seg.typ {
+		case "*":
+			raw[seg.name] = varValue(r, strings.Join(rsplit[i:], "/"))
+			vars[seg.name] = raw[seg.name]
+
+		default: // named, enum or regex type
+			raw[seg.name] = varValue(r, exp)
+			if seg.convert == nil {
+				vars[seg.name] = raw[seg.name]
+				break
+			}
+			v, err := seg.convert(raw[seg.name])
+			if err != nil {
+				return nil, nil, fmt.Errorf(
+					"invalid %s value %s: %v", seg.typ, exp, err,
+				)
+			}
+			vars[seg.name] = v
+		}
+	}
+
+	return vars, raw, nil
 }
 
 // PathPrefixFilter takes care of filtering requests by URL path prefix.
@@ -216,3 +412,122 @@ func (fil *SchemesFilter) Match(r *http.Request) bool {
 
 	return fil.Schemes.Has(scheme)
 }
+
+// HostFilter takes care of filtering requests by host (e.g. "example.com").
+// Hosts are compared case-insensitively and without port, so that the same
+// filter works behind proxies and on non-standard ports.
+type HostFilter struct {
+	Hosts set
+}
+
+// NewHostFilter function returns pointer to a custom HostFilter.
+func NewHostFilter(hosts ...string) *HostFilter {
+	fil := &HostFilter{newSet()}
+	for _, h := range hosts {
+		fil.Hosts.Add(strings.ToLower(h))
+	}
+	return fil
+}
+
+// Match method returns boolean value that tells you whether given request
+// passed the filter. Also, *HostFilter implements the Filter interface since
+// it has this method.
+func (fil *HostFilter) Match(r *http.Request) bool {
+	return fil.Hosts.Has(strings.ToLower(requestHost(r)))
+}
+
+// UserAgentFilter takes care of filtering requests by their User-Agent header.
+// It holds a list of compiled regular expressions and matches whenever at
+// least one of them matches the header value. This is useful when you want to
+// route known bots and crawlers to cached or simplified handlers:
+//
+//	rtr.Subrouter().
+//	    UserAgent(`(?i)bot|crawler|spider`).
+//	    Handler(cached)
+type UserAgentFilter struct {
+	Patterns []*regexp.Regexp
+}
+
+// NewUserAgentFilter returns pointer to a newly created UserAgentFilter. It
+// panics if any of the given patterns fails to compile.
+func NewUserAgentFilter(patterns ...string) *UserAgentFilter {
+	fil := &UserAgentFilter{make([]*regexp.Regexp, 0, len(patterns))}
+	for _, p := range patterns {
+		regex, err := regexp.Compile(p)
+		if err != nil {
+			panic(fmt.Sprintf("can't compile regex %s: %v", p, err))
+		}
+		fil.Patterns = append(fil.Patterns, regex)
+	}
+	return fil
+}
+
+// Match method returns boolean value that tells you whether given request
+// passed the filter. Also, *UserAgentFilter implements the Filter interface
+// since it has this method.
+func (fil *UserAgentFilter) Match(r *http.Request) bool {
+	ua := r.UserAgent()
+	for _, regex := range fil.Patterns {
+		if regex.MatchString(ua) {
+			return true
+		}
+	}
+	return false
+}
+
+// ClientCertFilter takes care of filtering requests by TLS client certificate.
+// It matches only those requests that carry a client certificate verified by
+// the server (see tls.Config.ClientAuth). If Patterns are given, the subject
+// common name or one of the subject alternative names of the leaf certificate
+// must also match at least one of them.
+type ClientCertFilter struct {
+	Patterns []*regexp.Regexp
+}
+
+// NewClientCertFilter returns pointer to a newly created ClientCertFilter. It
+// panics if any of the given patterns fails to compile.
+func NewClientCertFilter(patterns ...string) *ClientCertFilter {
+	fil := &ClientCertFilter{make([]*regexp.Regexp, 0, len(patterns))}
+	for _, p := range patterns {
+		regex, err := regexp.Compile(p)
+		if err != nil {
+			panic(fmt.Sprintf("can't compile regex %s: %v", p, err))
+		}
+		fil.Patterns = append(fil.Patterns, regex)
+	}
+	return fil
+}
+
+// Match method returns boolean value that tells you whether given request
+// passed the filter. Also, *ClientCertFilter implements the Filter interface
+// since it has this method.
+func (fil *ClientCertFilter) Match(r *http.Request) bool {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 {
+		return false
+	}
+	chain := r.TLS.VerifiedChains[0]
+	if len(chain) == 0 {
+		return false
+	}
+	if len(fil.Patterns) == 0 {
+		return true
+	}
+
+	// Collect every name the leaf certificate identifies itself with.
+	leaf := chain[0]
+	names := []string{leaf.Subject.CommonName}
+	names = append(names, leaf.DNSNames...)
+	names = append(names, leaf.EmailAddresses...)
+	for _, uri := range leaf.URIs {
+		names = append(names, uri.String())
+	}
+
+	for _, regex := range fil.Patterns {
+		for _, name := range names {
+			if name != "" && regex.MatchString(name) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -1,9 +1,13 @@
 package mux
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"fmt"
 	"net/http"
 	"testing"
+	"time"
 )
 
 func TestMethodsFilter(t *testing.T) {
@@ -133,6 +137,50 @@ func TestPathFilter(t *testing.T) {
 	}
 }
 
+func TestStrictPathFilter(t *testing.T) {
+	strict := NewPathFilter("/user")
+	loose := NewLoosePathFilter("/user")
+
+	for _, path := range []string{"/users", "/log/user/thing"} {
+		req, err := http.NewRequest(http.MethodGet, path, nil)
+		if err != nil {
+			t.Fatalf("can't create request: %v", err)
+		}
+		if strict.Match(req) {
+			t.Errorf("the strict PathFilter matched %s", path)
+		}
+		if !loose.Match(req) {
+			t.Errorf("the loose PathFilter did not match %s", path)
+		}
+	}
+	//-------------------- Another Test Case --------------------
+	rtr := New().Path("/user")
+	if !rtr.filters.Path.Strict {
+		t.Error("path filters are not strict by default")
+	}
+	rtr.StrictPath(false)
+	if rtr.filters.Path.Strict || rtr.filters.Path.Path != "/user" {
+		t.Error("StrictPath did not rebuild the path filter")
+	}
+	//-------------------- Another Test Case --------------------
+	state := NewPathFilter("/x/{s:open|closed}")
+	for path, match := range map[string]bool{
+		"/x/open":     true,
+		"/x/closed":   true,
+		"/x/opened":   false,
+		"/y/closed":   false,
+		"/x/y/closed": false,
+	} {
+		req, err := http.NewRequest(http.MethodGet, path, nil)
+		if err != nil {
+			t.Fatalf("can't create request: %v", err)
+		}
+		if state.Match(req) != match {
+			t.Errorf("the regex PathFilter matched %s: %v", path, !match)
+		}
+	}
+}
+
 func TestPathFilterVars(t *testing.T) {
 	rtr := New().Path("/r/{article:str}/{id:nat}").HandleFunc(
 		func(w http.ResponseWriter, r *http.Request) {
@@ -177,6 +225,143 @@ func TestPathFilterVars(t *testing.T) {
 	rtr.ServeHTTP(rec, req)
 }
 
+func TestFloatVar(t *testing.T) {
+	fil := NewPathFilter("/price/{p:float}")
+	for path, ok := range map[string]bool{
+		"/price/42":    true,
+		"/price/-4.20": true,
+		"/price/4.":    false,
+		"/price/abc":   false,
+	} {
+		req, err := http.NewRequest(http.MethodGet, path, nil)
+		if err != nil {
+			t.Fatalf("can't create request: %v", err)
+		}
+		if fil.Match(req) != ok {
+			t.Errorf("the PathFilter matched %s incorrectly", path)
+		}
+	}
+	//-------------------- Another Test Case --------------------
+	var price interface{}
+	rtr := New().Path("/price/{p:float}").HandleFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			vars, _ := Vars(r)
+			price = vars["p"]
+		},
+	)
+	rec, req, err := request(http.MethodGet, "/price/19.99", nil)
+	if err != nil {
+		t.Fatalf("can't create request: %v", err)
+	}
+	rtr.ServeHTTP(rec, req)
+	if price != 19.99 {
+		t.Errorf("got '%v'; expected '19.99'", price)
+	}
+}
+
+func TestBoolAndEnumVars(t *testing.T) {
+	var vars map[string]interface{}
+	rtr := New().Path("/issues/{status:enum(open,closed)}/{mine:bool}").
+		HandleFunc(func(w http.ResponseWriter, r *http.Request) {
+			vars, _ = Vars(r)
+		})
+
+	rec, req, err := request(http.MethodGet, "/issues/closed/1", nil)
+	if err != nil {
+		t.Fatalf("can't create request: %v", err)
+	}
+	rtr.ServeHTTP(rec, req)
+	if vars["status"] != "closed" || vars["mine"] != true {
+		t.Errorf("got '%v'; expected status 'closed' and mine 'true'", vars)
+	}
+	//-------------------- Another Test Case --------------------
+	fil := rtr.filters.Path
+	for path, ok := range map[string]bool{
+		"/issues/open/false":  true,
+		"/issues/opened/true": false,
+		"/issues/open/yes":    false,
+	} {
+		req, err := http.NewRequest(http.MethodGet, path, nil)
+		if err != nil {
+			t.Fatalf("can't create request: %v", err)
+		}
+		if fil.Match(req) != ok {
+			t.Errorf("the PathFilter matched %s incorrectly", path)
+		}
+	}
+}
+
+func TestTimeVars(t *testing.T) {
+	var vars map[string]interface{}
+	rtr := New().Path("/log/{day:date}/{ts:rfc3339}").HandleFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			vars, _ = Vars(r)
+		},
+	)
+
+	rec, req, err := request(
+		http.MethodGet, "/log/2021-03-14/2021-03-14T15:09:26.53Z", nil,
+	)
+	if err != nil {
+		t.Fatalf("can't create request: %v", err)
+	}
+	rtr.ServeHTTP(rec, req)
+	day := time.Date(2021, 3, 14, 0, 0, 0, 0, time.UTC)
+	ts := time.Date(2021, 3, 14, 15, 9, 26, 530000000, time.UTC)
+	if !day.Equal(vars["day"].(time.Time)) || !ts.Equal(vars["ts"].(time.Time)) {
+		t.Errorf("got '%v'; expected day '%v' and ts '%v'", vars, day, ts)
+	}
+	//-------------------- Another Test Case --------------------
+	fil := rtr.filters.Path
+	for path, ok := range map[string]bool{
+		"/log/2021-02-28/2021-02-28T00:00:00+03:00": true,
+		"/log/2021-02-30/2021-02-28T00:00:00Z":      false,
+		"/log/2021-02-28/2021-02-28T25:00:00Z":      false,
+		"/log/today/2021-02-28T00:00:00Z":           false,
+	} {
+		req, err := http.NewRequest(http.MethodGet, path, nil)
+		if err != nil {
+			t.Fatalf("can't create request: %v", err)
+		}
+		if fil.Match(req) != ok {
+			t.Errorf("the PathFilter matched %s incorrectly", path)
+		}
+	}
+}
+
+func TestCatchAllVar(t *testing.T) {
+	var rest interface{}
+	rtr := New()
+	rtr.Get("/files/{path:*}", func(w http.ResponseWriter, r *http.Request) {
+		vars, _ := Vars(r)
+		rest = vars["path"]
+	})
+
+	cases := map[string]string{
+		"/files/index.html":        "index.html",
+		"/files/css/app/theme.css": "css/app/theme.css",
+		"/files/":                  "",
+	}
+	for path, expected := range cases {
+		rest = nil
+		rec, req, err := request(http.MethodGet, path, nil)
+		if err != nil {
+			t.Fatalf("can't create request: %v", err)
+		}
+		rtr.ServeHTTP(rec, req)
+		if rest != expected {
+			t.Errorf("got '%v'; expected '%s'", rest, expected)
+		}
+	}
+	//-------------------- Another Test Case --------------------
+	defer func() {
+		if recover() == nil {
+			t.Error("catch-all variable in the middle of path did not panic")
+		}
+	}()
+	NewPathFilter("/files/{path:*}/edit")
+}
+
 func TestPathPrefixFilter(t *testing.T) {
 	api := New().PathPrefix("/api")
 	api.Subrouter().Path("/song/{id:int}").HandleFunc(
@@ -218,3 +403,162 @@ func TestSchemes(t *testing.T) {
 		t.Error("the SchemesFilter matched an incorrect path")
 	}
 }
+
+func TestUserAgentFilter(t *testing.T) {
+	fil := NewUserAgentFilter(`(?i)bot`, `crawler`)
+
+	req, err := http.NewRequest(http.MethodGet, "/", nil)
+	if err != nil {
+		t.Fatalf("can't create request: %v", err)
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (compatible; Googlebot/2.1)")
+	if !fil.Match(req) {
+		t.Error("the UserAgentFilter did not match a bot user agent")
+	}
+	//-------------------- Another Test Case --------------------
+	req.Header.Set("User-Agent", "Mozilla/5.0 (X11; Linux x86_64) Firefox/90.0")
+	if fil.Match(req) {
+		t.Error("the UserAgentFilter matched a human user agent")
+	}
+	//-------------------- Another Test Case --------------------
+	rtr := New()
+	rtr.Subrouter().UserAgent(`(?i)bot`).HandleFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, "cached")
+		},
+	)
+	rec, req, err := request(http.MethodGet, "/", nil)
+	if err != nil {
+		t.Fatalf("can't create request: %v", err)
+	}
+	req.Header.Set("User-Agent", "SomeBot/1.0")
+	rtr.ServeHTTP(rec, req)
+	if body := rec.Body.String(); body != "cached" {
+		t.Errorf("got '%s'; expected 'cached'", body)
+	}
+}
+
+func TestCustomFilters(t *testing.T) {
+	fils := NewFilters()
+	fils.Custom = append(fils.Custom,
+		MatcherFunc(func(r *http.Request) bool {
+			return r.Header.Get("X-Token") != ""
+		}),
+		MatcherFunc(func(r *http.Request) bool {
+			return r.URL.Query().Get("debug") == "1"
+		}),
+	)
+
+	req, err := http.NewRequest(http.MethodGet, "/?debug=1", nil)
+	if err != nil {
+		t.Fatalf("can't create request: %v", err)
+	}
+	if fils.Match(req) {
+		t.Error("the custom filters matched a request without the header")
+	}
+	req.Header.Set("X-Token", "secret")
+	if !fils.Match(req) {
+		t.Error("the custom filters did not match a correct request")
+	}
+	//-------------------- Another Test Case --------------------
+	rtr := New()
+	rtr.Subrouter().
+		MatcherFunc(func(r *http.Request) bool {
+			return r.Header.Get("X-Token") == "secret"
+		}).
+		HandleFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, "custom")
+		})
+	rec, req, err := request(http.MethodGet, "/", nil)
+	if err != nil {
+		t.Fatalf("can't create request: %v", err)
+	}
+	req.Header.Set("X-Token", "secret")
+	rtr.ServeHTTP(rec, req)
+	if body := rec.Body.String(); body != "custom" {
+		t.Errorf("got '%s'; expected 'custom'", body)
+	}
+}
+
+func TestFilterCombinators(t *testing.T) {
+	json := MatcherFunc(func(r *http.Request) bool {
+		return r.Header.Get("Content-Type") == "application/json"
+	})
+	header := MatcherFunc(func(r *http.Request) bool {
+		return r.Header.Get("X-Api") != ""
+	})
+	post := NewMethodsFilter(http.MethodPost)
+
+	req, err := http.NewRequest(http.MethodPost, "/", nil)
+	if err != nil {
+		t.Fatalf("can't create request: %v", err)
+	}
+	req.Header.Set("X-Api", "1")
+
+	if !Or(json, header).Match(req) {
+		t.Error("Or did not match when one of the filters matched")
+	}
+	if And(json, header).Match(req) {
+		t.Error("And matched when one of the filters did not match")
+	}
+	if !And(post, header).Match(req) {
+		t.Error("And did not match when all of the filters matched")
+	}
+	if !Not(json).Match(req) {
+		t.Error("Not did not invert a failed match")
+	}
+	if Or().Match(req) || !And().Match(req) {
+		t.Error("empty combinators behave incorrectly")
+	}
+}
+
+func TestClientCertFilter(t *testing.T) {
+	req, err := http.NewRequest(http.MethodGet, "https://foo.com/admin", nil)
+	if err != nil {
+		t.Fatalf("can't create request: %v", err)
+	}
+	if NewClientCertFilter().Match(req) {
+		t.Error("the ClientCertFilter matched a request without TLS")
+	}
+	//-------------------- Another Test Case --------------------
+	req.TLS = &tls.ConnectionState{}
+	if NewClientCertFilter().Match(req) {
+		t.Error("the ClientCertFilter matched a request without certificate")
+	}
+	//-------------------- Another Test Case --------------------
+	cert := &x509.Certificate{
+		Subject:  pkix.Name{CommonName: "billing"},
+		DNSNames: []string{"billing.internal"},
+	}
+	req.TLS.VerifiedChains = [][]*x509.Certificate{{cert}}
+	if !NewClientCertFilter().Match(req) {
+		t.Error("the ClientCertFilter did not match a verified certificate")
+	}
+	if !NewClientCertFilter(`^billing\.internal$`).Match(req) {
+		t.Error("the ClientCertFilter did not match a correct SAN")
+	}
+	if NewClientCertFilter(`^orders$`).Match(req) {
+		t.Error("the ClientCertFilter matched an incorrect subject")
+	}
+}
+
+func TestHostFilter(t *testing.T) {
+	fil := NewHostFilter("Example.com")
+	for _, c := range []struct {
+		host  string
+		match bool
+	}{
+		{"example.com", true},
+		{"EXAMPLE.COM:8080", true},
+		{"www.example.com", false},
+	} {
+		req, err := http.NewRequest(http.MethodGet, "/", nil)
+		if err != nil {
+			t.Fatalf("can't create request: %v", err)
+		}
+		req.Host = c.host
+		if fil.Match(req) != c.match {
+			t.Errorf("HostFilter.Match(%q) != %v", c.host, c.match)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package mux
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"reflect"
+)
+
+// Default limits used by BindForm.
+const (
+	// DefaultMaxFormBytes is the maximum size of the request body.
+	DefaultMaxFormBytes = 32 << 20
+
+	// DefaultMaxFormMemory is the maximum number of bytes of multipart
+	// file parts that are stored in memory; the rest is stored on disk.
+	DefaultMaxFormMemory = 10 << 20
+)
+
+// FormOptions configures BindForm.
+type FormOptions struct {
+	// MaxBytes is the maximum size of the request body. Zero means
+	// DefaultMaxFormBytes; negative values remove the limit.
+	MaxBytes int64
+
+	// MaxMemory is the maximum number of bytes of file parts stored in
+	// memory. Zero means DefaultMaxFormMemory.
+	MaxMemory int64
+
+	// MaxFileBytes is the maximum size of a single uploaded file. Zero means
+	// no limit other than MaxBytes.
+	MaxFileBytes int64
+}
+
+// errBodyTooLarge is returned by limitedBody once the limit is exceeded.
+var errBodyTooLarge = errors.New("request body too large")
+
+// fileHeaderType and fileHeadersType are the types of the fields that receive
+// uploaded files.
+var (
+	fileHeaderType  = reflect.TypeOf((*multipart.FileHeader)(nil))
+	fileHeadersType = reflect.TypeOf([]*multipart.FileHeader(nil))
+)
+
+// BindForm parses the body of the request as application/x-www-form-urlencoded
+// or multipart/form-data and populates the struct pointed to by dst with its
+// fields according to their `mux` tags, just like Bind does. Uploaded files
+// are bound to the fields of type *multipart.FileHeader or
+// []*multipart.FileHeader:
+//
+//	var form struct {
+//	    Title  string                `mux:"title"`
+//	    Public bool                  `mux:"public"`
+//	    Cover  *multipart.FileHeader `mux:"cover"`
+//	}
+//	err := mux.BindForm(r, &form, &mux.FormOptions{MaxFileBytes: 5 << 20})
+//
+// The returned errors are *HTTPError values: "415 Unsupported Media Type" for
+// other content types, "413 Request Entity Too Large" if the body or one of
+// the files is too large and "400 Bad Request" for malformed bodies and
+// conversion errors. Bound values are validated afterwards (see Validator). If
+// opts is nil, defaults are used.
+func BindForm(r *http.Request, dst interface{}, opts *FormOptions) error {
+	if opts == nil {
+		opts = &FormOptions{}
+	}
+	if err := parseForm(r, opts); err != nil {
+		return err
+	}
+
+	err := bindStruct(dst, func(field reflect.Value, param string) (bool, error) {
+		switch field.Type() {
+		case fileHeaderType, fileHeadersType:
+			return bindFiles(field, r.MultipartForm, param, opts.MaxFileBytes)
+		}
+		if values, ok := r.PostForm[param]; ok {
+			return true, bindStrings(field, values)
+		}
+		return false, nil
+	})
+	if err != nil {
+		return err
+	}
+	return validate(r, dst)
+}
+
+// parseForm parses the body of the request according to its content type and
+// the options.
+func parseForm(r *http.Request, opts *FormOptions) error {
+	limit := opts.MaxBytes
+	if limit == 0 {
+		limit = DefaultMaxFormBytes
+	}
+	if limit > 0 && r.Body != nil {
+		r.Body = &limitedBody{r.Body, limit}
+	}
+
+	memory := opts.MaxMemory
+	if memory == 0 {
+		memory = DefaultMaxFormMemory
+	}
+
+	var err error
+	ct, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch ct {
+	case "application/x-www-form-urlencoded":
+		err = r.ParseForm()
+	case "multipart/form-data":
+		err = r.ParseMultipartForm(memory)
+	default:
+		return NewHTTPError(http.StatusUnsupportedMediaType,
+			"unsupported content type %q", ct)
+	}
+
+	if errors.Is(err, errBodyTooLarge) {
+		return NewHTTPError(http.StatusRequestEntityTooLarge,
+			"request body must not be larger than %d bytes", limit)
+	}
+	if err != nil {
+		return &HTTPError{http.StatusBadRequest, err}
+	}
+	return nil
+}
+
+// bindFiles sets field to the uploaded files with given name.
+func bindFiles(
+	field reflect.Value, form *multipart.Form, param string, limit int64,
+) (bool, error) {
+	if form == nil || len(form.File[param]) == 0 {
+		return false, nil
+	}
+	files := form.File[param]
+	for _, fh := range files {
+		if limit > 0 && fh.Size > limit {
+			return true, NewHTTPError(http.StatusRequestEntityTooLarge,
+				"file %q must not be larger than %d bytes", fh.Filename, limit)
+		}
+	}
+	if field.Type() == fileHeaderType {
+		field.Set(reflect.ValueOf(files[0]))
+	} else {
+		field.Set(reflect.ValueOf(files))
+	}
+	return true, nil
+}
+
+// limitedBody is a request body that fails with errBodyTooLarge once more than
+// n bytes are read from it.
+type limitedBody struct {
+	io.ReadCloser
+	n int64
+}
+
+// Read method ensures that limitedBody implements the io.Reader interface.
+func (lb *limitedBody) Read(p []byte) (int, error) {
+	if lb.n < 0 {
+		return 0, errBodyTooLarge
+	}
+	if int64(len(p)) > lb.n+1 {
+		p = p[:lb.n+1]
+	}
+	n, err := lb.ReadCloser.Read(p)
+	lb.n -= int64(n)
+	if lb.n < 0 {
+		return n, errBodyTooLarge
+	}
+	return n, err
+}
@@ -0,0 +1,98 @@
+package mux
+
+import (
+	"bytes"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBindFormURLEncoded(t *testing.T) {
+	var form struct {
+		Title  string   `mux:"title"`
+		Public bool     `mux:"public"`
+		Tags   []string `mux:"tag"`
+	}
+
+	body := url.Values{
+		"title":  {"Hello"},
+		"public": {"on"},
+		"tag":    {"go", "mux"},
+	}.Encode()
+	req, err := http.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	assert.NoError(t, err)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	err = BindForm(req, &form, nil)
+	assert.Equal(t, http.StatusBadRequest, StatusOf(err))
+	//-------------------- Another Test Case --------------------
+	body = strings.Replace(body, "on", "true", 1)
+	req, err = http.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	assert.NoError(t, err)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	assert.NoError(t, BindForm(req, &form, nil))
+	assert.Equal(t, "Hello", form.Title)
+	assert.True(t, form.Public)
+	assert.Equal(t, []string{"go", "mux"}, form.Tags)
+	//-------------------- Another Test Case --------------------
+	req, err = http.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	assert.NoError(t, err)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	err = BindForm(req, &form, &FormOptions{MaxBytes: 8})
+	assert.Equal(t, http.StatusRequestEntityTooLarge, StatusOf(err))
+	//-------------------- Another Test Case --------------------
+	req, err = http.NewRequest(http.MethodPost, "/", strings.NewReader("{}"))
+	assert.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	err = BindForm(req, &form, nil)
+	assert.Equal(t, http.StatusUnsupportedMediaType, StatusOf(err))
+}
+
+func TestBindFormMultipart(t *testing.T) {
+	var form struct {
+		Title string                  `mux:"title"`
+		Cover *multipart.FileHeader   `mux:"cover"`
+		Pages []*multipart.FileHeader `mux:"page"`
+	}
+
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	mw.WriteField("title", "Book")
+	fw, _ := mw.CreateFormFile("cover", "cover.png")
+	fw.Write([]byte("png"))
+	for _, name := range []string{"1.txt", "2.txt"} {
+		fw, _ = mw.CreateFormFile("page", name)
+		fw.Write([]byte("page " + name))
+	}
+	mw.Close()
+	data := buf.Bytes()
+
+	newRequest := func() *http.Request {
+		req, err := http.NewRequest(
+			http.MethodPost, "/", bytes.NewReader(data),
+		)
+		assert.NoError(t, err)
+		req.Header.Set("Content-Type", mw.FormDataContentType())
+		return req
+	}
+
+	assert.NoError(t, BindForm(newRequest(), &form, nil))
+	assert.Equal(t, "Book", form.Title)
+	if assert.NotNil(t, form.Cover) {
+		assert.Equal(t, "cover.png", form.Cover.Filename)
+		f, err := form.Cover.Open()
+		assert.NoError(t, err)
+		content, _ := ioutil.ReadAll(f)
+		assert.Equal(t, "png", string(content))
+	}
+	assert.Len(t, form.Pages, 2)
+	//-------------------- Another Test Case --------------------
+	err := BindForm(newRequest(), &form, &FormOptions{MaxFileBytes: 4})
+	assert.Equal(t, http.StatusRequestEntityTooLarge, StatusOf(err))
+}
@@ -0,0 +1,134 @@
+package mux
+
+import (
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// GeoLocation is where an IP address is located.
+type GeoLocation struct {
+	// Country is ISO 3166-1 alpha-2 code of the country, e.g. "DE".
+	Country string
+
+	// Continent is two-letter code of the continent: "AF", "AN", "AS",
+	// "EU", "NA", "OC" or "SA".
+	Continent string
+}
+
+// GeoLookup looks up where IP addresses are located, e.g. in a GeoIP
+// database. Adapt databases to it and pass them to GeoFilter.
+type GeoLookup interface {
+	LookupGeo(ip netip.Addr) (GeoLocation, error)
+}
+
+// GeoLookupFunc is an adapter that allows the use of ordinary functions as
+// GeoLookup.
+type GeoLookupFunc func(ip netip.Addr) (GeoLocation, error)
+
+// LookupGeo method calls the function itself. It ensures that GeoLookupFunc
+// implements the GeoLookup interface.
+func (f GeoLookupFunc) LookupGeo(ip netip.Addr) (GeoLocation, error) {
+	return f(ip)
+}
+
+// MaxMindReader is the reader of MaxMind DB files, such as GeoLite2 Country,
+// implemented by *maxminddb.Reader of github.com/oschwald/maxminddb-golang.
+type MaxMindReader interface {
+	Lookup(ip net.IP, result interface{}) error
+}
+
+// MaxMindGeo returns GeoLookup that looks up IP addresses in MaxMind DB
+// files of GeoIP2 or GeoLite2 Country or City databases:
+//
+//	db, err := maxminddb.Open("GeoLite2-Country.mmdb")
+//	...
+//	geo := mux.MaxMindGeo(db)
+func MaxMindGeo(reader MaxMindReader) GeoLookup {
+	return GeoLookupFunc(func(ip netip.Addr) (GeoLocation, error) {
+		var record struct {
+			Country struct {
+				ISOCode string `maxminddb:"iso_code"`
+			} `maxminddb:"country"`
+			Continent struct {
+				Code string `maxminddb:"code"`
+			} `maxminddb:"continent"`
+		}
+		if err := reader.Lookup(ip.AsSlice(), &record); err != nil {
+			return GeoLocation{}, err
+		}
+		return GeoLocation{record.Country.ISOCode, record.Continent.Code}, nil
+	})
+}
+
+// GeoFilter takes care of filtering requests by where their clients are
+// located, so that region-restricted content can be routed. It matches the
+// requests from any of Countries or any of Continents; codes are compared
+// case-insensitively. Requests from unknown locations don't match. Use
+// RealIP if the server is behind a proxy.
+type GeoFilter struct {
+	Lookup     GeoLookup
+	Countries  []string
+	Continents []string
+}
+
+// NewGeoFilter returns pointer to a new GeoFilter of the countries, given as
+// ISO 3166-1 alpha-2 codes.
+func NewGeoFilter(lookup GeoLookup, countries ...string) *GeoFilter {
+	return &GeoFilter{Lookup: lookup, Countries: countries}
+}
+
+// NewContinentFilter returns pointer to a new GeoFilter of the continents,
+// given as two-letter codes (see GeoLocation).
+func NewContinentFilter(lookup GeoLookup, continents ...string) *GeoFilter {
+	return &GeoFilter{Lookup: lookup, Continents: continents}
+}
+
+// Match method returns boolean value that tells you whether given request
+// passed the filter. Also, *GeoFilter implements the Filter interface since it
+// has this method.
+func (fil *GeoFilter) Match(r *http.Request) bool {
+	ip := clientAddr(r)
+	if !ip.IsValid() {
+		return false
+	}
+	loc, err := fil.Lookup.LookupGeo(ip.Unmap())
+	if err != nil {
+		return false
+	}
+	return hasCode(fil.Countries, loc.Country) ||
+		hasCode(fil.Continents, loc.Continent)
+}
+
+// Country method adds a GeoFilter to the Router, so that it only accepts
+// requests from clients in the countries:
+//
+//	rtr.Subrouter().PathPrefix("/shows").Country(geo, "US", "CA").
+//	    Handler(shows)
+//	rtr.Subrouter().PathPrefix("/shows").Handler(unavailable)
+func (rtr *Router) Country(lookup GeoLookup, countries ...string) *Router {
+	return rtr.Filter(NewGeoFilter(lookup, countries...))
+}
+
+// Continent method adds a GeoFilter to the Router, so that it only accepts
+// requests from clients on the continents.
+func (rtr *Router) Continent(
+	lookup GeoLookup, continents ...string,
+) *Router {
+	return rtr.Filter(NewContinentFilter(lookup, continents...))
+}
+
+// hasCode tells whether the codes contain the code, compared
+// case-insensitively. Empty code is never contained.
+func hasCode(codes []string, code string) bool {
+	if code == "" {
+		return false
+	}
+	for _, c := range codes {
+		if strings.EqualFold(c, code) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,65 @@
+package mux
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeMaxMind is MaxMindReader of a few networks.
+type fakeMaxMind map[string]string
+
+func (db fakeMaxMind) Lookup(ip net.IP, result interface{}) error {
+	for network, record := range db {
+		_, ipnet, _ := net.ParseCIDR(network)
+		if ipnet.Contains(ip) {
+			return json.Unmarshal([]byte(record), result)
+		}
+	}
+	if ip.To4() == nil {
+		return errors.New("IPv6 lookup in IPv4-only database")
+	}
+	return nil
+}
+
+func TestGeoFilter(t *testing.T) {
+	geo := MaxMindGeo(fakeMaxMind{
+		"192.0.2.0/24": `{"Country": {"ISOCode": "DE"},
+			"Continent": {"Code": "EU"}}`,
+		"198.51.100.0/24": `{"Country": {"ISOCode": "CA"},
+			"Continent": {"Code": "NA"}}`,
+		"203.0.113.0/24": `{"Country": {"ISOCode": "JP"},
+			"Continent": {"Code": "AS"}}`,
+	})
+	text := func(s string) View {
+		return func(w http.ResponseWriter, r *http.Request) {
+			Text(w, http.StatusOK, s)
+		}
+	}
+	rtr := New()
+	rtr.Get("/shows", text("americas")).Country(geo, "us", "CA")
+	rtr.Get("/shows", text("europe")).Continent(geo, "EU")
+	rtr.Get("/shows", text("unavailable"))
+
+	cases := []struct {
+		ip, body string
+	}{
+		{"192.0.2.1", "europe"},
+		{"198.51.100.1", "americas"},
+		{"[::ffff:198.51.100.1]", "americas"},
+		{"203.0.113.1", "unavailable"},
+		{"10.0.0.1", "unavailable"},
+		{"[2001:db8::1]", "unavailable"},
+	}
+	for _, c := range cases {
+		rec, req, err := request(http.MethodGet, "/shows", nil)
+		assert.NoError(t, err)
+		req.RemoteAddr = c.ip + ":1234"
+		rtr.ServeHTTP(rec, req)
+		assert.Equal(t, c.body, rec.Body.String(), c.ip)
+	}
+}
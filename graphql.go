@@ -0,0 +1,281 @@
+package mux
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// DefaultMaxGraphQLBatch is the maximum number of operations in a batch unless
+// specified otherwise.
+const DefaultMaxGraphQLBatch = 10
+
+// GraphQLRequest is a GraphQL operation requested by the client.
+type GraphQLRequest struct {
+	// Query is the GraphQL document.
+	Query string `json:"query"`
+
+	// OperationName is the name of the operation to execute if the document
+	// contains several.
+	OperationName string `json:"operationName,omitempty"`
+
+	// Variables are the values of the variables of the operation.
+	Variables map[string]interface{} `json:"variables,omitempty"`
+
+	// Extensions are the extensions of the request, e.g. persisted query
+	// hashes.
+	Extensions map[string]interface{} `json:"extensions,omitempty"`
+
+	// Method is the HTTP method of the request. Executors should refuse to
+	// execute mutations requested with GET, since GET requests must not have
+	// side effects.
+	Method string `json:"-"`
+}
+
+// GraphQLResponse is the outcome of a GraphQL operation.
+type GraphQLResponse struct {
+	// Data is the result of the operation.
+	Data interface{} `json:"data,omitempty"`
+
+	// Errors are the errors that occurred during the operation.
+	Errors []*GraphQLError `json:"errors,omitempty"`
+
+	// Extensions are the extensions of the response, e.g. tracing data.
+	Extensions map[string]interface{} `json:"extensions,omitempty"`
+}
+
+// GraphQLError is an error of a GraphQL operation.
+type GraphQLError struct {
+	// Message describes the error.
+	Message string `json:"message"`
+
+	// Locations are the places in the document the error refers to.
+	Locations []GraphQLLocation `json:"locations,omitempty"`
+
+	// Path is the path of the response field the error occurred in.
+	Path []interface{} `json:"path,omitempty"`
+
+	// Extensions are additional details, e.g. the error code.
+	Extensions map[string]interface{} `json:"extensions,omitempty"`
+}
+
+// GraphQLLocation is a place in GraphQL document.
+type GraphQLLocation struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// GraphQLExecutor executes GraphQL operations, e.g. with a schema built by a
+// GraphQL library.
+type GraphQLExecutor interface {
+	Execute(ctx context.Context, req *GraphQLRequest) *GraphQLResponse
+}
+
+// GraphQLExecutorFunc is an adapter that allows the use of ordinary functions
+// as GraphQLExecutor.
+type GraphQLExecutorFunc func(
+	ctx context.Context, req *GraphQLRequest,
+) *GraphQLResponse
+
+// Execute method calls the function itself. It ensures that
+// GraphQLExecutorFunc implements the GraphQLExecutor interface.
+func (f GraphQLExecutorFunc) Execute(
+	ctx context.Context, req *GraphQLRequest,
+) *GraphQLResponse {
+	return f(ctx, req)
+}
+
+// GraphQLOptions configures the GraphQL endpoint.
+type GraphQLOptions struct {
+	// MaxBytes is the maximum size of the request body. Zero means
+	// DefaultMaxJSONBytes; negative values remove the limit.
+	MaxBytes int64
+
+	// MaxBatch is the maximum number of operations in a batch, i.e. a JSON
+	// array of requests. Zero means DefaultMaxGraphQLBatch; negative values
+	// disable batching.
+	MaxBatch int
+
+	// GraphiQL serves the GraphiQL IDE to browsers that open the endpoint,
+	// i.e. GET requests without query that accept HTML.
+	GraphiQL bool
+}
+
+// GraphQL method creates a sub-router that serves GraphQL operations at the
+// path with the executor, following the GraphQL over HTTP conventions:
+//
+//   - GET requests carry the operation in "query", "operationName",
+//     "variables" and "extensions" query parameters, the last two encoded as
+//     JSON;
+//   - POST requests carry it as a JSON object or as the document itself with
+//     "application/graphql" content type;
+//   - POST requests may carry a JSON array of operations (batch), which are
+//     executed in order and answered with an array of responses.
+//
+// Responses are encoded as JSON with "200 OK"; malformed requests are reported
+// with "400 Bad Request" through Error. If opts is nil, defaults are used:
+//
+//	rtr.GraphQL("/graphql", executor, &mux.GraphQLOptions{GraphiQL: true})
+func (rtr *Router) GraphQL(
+	path string, exec GraphQLExecutor, opts *GraphQLOptions,
+) *Router {
+	if opts == nil {
+		opts = &GraphQLOptions{}
+	}
+	maxBatch := opts.MaxBatch
+	if maxBatch == 0 {
+		maxBatch = DefaultMaxGraphQLBatch
+	}
+	jsonOpts := &JSONOptions{MaxBytes: opts.MaxBytes}
+
+	return rtr.Subrouter().
+		Methods(http.MethodGet, http.MethodPost).
+		Path(path).
+		HandleFunc(func(w http.ResponseWriter, r *http.Request) {
+			if opts.GraphiQL && wantsGraphiQL(r) {
+				write(w, http.StatusOK, ContentTypeHTML, []byte(graphiQL))
+				return
+			}
+			reqs, batch, err := graphQLRequests(r, jsonOpts, maxBatch)
+			if err != nil {
+				Error(w, r, err)
+				return
+			}
+			res := make([]*GraphQLResponse, len(reqs))
+			for i, req := range reqs {
+				req.Method = r.Method
+				res[i] = exec.Execute(r.Context(), req)
+			}
+			if batch {
+				JSON(w, http.StatusOK, res)
+			} else {
+				JSON(w, http.StatusOK, res[0])
+			}
+		})
+}
+
+// graphQLRequests parses the operations requested by the client and tells
+// whether they were requested as a batch.
+func graphQLRequests(
+	r *http.Request, opts *JSONOptions, maxBatch int,
+) (reqs []*GraphQLRequest, batch bool, err error) {
+	if r.Method == http.MethodGet {
+		req, err := graphQLQuery(r)
+		return []*GraphQLRequest{req}, false, err
+	}
+
+	typ, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if typ == "application/graphql" {
+		limit := opts.MaxBytes
+		if limit == 0 {
+			limit = DefaultMaxJSONBytes
+		}
+		body, err := readBody(r, limit)
+		if err != nil {
+			return nil, false, err
+		}
+		req, err := graphQLQuery(r)
+		if err != nil {
+			return nil, false, err
+		}
+		req.Query = string(body)
+		return []*GraphQLRequest{req}, false, nil
+	}
+
+	var raw json.RawMessage
+	if err := DecodeJSON(r, &raw, opts); err != nil {
+		return nil, false, err
+	}
+	raw = bytes.TrimSpace(raw)
+	if len(raw) > 0 && raw[0] == '[' {
+		if maxBatch < 0 {
+			return nil, true, NewHTTPError(http.StatusBadRequest,
+				"batching is not supported")
+		}
+		if err := json.Unmarshal(raw, &reqs); err != nil {
+			return nil, true, jsonError(err)
+		}
+		if len(reqs) == 0 || len(reqs) > maxBatch {
+			return nil, true, NewHTTPError(http.StatusBadRequest,
+				"batch must contain from 1 to %d operations", maxBatch)
+		}
+		batch = true
+	} else {
+		req := new(GraphQLRequest)
+		if err := json.Unmarshal(raw, req); err != nil {
+			return nil, false, jsonError(err)
+		}
+		reqs = []*GraphQLRequest{req}
+	}
+	for _, req := range reqs {
+		if req == nil || req.Query == "" && req.Extensions == nil {
+			return nil, batch, NewHTTPError(http.StatusBadRequest,
+				"query is missing")
+		}
+	}
+	return reqs, batch, nil
+}
+
+// graphQLQuery parses the operation from the query parameters.
+func graphQLQuery(r *http.Request) (*GraphQLRequest, error) {
+	q := r.URL.Query()
+	req := &GraphQLRequest{
+		Query:         q.Get("query"),
+		OperationName: q.Get("operationName"),
+	}
+	for name, dst := range map[string]*map[string]interface{}{
+		"variables":  &req.Variables,
+		"extensions": &req.Extensions,
+	} {
+		if v := q.Get(name); v != "" {
+			if err := json.Unmarshal([]byte(v), dst); err != nil {
+				return nil, NewHTTPError(http.StatusBadRequest,
+					"%s must be a JSON object", name)
+			}
+		}
+	}
+	if r.Method == http.MethodGet && req.Query == "" &&
+		req.Extensions == nil {
+		return nil, NewHTTPError(http.StatusBadRequest, "query is missing")
+	}
+	return req, nil
+}
+
+// wantsGraphiQL tells whether the request comes from a browser that opened
+// the endpoint.
+func wantsGraphiQL(r *http.Request) bool {
+	return r.Method == http.MethodGet && r.URL.Query().Get("query") == "" &&
+		strings.Contains(r.Header.Get("Accept"), "text/html")
+}
+
+// graphiQL is the page of GraphiQL IDE that sends operations to the path it
+// was served at.
+const graphiQL = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>GraphiQL</title>
+<link rel="stylesheet" href="https://unpkg.com/graphiql@3/graphiql.min.css">
+<style>body { margin: 0; height: 100vh; } #graphiql { height: 100vh; }</style>
+</head>
+<body>
+<div id="graphiql"></div>
+<script crossorigin
+  src="https://unpkg.com/react@18/umd/react.production.min.js"></script>
+<script crossorigin
+  src="https://unpkg.com/react-dom@18/umd/react-dom.production.min.js"></script>
+<script crossorigin
+  src="https://unpkg.com/graphiql@3/graphiql.min.js"></script>
+<script>
+ReactDOM.createRoot(document.getElementById("graphiql")).render(
+  React.createElement(GraphiQL, {
+    fetcher: GraphiQL.createFetcher({url: window.location.pathname}),
+  }),
+);
+</script>
+</body>
+</html>
+`
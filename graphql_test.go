@@ -0,0 +1,117 @@
+package mux
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// echoExecutor responds with the operation it got.
+var echoExecutor = GraphQLExecutorFunc(
+	func(ctx context.Context, req *GraphQLRequest) *GraphQLResponse {
+		if req.Query == "{ fail }" {
+			return &GraphQLResponse{Errors: []*GraphQLError{{
+				Message:   "no such field",
+				Locations: []GraphQLLocation{{1, 3}},
+			}}}
+		}
+		return &GraphQLResponse{Data: map[string]interface{}{
+			"query":     req.Query,
+			"operation": req.OperationName,
+			"variables": req.Variables,
+			"method":    req.Method,
+		}}
+	},
+)
+
+func TestGraphQL(t *testing.T) {
+	rtr := New()
+	rtr.GraphQL("/graphql", echoExecutor, &GraphQLOptions{
+		MaxBatch: 2,
+		GraphiQL: true,
+	})
+
+	q := url.Values{
+		"query":         {"query Q($id: ID) { user(id: $id) { name } }"},
+		"operationName": {"Q"},
+		"variables":     {`{"id":"1"}`},
+	}
+	cases := []struct {
+		method string
+		target string
+		typ    string
+		body   string
+		code   int
+		res    string
+	}{
+		{http.MethodGet, "/graphql?" + q.Encode(), "", "", http.StatusOK,
+			`{"data":{"method":"GET","operation":"Q","query":` +
+				`"query Q($id: ID) { user(id: $id) { name } }",` +
+				`"variables":{"id":"1"}}}`},
+		{http.MethodPost, "/graphql", "application/json",
+			`{"query":"{ me }","variables":{"x":1}}`, http.StatusOK,
+			`{"data":{"method":"POST","operation":"","query":"{ me }",` +
+				`"variables":{"x":1}}}`},
+		{http.MethodPost, "/graphql?operationName=A", "application/graphql",
+			"query A { me }", http.StatusOK,
+			`{"data":{"method":"POST","operation":"A",` +
+				`"query":"query A { me }","variables":null}}`},
+		{http.MethodPost, "/graphql", "application/json",
+			`[{"query":"{ a }"}, {"query":"{ fail }"}]`, http.StatusOK,
+			`[{"data":{"method":"POST","operation":"","query":"{ a }",` +
+				`"variables":null}},{"errors":[{"message":"no such field",` +
+				`"locations":[{"line":1,"column":3}]}]}]`},
+		{http.MethodPost, "/graphql", "application/json",
+			`[{"query":"{ a }"}, {"query":"{ b }"}, {"query":"{ c }"}]`,
+			http.StatusBadRequest, ""},
+		{http.MethodPost, "/graphql", "application/json", `{}`,
+			http.StatusBadRequest, ""},
+		{http.MethodPost, "/graphql", "application/json", `{"query":`,
+			http.StatusBadRequest, ""},
+		{http.MethodGet, "/graphql?query=x&variables=[]", "", "",
+			http.StatusBadRequest, ""},
+		{http.MethodGet, "/graphql", "", "", http.StatusBadRequest, ""},
+	}
+	for _, c := range cases {
+		var body io.Reader
+		if c.body != "" {
+			body = strings.NewReader(c.body)
+		}
+		rec, req, err := request(c.method, c.target, body)
+		assert.NoError(t, err)
+		if c.typ != "" {
+			req.Header.Set("Content-Type", c.typ)
+		}
+		rtr.ServeHTTP(rec, req)
+		assert.Equal(t, c.code, rec.Code, c.body)
+		if c.res != "" {
+			assert.JSONEq(t, c.res, rec.Body.String(), c.body)
+		}
+	}
+	//-------------------- Another Test Case --------------------
+	rec, req, _ := request(http.MethodGet, "/graphql", nil)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "GraphiQL.createFetcher")
+	//-------------------- Another Test Case --------------------
+	rtr = New()
+	rtr.GraphQL("/graphql", echoExecutor, &GraphQLOptions{MaxBatch: -1})
+	rec, req, _ = request(http.MethodPost, "/graphql",
+		strings.NewReader(`[{"query":"{ a }"}]`))
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "batching is not supported")
+	rec, req, _ = request(http.MethodGet, "/graphql", nil)
+	req.Header.Set("Accept", "text/html")
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	var res GraphQLResponse
+	assert.Error(t, json.Unmarshal(rec.Body.Bytes(), &res))
+}
@@ -0,0 +1,96 @@
+package mux
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// Group method creates a sub-router without filters and passes it to fn, so
+// that related routes can be declared in a block and share middleware or
+// settings:
+//
+//	rtr.Group(func(r *mux.Router) {
+//	    r.Use(auth)
+//	    r.Get("/profile", profile)
+//	    r.Post("/logout", logout)
+//	})
+//
+// Unlike ordinary sub-routers, group only matches requests that one of its
+// routes (or its handler, if set) can serve, so all other requests fall through
+// to the following siblings. The group is added to the routes only after fn
+// returns, so it is safe to add groups while the server is running. It returns
+// pointer to the group.
+func (rtr *Router) Group(fn func(r *Router)) *Router {
+	sub := rtr.detached()
+	sub.group = true
+	fn(sub)
+	return rtr.attach(sub)
+}
+
+// Route method creates a group (see Group) under the path prefix and passes it
+// to fn. The prefix is cut from the request path, so routes of the group use
+// paths relative to it:
+//
+//	rtr.Route("/api", func(r *mux.Router) {
+//	    r.Get("/", index)
+//	    r.Route("/users", func(r *mux.Router) {
+//	        r.Get("/", listUsers)
+//	        r.Get("/{id:int}", showUser)
+//	    })
+//	})
+//
+// The prefix is matched on segment boundaries: "/api" matches "/api/users",
+// but not "/apiary". Request path equal to the prefix reaches the group as "/".
+// Prefix must not contain path variables. It returns pointer to the group.
+func (rtr *Router) Route(prefix string, fn func(r *Router)) *Router {
+	prefix = strings.TrimSuffix(prefix, "/")
+	return rtr.Group(func(sub *Router) {
+		sub.PathPrefix(prefix).MatcherFunc(segmentPrefix(prefix))
+		fn(sub)
+	})
+}
+
+// accepts method tells whether the router matches the request. Disabled
+// routers never match. Groups match only if they have a handler or one of
+// their routes matches.
+func (rtr *Router) accepts(r *http.Request) bool {
+	if !rtr.Enabled() || !rtr.filters.Match(r) {
+		return false
+	}
+	if !rtr.group || rtr.handler != nil {
+		return true
+	}
+	r = rtr.trim(r)
+	if rtr.headFallback {
+		r = r.WithContext(context.WithValue(r.Context(), headFallbackKey, true))
+	}
+	if _, match := rtr.Match(r); match {
+		return true
+	}
+	_, _, match := rtr.matchHead(r)
+	return match
+}
+
+// trim method cuts router's path prefix (if set) from the request path. The
+// path it had before is remembered as the original one. Groups receive "/"
+// instead of empty path.
+func (rtr *Router) trim(r *http.Request) *http.Request {
+	if rtr.filters.PathPrefix == nil {
+		return r
+	}
+	r = withoutPrefix(withOriginalPath(r), string(*rtr.filters.PathPrefix))
+	if rtr.group && r.URL.Path == "" {
+		r = withPath(r, "/", "")
+	}
+	return r
+}
+
+// segmentPrefix returns a filter function that accepts paths that are equal to
+// the prefix or continue it with a new segment.
+func segmentPrefix(prefix string) func(r *http.Request) bool {
+	return func(r *http.Request) bool {
+		rest := strings.TrimPrefix(r.URL.Path, prefix)
+		return rest == "" || strings.HasPrefix(rest, "/")
+	}
+}
@@ -0,0 +1,105 @@
+package mux
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroup(t *testing.T) {
+	text := func(s string) View {
+		return func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(s))
+		}
+	}
+
+	var authorized int
+	rtr := New()
+	rtr.Group(func(r *Router) {
+		r.UseFunc(func(w http.ResponseWriter, r *http.Request) {
+			authorized++
+		})
+		r.Get("/profile", text("profile"))
+		r.Post("/logout", text("logout"))
+	})
+	rtr.Route("/api", func(r *Router) {
+		r.Get("/", text("api"))
+		r.Route("/users", func(r *Router) {
+			r.Get("/", text("users"))
+			r.Get("/{id:int}", text("user"))
+		})
+	})
+	rtr.Get("/apiary", text("bees"))
+	rtr.Get("/api/health", text("ok"))
+	rtr.Get("/about", text("about"))
+
+	cases := []struct {
+		method string
+		path   string
+		code   int
+		body   string
+	}{
+		{http.MethodGet, "/profile", http.StatusOK, "profile"},
+		{http.MethodPost, "/logout", http.StatusOK, "logout"},
+		{http.MethodGet, "/about", http.StatusOK, "about"},
+		{http.MethodGet, "/api", http.StatusOK, "api"},
+		{http.MethodGet, "/api/", http.StatusOK, "api"},
+		{http.MethodGet, "/api/users", http.StatusOK, "users"},
+		{http.MethodGet, "/api/users/42", http.StatusOK, "user"},
+		{http.MethodGet, "/api/health", http.StatusOK, "ok"},
+		{http.MethodGet, "/apiary", http.StatusOK, "bees"},
+		{http.MethodGet, "/api/missing", http.StatusNotFound, ""},
+		{http.MethodDelete, "/profile", http.StatusMethodNotAllowed, ""},
+		{http.MethodPost, "/api/users/42", http.StatusMethodNotAllowed, ""},
+	}
+	for _, c := range cases {
+		rec, req, err := request(c.method, c.path, nil)
+		assert.NoError(t, err)
+		rtr.ServeHTTP(rec, req)
+		assert.Equal(t, c.code, rec.Code, c.method+" "+c.path)
+		if c.body != "" {
+			assert.Equal(t, c.body, rec.Body.String(), c.method+" "+c.path)
+		}
+	}
+	assert.Equal(t, 2, authorized)
+
+	rec, req, err := request(http.MethodDelete, "/profile", nil)
+	assert.NoError(t, err)
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, "GET", rec.Header().Get("Allow"))
+}
+
+//-------------------- Another Test Case --------------------
+
+func TestRuntimeRegistration(t *testing.T) {
+	rtr := New()
+	rtr.Get("/ping", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("pong"))
+	})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			rec, req, err := request(http.MethodGet, "/ping", nil)
+			assert.NoError(t, err)
+			rtr.ServeHTTP(rec, req)
+			assert.Equal(t, "pong", rec.Body.String())
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		path := fmt.Sprintf("/plugins/%d", i)
+		rtr.Get(path, func(w http.ResponseWriter, r *http.Request) {})
+		rtr.Route(path+"/admin", func(r *Router) {
+			r.Get("/", func(w http.ResponseWriter, r *http.Request) {})
+		})
+	}
+	<-done
+
+	rec, req, err := request(http.MethodGet, "/plugins/42/admin", nil)
+	assert.NoError(t, err)
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
@@ -0,0 +1,98 @@
+package mux
+
+import (
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// GRPCFilter takes care of filtering gRPC requests: POST requests with
+// content type "application/grpc" or "application/grpc+<codec>" (e.g.
+// "application/grpc+proto").
+type GRPCFilter struct{}
+
+// Match method returns boolean value that tells you whether given request
+// passed the filter. Also, GRPCFilter implements the Filter interface since it
+// has this method.
+func (GRPCFilter) Match(r *http.Request) bool {
+	return IsGRPC(r)
+}
+
+// GRPCWebFilter takes care of filtering gRPC-Web requests: POST requests with
+// content type "application/grpc-web" or "application/grpc-web-text" (with
+// optional "+<codec>"), as well as CORS preflight requests that announce the
+// X-Grpc-Web header.
+type GRPCWebFilter struct{}
+
+// Match method returns boolean value that tells you whether given request
+// passed the filter. Also, GRPCWebFilter implements the Filter interface since
+// it has this method.
+func (GRPCWebFilter) Match(r *http.Request) bool {
+	return IsGRPCWeb(r)
+}
+
+// IsGRPC tells whether the request is a gRPC call.
+func IsGRPC(r *http.Request) bool {
+	if r.Method != http.MethodPost {
+		return false
+	}
+	typ := grpcContentType(r)
+	return typ == "application/grpc" ||
+		strings.HasPrefix(typ, "application/grpc+")
+}
+
+// IsGRPCWeb tells whether the request is a gRPC-Web call or its CORS
+// preflight request.
+func IsGRPCWeb(r *http.Request) bool {
+	if r.Method == http.MethodOptions {
+		return headerHasToken(r.Header, "Access-Control-Request-Headers",
+			"x-grpc-web")
+	}
+	if r.Method != http.MethodPost {
+		return false
+	}
+	typ := grpcContentType(r)
+	for _, prefix := range []string{
+		"application/grpc-web-text",
+		"application/grpc-web",
+	} {
+		if typ == prefix || strings.HasPrefix(typ, prefix+"+") {
+			return true
+		}
+	}
+	return false
+}
+
+// grpcContentType returns the media type of the request body in lower case.
+func grpcContentType(r *http.Request) string {
+	typ, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return ""
+	}
+	return typ
+}
+
+// GRPC method creates a sub-router that dispatches gRPC calls to h, e.g.
+// *grpc.Server of grpc-go, which implements http.Handler. The rest of the
+// requests go on to the other routes, so that gRPC and REST APIs share the
+// same port:
+//
+//	rtr.GRPC(grpcServer)
+//	rtr.Get("/users/{id:int}", showUser)
+//	mux.ServeTLS(":443", rtr, opts)
+//
+// gRPC requires HTTP/2, which Go servers only speak over TLS unless h2c.Enable
+// is set as ServeOptions.Server. Register it before catch-all routes (e.g.
+// Mount("/")) that would match gRPC paths, and keep middleware that alters
+// responses (e.g. Compress) away from it, since gRPC relies on trailers and
+// flushing.
+func (rtr *Router) GRPC(h http.Handler) *Router {
+	return rtr.Subrouter().Filter(GRPCFilter{}).Handler(h)
+}
+
+// GRPCWeb method creates a sub-router that dispatches gRPC-Web calls, along
+// with their CORS preflight requests, to h, e.g. a grpc-web wrapper of the
+// gRPC server. The rest of the requests go on to the other routes. See GRPC.
+func (rtr *Router) GRPCWeb(h http.Handler) *Router {
+	return rtr.Subrouter().Filter(GRPCWebFilter{}).Handler(h)
+}
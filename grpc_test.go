@@ -0,0 +1,71 @@
+package mux
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsGRPC(t *testing.T) {
+	cases := []struct {
+		method string
+		header map[string]string
+		grpc   bool
+		web    bool
+	}{
+		{http.MethodPost, map[string]string{
+			"Content-Type": "application/grpc"}, true, false},
+		{http.MethodPost, map[string]string{
+			"Content-Type": "Application/gRPC+proto"}, true, false},
+		{http.MethodGet, map[string]string{
+			"Content-Type": "application/grpc"}, false, false},
+		{http.MethodPost, map[string]string{
+			"Content-Type": "application/grpcx"}, false, false},
+		{http.MethodPost, map[string]string{
+			"Content-Type": "application/grpc-web+proto"}, false, true},
+		{http.MethodPost, map[string]string{
+			"Content-Type": "application/grpc-web-text"}, false, true},
+		{http.MethodOptions, map[string]string{
+			"Access-Control-Request-Headers": "content-type, x-grpc-web",
+		}, false, true},
+		{http.MethodPost, map[string]string{
+			"Content-Type": "application/json"}, false, false},
+	}
+	for _, c := range cases {
+		req, err := http.NewRequest(c.method, "/pkg.Service/Method", nil)
+		assert.NoError(t, err)
+		for k, v := range c.header {
+			req.Header.Set(k, v)
+		}
+		assert.Equal(t, c.grpc, IsGRPC(req), c.header)
+		assert.Equal(t, c.web, IsGRPCWeb(req), c.header)
+	}
+}
+
+func TestGRPC(t *testing.T) {
+	rtr := New()
+	rtr.GRPC(View(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("grpc"))
+	}))
+	rtr.GRPCWeb(View(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("grpc-web"))
+	}))
+	rtr.Subrouter().PathPrefix("/").HandleFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("rest"))
+		},
+	)
+
+	for typ, body := range map[string]string{
+		"application/grpc":     "grpc",
+		"application/grpc-web": "grpc-web",
+		"application/json":     "rest",
+	} {
+		rec, req, err := request(http.MethodPost, "/pkg.Service/Method", nil)
+		assert.NoError(t, err)
+		req.Header.Set("Content-Type", typ)
+		rtr.ServeHTTP(rec, req)
+		assert.Equal(t, body, rec.Body.String(), typ)
+	}
+}
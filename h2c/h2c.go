@@ -0,0 +1,26 @@
+// Use of this source code is governed by the Mozilla Public License Version 2.0
+// that can be found in the LICENSE file.
+
+/*
+Package h2c makes servers started by mux.Serve speak HTTP/2 without TLS
+("h2c"), e.g. to serve gRPC behind a load balancer that terminates TLS:
+
+	mux.Serve(":8080", rtr, &mux.ServeOptions{Server: h2c.Enable})
+
+Over TLS, HTTP/2 is enabled anyway.
+*/
+package h2c
+
+import (
+	"net/http"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+// Enable makes the server speak HTTP/2 without TLS along with HTTP/1. It is
+// meant to be passed as mux.ServeOptions.Server; call it from there if the
+// server needs other settings as well.
+func Enable(srv *http.Server) {
+	srv.Handler = h2c.NewHandler(srv.Handler, &http2.Server{})
+}
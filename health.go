@@ -0,0 +1,263 @@
+package mux
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultHealthTimeout is how long health checks may take unless specified
+// otherwise.
+const DefaultHealthTimeout = 5 * time.Second
+
+// HealthCheckFunc checks a part of the server or one of its dependencies,
+// e.g. pings the database. It returns an error if the part is unhealthy. It
+// should give up once the context is done.
+type HealthCheckFunc func(ctx context.Context) error
+
+// Health aggregates named health checks and serves them as probes the way
+// orchestrators like Kubernetes expect:
+//
+//   - liveness ("/healthz") tells whether the server works at all and has to
+//     be restarted otherwise. Keep dependencies out of it, so that an outage
+//     of the database doesn't restart all the servers;
+//   - readiness ("/readyz") tells whether the server can serve requests, so
+//     that it gets traffic only when it can;
+//   - startup ("/startupz") tells whether the server has started. Startup
+//     checks are only run until they pass; until then the server isn't ready.
+//
+// Health is safe for concurrent use.
+type Health struct {
+	mu       sync.RWMutex
+	live     []namedCheck
+	ready    []namedCheck
+	startup  []namedCheck
+	timeout  time.Duration
+	started  atomic.Bool
+	draining atomic.Bool
+}
+
+// namedCheck is a health check registered with Health.
+type namedCheck struct {
+	name  string
+	check HealthCheckFunc
+}
+
+// HealthReport is the outcome of health checks served as JSON by the probes.
+type HealthReport struct {
+	// Status is "ok" if all the checks passed and "fail" otherwise.
+	Status string `json:"status"`
+
+	// Checks are the outcomes of the individual checks by name.
+	Checks map[string]*HealthCheckReport `json:"checks,omitempty"`
+}
+
+// HealthCheckReport is the outcome of a single health check.
+type HealthCheckReport struct {
+	// Status is "ok" if the check passed and "fail" otherwise.
+	Status string `json:"status"`
+
+	// Error is the error message of the failed check.
+	Error string `json:"error,omitempty"`
+
+	// Duration is the time the check took, e.g. "1.2ms".
+	Duration string `json:"duration"`
+}
+
+// OK method tells whether all the checks passed.
+func (rep *HealthReport) OK() bool {
+	return rep.Status == "ok"
+}
+
+// NewHealth returns pointer to a new Health without checks.
+func NewHealth() *Health {
+	return &Health{timeout: DefaultHealthTimeout}
+}
+
+// Timeout method sets the time each check may take. Checks that take longer
+// fail. Zero means DefaultHealthTimeout.
+func (h *Health) Timeout(d time.Duration) *Health {
+	if d == 0 {
+		d = DefaultHealthTimeout
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.timeout = d
+	return h
+}
+
+// Liveness method registers a liveness check.
+func (h *Health) Liveness(name string, check HealthCheckFunc) *Health {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.live = append(h.live, namedCheck{name, check})
+	return h
+}
+
+// Readiness method registers a readiness check, e.g. a database ping.
+func (h *Health) Readiness(name string, check HealthCheckFunc) *Health {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.ready = append(h.ready, namedCheck{name, check})
+	return h
+}
+
+// Startup method registers a startup check, e.g. one that tells whether the
+// caches were warmed up.
+func (h *Health) Startup(name string, check HealthCheckFunc) *Health {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.startup = append(h.startup, namedCheck{name, check})
+	h.started.Store(false)
+	return h
+}
+
+// Drain method makes the server report that it isn't ready, so that it stops
+// getting new requests before it shuts down. Serve does that on shutdown (see
+// ServeOptions.Health).
+func (h *Health) Drain() {
+	h.draining.Store(true)
+}
+
+// CheckLiveness method runs the liveness checks.
+func (h *Health) CheckLiveness(ctx context.Context) *HealthReport {
+	h.mu.RLock()
+	checks := h.live
+	h.mu.RUnlock()
+	return h.run(ctx, checks)
+}
+
+// CheckStartup method runs the startup checks, unless they have passed
+// already.
+func (h *Health) CheckStartup(ctx context.Context) *HealthReport {
+	if h.started.Load() {
+		return &HealthReport{Status: "ok"}
+	}
+	h.mu.RLock()
+	checks := h.startup
+	h.mu.RUnlock()
+	rep := h.run(ctx, checks)
+	if rep.OK() {
+		h.started.Store(true)
+	}
+	return rep
+}
+
+// CheckReadiness method runs the readiness checks, as well as the startup
+// ones until they pass. The server isn't ready while it drains (see Drain).
+func (h *Health) CheckReadiness(ctx context.Context) *HealthReport {
+	h.mu.RLock()
+	checks := h.ready
+	if !h.started.Load() {
+		checks = append(h.startup[:len(h.startup):len(h.startup)], checks...)
+	}
+	h.mu.RUnlock()
+	rep := h.run(ctx, checks)
+	if rep.OK() && !h.started.Load() {
+		h.started.Store(true)
+	}
+	if h.draining.Load() {
+		rep.Status = "fail"
+		rep.Checks["draining"] = &HealthCheckReport{
+			Status:   "fail",
+			Error:    "server is shutting down",
+			Duration: time.Duration(0).String(),
+		}
+	}
+	return rep
+}
+
+// run method runs the checks concurrently and reports their outcome.
+func (h *Health) run(ctx context.Context, checks []namedCheck) *HealthReport {
+	h.mu.RLock()
+	timeout := h.timeout
+	h.mu.RUnlock()
+
+	rep := &HealthReport{
+		Status: "ok",
+		Checks: make(map[string]*HealthCheckReport, len(checks)),
+	}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, c := range checks {
+		wg.Add(1)
+		go func(c namedCheck) {
+			defer wg.Done()
+			res := runCheck(ctx, c.check, timeout)
+			mu.Lock()
+			defer mu.Unlock()
+			rep.Checks[c.name] = res
+			if res.Status != "ok" {
+				rep.Status = "fail"
+			}
+		}(c)
+	}
+	wg.Wait()
+	return rep
+}
+
+// runCheck runs the check with the timeout. Checks that ignore the context
+// are abandoned once it is done.
+func runCheck(
+	ctx context.Context, check HealthCheckFunc, timeout time.Duration,
+) *HealthCheckReport {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if v := recover(); v != nil {
+				done <- fmt.Errorf("panic: %v", v)
+			}
+		}()
+		done <- check(ctx)
+	}()
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = fmt.Errorf("timed out after %s", timeout)
+	}
+
+	res := &HealthCheckReport{
+		Status:   "ok",
+		Duration: time.Since(start).Round(time.Microsecond).String(),
+	}
+	if err != nil {
+		res.Status = "fail"
+		res.Error = err.Error()
+	}
+	return res
+}
+
+// Routes method registers the probes on the Router: "GET /healthz",
+// "GET /readyz" and "GET /startupz". They respond with HealthReport encoded
+// as JSON, with "200 OK" if all the checks passed and "503 Service
+// Unavailable" otherwise. Reports reveal the errors of the checks, so keep
+// the probes away from the public.
+func (h *Health) Routes(rtr *Router) *Router {
+	rtr.Get("/healthz", h.probe(h.CheckLiveness))
+	rtr.Get("/readyz", h.probe(h.CheckReadiness))
+	rtr.Get("/startupz", h.probe(h.CheckStartup))
+	return rtr
+}
+
+// probe method returns View that serves the report of the checks.
+func (h *Health) probe(
+	check func(ctx context.Context) *HealthReport,
+) View {
+	return func(w http.ResponseWriter, r *http.Request) {
+		rep := check(r.Context())
+		code := http.StatusOK
+		if !rep.OK() {
+			code = http.StatusServiceUnavailable
+		}
+		w.Header().Set("Cache-Control", "no-store")
+		JSON(w, code, rep)
+	}
+}
@@ -0,0 +1,84 @@
+package mux
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealth(t *testing.T) {
+	dbErr := errors.New("connection refused")
+	warm := false
+	h := NewHealth().
+		Timeout(20*time.Millisecond).
+		Liveness("goroutines", func(ctx context.Context) error {
+			return nil
+		}).
+		Readiness("db", func(ctx context.Context) error {
+			return dbErr
+		}).
+		Readiness("slow", func(ctx context.Context) error {
+			<-ctx.Done()
+			return nil
+		}).
+		Startup("cache", func(ctx context.Context) error {
+			if !warm {
+				return errors.New("cache is cold")
+			}
+			return nil
+		})
+	rtr := New()
+	h.Routes(rtr.Subrouter().PathPrefix("/probes"))
+
+	probe := func(path string) (int, *HealthReport) {
+		rec, req, err := request(http.MethodGet, "/probes"+path, nil)
+		assert.NoError(t, err)
+		rtr.ServeHTTP(rec, req)
+		assert.Equal(t, "no-store", rec.Header().Get("Cache-Control"))
+		var rep HealthReport
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &rep))
+		return rec.Code, &rep
+	}
+
+	code, rep := probe("/healthz")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "ok", rep.Status)
+	assert.Equal(t, "ok", rep.Checks["goroutines"].Status)
+
+	code, rep = probe("/startupz")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "cache is cold", rep.Checks["cache"].Error)
+
+	code, rep = probe("/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "fail", rep.Status)
+	assert.Len(t, rep.Checks, 3)
+	assert.Equal(t, "connection refused", rep.Checks["db"].Error)
+	assert.Equal(t, "timed out after 20ms", rep.Checks["slow"].Error)
+
+	// Startup checks are not run once they pass.
+	warm = true
+	code, _ = probe("/startupz")
+	assert.Equal(t, http.StatusOK, code)
+	warm = false
+	code, _ = probe("/startupz")
+	assert.Equal(t, http.StatusOK, code)
+	_, rep = probe("/readyz")
+	assert.Len(t, rep.Checks, 2)
+
+	//-------------------- Another Test Case --------------------
+
+	h = NewHealth()
+	rep = h.CheckReadiness(context.Background())
+	assert.True(t, rep.OK())
+	h.Drain()
+	rep = h.CheckReadiness(context.Background())
+	assert.False(t, rep.OK())
+	assert.Equal(t, "server is shutting down", rep.Checks["draining"].Error)
+	assert.True(t, h.CheckLiveness(context.Background()).OK())
+}
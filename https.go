@@ -0,0 +1,112 @@
+package mux
+
+import (
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultHSTSMaxAge is how long browsers remember to use HTTPS only unless
+// specified otherwise.
+const DefaultHSTSMaxAge = 2 * 365 * 24 * time.Hour
+
+// HTTPSRedirect returns View that redirects requests to the same URL over
+// HTTPS, keeping host, path and query. The port is the port of the secure
+// server; empty or "443" means the default one, so that it is left out of the
+// URL. It is meant to serve plain HTTP (see HTTPSRedirectRouter).
+//
+// GET and HEAD requests are redirected with "301 Moved Permanently". Other
+// methods get "308 Permanent Redirect", so that clients don't turn them into
+// GET and drop the body.
+func HTTPSRedirect(port string) View {
+	if port == "443" {
+		port = ""
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		host := requestHost(r)
+		switch {
+		case port != "":
+			host = net.JoinHostPort(host, port)
+		case strings.Contains(host, ":"):
+			host = "[" + host + "]"
+		}
+		uri := r.RequestURI
+		if uri == "" || !strings.HasPrefix(uri, "/") {
+			uri = r.URL.RequestURI()
+		}
+
+		code := http.StatusPermanentRedirect
+		if r.Method == http.MethodGet || r.Method == http.MethodHead {
+			code = http.StatusMovedPermanently
+		}
+		w.Header().Set("Location", "https://"+host+uri)
+		w.WriteHeader(code)
+	}
+}
+
+// HTTPSRedirectRouter returns pointer to a new Router that redirects all the
+// requests to HTTPS (see HTTPSRedirect). Routes registered on it take
+// precedence, e.g. to serve ACME challenges or health probes over plain
+// HTTP:
+//
+//	go mux.Serve(":80", mux.HTTPSRedirectRouter(""), nil)
+//	mux.ServeTLS(":443", rtr, opts)
+func HTTPSRedirectRouter(port string) *Router {
+	return New().HandleFunc(HTTPSRedirect(port))
+}
+
+// HSTSOptions configures HSTS.
+type HSTSOptions struct {
+	// MaxAge is how long browsers remember to use HTTPS only. Zero means
+	// DefaultHSTSMaxAge; negative makes browsers forget the policy.
+	MaxAge time.Duration
+
+	// IncludeSubdomains applies the policy to all subdomains of the host.
+	IncludeSubdomains bool
+
+	// Preload allows browsers to ship the policy built in (see
+	// https://hstspreload.org). It requires IncludeSubdomains and MaxAge of
+	// at least a year.
+	Preload bool
+}
+
+// HSTS returns Middleware that sets the Strict-Transport-Security header, so
+// that browsers use nothing but HTTPS for the host once they have visited it:
+//
+//	rtr.HSTS(&mux.HSTSOptions{IncludeSubdomains: true})
+//
+// Register it on the secure server; browsers ignore the header received over
+// plain HTTP. If opts is nil, defaults are used.
+func HSTS(opts *HSTSOptions) Middleware {
+	if opts == nil {
+		opts = &HSTSOptions{}
+	}
+	maxAge := opts.MaxAge
+	if maxAge == 0 {
+		maxAge = DefaultHSTSMaxAge
+	}
+	if maxAge < 0 {
+		maxAge = 0
+	}
+	value := "max-age=" + strconv.FormatInt(int64(maxAge/time.Second), 10)
+	if opts.IncludeSubdomains {
+		value += "; includeSubDomains"
+	}
+	if opts.Preload {
+		value += "; preload"
+	}
+
+	return func(next http.Handler) http.Handler {
+		return View(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Strict-Transport-Security", value)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// HSTS method registers HSTS middleware on the Router. See HSTS.
+func (rtr *Router) HSTS(opts *HSTSOptions) *Router {
+	return rtr.Wrap(HSTS(opts))
+}
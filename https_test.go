@@ -0,0 +1,75 @@
+package mux
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPSRedirect(t *testing.T) {
+	cases := []struct {
+		method   string
+		host     string
+		target   string
+		port     string
+		code     int
+		location string
+	}{
+		{http.MethodGet, "example.com", "/a/b?c=d", "",
+			http.StatusMovedPermanently, "https://example.com/a/b?c=d"},
+		{http.MethodHead, "example.com:80", "/", "443",
+			http.StatusMovedPermanently, "https://example.com/"},
+		{http.MethodPost, "example.com:8080", "/form", "8443",
+			http.StatusPermanentRedirect, "https://example.com:8443/form"},
+		{http.MethodGet, "[::1]:80", "/%2F?q", "",
+			http.StatusMovedPermanently, "https://[::1]/%2F?q"},
+	}
+	for _, c := range cases {
+		rtr := HTTPSRedirectRouter(c.port)
+		rec, req, err := request(c.method, c.target, nil)
+		assert.NoError(t, err)
+		req.Host = c.host
+		req.RequestURI = c.target
+		rtr.ServeHTTP(rec, req)
+		assert.Equal(t, c.code, rec.Code, c.target)
+		assert.Equal(t, c.location, rec.Header().Get("Location"), c.target)
+	}
+	//-------------------- Another Test Case --------------------
+	rtr := HTTPSRedirectRouter("")
+	rtr.Get("/.well-known/acme-challenge/{token:segment}",
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("challenge"))
+		})
+	rec, req, err := request(http.MethodGet,
+		"/.well-known/acme-challenge/abc", nil)
+	assert.NoError(t, err)
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "challenge", rec.Body.String())
+}
+
+func TestHSTS(t *testing.T) {
+	cases := []struct {
+		opts   *HSTSOptions
+		header string
+	}{
+		{nil, "max-age=63072000"},
+		{&HSTSOptions{
+			MaxAge:            365 * 24 * time.Hour,
+			IncludeSubdomains: true,
+			Preload:           true,
+		}, "max-age=31536000; includeSubDomains; preload"},
+		{&HSTSOptions{MaxAge: -1}, "max-age=0"},
+	}
+	for _, c := range cases {
+		rtr := New().HSTS(c.opts)
+		rtr.Get("/", func(w http.ResponseWriter, r *http.Request) {})
+		rec, req, err := request(http.MethodGet, "/", nil)
+		assert.NoError(t, err)
+		rtr.ServeHTTP(rec, req)
+		assert.Equal(t, c.header,
+			rec.Header().Get("Strict-Transport-Security"))
+	}
+}
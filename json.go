@@ -0,0 +1,131 @@
+package mux
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+)
+
+// DefaultMaxJSONBytes is the maximum size of request body accepted by
+// DecodeJSON unless specified otherwise.
+const DefaultMaxJSONBytes = 1 << 20
+
+// JSONOptions configures DecodeJSON.
+type JSONOptions struct {
+	// MaxBytes is the maximum size of the request body. Zero means
+	// DefaultMaxJSONBytes; negative values remove the limit.
+	MaxBytes int64
+
+	// DisallowUnknownFields makes decoding fail if the body contains object
+	// keys that do not match any exported field of the destination.
+	DisallowUnknownFields bool
+
+	// RequireContentType makes decoding fail with "415 Unsupported Media
+	// Type" unless request's Content-Type is application/json.
+	RequireContentType bool
+}
+
+// DecodeJSON decodes JSON body of the request into dst. The returned errors
+// are *HTTPError values with appropriate status codes, so they can be passed
+// to Error as is:
+//
+//   - 400 Bad Request for malformed JSON, type mismatches, unknown fields (in
+//     strict mode) and trailing data;
+//   - 413 Request Entity Too Large if the body exceeds the limit;
+//   - 415 Unsupported Media Type if Content-Type is required but wrong.
+//
+// Decoded values are validated afterwards (see Validator); validation failures
+// are reported with "422 Unprocessable Entity". If opts is nil, defaults are
+// used.
+func DecodeJSON(r *http.Request, dst interface{}, opts *JSONOptions) error {
+	if err := decodeJSON(r, dst, opts); err != nil {
+		return err
+	}
+	return validate(r, dst)
+}
+
+// decodeJSON decodes JSON body of the request into dst like DecodeJSON, but
+// doesn't validate it.
+func decodeJSON(r *http.Request, dst interface{}, opts *JSONOptions) error {
+	if opts == nil {
+		opts = &JSONOptions{}
+	}
+
+	if opts.RequireContentType {
+		ct, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if ct != "application/json" {
+			return NewHTTPError(http.StatusUnsupportedMediaType,
+				"content type must be application/json")
+		}
+	}
+
+	if r.Body == nil || r.Body == http.NoBody {
+		return NewHTTPError(http.StatusBadRequest, "request body is empty")
+	}
+
+	var body io.Reader = r.Body
+	limit := opts.MaxBytes
+	if limit == 0 {
+		limit = DefaultMaxJSONBytes
+	}
+	if limit > 0 {
+		// Read one extra byte to tell whether the limit was exceeded.
+		body = io.LimitReader(r.Body, limit+1)
+	}
+	lr := &countingReader{body, 0}
+
+	dec := json.NewDecoder(lr)
+	if opts.DisallowUnknownFields {
+		dec.DisallowUnknownFields()
+	}
+
+	err := dec.Decode(dst)
+	if limit > 0 && lr.n > limit {
+		return NewHTTPError(http.StatusRequestEntityTooLarge,
+			"request body must not be larger than %d bytes", limit)
+	}
+	if err != nil {
+		return jsonError(err)
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return NewHTTPError(http.StatusBadRequest,
+			"request body must contain a single JSON value")
+	}
+	return nil
+}
+
+// jsonError converts JSON decoding error into *HTTPError with a message that
+// is safe to show to the client.
+func jsonError(err error) *HTTPError {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		return &HTTPError{http.StatusBadRequest, fmt.Errorf(
+			"malformed JSON at position %d", syntaxErr.Offset)}
+	case errors.As(err, &typeErr):
+		return &HTTPError{http.StatusBadRequest, fmt.Errorf(
+			"invalid value for field %q", typeErr.Field)}
+	case errors.Is(err, io.EOF):
+		return NewHTTPError(http.StatusBadRequest, "request body is empty")
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return NewHTTPError(http.StatusBadRequest, "malformed JSON")
+	}
+	return &HTTPError{http.StatusBadRequest, err}
+}
+
+// countingReader counts bytes read through it.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+// Read method ensures that countingReader implements the io.Reader interface.
+func (cr *countingReader) Read(p []byte) (int, error) {
+	n, err := cr.r.Read(p)
+	cr.n += int64(n)
+	return n, err
+}
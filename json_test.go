@@ -0,0 +1,51 @@
+package mux
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeJSON(t *testing.T) {
+	type user struct {
+		Name string `json:"name"`
+		Age  int    `json:"age"`
+	}
+
+	cases := []struct {
+		body string
+		opts *JSONOptions
+		code int
+	}{
+		{`{"name":"Viktor","age":21}`, nil, 0},
+		{`{"name":"Viktor","age":21,"x":1}`, nil, 0},
+		{`{"name":"Viktor","age":21,"x":1}`,
+			&JSONOptions{DisallowUnknownFields: true}, http.StatusBadRequest},
+		{`{"name":"Viktor",`, nil, http.StatusBadRequest},
+		{`{"name":"Viktor","age":"old"}`, nil, http.StatusBadRequest},
+		{`{"name":"Viktor"} {"name":"Alex"}`, nil, http.StatusBadRequest},
+		{``, nil, http.StatusBadRequest},
+		{`{"name":"` + strings.Repeat("a", 100) + `"}`,
+			&JSONOptions{MaxBytes: 64}, http.StatusRequestEntityTooLarge},
+		{`{"name":"Viktor"}`,
+			&JSONOptions{RequireContentType: true},
+			http.StatusUnsupportedMediaType},
+	}
+
+	for _, c := range cases {
+		req, err := http.NewRequest(
+			http.MethodPost, "/", strings.NewReader(c.body),
+		)
+		assert.NoError(t, err)
+		var u user
+		err = DecodeJSON(req, &u, c.opts)
+		if c.code == 0 {
+			assert.NoError(t, err, c.body)
+			assert.Equal(t, "Viktor", u.Name)
+		} else {
+			assert.Equal(t, c.code, StatusOf(err), c.body)
+		}
+	}
+}
@@ -0,0 +1,364 @@
+package mux
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// DefaultMaxRPCBatch is the maximum number of requests in a JSON-RPC batch
+// unless specified otherwise.
+const DefaultMaxRPCBatch = 100
+
+// Error codes defined by the JSON-RPC 2.0 specification. Codes from -32000 to
+// -32099 are reserved for implementation-defined server errors.
+const (
+	RPCParseError     = -32700
+	RPCInvalidRequest = -32600
+	RPCMethodNotFound = -32601
+	RPCInvalidParams  = -32602
+	RPCInternalError  = -32603
+)
+
+// RPCError is the error object of JSON-RPC response. Methods return it to
+// report errors with their own codes and data.
+type RPCError struct {
+	// Code is the error code, e.g. RPCInvalidParams.
+	Code int `json:"code"`
+
+	// Message describes the error.
+	Message string `json:"message"`
+
+	// Data carries additional details, if any.
+	Data interface{} `json:"data,omitempty"`
+}
+
+// NewRPCError returns pointer to an RPCError with given code and message
+// formatted according to the format specifier.
+func NewRPCError(code int, format string, a ...interface{}) *RPCError {
+	return &RPCError{Code: code, Message: fmt.Sprintf(format, a...)}
+}
+
+// Error method ensures that RPCError implements the error interface.
+func (e *RPCError) Error() string {
+	return e.Message
+}
+
+// JSONRPCOptions configures JSONRPC.
+type JSONRPCOptions struct {
+	// MaxBytes is the maximum size of the request body. Zero means
+	// DefaultMaxJSONBytes; negative values remove the limit.
+	MaxBytes int64
+
+	// MaxBatch is the maximum number of requests in a batch. Zero means
+	// DefaultMaxRPCBatch; negative values disable batching.
+	MaxBatch int
+}
+
+// JSONRPC is an http.Handler that serves JSON-RPC 2.0 requests sent with POST
+// by calling the methods registered under their names. It is mounted with
+// Router.JSONRPC.
+type JSONRPC struct {
+	opts JSONRPCOptions
+
+	mu      sync.RWMutex
+	methods map[string]*rpcMethod
+}
+
+// rpcMethod is a registered JSON-RPC method.
+type rpcMethod struct {
+	fn reflect.Value
+
+	// params is the type of the parameters or nil if the method takes none.
+	params reflect.Type
+}
+
+// rpcRequest is a JSON-RPC request object.
+type rpcRequest struct {
+	Version string          `json:"jsonrpc"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params"`
+
+	// ID is nil for notifications, which are answered with nothing.
+	ID json.RawMessage `json:"id"`
+}
+
+// rpcResponse is a JSON-RPC response object. Exactly one of Result and Error
+// is set.
+type rpcResponse struct {
+	Version string           `json:"jsonrpc"`
+	Result  *json.RawMessage `json:"result,omitempty"`
+	Error   *RPCError        `json:"error,omitempty"`
+	ID      json.RawMessage  `json:"id"`
+}
+
+var (
+	contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// NewJSONRPC returns pointer to a new JSONRPC without methods. If opts is nil,
+// defaults are used.
+func NewJSONRPC(opts *JSONRPCOptions) *JSONRPC {
+	rpc := &JSONRPC{methods: make(map[string]*rpcMethod)}
+	if opts != nil {
+		rpc.opts = *opts
+	}
+	if rpc.opts.MaxBytes == 0 {
+		rpc.opts.MaxBytes = DefaultMaxJSONBytes
+	}
+	if rpc.opts.MaxBatch == 0 {
+		rpc.opts.MaxBatch = DefaultMaxRPCBatch
+	}
+	return rpc
+}
+
+// Register method adds the method under the name. The method must be a
+// function with one of the signatures
+//
+//	func(ctx context.Context) (Result, error)
+//	func(ctx context.Context, params Params) (Result, error)
+//
+// where Params is the type the params of the request are decoded into as JSON
+// (e.g. a struct for named params or a slice for positional ones), and Result
+// is encoded as JSON into the result of the response. Decoded params are
+// validated (see Validator). The context is the one of the HTTP request.
+//
+// Errors returned as *RPCError are reported to the client as is; others are
+// logged and reported as internal errors, so that their details don't leak:
+//
+//	rpc := mux.NewJSONRPC(nil).
+//	    Register("sum", func(ctx context.Context, xs []int) (int, error) {
+//	        sum := 0
+//	        for _, x := range xs {
+//	            sum += x
+//	        }
+//	        return sum, nil
+//	    })
+//	rtr.JSONRPC("/rpc", rpc)
+//
+// It panics if the method has a wrong signature, if the name is taken, or if
+// it is reserved, i.e. starts with "rpc.".
+func (rpc *JSONRPC) Register(name string, method interface{}) *JSONRPC {
+	fn := reflect.ValueOf(method)
+	typ := fn.Type()
+	if typ.Kind() != reflect.Func || typ.NumIn() < 1 || typ.NumIn() > 2 ||
+		typ.In(0) != contextType || typ.NumOut() != 2 ||
+		typ.Out(1) != errorType || typ.IsVariadic() {
+		panic(fmt.Sprintf(
+			"can't register JSON-RPC method %q of type %s", name, typ))
+	}
+	if strings.HasPrefix(name, "rpc.") {
+		panic(fmt.Sprintf(
+			"can't register JSON-RPC method %q: the name is reserved", name))
+	}
+
+	m := &rpcMethod{fn: fn}
+	if typ.NumIn() == 2 {
+		m.params = typ.In(1)
+	}
+	rpc.mu.Lock()
+	defer rpc.mu.Unlock()
+	if _, ok := rpc.methods[name]; ok {
+		panic(fmt.Sprintf(
+			"can't register JSON-RPC method %q twice", name))
+	}
+	rpc.methods[name] = m
+	return rpc
+}
+
+// JSONRPC method creates a sub-router that serves the JSON-RPC methods with
+// POST requests to the path.
+func (rtr *Router) JSONRPC(path string, rpc *JSONRPC) *Router {
+	return rtr.Subrouter().Methods(http.MethodPost).Path(path).Handler(rpc)
+}
+
+// ServeHTTP method serves a single request or a batch. Responses are sent
+// with "200 OK" even for failed calls, as the protocol requires; requests that
+// consist of notifications only are answered with "204 No Content". It
+// ensures that JSONRPC implements the http.Handler interface.
+func (rpc *JSONRPC) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := readBody(r, rpc.opts.MaxBytes)
+	if err != nil {
+		Error(w, r, err)
+		return
+	}
+	body = bytes.TrimSpace(body)
+
+	if len(body) == 0 || body[0] != '[' {
+		if res := rpc.call(r, body); res != nil {
+			JSON(w, http.StatusOK, res)
+		} else {
+			NoContent(w)
+		}
+		return
+	}
+
+	var batch []json.RawMessage
+	if err := json.Unmarshal(body, &batch); err != nil {
+		JSON(w, http.StatusOK, rpcFailure(nil, RPCParseError, jsonError(err)))
+		return
+	}
+	switch {
+	case len(batch) == 0:
+		JSON(w, http.StatusOK, rpcFailure(nil, RPCInvalidRequest,
+			errors.New("batch is empty")))
+		return
+	case rpc.opts.MaxBatch < 0:
+		JSON(w, http.StatusOK, rpcFailure(nil, RPCInvalidRequest,
+			errors.New("batching is not supported")))
+		return
+	case len(batch) > rpc.opts.MaxBatch:
+		JSON(w, http.StatusOK, rpcFailure(nil, RPCInvalidRequest,
+			fmt.Errorf("batch must contain at most %d requests",
+				rpc.opts.MaxBatch)))
+		return
+	}
+	var res []*rpcResponse
+	for _, raw := range batch {
+		if resp := rpc.call(r, raw); resp != nil {
+			res = append(res, resp)
+		}
+	}
+	if len(res) == 0 {
+		NoContent(w)
+		return
+	}
+	JSON(w, http.StatusOK, res)
+}
+
+// call method parses the request object and calls its method. It returns nil
+// for notifications.
+func (rpc *JSONRPC) call(r *http.Request, raw json.RawMessage) *rpcResponse {
+	var req rpcRequest
+	if err := json.Unmarshal(raw, &req); err != nil {
+		var syntaxErr *json.SyntaxError
+		if len(raw) == 0 || errors.As(err, &syntaxErr) {
+			return rpcFailure(nil, RPCParseError, jsonError(err))
+		}
+		return rpcFailure(nil, RPCInvalidRequest,
+			errors.New("request must be an object"))
+	}
+	if !validRPCID(req.ID) {
+		return rpcFailure(nil, RPCInvalidRequest,
+			errors.New("id must be a string, a number or null"))
+	}
+	if req.Version != "2.0" || req.Method == "" {
+		return rpcFailure(req.ID, RPCInvalidRequest,
+			errors.New(`request must have "jsonrpc": "2.0" and method`))
+	}
+
+	rpc.mu.RLock()
+	m, ok := rpc.methods[req.Method]
+	rpc.mu.RUnlock()
+	if !ok {
+		return rpcResult(req.ID, nil, NewRPCError(RPCMethodNotFound,
+			"method %q not found", req.Method))
+	}
+	result, err := m.call(r, req.Params)
+	var rerr *RPCError
+	if err != nil && !errors.As(err, &rerr) {
+		logError(r, err)
+		rerr = NewRPCError(RPCInternalError, "internal error")
+	}
+	return rpcResult(req.ID, result, rerr)
+}
+
+// call method decodes the params and calls the method with them.
+func (m *rpcMethod) call(
+	r *http.Request, params json.RawMessage,
+) (interface{}, error) {
+	args := []reflect.Value{reflect.ValueOf(r.Context())}
+	if m.params != nil {
+		p := reflect.New(m.params)
+		if len(params) > 0 && !bytes.Equal(params, []byte("null")) {
+			if err := json.Unmarshal(params, p.Interface()); err != nil {
+				var typeErr *json.UnmarshalTypeError
+				if errors.As(err, &typeErr) && typeErr.Field == "" {
+					return nil, NewRPCError(RPCInvalidParams,
+						"params must be %s", jsonKind(m.params))
+				}
+				return nil, NewRPCError(RPCInvalidParams, "%s",
+					jsonError(err).Error())
+			}
+		}
+		if err := validate(r, p.Interface()); err != nil {
+			return nil, NewRPCError(RPCInvalidParams, "%s", err.Error())
+		}
+		args = append(args, p.Elem())
+	}
+	out := m.fn.Call(args)
+	if err, _ := out[1].Interface().(error); err != nil {
+		return nil, err
+	}
+	return out[0].Interface(), nil
+}
+
+// jsonKind describes the JSON values that the type is decoded from.
+func jsonKind(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	case reflect.Struct, reflect.Map:
+		return "an object"
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Ptr:
+		return jsonKind(t.Elem())
+	}
+	return "a number"
+}
+
+// rpcResult returns the response to the request with the ID, or nil if the
+// request is a notification. Results that can't be encoded are reported as
+// internal errors.
+func rpcResult(
+	id json.RawMessage, result interface{}, err *RPCError,
+) *rpcResponse {
+	if id == nil {
+		return nil
+	}
+	if err != nil {
+		return &rpcResponse{Version: "2.0", Error: err, ID: id}
+	}
+	b, merr := json.Marshal(result)
+	if merr != nil {
+		return rpcFailure(id, RPCInternalError,
+			errors.New("internal error"))
+	}
+	raw := json.RawMessage(b)
+	return &rpcResponse{Version: "2.0", Result: &raw, ID: id}
+}
+
+// rpcFailure returns the error response to the request with the ID. Nil ID
+// means that it couldn't be determined, and is sent as null.
+func rpcFailure(id json.RawMessage, code int, err error) *rpcResponse {
+	if id == nil {
+		id = json.RawMessage("null")
+	}
+	return &rpcResponse{
+		Version: "2.0",
+		Error:   &RPCError{Code: code, Message: err.Error()},
+		ID:      id,
+	}
+}
+
+// validRPCID tells whether the ID is absent, a string, a number or null.
+func validRPCID(id json.RawMessage) bool {
+	if id == nil {
+		return true
+	}
+	switch c := id[0]; {
+	case c == '"', c == '-', c >= '0' && c <= '9':
+		return true
+	}
+	return bytes.Equal(id, []byte("null"))
+}
@@ -0,0 +1,122 @@
+package mux
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONRPC(t *testing.T) {
+	rpc := NewJSONRPC(&JSONRPCOptions{MaxBatch: 3}).
+		Register("sum", func(ctx context.Context, xs []int) (int, error) {
+			sum := 0
+			for _, x := range xs {
+				sum += x
+			}
+			return sum, nil
+		}).
+		Register("signup", func(ctx context.Context, s signup) (string, error) {
+			return s.Email, nil
+		}).
+		Register("ping", func(ctx context.Context) (string, error) {
+			return "pong", nil
+		}).
+		Register("fail", func(ctx context.Context) (interface{}, error) {
+			return nil, &RPCError{Code: -32000, Message: "busy", Data: 5}
+		}).
+		Register("crash", func(ctx context.Context) (interface{}, error) {
+			return nil, errors.New("database is down")
+		})
+	rtr := New()
+	rtr.JSONRPC("/rpc", rpc)
+
+	cases := []struct {
+		body string
+		code int
+		res  string
+	}{
+		{`{"jsonrpc":"2.0","method":"sum","params":[1,2,3],"id":1}`,
+			http.StatusOK, `{"jsonrpc":"2.0","result":6,"id":1}`},
+		{`{"jsonrpc":"2.0","method":"ping","id":"a"}`,
+			http.StatusOK, `{"jsonrpc":"2.0","result":"pong","id":"a"}`},
+		{`{"jsonrpc":"2.0","method":"signup",` +
+			`"params":{"email":"me@example.com"},"id":null}`,
+			http.StatusOK,
+			`{"jsonrpc":"2.0","result":"me@example.com","id":null}`},
+		{`{"jsonrpc":"2.0","method":"signup","params":{"email":"me"},"id":2}`,
+			http.StatusOK, `{"jsonrpc":"2.0","id":2,` +
+				`"error":{"code":-32602,"message":"email is invalid"}}`},
+		{`{"jsonrpc":"2.0","method":"sum","params":{"x":1},"id":3}`,
+			http.StatusOK, `{"jsonrpc":"2.0","id":3,"error":{"code":-32602,` +
+				`"message":"params must be an array"}}`},
+		{`{"jsonrpc":"2.0","method":"fail","id":4}`, http.StatusOK,
+			`{"jsonrpc":"2.0","id":4,` +
+				`"error":{"code":-32000,"message":"busy","data":5}}`},
+		{`{"jsonrpc":"2.0","method":"crash","id":5}`, http.StatusOK,
+			`{"jsonrpc":"2.0","id":5,` +
+				`"error":{"code":-32603,"message":"internal error"}}`},
+		{`{"jsonrpc":"2.0","method":"nope","id":6}`, http.StatusOK,
+			`{"jsonrpc":"2.0","id":6,` +
+				`"error":{"code":-32601,` +
+				`"message":"method \"nope\" not found"}}`},
+		{`{"jsonrpc":"1.0","method":"ping","id":7}`, http.StatusOK,
+			`{"jsonrpc":"2.0","id":7,"error":{"code":-32600,` +
+				`"message":` +
+				`"request must have \"jsonrpc\": \"2.0\" and method"}}`},
+		{`{"jsonrpc":"2.0","method":"ping","id":{}}`, http.StatusOK,
+			`{"jsonrpc":"2.0","id":null,"error":{"code":-32600,` +
+				`"message":"id must be a string, a number or null"}}`},
+		{`{"jsonrpc":"2.0","method":`, http.StatusOK,
+			`{"jsonrpc":"2.0","id":null,` +
+				`"error":{"code":-32700,` +
+				`"message":"malformed JSON at position 26"}}`},
+		{`{"jsonrpc":"2.0","method":"ping"}`, http.StatusNoContent, ""},
+		{`[{"jsonrpc":"2.0","method":"ping","id":1},` +
+			`{"jsonrpc":"2.0","method":"ping"},` +
+			`{"jsonrpc":"2.0","method":"nope","id":2}]`, http.StatusOK,
+			`[{"jsonrpc":"2.0","result":"pong","id":1},` +
+				`{"jsonrpc":"2.0","id":2,` +
+				`"error":{"code":-32601,` +
+				`"message":"method \"nope\" not found"}}]`},
+		{`[1]`, http.StatusOK, `[{"jsonrpc":"2.0","id":null,` +
+			`"error":{"code":-32600,"message":"request must be an object"}}]`},
+		{`[{"jsonrpc":"2.0","method":"ping"}]`, http.StatusNoContent, ""},
+		{`[]`, http.StatusOK, `{"jsonrpc":"2.0","id":null,` +
+			`"error":{"code":-32600,"message":"batch is empty"}}`},
+		{`[1, 2, 3, 4]`, http.StatusOK, `{"jsonrpc":"2.0","id":null,` +
+			`"error":{"code":-32600,` +
+			`"message":"batch must contain at most 3 requests"}}`},
+	}
+	for _, c := range cases {
+		rec, req, err := request(http.MethodPost, "/rpc",
+			strings.NewReader(c.body))
+		assert.NoError(t, err)
+		rtr.ServeHTTP(rec, req)
+		assert.Equal(t, c.code, rec.Code, c.body)
+		if c.res != "" {
+			assert.JSONEq(t, c.res, rec.Body.String(), c.body)
+		}
+	}
+	//-------------------- Another Test Case --------------------
+	rec, req, _ := request(http.MethodGet, "/rpc", nil)
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	//-------------------- Another Test Case --------------------
+	assert.Panics(t, func() {
+		rpc.Register("ping", func(ctx context.Context) (int, error) {
+			return 0, nil
+		})
+	})
+	assert.Panics(t, func() {
+		rpc.Register("rpc.discover", func(ctx context.Context) (int, error) {
+			return 0, nil
+		})
+	})
+	assert.Panics(t, func() {
+		rpc.Register("bad", func(x int) error { return nil })
+	})
+}
@@ -0,0 +1,172 @@
+package mux
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// NegotiateLanguage returns the language from the list of supported ones that
+// suits the Accept-Language header of the request best, or an empty string if
+// none is acceptable. If the request has no Accept-Language header, the first
+// language is returned. Languages are language tags, e.g. "en", "en-GB" or
+// "de". Ranges that are more specific than supported languages fall back to
+// them ("de-AT" gets "de"), and less specific ranges accept their variants
+// ("en" accepts "en-GB").
+func NegotiateLanguage(r *http.Request, supported ...string) string {
+	header := r.Header.Values("Accept-Language")
+	if len(header) == 0 {
+		if len(supported) == 0 {
+			return ""
+		}
+		return supported[0]
+	}
+	return negotiateLanguage(strings.Join(header, ","), supported)
+}
+
+// negotiateLanguage returns the supported language that suits the
+// Accept-Language header value best, or an empty string if none is
+// acceptable.
+func negotiateLanguage(header string, supported []string) string {
+	// Language ranges look like media types without subtypes to parseAccept.
+	ranges := parseAccept(header)
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return ranges[i].q > ranges[j].q
+	})
+	for _, ar := range ranges {
+		if ar.q <= 0 {
+			break
+		}
+		if ar.typ == "*" && len(supported) > 0 {
+			return supported[0]
+		}
+		for tag := ar.typ; tag != ""; tag = truncateTag(tag) {
+			for _, lang := range supported {
+				if strings.EqualFold(lang, tag) {
+					return lang
+				}
+			}
+		}
+		for _, lang := range supported {
+			if strings.HasPrefix(strings.ToLower(lang), ar.typ+"-") {
+				return lang
+			}
+		}
+	}
+	return ""
+}
+
+// truncateTag cuts the last subtag from the language tag, e.g. "zh-Hant-TW"
+// becomes "zh-Hant". It returns an empty string if there is one subtag only.
+func truncateTag(tag string) string {
+	i := strings.LastIndexByte(tag, '-')
+	if i < 0 {
+		return ""
+	}
+	return tag[:i]
+}
+
+// Localize returns Middleware that negotiates the locale of the request among
+// the supported languages (see NegotiateLanguage), so that handlers get it
+// with Locale. Requests that accept none of them get the first one. Routes
+// declared with LocaleRoute override the negotiated locale with theirs:
+//
+//	rtr.Localize("en", "de", "fr")
+//	rtr.Get("/", func(w http.ResponseWriter, r *http.Request) {
+//	    mux.Render(w, r, mux.Locale(r)+"/index.html", nil)
+//	})
+//
+// It panics if there are no supported languages.
+func Localize(supported ...string) Middleware {
+	if len(supported) == 0 {
+		panic("can't negotiate locale without supported languages")
+	}
+	return func(next http.Handler) http.Handler {
+		return View(func(w http.ResponseWriter, r *http.Request) {
+			locale := NegotiateLanguage(r, supported...)
+			if locale == "" {
+				locale = supported[0]
+			}
+			w.Header().Add("Vary", "Accept-Language")
+			next.ServeHTTP(w, withLocale(r, locale))
+		})
+	}
+}
+
+// Localize method registers Localize middleware on the Router. See Localize.
+func (rtr *Router) Localize(supported ...string) *Router {
+	return rtr.Wrap(Localize(supported...))
+}
+
+// LocaleRoute method creates a group (see Route) under the path prefix named
+// after the locale, e.g. "/de", and passes it to fn. Requests served by the
+// group have the locale no matter what they accept:
+//
+//	for _, locale := range []string{"en", "de"} {
+//	    rtr.LocaleRoute(locale, func(r *mux.Router) {
+//	        r.Get("/about", about)
+//	    })
+//	}
+func (rtr *Router) LocaleRoute(locale string, fn func(r *Router)) *Router {
+	return rtr.Route("/"+locale, func(sub *Router) {
+		sub.Wrap(func(next http.Handler) http.Handler {
+			return View(func(w http.ResponseWriter, r *http.Request) {
+				next.ServeHTTP(w, withLocale(r, locale))
+			})
+		})
+		fn(sub)
+	})
+}
+
+// Locale returns the locale of the request set by Localize or LocaleRoute, or
+// an empty string if there is none.
+func Locale(r *http.Request) string {
+	locale, _ := r.Context().Value(localeKey).(string)
+	return locale
+}
+
+// withLocale returns a copy of request that carries the locale.
+func withLocale(r *http.Request, locale string) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), localeKey, locale))
+}
+
+// LanguageFilter takes care of filtering requests by their locale, so that
+// localized variants of routes can be served. If the locale of the request is
+// set (see Locale), it must be one of Languages. Otherwise, the request must
+// accept one of them in its Accept-Language header.
+type LanguageFilter struct {
+	Languages []string
+}
+
+// NewLanguageFilter returns pointer to a new LanguageFilter of the languages.
+func NewLanguageFilter(languages ...string) *LanguageFilter {
+	return &LanguageFilter{languages}
+}
+
+// Match method returns boolean value that tells you whether given request
+// passed the filter. Also, *LanguageFilter implements the Filter interface
+// since it has this method.
+func (fil *LanguageFilter) Match(r *http.Request) bool {
+	if locale := Locale(r); locale != "" {
+		for _, lang := range fil.Languages {
+			if strings.EqualFold(lang, locale) {
+				return true
+			}
+		}
+		return false
+	}
+	header := r.Header.Values("Accept-Language")
+	return len(header) > 0 &&
+		negotiateLanguage(strings.Join(header, ","), fil.Languages) != ""
+}
+
+// Language method adds a LanguageFilter to the Router, so that it only accepts
+// requests of the languages:
+//
+//	rtr.Localize("en", "de")
+//	rtr.Get("/", germanIndex).Language("de")
+//	rtr.Get("/", index)
+func (rtr *Router) Language(languages ...string) *Router {
+	return rtr.Filter(NewLanguageFilter(languages...))
+}
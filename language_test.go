@@ -0,0 +1,88 @@
+package mux
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiateLanguage(t *testing.T) {
+	supported := []string{"en", "en-GB", "de", "zh-Hant"}
+	cases := []struct {
+		header, lang string
+	}{
+		{"", "en"},
+		{"de", "de"},
+		{"DE-at", "de"},
+		{"en-GB,en;q=0.8", "en-GB"},
+		{"en-AU,en;q=0.8", "en"},
+		{"fr;q=0.9, de;q=0.5", "de"},
+		{"zh, en;q=0.1", "zh-Hant"},
+		{"zh-Hant-TW", "zh-Hant"},
+		{"fr, *;q=0.1", "en"},
+		{"de;q=0, fr", ""},
+		{"fr", ""},
+	}
+	for _, c := range cases {
+		_, req, err := request(http.MethodGet, "/", nil)
+		assert.NoError(t, err)
+		if c.header != "" {
+			req.Header.Set("Accept-Language", c.header)
+		}
+		assert.Equal(t, c.lang, NegotiateLanguage(req, supported...), c.header)
+	}
+}
+
+func TestLocalize(t *testing.T) {
+	locale := func(w http.ResponseWriter, r *http.Request) {
+		Text(w, http.StatusOK, Locale(r))
+	}
+	german := func(w http.ResponseWriter, r *http.Request) {
+		Text(w, http.StatusOK, "german "+Locale(r))
+	}
+	rtr := New().Localize("en", "de", "fr")
+	rtr.LocaleRoute("de", func(r *Router) {
+		r.Get("/about", locale)
+	})
+	rtr.Get("/about", locale)
+	rtr.Get("/", german).Language("de")
+	rtr.Get("/", locale)
+
+	cases := []struct {
+		path, header, body string
+	}{
+		{"/about", "", "en"},
+		{"/about", "fr-CA, de;q=0.5", "fr"},
+		{"/about", "es", "en"},
+		{"/de/about", "fr", "de"},
+		{"/", "de-CH", "german de"},
+		{"/", "fr, de;q=0.9", "fr"},
+	}
+	for _, c := range cases {
+		rec, req, err := request(http.MethodGet, c.path, nil)
+		assert.NoError(t, err)
+		if c.header != "" {
+			req.Header.Set("Accept-Language", c.header)
+		}
+		rtr.ServeHTTP(rec, req)
+		assert.Equal(t, c.body, rec.Body.String(), c)
+		assert.Equal(t, "Accept-Language", rec.Header().Get("Vary"))
+	}
+	//-------------------- Another Test Case --------------------
+	assert.Panics(t, func() { Localize() })
+}
+
+func TestLanguageFilter(t *testing.T) {
+	fil := NewLanguageFilter("de", "fr")
+	_, req, err := request(http.MethodGet, "/", nil)
+	assert.NoError(t, err)
+	assert.False(t, fil.Match(req))
+	req.Header.Set("Accept-Language", "en, fr-BE;q=0.5")
+	assert.True(t, fil.Match(req))
+	assert.False(t, fil.Match(withLocale(req, "en")))
+	//-------------------- Another Test Case --------------------
+	req.Header.Set("Accept-Language", "en")
+	assert.False(t, fil.Match(req))
+	assert.True(t, fil.Match(withLocale(req, "DE")))
+}
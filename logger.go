@@ -0,0 +1,121 @@
+package mux
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+)
+
+// discardLogger is returned by LoggerOf when no router has a logger.
+var discardLogger = slog.New(discardHandler{})
+
+// discardHandler is a slog.Handler that is never enabled.
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool { return false }
+
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+
+func (h discardHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+
+func (h discardHandler) WithGroup(string) slog.Handler { return h }
+
+// Logger method sets the logger used for internal events of this Router and
+// its sub-routers (unless they have their own logger set):
+//
+//   - shadowed routes are reported at warn level when routes are compiled;
+//   - requests that don't match any route are reported at debug level;
+//   - errors reported through Error are logged at debug level, or at error
+//     level if their code is 5xx;
+//   - panics caught by Recover with DefaultPanicHook are logged at error
+//     level.
+//
+// Without a logger, these events are silent.
+func (rtr *Router) Logger(l *slog.Logger) *Router {
+	rtr.logger = l
+	return rtr
+}
+
+// LoggerOf returns the logger of the closest router that has one, so that
+// handlers can log with it too. If there is none, it returns a logger that
+// discards everything.
+func LoggerOf(r *http.Request) *slog.Logger {
+	if l := logger(r); l != nil {
+		return l
+	}
+	return discardLogger
+}
+
+// logger returns the logger of the closest router that has one, or nil.
+func logger(r *http.Request) *slog.Logger {
+	l, _ := r.Context().Value(loggerKey).(*slog.Logger)
+	return l
+}
+
+// withLogger returns a copy of request that carries Router's logger in case it
+// is set.
+func (rtr *Router) withLogger(r *http.Request) *http.Request {
+	if rtr.logger == nil {
+		return r
+	}
+	return r.WithContext(context.WithValue(r.Context(), loggerKey, rtr.logger))
+}
+
+// treeLogger method returns the logger of this Router or of its closest parent
+// that has one. It is used outside of requests.
+func (rtr *Router) treeLogger() *slog.Logger {
+	for p := rtr; p != nil; p = p.parent {
+		if p.logger != nil {
+			return p.logger
+		}
+	}
+	return nil
+}
+
+// logShadowed method reports sub-routers that can never be matched because
+// the routes registered before them accept all their requests.
+func (rtr *Router) logShadowed(idx *routeIndex) {
+	l := rtr.treeLogger()
+	if l == nil {
+		return
+	}
+	prefixes := rtr.Info().Prefixes
+	routes := make([]*Router, len(idx.order))
+	for i, j := range idx.order {
+		routes[i] = idx.routes[j]
+	}
+	for j, b := range routes {
+		for _, a := range routes[:j] {
+			if a.shadows(b) {
+				l.Warn("mux: route is shadowed",
+					"route", describe(b.info(prefixes, 0)),
+					"by", describe(a.info(prefixes, 0)))
+				break
+			}
+		}
+	}
+}
+
+// logMismatch reports a request that didn't match any route.
+func logMismatch(r *http.Request, msg string) {
+	if l := logger(r); l != nil {
+		l.DebugContext(r.Context(), msg,
+			"method", r.Method, "path", originalPath(r))
+	}
+}
+
+// logError reports an error passed to Error.
+func logError(r *http.Request, err error) {
+	l := logger(r)
+	if l == nil {
+		return
+	}
+	code := StatusOf(err)
+	level := slog.LevelDebug
+	if code >= 500 {
+		level = slog.LevelError
+	}
+	l.Log(r.Context(), level, "mux: request failed",
+		"method", r.Method, "path", originalPath(r), "status", code,
+		"err", err)
+}
@@ -0,0 +1,74 @@
+package mux
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogger(t *testing.T) {
+	var buf bytes.Buffer
+	l := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey || a.Key == "stack" {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+
+	rtr := New().Logger(l).Wrap(Recover(nil))
+	api := rtr.Subrouter().PathPrefix("/api")
+	api.Get("/users", noop)
+	api.Get("/users", noop)
+	api.Get("/boom", func(w http.ResponseWriter, r *http.Request) {
+		Error(w, r, errors.New("db is down"))
+	})
+	api.Get("/panic", func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})
+	api.Get("/log", func(w http.ResponseWriter, r *http.Request) {
+		LoggerOf(r).Info("hello")
+	})
+
+	serve := func(method, path string) string {
+		buf.Reset()
+		rec, req, err := request(method, path, nil)
+		assert.NoError(t, err)
+		rtr.ServeHTTP(rec, req)
+		return buf.String()
+	}
+
+	out := serve(http.MethodGet, "/api/users")
+	assert.Contains(t, out, `level=WARN msg="mux: route is shadowed" `+
+		`route="GET /api/users (logger_test.go:29)" `+
+		`by="GET /api/users (logger_test.go:28)"`)
+	assert.Equal(t,
+		"level=DEBUG msg=\"mux: no route matched\" method=GET path=/missing\n",
+		serve(http.MethodGet, "/missing"))
+	assert.Equal(t,
+		"level=DEBUG msg=\"mux: method not allowed\" "+
+			"method=POST path=/api/users\n",
+		serve(http.MethodPost, "/api/users"))
+	assert.Equal(t,
+		"level=ERROR msg=\"mux: request failed\" method=GET path=/api/boom "+
+			"status=500 err=\"db is down\"\n",
+		serve(http.MethodGet, "/api/boom"))
+	assert.Contains(t, serve(http.MethodGet, "/api/panic"),
+		"level=ERROR msg=\"mux: panic\" method=GET path=/api/panic "+
+			"err=\"panic: boom\"\n")
+	assert.Equal(t, "level=INFO msg=hello\n", serve(http.MethodGet, "/api/log"))
+
+	//-------------------- Another Test Case --------------------
+
+	// Without a logger, LoggerOf discards everything.
+	_, req, err := request(http.MethodGet, "/", nil)
+	assert.NoError(t, err)
+	assert.False(t, LoggerOf(req).Enabled(req.Context(), slog.LevelError))
+}
@@ -0,0 +1,113 @@
+package mux
+
+import (
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultMaintenanceRetryAfter is the value of Retry-After header sent during
+// maintenance unless specified otherwise.
+const DefaultMaintenanceRetryAfter = 5 * time.Minute
+
+// MaintenanceOptions configures Maintenance.
+type MaintenanceOptions struct {
+	// RetryAfter is when clients should retry, sent in Retry-After header.
+	// Zero means DefaultMaintenanceRetryAfter; negative values omit the
+	// header.
+	RetryAfter time.Duration
+
+	// Allow lets the requests it matches through during maintenance, e.g.
+	// health checks and the admin panel:
+	//
+	//	mux.Or(
+	//	    mux.NewPathFilter("/healthz"),
+	//	    mux.NewIPFilter("10.0.0.0/8"),
+	//	)
+	Allow Filter
+}
+
+// Maintenance returns Middleware that answers requests with "503 Service
+// Unavailable" while enabled is set, so that the app can be switched to
+// maintenance mode at runtime:
+//
+//	var maintenance atomic.Bool
+//	rtr.Maintenance(&maintenance, page, &mux.MaintenanceOptions{
+//	    Allow: mux.NewIPFilter("10.0.0.0/8"),
+//	})
+//	...
+//	maintenance.Store(true)
+//
+// The maintenance page is rendered by h, which gets "503 Service
+// Unavailable" status no matter what status it writes. If h is nil, the error
+// is reported through Error, so that status handlers render it. Register it on
+// the root Router to cover all routes, or on sub-routers to cover some of
+// them. If opts is nil, defaults are used.
+func Maintenance(
+	enabled *atomic.Bool, h http.Handler, opts *MaintenanceOptions,
+) Middleware {
+	if opts == nil {
+		opts = &MaintenanceOptions{}
+	}
+	retryAfter := opts.RetryAfter
+	if retryAfter == 0 {
+		retryAfter = DefaultMaintenanceRetryAfter
+	}
+	value := ceilSeconds(retryAfter)
+
+	return func(next http.Handler) http.Handler {
+		return View(func(w http.ResponseWriter, r *http.Request) {
+			if !enabled.Load() || opts.Allow != nil && opts.Allow.Match(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if retryAfter > 0 {
+				w.Header().Set("Retry-After", value)
+			}
+			if h == nil {
+				Error(w, r, NewHTTPError(http.StatusServiceUnavailable,
+					"service is under maintenance"))
+				return
+			}
+			w.Header().Set("Cache-Control", "no-store")
+			h.ServeHTTP(&unavailableWriter{ResponseWriter: w}, r)
+		})
+	}
+}
+
+// Maintenance method registers Maintenance middleware on the Router. See
+// Maintenance.
+func (rtr *Router) Maintenance(
+	enabled *atomic.Bool, h http.Handler, opts *MaintenanceOptions,
+) *Router {
+	return rtr.Wrap(Maintenance(enabled, h, opts))
+}
+
+// unavailableWriter is http.ResponseWriter that responds with "503 Service
+// Unavailable" whatever status code it is given.
+type unavailableWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+}
+
+// WriteHeader method writes "503 Service Unavailable" status code instead of
+// the given one.
+func (uw *unavailableWriter) WriteHeader(int) {
+	if uw.wroteHeader {
+		return
+	}
+	uw.wroteHeader = true
+	uw.ResponseWriter.WriteHeader(http.StatusServiceUnavailable)
+}
+
+// Write method writes the status code before the data, if it wasn't written.
+func (uw *unavailableWriter) Write(p []byte) (int, error) {
+	uw.WriteHeader(http.StatusServiceUnavailable)
+	return uw.ResponseWriter.Write(p)
+}
+
+// Unwrap method returns the underlying http.ResponseWriter, so that
+// http.ResponseController can reach it.
+func (uw *unavailableWriter) Unwrap() http.ResponseWriter {
+	return uw.ResponseWriter
+}
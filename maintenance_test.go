@@ -0,0 +1,57 @@
+package mux
+
+import (
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaintenance(t *testing.T) {
+	ok := func(w http.ResponseWriter, r *http.Request) {
+		Text(w, http.StatusOK, "ok")
+	}
+	page := View(func(w http.ResponseWriter, r *http.Request) {
+		Text(w, http.StatusOK, "back soon")
+	})
+	var enabled atomic.Bool
+	rtr := New().Maintenance(&enabled, page, &MaintenanceOptions{
+		RetryAfter: 90 * time.Second,
+		Allow:      Or(NewPathFilter("/healthz"), NewIPFilter("10.0.0.0/8")),
+	})
+	rtr.Get("/", ok)
+	rtr.Get("/healthz", ok)
+	var apiEnabled atomic.Bool
+	api := rtr.Subrouter().PathPrefix("/api").
+		Maintenance(&apiEnabled, nil, &MaintenanceOptions{RetryAfter: -1})
+	api.Get("/users", ok)
+
+	serve := func(path, ip string) *http.Response {
+		rec, req, _ := request(http.MethodGet, path, nil)
+		req.RemoteAddr = ip + ":1234"
+		rtr.ServeHTTP(rec, req)
+		return rec.Result()
+	}
+	res := serve("/", "192.0.2.1")
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Empty(t, res.Header.Get("Retry-After"))
+	//-------------------- Another Test Case --------------------
+	enabled.Store(true)
+	res = serve("/", "192.0.2.1")
+	assert.Equal(t, http.StatusServiceUnavailable, res.StatusCode)
+	assert.Equal(t, "90", res.Header.Get("Retry-After"))
+	assert.Equal(t, "no-store", res.Header.Get("Cache-Control"))
+	assert.Equal(t, http.StatusOK, serve("/healthz", "192.0.2.1").StatusCode)
+	assert.Equal(t, http.StatusOK, serve("/", "10.0.0.1").StatusCode)
+	assert.Equal(t, http.StatusServiceUnavailable,
+		serve("/api/users", "192.0.2.1").StatusCode)
+	//-------------------- Another Test Case --------------------
+	enabled.Store(false)
+	apiEnabled.Store(true)
+	assert.Equal(t, http.StatusOK, serve("/", "192.0.2.1").StatusCode)
+	res = serve("/api/users", "10.0.0.1")
+	assert.Equal(t, http.StatusServiceUnavailable, res.StatusCode)
+	assert.Empty(t, res.Header.Get("Retry-After"))
+}
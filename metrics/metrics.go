@@ -0,0 +1,185 @@
+// Use of this source code is governed by the Mozilla Public License Version 2.0
+// that can be found in the LICENSE file.
+
+/*
+Package metrics records Prometheus metrics of requests served by mux routing
+trees.
+
+Requests are labeled with the method, the status code and the template of the
+route that served them (e.g. "/users/{id:int}"), rather than the raw path, so
+that the number of time series stays proportional to the number of routes:
+
+	m := metrics.New(nil)
+	rtr := mux.New().Wrap(m.Middleware())
+	rtr.Get("/users/{id:int}", showUser)
+	rtr.Get("/metrics", metrics.Handler().ServeHTTP)
+
+The following metrics are recorded:
+
+  - http_requests_total: counter of served requests;
+  - http_request_duration_seconds: histogram of request durations;
+  - http_request_size_bytes: histogram of request body sizes;
+  - http_response_size_bytes: histogram of response body sizes;
+  - http_requests_in_flight: gauge of requests being served.
+*/
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/sharpvik/mux"
+)
+
+// Unmatched is the route label of requests that weren't served by any route,
+// e.g. those that got "404 Not Found".
+const Unmatched = "unmatched"
+
+// Options configures Metrics.
+type Options struct {
+	// Registerer is where the metrics are registered. If nil,
+	// prometheus.DefaultRegisterer is used.
+	Registerer prometheus.Registerer
+
+	// Namespace is prepended to the names of the metrics, e.g. "shop" makes
+	// "shop_http_requests_total".
+	Namespace string
+
+	// DurationBuckets are the buckets of the duration histogram in seconds.
+	// If nil, prometheus.DefBuckets are used.
+	DurationBuckets []float64
+
+	// SizeBuckets are the buckets of the size histograms in bytes. If nil,
+	// buckets from 100B to 100MB growing tenfold are used.
+	SizeBuckets []float64
+
+	// ConstLabels are added to all the metrics, e.g. {"service": "shop"}.
+	ConstLabels prometheus.Labels
+}
+
+// Metrics records metrics of the requests. Create it with New.
+type Metrics struct {
+	requests     *prometheus.CounterVec
+	duration     *prometheus.HistogramVec
+	requestSize  *prometheus.HistogramVec
+	responseSize *prometheus.HistogramVec
+	inFlight     prometheus.Gauge
+}
+
+// New returns pointer to a new Metrics with its metrics registered. It panics
+// if they are registered already, e.g. by another Metrics with the same
+// Namespace and Registerer. If opts is nil, defaults are used.
+func New(opts *Options) *Metrics {
+	if opts == nil {
+		opts = &Options{}
+	}
+	reg := opts.Registerer
+	if reg == nil {
+		reg = prometheus.DefaultRegisterer
+	}
+	durations := opts.DurationBuckets
+	if durations == nil {
+		durations = prometheus.DefBuckets
+	}
+	sizes := opts.SizeBuckets
+	if sizes == nil {
+		sizes = prometheus.ExponentialBuckets(100, 10, 7)
+	}
+
+	labels := []string{"method", "route", "status"}
+	m := &Metrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   opts.Namespace,
+			Name:        "http_requests_total",
+			Help:        "Number of HTTP requests served.",
+			ConstLabels: opts.ConstLabels,
+		}, labels),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace:   opts.Namespace,
+			Name:        "http_request_duration_seconds",
+			Help:        "Time it took to serve HTTP requests.",
+			ConstLabels: opts.ConstLabels,
+			Buckets:     durations,
+		}, labels),
+		requestSize: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace:   opts.Namespace,
+			Name:        "http_request_size_bytes",
+			Help:        "Size of HTTP request bodies.",
+			ConstLabels: opts.ConstLabels,
+			Buckets:     sizes,
+		}, labels),
+		responseSize: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace:   opts.Namespace,
+			Name:        "http_response_size_bytes",
+			Help:        "Size of HTTP response bodies.",
+			ConstLabels: opts.ConstLabels,
+			Buckets:     sizes,
+		}, labels),
+		inFlight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace:   opts.Namespace,
+			Name:        "http_requests_in_flight",
+			Help:        "Number of HTTP requests being served.",
+			ConstLabels: opts.ConstLabels,
+		}),
+	}
+	reg.MustRegister(
+		m.requests, m.duration, m.requestSize, m.responseSize, m.inFlight,
+	)
+	return m
+}
+
+// Middleware method returns mux.Middleware that records the metrics. Register
+// it on the root router, so that it sees all the requests, including the ones
+// that didn't match any route.
+func (m *Metrics) Middleware() mux.Middleware {
+	return func(next http.Handler) http.Handler {
+		return mux.View(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			m.inFlight.Inc()
+			defer m.inFlight.Dec()
+
+			rw := mux.NewResponseWriter(w)
+			r = mux.RecordRoute(r)
+			next.ServeHTTP(rw, r)
+
+			route := Unmatched
+			if info := mux.MatchedRoute(r); info != nil {
+				route = info.Template()
+			}
+			labels := prometheus.Labels{
+				"method": method(r.Method),
+				"route":  route,
+				"status": strconv.Itoa(rw.Status()),
+			}
+			m.requests.With(labels).Inc()
+			m.duration.With(labels).Observe(time.Since(start).Seconds())
+			if r.ContentLength >= 0 {
+				m.requestSize.With(labels).Observe(float64(r.ContentLength))
+			}
+			m.responseSize.With(labels).Observe(float64(rw.Size()))
+		})
+	}
+}
+
+// Handler returns an http.Handler that exposes the metrics registered with
+// prometheus.DefaultRegisterer in the Prometheus text format. Use
+// promhttp.HandlerFor to expose metrics of a custom registry.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// method returns the method label of the request. Methods other than the
+// standard ones are reported as "OTHER", so that clients can't create new
+// time series by sending made up methods.
+func method(m string) string {
+	switch m {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut,
+		http.MethodPatch, http.MethodDelete, http.MethodConnect,
+		http.MethodOptions, http.MethodTrace:
+		return m
+	}
+	return "OTHER"
+}
@@ -0,0 +1,57 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/sharpvik/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m := New(&Options{Registerer: reg, Namespace: "test"})
+
+	rtr := mux.New().Wrap(m.Middleware())
+	rtr.Subrouter().PathPrefix("/api").
+		Get("/users/{id:int}", func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("user"))
+		})
+	rtr.Get("/metrics", promhttp.HandlerFor(reg, promhttp.HandlerOpts{}).
+		ServeHTTP)
+
+	for _, path := range []string{"/api/users/1", "/api/users/2", "/nope"} {
+		rtr.ServeHTTP(httptest.NewRecorder(),
+			httptest.NewRequest(http.MethodGet, path, nil))
+	}
+	rtr.ServeHTTP(httptest.NewRecorder(),
+		httptest.NewRequest("BREW", "/api/users/3", nil))
+
+	rec := httptest.NewRecorder()
+	rtr.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := rec.Body.String()
+	for _, line := range []string{
+		`test_http_requests_total{method="GET",route="/api/users/{id:int}",` +
+			`status="200"} 2`,
+		`test_http_requests_total{method="GET",route="unmatched",` +
+			`status="404"} 1`,
+		`test_http_requests_total{method="OTHER",route="unmatched",` +
+			`status="405"} 1`,
+		`test_http_response_size_bytes_sum{method="GET",` +
+			`route="/api/users/{id:int}",status="200"} 8`,
+		`test_http_request_duration_seconds_count{method="GET",` +
+			`route="/api/users/{id:int}",status="200"} 2`,
+		`test_http_requests_in_flight 1`,
+	} {
+		assert.Contains(t, body, line+"\n")
+	}
+	assert.False(t, strings.Contains(body, "/api/users/1"))
+
+	assert.Panics(t, func() {
+		New(&Options{Registerer: reg, Namespace: "test"})
+	})
+}
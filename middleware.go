@@ -0,0 +1,30 @@
+package mux
+
+import "net/http"
+
+// Middleware is a function that wraps an http.Handler. Unlike middleware
+// handlers registered with Use, which run one after another before the request
+// is routed, Middleware controls the rest of request handling: it may replace
+// the response writer or the request, act after the response is written, or
+// stop the request altogether by not calling next.
+type Middleware func(next http.Handler) http.Handler
+
+// Wrap method registers Middleware on the Router. It wraps the middleware
+// handlers registered with Use, the routing of the request and everything
+// that happens within sub-routers, so it sees the outcome of the whole
+// subtree:
+//
+//	rtr.Wrap(mux.Recover(nil))
+//
+// Middleware is applied in order of registration: the first one is the
+// outermost. It receives the request after path prefixes of this Router were
+// cut and path variables were parsed.
+func (rtr *Router) Wrap(mw ...Middleware) *Router {
+	rtr.wrappers = append(rtr.wrappers, mw...)
+	var h http.Handler = View(rtr.serve)
+	for i := len(rtr.wrappers) - 1; i >= 0; i-- {
+		h = rtr.wrappers[i](h)
+	}
+	rtr.wrapped = h
+	return rtr
+}
@@ -0,0 +1,83 @@
+package mux
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrap(t *testing.T) {
+	var trace []string
+	mw := func(name string) Middleware {
+		return func(next http.Handler) http.Handler {
+			return View(func(w http.ResponseWriter, r *http.Request) {
+				trace = append(trace, name+" in")
+				next.ServeHTTP(w, r)
+				trace = append(trace, name+" out")
+			})
+		}
+	}
+	deny := func(next http.Handler) http.Handler {
+		return View(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusForbidden)
+		})
+	}
+
+	rtr := New().Wrap(mw("a"), mw("b")).
+		UseFunc(func(w http.ResponseWriter, r *http.Request) {
+			trace = append(trace, "use")
+		})
+	rtr.Get("/users/{id:int}", func(w http.ResponseWriter, r *http.Request) {
+		trace = append(trace, "handler")
+	})
+	rtr.Subrouter().PathPrefix("/admin").Wrap(deny).
+		Get("/", func(w http.ResponseWriter, r *http.Request) {
+			t.Error("handler must not be reached")
+		})
+
+	rec, req, err := request(http.MethodGet, "/users/42", nil)
+	assert.NoError(t, err)
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t,
+		[]string{"a in", "b in", "use", "handler", "b out", "a out"}, trace)
+
+	//-------------------- Another Test Case --------------------
+
+	rec, req, err = request(http.MethodGet, "/admin/", nil)
+	assert.NoError(t, err)
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
+
+func TestUseStops(t *testing.T) {
+	rtr := New().
+		UseFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Checked", "yes")
+		}).
+		UseFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") == "" {
+				w.WriteHeader(http.StatusUnauthorized)
+			}
+		})
+	rtr.Get("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("secret"))
+	})
+
+	rec, req, err := request(http.MethodGet, "/", nil)
+	assert.NoError(t, err)
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Equal(t, "yes", rec.Header().Get("X-Checked"))
+	assert.Empty(t, rec.Body.String())
+
+	//-------------------- Another Test Case --------------------
+
+	rec, req, err = request(http.MethodGet, "/", nil)
+	assert.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer token")
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "secret", rec.Body.String())
+}
@@ -0,0 +1,66 @@
+package mux
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Mount method creates a sub-router that delegates every request under the
+// path prefix to h, regardless of method. The prefix is cut from the request
+// path, so h sees paths relative to the mount point, the way it would if it
+// was served on its own:
+//
+//	rtr.Mount("/debug/pprof", debug.Pprof())
+//	rtr.Mount("/legacy", legacyMux)
+//
+// Unlike the bare PathPrefix filter, Mount respects segment boundaries:
+// "/admin" matches "/admin" and "/admin/users", but not "/administrator".
+// Request path "/admin" reaches h as "/". The request passed to h is a copy, so
+// the original path is intact once h returns; use OriginalPath to see it from
+// within h.
+func (rtr *Router) Mount(prefix string, h http.Handler) *Router {
+	prefix = strings.TrimSuffix(prefix, "/")
+	return rtr.attach(rtr.detached().
+		PathPrefix(prefix).
+		MatcherFunc(segmentPrefix(prefix)).
+		Handler(mounted{h}))
+}
+
+// mounted wraps handler of the Mount route.
+type mounted struct {
+	http.Handler
+}
+
+// ServeHTTP method ensures that mounted handler always receives rooted path.
+func (m mounted) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "" {
+		r = withPath(r, "/", "")
+	}
+	m.Handler.ServeHTTP(w, r)
+}
+
+// OriginalPath returns the request URL path as it was before path prefixes
+// were cut from it by routers with PathPrefix filter (e.g. by Mount).
+func OriginalPath(r *http.Request) string {
+	return originalPath(r)
+}
+
+// withoutPrefix returns shallow copy of the request with prefix cut from its
+// URL path. The original request is left intact.
+func withoutPrefix(r *http.Request, prefix string) *http.Request {
+	rawPath := ""
+	if r.URL.RawPath != "" {
+		rawPath = strings.TrimPrefix(r.URL.RawPath, prefix)
+	}
+	return withPath(r, strings.TrimPrefix(r.URL.Path, prefix), rawPath)
+}
+
+// withPath returns shallow copy of the request with a copy of its URL that has
+// different path.
+func withPath(r *http.Request, path, rawPath string) *http.Request {
+	u := *r.URL
+	u.Path, u.RawPath = path, rawPath
+	r2 := r.WithContext(r.Context())
+	r2.URL = &u
+	return r2
+}
@@ -0,0 +1,65 @@
+package mux
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMount(t *testing.T) {
+	legacy := http.NewServeMux()
+	legacy.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Method + " " + r.URL.Path + " " + OriginalPath(r)))
+	})
+	legacy.HandleFunc("/users/", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("users " + r.URL.Path))
+	})
+
+	rtr := New()
+	rtr.Mount("/legacy/", legacy)
+	rtr.Get("/legacyapp", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("app"))
+	})
+
+	cases := []struct {
+		method string
+		path   string
+		body   string
+	}{
+		{http.MethodGet, "/legacy", "GET / /legacy"},
+		{http.MethodPost, "/legacy/", "POST / /legacy/"},
+		{http.MethodGet, "/legacy/users/42", "users /users/42"},
+		{http.MethodGet, "/legacyapp", "app"},
+	}
+	for _, c := range cases {
+		rec, req, err := request(c.method, c.path, nil)
+		assert.NoError(t, err)
+		rtr.ServeHTTP(rec, req)
+		assert.Equal(t, c.body, rec.Body.String(), c.path)
+	}
+
+	rec, req, err := request(http.MethodGet, "/legacyother", nil)
+	assert.NoError(t, err)
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+//-------------------- Another Test Case --------------------
+
+func TestMountRestoresPath(t *testing.T) {
+	var inner string
+	rtr := New()
+	api := rtr.Subrouter().PathPrefix("/api")
+	api.Mount("/v1", http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			inner = r.URL.EscapedPath()
+		}))
+
+	rec, req, err := request(http.MethodGet, "/api/v1/files/a%2Fb", nil)
+	assert.NoError(t, err)
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, "/files/a%2Fb", inner)
+	assert.Equal(t, "/api/v1/files/a/b", req.URL.Path)
+	assert.Equal(t, "/api/v1/files/a%2Fb", req.URL.EscapedPath())
+}
@@ -0,0 +1,166 @@
+package mux
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Offer is a representation of the response that can be chosen by Negotiate.
+type Offer struct {
+	// MediaType is the media type of the representation, e.g.
+	// "application/json".
+	MediaType string
+
+	// Render writes the representation to the response.
+	Render func(w http.ResponseWriter, r *http.Request) error
+}
+
+// OfferJSON returns an Offer that renders v as JSON (see JSON).
+func OfferJSON(code int, v interface{}) Offer {
+	return Offer{"application/json",
+		func(w http.ResponseWriter, r *http.Request) error {
+			return JSON(w, code, v)
+		}}
+}
+
+// OfferXML returns an Offer that renders v as XML (see XML).
+func OfferXML(code int, v interface{}) Offer {
+	return Offer{"application/xml",
+		func(w http.ResponseWriter, r *http.Request) error {
+			return XML(w, code, v)
+		}}
+}
+
+// OfferText returns an Offer that renders s as plain text (see Text).
+func OfferText(code int, s string) Offer {
+	return Offer{"text/plain",
+		func(w http.ResponseWriter, r *http.Request) error {
+			return Text(w, code, s)
+		}}
+}
+
+// OfferHTML returns an Offer that renders the page using the Renderer attached
+// to the router (see Render).
+func OfferHTML(code int, name string, data interface{}) Offer {
+	return Offer{"text/html",
+		func(w http.ResponseWriter, r *http.Request) error {
+			rnd, ok := r.Context().Value(rendererKey).(*Renderer)
+			if !ok {
+				return errNoRenderer
+			}
+			return rnd.HTML(w, code, name, data)
+		}}
+}
+
+// Negotiate picks the offer that suits the Accept header of the request best
+// and renders it. If none of the offers is acceptable, it reports "406 Not
+// Acceptable" through Error. Requests without Accept header get the first
+// offer:
+//
+//	err := mux.Negotiate(w, r,
+//	    mux.OfferJSON(http.StatusOK, user),
+//	    mux.OfferXML(http.StatusOK, user),
+//	    mux.OfferHTML(http.StatusOK, "users/show.html", user),
+//	)
+func Negotiate(w http.ResponseWriter, r *http.Request, offers ...Offer) error {
+	w.Header().Add("Vary", "Accept")
+
+	types := make([]string, len(offers))
+	for i, o := range offers {
+		types[i] = o.MediaType
+	}
+	best := NegotiateContentType(r, types...)
+	for _, o := range offers {
+		if o.MediaType == best {
+			return o.Render(w, r)
+		}
+	}
+
+	Error(w, r, NewHTTPError(http.StatusNotAcceptable,
+		"acceptable media types: %s", strings.Join(types, ", ")))
+	return nil
+}
+
+// NegotiateContentType returns the media type from the list that suits the
+// Accept header of the request best, or an empty string if none is
+// acceptable. If the request has no Accept header, the first media type is
+// returned.
+func NegotiateContentType(r *http.Request, types ...string) string {
+	accept := r.Header.Values("Accept")
+	if len(accept) == 0 {
+		if len(types) == 0 {
+			return ""
+		}
+		return types[0]
+	}
+	ranges := parseAccept(strings.Join(accept, ","))
+
+	best, bestQ := "", 0.0
+	for _, t := range types {
+		if q := acceptQuality(ranges, t); q > bestQ {
+			best, bestQ = t, q
+		}
+	}
+	return best
+}
+
+// acceptRange is a single media range of the Accept header.
+type acceptRange struct {
+	typ, sub string
+	q        float64
+}
+
+// parseAccept parses the Accept header value into media ranges.
+func parseAccept(header string) []acceptRange {
+	var ranges []acceptRange
+	for _, part := range strings.Split(header, ",") {
+		params := strings.Split(part, ";")
+		mt := strings.ToLower(strings.TrimSpace(params[0]))
+		if mt == "" {
+			continue
+		}
+		typ, sub := mt, "*"
+		if i := strings.IndexByte(mt, '/'); i >= 0 {
+			typ, sub = mt[:i], mt[i+1:]
+		}
+		q := 1.0
+		for _, p := range params[1:] {
+			p = strings.TrimSpace(p)
+			if strings.HasPrefix(p, "q=") {
+				if v, err := strconv.ParseFloat(p[2:], 64); err == nil {
+					q = v
+				}
+			}
+		}
+		ranges = append(ranges, acceptRange{typ, sub, q})
+	}
+	return ranges
+}
+
+// acceptQuality returns the quality of media type t according to the most
+// specific of the ranges that matches it.
+func acceptQuality(ranges []acceptRange, t string) float64 {
+	t = strings.ToLower(t)
+	typ, sub := t, ""
+	if i := strings.IndexByte(t, '/'); i >= 0 {
+		typ, sub = t[:i], t[i+1:]
+	}
+
+	q, specificity := 0.0, -1
+	for _, ar := range ranges {
+		s := -1
+		switch {
+		case ar.typ == typ && ar.sub == sub:
+			s = 2
+		case ar.typ == typ && ar.sub == "*":
+			s = 1
+		case ar.typ == "*" && ar.sub == "*":
+			s = 0
+		}
+		if s > specificity {
+			q, specificity = ar.q, s
+		}
+	}
+	return q
+}
@@ -0,0 +1,66 @@
+package mux
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiateContentType(t *testing.T) {
+	types := []string{"application/json", "application/xml", "text/html"}
+	cases := map[string]string{
+		"":                                       "application/json",
+		"application/xml":                        "application/xml",
+		"text/*":                                 "text/html",
+		"text/html;q=0.5, application/xml;q=0.9": "application/xml",
+		"*/*;q=0.1, application/json;q=0":        "application/xml",
+		"image/png":                              "",
+	}
+	for accept, expected := range cases {
+		req, err := http.NewRequest(http.MethodGet, "/", nil)
+		assert.NoError(t, err)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		assert.Equal(t, expected, NegotiateContentType(req, types...), accept)
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	type song struct {
+		Title string `json:"title" xml:"title"`
+	}
+	s := song{"Numb"}
+
+	rtr := New()
+	rtr.Get("/song", func(w http.ResponseWriter, r *http.Request) {
+		Negotiate(w, r,
+			OfferJSON(http.StatusOK, s),
+			OfferXML(http.StatusOK, s),
+			OfferText(http.StatusOK, s.Title),
+		)
+	})
+
+	cases := map[string]string{
+		"application/json": `{"title":"Numb"}`,
+		"application/xml":  `<song><title>Numb</title></song>`,
+		"text/plain":       `Numb`,
+	}
+	for accept, body := range cases {
+		rec, req, err := request(http.MethodGet, "/song", nil)
+		assert.NoError(t, err)
+		req.Header.Set("Accept", accept)
+		rtr.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.True(t, strings.Contains(rec.Body.String(), body), accept)
+		assert.Equal(t, "Accept", rec.Header().Get("Vary"))
+	}
+
+	rec, req, err := request(http.MethodGet, "/song", nil)
+	assert.NoError(t, err)
+	req.Header.Set("Accept", "image/png")
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotAcceptable, rec.Code)
+}
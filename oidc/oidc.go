@@ -0,0 +1,435 @@
+// Use of this source code is governed by the Mozilla Public License Version 2.0
+// that can be found in the LICENSE file.
+
+/*
+Package oidc adds OpenID Connect login to mux routing trees.
+
+Auth handles the authorization code flow with PKCE on three routes and keeps
+the identity of the user in an encrypted session cookie. Protected subtrees are
+guarded with the Require middleware, which places the identity in the request
+context, where mux.IdentityOf finds it:
+
+	auth, err := oidc.New(ctx, oidc.Config{
+	    Issuer:       "https://accounts.google.com",
+	    ClientID:     os.Getenv("CLIENT_ID"),
+	    ClientSecret: os.Getenv("CLIENT_SECRET"),
+	    RedirectURL:  "https://example.com/auth/callback",
+	    CookieKey:    key, // 32 random bytes
+	    LoginPath:    "/auth/login",
+	})
+	if err != nil {
+	    log.Fatal(err)
+	}
+
+	rtr := mux.New()
+	auth.Routes(rtr.Subrouter().PathPrefix("/auth"))
+	app := rtr.Subrouter().PathPrefix("/app").Wrap(auth.Require())
+*/
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	gooidc "github.com/coreos/go-oidc/v3/oidc"
+	"github.com/sharpvik/mux"
+	"golang.org/x/oauth2"
+)
+
+// Config configures Auth.
+type Config struct {
+	// Issuer is the URL of the OpenID provider. Its configuration is
+	// discovered from Issuer + "/.well-known/openid-configuration".
+	Issuer string
+
+	// ClientID and ClientSecret are the credentials of the app registered
+	// with the provider.
+	ClientID     string
+	ClientSecret string
+
+	// RedirectURL is the full URL of the callback route registered with the
+	// provider, e.g. "https://example.com/auth/callback".
+	RedirectURL string
+
+	// Scopes are the scopes requested from the provider. If nil, "openid",
+	// "profile" and "email" are requested.
+	Scopes []string
+
+	// CookieKey is the key the cookies are encrypted with. It should be at
+	// least 32 random bytes. It is required unless Cookies are given.
+	CookieKey []byte
+
+	// Cookies protect the cookies. If nil, they are encrypted with the
+	// CookieKey; set it to rotate keys. Identities kept in cookies that are
+	// signed but not encrypted can be read by the client.
+	Cookies *mux.Cookies
+
+	// CookieName is the name of the session cookie. Empty means
+	// "mux_session".
+	CookieName string
+
+	// SessionTTL is how long sessions last. Zero means 24 hours.
+	SessionTTL time.Duration
+
+	// LoginPath is the path of the login route that Require redirects
+	// browsers to. Empty means "/login".
+	LoginPath string
+
+	// AfterLogout is where users are redirected after logout, unless the
+	// provider supports RP-initiated logout. Empty means "/".
+	AfterLogout string
+
+	// Identity converts claims of the ID token and the scopes granted with
+	// the access token into the identity of the user. If nil, the subject
+	// is the "sub" claim, roles are taken from the "roles" claim, and the
+	// granted scopes are used as they are.
+	Identity func(claims map[string]interface{}, scopes []string) *mux.Identity
+}
+
+// Auth handles OpenID Connect login. Create it with New.
+type Auth struct {
+	cfg        Config
+	oauth      *oauth2.Config
+	verifier   *gooidc.IDTokenVerifier
+	endSession string
+	secure     bool
+}
+
+// New discovers the configuration of the provider and returns pointer to a
+// new Auth.
+func New(ctx context.Context, cfg Config) (*Auth, error) {
+	if len(cfg.CookieKey) == 0 && cfg.Cookies == nil {
+		return nil, errors.New("oidc: cookie key is required")
+	}
+	provider, err := gooidc.NewProvider(ctx, cfg.Issuer)
+	if err != nil {
+		return nil, fmt.Errorf("oidc: %w", err)
+	}
+	var meta struct {
+		EndSession string `json:"end_session_endpoint"`
+	}
+	if err := provider.Claims(&meta); err != nil {
+		return nil, fmt.Errorf("oidc: %w", err)
+	}
+
+	if cfg.Cookies == nil {
+		cfg.Cookies = mux.SecureCookies(cfg.CookieKey).Encrypted()
+	}
+	if cfg.Scopes == nil {
+		cfg.Scopes = []string{gooidc.ScopeOpenID, "profile", "email"}
+	}
+	if cfg.CookieName == "" {
+		cfg.CookieName = "mux_session"
+	}
+	if cfg.SessionTTL == 0 {
+		cfg.SessionTTL = 24 * time.Hour
+	}
+	if cfg.LoginPath == "" {
+		cfg.LoginPath = "/login"
+	}
+	if cfg.AfterLogout == "" {
+		cfg.AfterLogout = "/"
+	}
+	if cfg.Identity == nil {
+		cfg.Identity = defaultIdentity
+	}
+	return &Auth{
+		cfg: cfg,
+		oauth: &oauth2.Config{
+			ClientID:     cfg.ClientID,
+			ClientSecret: cfg.ClientSecret,
+			Endpoint:     provider.Endpoint(),
+			RedirectURL:  cfg.RedirectURL,
+			Scopes:       cfg.Scopes,
+		},
+		verifier:   provider.Verifier(&gooidc.Config{ClientID: cfg.ClientID}),
+		endSession: meta.EndSession,
+		secure:     strings.HasPrefix(cfg.RedirectURL, "https:"),
+	}, nil
+}
+
+// Routes method registers the login, callback and logout routes on the
+// Router: "GET /login", "GET /callback" and "POST /logout". The callback route
+// must be reachable at the RedirectURL and the login one at LoginPath.
+func (a *Auth) Routes(rtr *mux.Router) *mux.Router {
+	rtr.Get("/login", a.Login)
+	rtr.Get("/callback", a.Callback)
+	rtr.Post("/logout", a.Logout)
+	return rtr
+}
+
+// login is the state of a login attempt kept in a cookie between the login
+// and callback requests.
+type login struct {
+	State    string `json:"state"`
+	Nonce    string `json:"nonce"`
+	Verifier string `json:"verifier"`
+	Next     string `json:"next"`
+}
+
+// session is the content of the session cookie.
+type session struct {
+	Identity *mux.Identity `json:"identity"`
+	Expires  time.Time     `json:"expires"`
+}
+
+// Login method redirects the user to the provider to log in. The "next" query
+// parameter is the local path the user is redirected to afterwards.
+func (a *Auth) Login(w http.ResponseWriter, r *http.Request) {
+	l := login{
+		State:    random(),
+		Nonce:    random(),
+		Verifier: oauth2.GenerateVerifier(),
+		Next:     localPath(r.URL.Query().Get("next")),
+	}
+	if err := a.setCookie(w, a.stateCookie(), l, 10*time.Minute); err != nil {
+		mux.Error(w, r, err)
+		return
+	}
+	http.Redirect(w, r, a.oauth.AuthCodeURL(l.State,
+		gooidc.Nonce(l.Nonce), oauth2.S256ChallengeOption(l.Verifier),
+	), http.StatusFound)
+}
+
+// Callback method completes the login: it exchanges the code for tokens,
+// verifies the ID token, starts the session and redirects the user to where
+// they were going.
+func (a *Auth) Callback(w http.ResponseWriter, r *http.Request) {
+	var l login
+	if err := a.cookie(r, a.stateCookie(), &l); err != nil {
+		mux.Error(w, r, mux.NewHTTPError(http.StatusBadRequest,
+			"login expired, please try again"))
+		return
+	}
+	a.deleteCookie(w, a.stateCookie())
+
+	q := r.URL.Query()
+	if e := q.Get("error"); e != "" {
+		mux.Error(w, r, mux.NewHTTPError(http.StatusUnauthorized,
+			"login failed: %s", e))
+		return
+	}
+	if q.Get("state") != l.State {
+		mux.Error(w, r, mux.NewHTTPError(http.StatusBadRequest,
+			"login state mismatch"))
+		return
+	}
+
+	token, err := a.oauth.Exchange(r.Context(), q.Get("code"),
+		oauth2.VerifierOption(l.Verifier))
+	if err != nil {
+		mux.Error(w, r, mux.NewHTTPError(http.StatusUnauthorized,
+			"login failed: %v", err))
+		return
+	}
+	raw, _ := token.Extra("id_token").(string)
+	idToken, err := a.verifier.Verify(r.Context(), raw)
+	if err != nil {
+		mux.Error(w, r, mux.NewHTTPError(http.StatusUnauthorized,
+			"invalid ID token: %v", err))
+		return
+	}
+	if idToken.Nonce != l.Nonce {
+		mux.Error(w, r, mux.NewHTTPError(http.StatusUnauthorized,
+			"invalid ID token: nonce mismatch"))
+		return
+	}
+	var claims map[string]interface{}
+	if err := idToken.Claims(&claims); err != nil {
+		mux.Error(w, r, err)
+		return
+	}
+	scope, _ := token.Extra("scope").(string)
+
+	s := session{
+		Identity: a.cfg.Identity(claims, strings.Fields(scope)),
+		Expires:  time.Now().Add(a.cfg.SessionTTL),
+	}
+	err = a.setCookie(w, a.cfg.CookieName, s, a.cfg.SessionTTL)
+	if err != nil {
+		mux.Error(w, r, err)
+		return
+	}
+	http.Redirect(w, r, l.Next, http.StatusFound)
+}
+
+// Logout method ends the session and redirects the user to the provider's
+// logout page, if there is one, or to AfterLogout. Only POST requests made by
+// pages of the same site are accepted, so that other sites can't log users
+// out; the rest get "405 Method Not Allowed" or "403 Forbidden" through
+// mux.Error.
+func (a *Auth) Logout(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		mux.Error(w, r, mux.NewHTTPError(http.StatusMethodNotAllowed,
+			"logout requires POST"))
+		return
+	}
+	if crossSite(r) {
+		mux.Error(w, r, mux.NewHTTPError(http.StatusForbidden,
+			"cross-site logout"))
+		return
+	}
+	a.deleteCookie(w, a.cfg.CookieName)
+	target := a.cfg.AfterLogout
+	if a.endSession != "" {
+		target = a.endSession + "?" + url.Values{
+			"client_id": {a.cfg.ClientID},
+		}.Encode()
+	}
+	http.Redirect(w, r, target, http.StatusFound)
+}
+
+// Require method returns mux.Middleware that lets only logged in users
+// through and places their identity in the request context (see
+// mux.IdentityOf). Browsers of users that are not logged in are redirected to
+// the login page and come back afterwards; other clients get "401
+// Unauthorized" through mux.Error.
+func (a *Auth) Require() mux.Middleware {
+	return func(next http.Handler) http.Handler {
+		return mux.View(func(w http.ResponseWriter, r *http.Request) {
+			if id := a.Identity(r); id != nil {
+				next.ServeHTTP(w, mux.WithIdentity(r, id))
+				return
+			}
+			if r.Method == http.MethodGet && accepts(r, "text/html") {
+				path := mux.OriginalPath(r)
+				if r.URL.RawQuery != "" {
+					path += "?" + r.URL.RawQuery
+				}
+				next := url.Values{"next": {path}}
+				http.Redirect(w, r, a.cfg.LoginPath+"?"+next.Encode(),
+					http.StatusFound)
+				return
+			}
+			mux.Error(w, r, mux.NewHTTPError(http.StatusUnauthorized,
+				"login required"))
+		})
+	}
+}
+
+// Identity method returns the identity of the logged in user, or nil if the
+// request has no valid session.
+func (a *Auth) Identity(r *http.Request) *mux.Identity {
+	var s session
+	if err := a.cookie(r, a.cfg.CookieName, &s); err != nil {
+		return nil
+	}
+	if s.Identity == nil || time.Now().After(s.Expires) {
+		return nil
+	}
+	return s.Identity
+}
+
+// stateCookie method returns the name of the login state cookie.
+func (a *Auth) stateCookie() string {
+	return a.cfg.CookieName + "_login"
+}
+
+// setCookie method stores v in the named cookie protected by Cookies.
+func (a *Auth) setCookie(
+	w http.ResponseWriter, name string, v interface{}, ttl time.Duration,
+) error {
+	plain, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return a.cfg.Cookies.Set(w, &http.Cookie{
+		Name:     name,
+		Value:    string(plain),
+		Path:     "/",
+		MaxAge:   int(ttl.Seconds()),
+		Secure:   a.secure,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// cookie method reads v from the named cookie protected by Cookies.
+func (a *Auth) cookie(r *http.Request, name string, v interface{}) error {
+	plain, err := a.cfg.Cookies.Get(r, name)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal([]byte(plain), v)
+}
+
+// deleteCookie method makes the browser drop the named cookie.
+func (a *Auth) deleteCookie(w http.ResponseWriter, name string) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     name,
+		Value:    "",
+		Path:     "/",
+		MaxAge:   -1,
+		Secure:   a.secure,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// defaultIdentity is the default Config.Identity.
+func defaultIdentity(
+	claims map[string]interface{}, scopes []string,
+) *mux.Identity {
+	id := &mux.Identity{Scopes: scopes}
+	id.Subject, _ = claims["sub"].(string)
+	roles, _ := claims["roles"].([]interface{})
+	for _, role := range roles {
+		if s, ok := role.(string); ok {
+			id.Roles = append(id.Roles, s)
+		}
+	}
+	return id
+}
+
+// random returns a random URL-safe string.
+func random() string {
+	b := make([]byte, 24)
+	rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// localPath returns p if it is a path on this site, and "/" otherwise, so
+// that the login can't be used to redirect users to other sites.
+func localPath(p string) string {
+	if !strings.HasPrefix(p, "/") || strings.HasPrefix(p, "//") ||
+		strings.HasPrefix(p, "/\\") {
+		return "/"
+	}
+	return p
+}
+
+// crossSite tells whether the request was made by a page of another site,
+// judging by Sec-Fetch-Site or Origin header.
+func crossSite(r *http.Request) bool {
+	if site := r.Header.Get("Sec-Fetch-Site"); site != "" {
+		return site != "same-origin" && site != "same-site" && site != "none"
+	}
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return false
+	}
+	u, err := url.Parse(origin)
+	return err != nil || !strings.EqualFold(u.Host, r.Host)
+}
+
+// accepts tells whether the request accepts the media type explicitly.
+func accepts(r *http.Request, mediaType string) bool {
+	for _, v := range r.Header.Values("Accept") {
+		for _, part := range strings.Split(v, ",") {
+			mt, _, _ := strings.Cut(part, ";")
+			if strings.EqualFold(strings.TrimSpace(mt), mediaType) {
+				return true
+			}
+		}
+	}
+	return false
+}
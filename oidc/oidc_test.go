@@ -0,0 +1,206 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v3"
+	"github.com/sharpvik/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+// provider is a fake OpenID provider. The authorization codes it accepts are
+// the nonces of the ID tokens it issues for them.
+func provider(t *testing.T) *httptest.Server {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	signer, err := jose.NewSigner(jose.SigningKey{
+		Algorithm: jose.RS256,
+		Key:       jose.JSONWebKey{Key: key, KeyID: "k1"},
+	}, nil)
+	assert.NoError(t, err)
+
+	var srv *httptest.Server
+	rtr := mux.New()
+	rtr.Get("/.well-known/openid-configuration",
+		func(w http.ResponseWriter, r *http.Request) {
+			mux.JSON(w, http.StatusOK, map[string]interface{}{
+				"issuer":                                srv.URL,
+				"authorization_endpoint":                srv.URL + "/authorize",
+				"token_endpoint":                        srv.URL + "/token",
+				"jwks_uri":                              srv.URL + "/keys",
+				"id_token_signing_alg_values_supported": []string{"RS256"},
+			})
+		})
+	rtr.Get("/keys", func(w http.ResponseWriter, r *http.Request) {
+		mux.JSON(w, http.StatusOK, jose.JSONWebKeySet{Keys: []jose.JSONWebKey{
+			{Key: &key.PublicKey, KeyID: "k1", Algorithm: "RS256", Use: "sig"},
+		}})
+	})
+	rtr.Post("/token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.Form.Get("code_verifier") == "" {
+			mux.JSON(w, http.StatusBadRequest,
+				map[string]string{"error": "invalid_grant"})
+			return
+		}
+		claims, _ := json.Marshal(map[string]interface{}{
+			"iss":   srv.URL,
+			"sub":   "user-1",
+			"aud":   "app",
+			"exp":   time.Now().Add(time.Hour).Unix(),
+			"iat":   time.Now().Unix(),
+			"nonce": r.Form.Get("code"),
+			"roles": []string{"admin"},
+		})
+		jws, err := signer.Sign(claims)
+		assert.NoError(t, err)
+		idToken, err := jws.CompactSerialize()
+		assert.NoError(t, err)
+		mux.JSON(w, http.StatusOK, map[string]interface{}{
+			"access_token": "at",
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+			"scope":        "openid orders:read",
+			"id_token":     idToken,
+		})
+	})
+	srv = httptest.NewServer(rtr)
+	return srv
+}
+
+func TestAuth(t *testing.T) {
+	idp := provider(t)
+	defer idp.Close()
+
+	auth, err := New(context.Background(), Config{
+		Issuer:      idp.URL,
+		ClientID:    "app",
+		RedirectURL: "https://example.com/auth/callback",
+		CookieKey:   make([]byte, 32),
+		LoginPath:   "/auth/login",
+	})
+	assert.NoError(t, err)
+
+	rtr := mux.New()
+	auth.Routes(rtr.Subrouter().PathPrefix("/auth"))
+	rtr.Subrouter().PathPrefix("/app").Wrap(auth.Require()).
+		Get("/", func(w http.ResponseWriter, r *http.Request) {
+			id := mux.IdentityOf(r)
+			w.Write([]byte(id.Subject + " " + strings.Join(id.Roles, ",") +
+				" " + strings.Join(id.Scopes, ",")))
+		})
+
+	var cookies []*http.Cookie
+	do := func(req *http.Request) *http.Response {
+		rec := httptest.NewRecorder()
+		for _, c := range cookies {
+			req.AddCookie(c)
+		}
+		rtr.ServeHTTP(rec, req)
+		res := rec.Result()
+		for _, c := range res.Cookies() {
+			for i, old := range cookies {
+				if old.Name == c.Name {
+					cookies = append(cookies[:i], cookies[i+1:]...)
+					break
+				}
+			}
+			if c.MaxAge >= 0 {
+				cookies = append(cookies, c)
+			}
+		}
+		return res
+	}
+	serve := func(path string, accept string) *http.Response {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Accept", accept)
+		return do(req)
+	}
+
+	// API clients are rejected, browsers are sent to the login page.
+	res := serve("/app/", "application/json")
+	assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+	res = serve("/app/", "text/html")
+	assert.Equal(t, http.StatusFound, res.StatusCode)
+	assert.Equal(t, "/auth/login?next=%2Fapp%2F", res.Header.Get("Location"))
+
+	res = serve("/auth/login?next=%2Fapp%2F", "text/html")
+	assert.Equal(t, http.StatusFound, res.StatusCode)
+	authorize, err := url.Parse(res.Header.Get("Location"))
+	assert.NoError(t, err)
+	assert.Equal(t, idp.URL+"/authorize", authorize.Scheme+"://"+
+		authorize.Host+authorize.Path)
+	q := authorize.Query()
+	assert.Equal(t, "S256", q.Get("code_challenge_method"))
+	if assert.Len(t, cookies, 1) {
+		assert.True(t, cookies[0].Secure)
+		assert.True(t, cookies[0].HttpOnly)
+	}
+
+	// The provider sends the user back.
+	res = serve("/auth/callback?state=wrong&code="+q.Get("nonce"), "")
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+	res = serve("/auth/login?next=https://evil.com", "text/html")
+	q = mustQuery(t, res.Header.Get("Location"))
+	res = serve("/auth/callback?"+url.Values{
+		"state": {q.Get("state")},
+		"code":  {q.Get("nonce")},
+	}.Encode(), "")
+	assert.Equal(t, http.StatusFound, res.StatusCode)
+	assert.Equal(t, "/", res.Header.Get("Location"))
+
+	res = serve("/app/", "text/html")
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	body := make([]byte, 100)
+	n, _ := res.Body.Read(body)
+	assert.Equal(t, "user-1 admin openid,orders:read", string(body[:n]))
+
+	//-------------------- Another Test Case --------------------
+
+	res = serve("/auth/logout", "text/html")
+	assert.Equal(t, http.StatusMethodNotAllowed, res.StatusCode)
+	logout := httptest.NewRequest(http.MethodPost, "/auth/logout", nil)
+	logout.Header.Set("Origin", "https://evil.com")
+	res = do(logout)
+	assert.Equal(t, http.StatusForbidden, res.StatusCode)
+	logout = httptest.NewRequest(http.MethodPost, "/auth/logout", nil)
+	logout.Header.Set("Sec-Fetch-Site", "cross-site")
+	res = do(logout)
+	assert.Equal(t, http.StatusForbidden, res.StatusCode)
+	assert.Len(t, cookies, 1)
+	logout = httptest.NewRequest(http.MethodPost, "/auth/logout", nil)
+	logout.Header.Set("Origin", "http://example.com")
+	res = do(logout)
+	assert.Equal(t, http.StatusFound, res.StatusCode)
+	assert.Equal(t, "/", res.Header.Get("Location"))
+	assert.Empty(t, cookies)
+	res = serve("/app/", "application/json")
+	assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+
+	//-------------------- Another Test Case --------------------
+
+	// Forged cookies are ignored.
+	cookies = []*http.Cookie{{Name: "mux_session", Value: "Zm9yZ2Vk"}}
+	res = serve("/app/", "application/json")
+	assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+}
+
+func TestNew(t *testing.T) {
+	_, err := New(context.Background(), Config{})
+	assert.EqualError(t, err, "oidc: cookie key is required")
+}
+
+func mustQuery(t *testing.T, rawURL string) url.Values {
+	u, err := url.Parse(rawURL)
+	assert.NoError(t, err)
+	return u.Query()
+}
@@ -0,0 +1,263 @@
+// Use of this source code is governed by the Mozilla Public License Version 2.0
+// that can be found in the LICENSE file.
+
+/*
+Package openapi generates OpenAPI 3 specifications from mux routing trees.
+
+The Generator walks the Router and emits one operation per route that has both
+a methods filter and a handler. Path templates are converted into OpenAPI form
+("/users/{id:int}" becomes "/users/{id}") and path variables are documented as
+parameters with schemas derived from their types. Summaries, request bodies and
+responses can be attached to the routes using the Doc method:
+
+	rtr := mux.New()
+	user := rtr.Get("/users/{id:int}", showUser)
+
+	gen := openapi.New("Users API", "1.0.0").Doc(user, openapi.Operation{
+	    Summary: "Show user by ID",
+	    Responses: map[string]*openapi.Response{
+	        "200": {Description: "User found"},
+	    },
+	})
+	spec := gen.Build(rtr)
+*/
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/sharpvik/mux"
+)
+
+// Version is the version of the OpenAPI specification emitted by Generator.
+const Version = "3.0.3"
+
+// Spec is the root object of an OpenAPI document.
+type Spec struct {
+	OpenAPI string               `json:"openapi"`
+	Info    Info                 `json:"info"`
+	Paths   map[string]*PathItem `json:"paths"`
+}
+
+// Info provides metadata about the API.
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// PathItem maps lowercase HTTP methods (e.g. "get") to operations.
+type PathItem map[string]*Operation
+
+// Operation describes a single API operation on a path.
+type Operation struct {
+	Summary     string               `json:"summary,omitempty"`
+	Description string               `json:"description,omitempty"`
+	OperationID string               `json:"operationId,omitempty"`
+	Tags        []string             `json:"tags,omitempty"`
+	Parameters  []*Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses"`
+}
+
+// Parameter describes a single operation parameter.
+type Parameter struct {
+	Name        string `json:"name"`
+	In          string `json:"in"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+	Schema      Schema `json:"schema,omitempty"`
+}
+
+// RequestBody describes a single request body.
+type RequestBody struct {
+	Description string                `json:"description,omitempty"`
+	Required    bool                  `json:"required,omitempty"`
+	Content     map[string]*MediaType `json:"content"`
+}
+
+// Response describes a single response from an API operation.
+type Response struct {
+	Description string                `json:"description"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+// MediaType provides schema for the media type identified by its key.
+type MediaType struct {
+	Schema Schema `json:"schema,omitempty"`
+}
+
+// Schema is a free-form JSON Schema object, e.g.
+//
+//	openapi.Schema{"type": "string", "format": "email"}
+type Schema map[string]interface{}
+
+// Generator builds OpenAPI specifications from routing trees.
+type Generator struct {
+	info Info
+	docs map[*mux.Router]Operation
+}
+
+// New returns pointer to a Generator that produces specifications with given
+// title and version in their Info object.
+func New(title string, version string) *Generator {
+	return &Generator{
+		info: Info{Title: title, Version: version},
+		docs: make(map[*mux.Router]Operation),
+	}
+}
+
+// Description method sets description of the API.
+func (gen *Generator) Description(desc string) *Generator {
+	gen.info.Description = desc
+	return gen
+}
+
+// Doc method attaches documentation to the route. Parameters listed in op
+// override the ones generated from the path template by name.
+func (gen *Generator) Doc(route *mux.Router, op Operation) *Generator {
+	gen.docs[route] = op
+	return gen
+}
+
+// Build method walks the routing tree and returns its specification. Routes
+// without a methods filter or without a handler are skipped since they don't
+// describe distinct operations.
+func (gen *Generator) Build(rtr *mux.Router) *Spec {
+	spec := &Spec{
+		OpenAPI: Version,
+		Info:    gen.info,
+		Paths:   make(map[string]*PathItem),
+	}
+	rtr.Walk(func(route *mux.RouteInfo) error {
+		if route.Handler == nil || len(route.Methods) == 0 {
+			return nil
+		}
+		path, params := convert(route.Template())
+		item, ok := spec.Paths[path]
+		if !ok {
+			item = &PathItem{}
+			spec.Paths[path] = item
+		}
+		for _, method := range route.Methods {
+			if !isOperation(method) {
+				continue
+			}
+			(*item)[strings.ToLower(method)] = gen.operation(route, params)
+		}
+		return nil
+	})
+	return spec
+}
+
+// Handler method returns an http.Handler that serves the specification of the
+// routing tree as JSON. The tree is inspected upon every request.
+func (gen *Generator) Handler(rtr *mux.Router) http.Handler {
+	return mux.View(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(gen.Build(rtr))
+	})
+}
+
+// operation method returns an Operation for the route, merging documentation
+// registered with Doc and parameters generated from the path template.
+func (gen *Generator) operation(
+	route *mux.RouteInfo, params []*Parameter,
+) *Operation {
+	op := gen.docs[route.Router]
+	if op.OperationID == "" {
+		op.OperationID = route.Name
+	}
+
+	// Documented parameters take precedence over generated ones.
+	documented := make(map[string]bool)
+	for _, p := range op.Parameters {
+		documented[p.In+":"+p.Name] = true
+	}
+	merged := append([]*Parameter(nil), op.Parameters...)
+	for _, p := range params {
+		if !documented[p.In+":"+p.Name] {
+			merged = append(merged, p)
+		}
+	}
+	op.Parameters = merged
+
+	if op.Responses == nil {
+		op.Responses = map[string]*Response{
+			"default": {Description: "Default response"},
+		}
+	}
+	return &op
+}
+
+// varRegexp matches path template variables such as "{id:int}".
+var varRegexp = regexp.MustCompile(`^\{(\w+):(.+)\}$`)
+
+// convert function turns mux path template into OpenAPI path template and
+// returns parameters that describe its variables.
+func convert(template string) (path string, params []*Parameter) {
+	split := strings.Split(template, "/")
+	for i, seg := range split {
+		m := varRegexp.FindStringSubmatch(seg)
+		if m == nil {
+			continue
+		}
+		name, typ := m[1], m[2]
+		split[i] = "{" + name + "}"
+		params = append(params, &Parameter{
+			Name:     name,
+			In:       "path",
+			Required: true,
+			Schema:   schema(typ),
+		})
+	}
+	return strings.Join(split, "/"), params
+}
+
+// schema function returns a JSON Schema for the path variable type.
+func schema(typ string) Schema {
+	switch typ {
+	case "int":
+		return Schema{"type": "integer"}
+	case "nat":
+		return Schema{"type": "integer", "minimum": 0}
+	case "float":
+		return Schema{"type": "number"}
+	case "bool":
+		return Schema{"type": "boolean"}
+	case "date":
+		return Schema{"type": "string", "format": "date"}
+	case "rfc3339":
+		return Schema{"type": "string", "format": "date-time"}
+	case "str":
+		return Schema{"type": "string", "pattern": "^[a-zA-Z_]+$"}
+	case "*":
+		return Schema{"type": "string"}
+	}
+	if strings.HasPrefix(typ, "enum(") && strings.HasSuffix(typ, ")") {
+		values := strings.Split(typ[len("enum("):len(typ)-1], ",")
+		enum := make([]interface{}, len(values))
+		for i, v := range values {
+			enum[i] = strings.TrimSpace(v)
+		}
+		return Schema{"type": "string", "enum": enum}
+	}
+	if pattern, ok := mux.VarTypePattern(typ); ok {
+		typ = pattern
+	}
+	return Schema{"type": "string", "pattern": "^(" + typ + ")$"}
+}
+
+// isOperation tells whether the method can be described by OpenAPI.
+func isOperation(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodPut, http.MethodPost, http.MethodDelete,
+		http.MethodOptions, http.MethodHead, http.MethodPatch,
+		http.MethodTrace:
+		return true
+	}
+	return false
+}
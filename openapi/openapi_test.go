@@ -0,0 +1,60 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sharpvik/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuild(t *testing.T) {
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+	rtr := mux.New()
+	api := rtr.Subrouter().PathPrefix("/api")
+	user := api.Get("/users/{id:int}", noop).Name("getUser")
+	api.Post("/users", noop)
+	api.Subrouter().Path("/any").HandleFunc(noop)
+
+	gen := New("Users API", "1.0.0").Doc(user, Operation{
+		Summary: "Show user",
+		Responses: map[string]*Response{
+			"200": {Description: "User found"},
+		},
+	})
+	spec := gen.Build(rtr)
+
+	assert.Equal(t, Version, spec.OpenAPI)
+	assert.Len(t, spec.Paths, 2)
+
+	get := (*spec.Paths["/api/users/{id}"])["get"]
+	if assert.NotNil(t, get) {
+		assert.Equal(t, "Show user", get.Summary)
+		assert.Equal(t, "getUser", get.OperationID)
+		assert.Len(t, get.Parameters, 1)
+		assert.Equal(t, "id", get.Parameters[0].Name)
+		assert.Equal(t, "integer", get.Parameters[0].Schema["type"])
+		assert.Contains(t, get.Responses, "200")
+	}
+
+	post := (*spec.Paths["/api/users"])["post"]
+	if assert.NotNil(t, post) {
+		assert.Contains(t, post.Responses, "default")
+	}
+}
+
+func TestHandler(t *testing.T) {
+	rtr := mux.New()
+	rtr.Get("/songs/{id:nat}", func(w http.ResponseWriter, r *http.Request) {})
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/openapi.json", nil)
+	New("Songs", "0.1.0").Handler(rtr).ServeHTTP(rec, req)
+
+	var spec map[string]interface{}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &spec))
+	assert.Equal(t, "3.0.3", spec["openapi"])
+	assert.Contains(t, spec["paths"], "/songs/{id}")
+}
@@ -0,0 +1,61 @@
+package mux
+
+import (
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// overridable is a set of methods POST requests may be overridden with.
+var overridable = newSet(http.MethodPut, http.MethodPatch, http.MethodDelete)
+
+// MethodOverride returns Middleware that lets clients which can only send
+// POST requests, such as HTML forms, use other methods: the method of the
+// request is taken from X-HTTP-Method-Override header or, for form
+// submissions, from "_method" form field:
+//
+//	<form method="POST" action="/posts/42">
+//	    <input type="hidden" name="_method" value="DELETE">
+//	    ...
+//	</form>
+//
+// Register it on the root Router, so that the method is replaced before
+// routes are matched. Only POST can be overridden, and only with PUT, PATCH or
+// DELETE, so that links and forms can't trigger other methods by accident.
+// Other values are ignored. Forms are parsed with the default limits of
+// FormOptions.
+func MethodOverride() Middleware {
+	return func(next http.Handler) http.Handler {
+		return View(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPost {
+				next.ServeHTTP(w, r)
+				return
+			}
+			method := r.Header.Get("X-HTTP-Method-Override")
+			if method == "" && isForm(r) {
+				if err := parseForm(r, &FormOptions{}); err == nil {
+					method = r.PostFormValue("_method")
+				}
+			}
+			method = strings.ToUpper(strings.TrimSpace(method))
+			if overridable.Has(method) {
+				r = r.WithContext(r.Context())
+				r.Method = method
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// MethodOverride method registers MethodOverride middleware on the Router.
+// See MethodOverride.
+func (rtr *Router) MethodOverride() *Router {
+	return rtr.Wrap(MethodOverride())
+}
+
+// isForm tells whether the request body is an HTML form submission.
+func isForm(r *http.Request) bool {
+	mt, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return mt == "application/x-www-form-urlencoded" ||
+		mt == "multipart/form-data"
+}
@@ -0,0 +1,57 @@
+package mux
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMethodOverride(t *testing.T) {
+	method := func(w http.ResponseWriter, r *http.Request) {
+		Text(w, http.StatusOK, r.Method+" "+r.PostFormValue("title"))
+	}
+	rtr := New().MethodOverride()
+	rtr.Post("/posts/{id:int}", method)
+	rtr.Put("/posts/{id:int}", method)
+	rtr.Delete("/posts/{id:int}", method)
+	rtr.Get("/posts/{id:int}", method)
+
+	cases := []struct {
+		method, header, form string
+		body                 string
+	}{
+		{http.MethodPost, "", "", "POST "},
+		{http.MethodPost, "delete", "", "DELETE "},
+		{http.MethodPost, "", "_method=PUT&title=Hi", "PUT Hi"},
+		{http.MethodPost, "", "_method=GET", "POST "},
+		{http.MethodPost, "CONNECT", "", "POST "},
+		{http.MethodGet, "DELETE", "", "GET "},
+	}
+	for _, c := range cases {
+		var body io.Reader
+		if c.form != "" {
+			body = strings.NewReader(c.form)
+		}
+		rec, req, err := request(c.method, "/posts/42", body)
+		assert.NoError(t, err)
+		if c.header != "" {
+			req.Header.Set("X-HTTP-Method-Override", c.header)
+		}
+		if c.form != "" {
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		}
+		rtr.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code, c)
+		assert.Equal(t, c.body, rec.Body.String(), c)
+	}
+	//-------------------- Another Test Case --------------------
+	rec, req, err := request(http.MethodPost, "/posts/42",
+		strings.NewReader(`{"_method": "DELETE"}`))
+	assert.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, "POST ", rec.Body.String())
+}
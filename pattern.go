@@ -0,0 +1,137 @@
+package mux
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// wildcardName matches names of wildcards in http.ServeMux patterns.
+var wildcardName = regexp.MustCompile(`^[A-Za-z_]\w*$`)
+
+// Pattern method creates a sub-router with filters translated from a pattern
+// in the format of http.ServeMux (Go 1.22+):
+//
+//	[METHOD ][HOST]/[PATH]
+//
+// For example,
+//
+//	rtr.Pattern("GET /users/{id}").HandleFunc(showUser)
+//
+// is equivalent to
+//
+//	rtr.Subrouter().
+//	    Methods(http.MethodGet, http.MethodHead).
+//	    Path("/users/{id:segment}").
+//	    HandleFunc(showUser)
+//
+// The semantics of http.ServeMux are preserved: "GET" also matches "HEAD"
+// requests, host is matched without port, "{name}" matches one path segment,
+// "{name...}" matches the rest of the path, and patterns that end with a slash
+// match every path under them unless they end with "{$}". Wildcard values are
+// available both through Vars and http.Request.PathValue.
+//
+// Unlike http.ServeMux, routes are still matched in order of registration
+// rather than by precedence, so register more specific patterns first. It
+// panics if the pattern is invalid.
+func (rtr *Router) Pattern(pattern string) *Router {
+	method, host, path, prefix := parsePattern(pattern)
+
+	sub := rtr.detached()
+	switch method {
+	case "":
+	case http.MethodGet:
+		sub.Methods(http.MethodGet, http.MethodHead)
+	default:
+		sub.Methods(method)
+	}
+	if host != "" {
+		sub.Host(host)
+	}
+
+	sub.Path(path)
+	if prefix {
+		// Keep the expression anchored at the start only, so that it matches
+		// every path under the template.
+		fil := sub.filters.Path
+		exp := strings.TrimSuffix(fil.Regexp.String(), "$")
+		fil.Regexp = regexp.MustCompile(exp)
+		fil.Strict = false
+	}
+	return rtr.attach(sub)
+}
+
+// parsePattern translates http.ServeMux pattern into method, host, and path
+// template. The prefix flag tells whether the template must match path prefix
+// rather than the whole path. It panics if the pattern is invalid.
+func parsePattern(pattern string) (method, host, path string, prefix bool) {
+	invalid := func(reason string) {
+		panic(fmt.Sprintf("invalid pattern %q: %s", pattern, reason))
+	}
+
+	rest := strings.TrimSpace(pattern)
+	if i := strings.IndexAny(rest, " \t"); i >= 0 {
+		method, rest = rest[:i], strings.TrimLeft(rest[i:], " \t")
+		if strings.Contains(method, "/") {
+			invalid("bad method")
+		}
+	}
+
+	i := strings.Index(rest, "/")
+	if i < 0 {
+		invalid("missing path")
+	}
+	host, rest = rest[:i], rest[i:]
+
+	segments := strings.Split(rest, "/")[1:]
+	names := newSet()
+	prefix = strings.HasSuffix(rest, "/")
+	for i, seg := range segments {
+		last := i == len(segments)-1
+		if !strings.ContainsAny(seg, "{}") {
+			continue
+		}
+		if !strings.HasPrefix(seg, "{") || !strings.HasSuffix(seg, "}") {
+			invalid("wildcard must be a full path segment")
+		}
+
+		name := seg[1 : len(seg)-1]
+		typ := "segment"
+		switch {
+		case name == "$":
+			if !last {
+				invalid("{$} must be at the end")
+			}
+			segments[i] = ""
+			prefix = false
+			continue
+		case strings.HasSuffix(name, "..."):
+			if !last {
+				invalid("{...} wildcard must be at the end")
+			}
+			name, typ = strings.TrimSuffix(name, "..."), "*"
+			prefix = false
+		}
+
+		if !wildcardName.MatchString(name) {
+			invalid(fmt.Sprintf("bad wildcard name %q", name))
+		}
+		if names.Has(name) {
+			invalid(fmt.Sprintf("duplicate wildcard name %q", name))
+		}
+		names.Add(name)
+		segments[i] = "{" + name + ":" + typ + "}"
+	}
+
+	return method, host, "/" + strings.Join(segments, "/"), prefix
+}
+
+// requestHost returns host of the request without port.
+func requestHost(r *http.Request) string {
+	if host, _, err := net.SplitHostPort(r.Host); err == nil {
+		return host
+	}
+	return r.Host
+}
@@ -0,0 +1,97 @@
+package mux
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPattern(t *testing.T) {
+	echo := func(name string) View {
+		return func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(
+				name + " " + r.PathValue("id") + r.PathValue("path")))
+		}
+	}
+
+	rtr := New()
+	rtr.Pattern("GET /users/{id}").HandleFunc(echo("user"))
+	rtr.Pattern("DELETE  /users/{id}").HandleFunc(echo("delete"))
+	rtr.Pattern("/files/{path...}").HandleFunc(echo("file"))
+	rtr.Pattern("GET /posts/{$}").HandleFunc(echo("posts"))
+	rtr.Pattern("GET /static/").HandleFunc(echo("static"))
+	rtr.Pattern("api.example.com/{id}").HandleFunc(echo("api"))
+	rtr.Pattern("/{$}").HandleFunc(echo("home"))
+
+	cases := []struct {
+		method string
+		host   string
+		path   string
+		code   int
+		body   string
+	}{
+		{http.MethodGet, "", "/users/john.doe", http.StatusOK, "user john.doe"},
+		{http.MethodHead, "", "/users/42", http.StatusOK, "user 42"},
+		{http.MethodDelete, "", "/users/42", http.StatusOK, "delete 42"},
+		{http.MethodPost, "", "/users/42", http.StatusMethodNotAllowed, ""},
+		{http.MethodGet, "", "/users/42/posts", http.StatusNotFound, ""},
+		{http.MethodPut, "", "/files/a/b.txt", http.StatusOK, "file a/b.txt"},
+		{http.MethodGet, "", "/files/", http.StatusOK, "file "},
+		{http.MethodGet, "", "/posts/", http.StatusOK, "posts "},
+		{http.MethodGet, "", "/posts/42", http.StatusNotFound, ""},
+		{http.MethodGet, "", "/static/css/app.css", http.StatusOK, "static "},
+		{http.MethodGet, "API.example.com:8080", "/7", http.StatusOK, "api 7"},
+		{http.MethodGet, "", "/7", http.StatusNotFound, ""},
+		{http.MethodGet, "", "/", http.StatusOK, "home "},
+	}
+	for _, c := range cases {
+		rec, req, err := request(c.method, c.path, nil)
+		assert.NoError(t, err)
+		if c.host != "" {
+			req.Host = c.host
+		}
+		rtr.ServeHTTP(rec, req)
+		assert.Equal(t, c.code, rec.Code, c.method+" "+c.path)
+		if c.body != "" {
+			assert.Equal(t, c.body, rec.Body.String(), c.method+" "+c.path)
+		}
+	}
+}
+
+//-------------------- Another Test Case --------------------
+
+func TestPatternInvalid(t *testing.T) {
+	for _, pattern := range []string{
+		"GET",
+		"GET users",
+		"/users/{id}/{id}",
+		"/users/id{id}",
+		"/files/{path...}/edit",
+		"/posts/{$}/edit",
+		"/users/{id:int}",
+		"/users/{1d}",
+	} {
+		assert.Panics(t, func() { New().Pattern(pattern) }, pattern)
+	}
+}
+
+//-------------------- Another Test Case --------------------
+
+func TestPathValue(t *testing.T) {
+	var id, name interface{}
+	rtr := New()
+	rtr.Get("/users/{id:int}/{name:segment}",
+		func(w http.ResponseWriter, r *http.Request) {
+			vars, _ := Vars(r)
+			id, name = vars["id"], r.PathValue("name")
+			w.Write([]byte(r.PathValue("id")))
+		})
+
+	rec, req, err := request(http.MethodGet, "/users/42/Jane%20Doe", nil)
+	assert.NoError(t, err)
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, "42", rec.Body.String())
+	assert.Equal(t, 42, id)
+	assert.Equal(t, "Jane Doe", name)
+}
@@ -0,0 +1,97 @@
+package mux
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// PrintTree method writes the routing tree to w as indented ASCII art, one
+// route per line in the order the routes are matched:
+//
+//	/
+//	├── GET /users  main.listUsers
+//	├── /api
+//	│   ├── GET /api/users/{id:int}  [user]  main.showUser
+//	│   └── GET,POST /api/posts  main.posts
+//	└── /static  *mux.FileServer
+//
+// Every line shows the methods (if filtered), the full path template, the
+// name in brackets (if set) and the handler (if set). Disabled routes are
+// marked as such.
+func (rtr *Router) PrintTree(w io.Writer) error {
+	if _, err := fmt.Fprintln(w, rtr.info(nil, 0).label()); err != nil {
+		return err
+	}
+	return rtr.printRoutes(w, nil, "")
+}
+
+// String method returns the routing tree as printed by PrintTree.
+func (rtr *Router) String() string {
+	var buf bytes.Buffer
+	rtr.PrintTree(&buf)
+	return buf.String()
+}
+
+// printRoutes method prints the sub-routers with given indentation.
+func (rtr *Router) printRoutes(
+	w io.Writer, prefixes []string, indent string,
+) error {
+	prefixes = rtr.info(prefixes, 0).Prefixes
+	routes := rtr.ordered()
+	for i, route := range routes {
+		branch, next := "├── ", "│   "
+		if i == len(routes)-1 {
+			branch, next = "└── ", "    "
+		}
+		label := route.info(prefixes, 0).label()
+		if _, err := fmt.Fprintln(w, indent+branch+label); err != nil {
+			return err
+		}
+		if err := route.printRoutes(w, prefixes, indent+next); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// label method returns the description of the route used by PrintTree.
+func (info *RouteInfo) label() string {
+	var parts []string
+	template := info.Template()
+	if template == "" {
+		template = "/"
+	}
+	if info.Methods != nil {
+		template = strings.Join(info.Methods, ",") + " " + template
+	}
+	parts = append(parts, template)
+	if info.Name != "" {
+		parts = append(parts, "["+info.Name+"]")
+	}
+	if info.Handler != nil {
+		parts = append(parts, HandlerName(info.Handler))
+	}
+	if info.Disabled {
+		parts = append(parts, "(disabled)")
+	}
+	return strings.Join(parts, "  ")
+}
+
+// HandlerName returns a human-readable name of the handler: the name of the
+// function for handler functions (e.g. "main.listUsers") and the type of the
+// handler otherwise (e.g. "*mux.FileServer").
+func HandlerName(h http.Handler) string {
+	switch h.(type) {
+	case View, http.HandlerFunc:
+		fn := runtime.FuncForPC(reflect.ValueOf(h).Pointer())
+		if fn != nil {
+			return fn.Name()
+		}
+	}
+	return fmt.Sprintf("%T", h)
+}
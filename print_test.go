@@ -0,0 +1,37 @@
+package mux
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func listUsers(w http.ResponseWriter, r *http.Request) {}
+
+func TestPrintTree(t *testing.T) {
+	rtr := New()
+	rtr.Get("/users", listUsers).Name("users")
+	rtr.Route("/api", func(r *Router) {
+		r.Get("/users/{id:int}", listUsers)
+		r.Subrouter().Methods(http.MethodGet, http.MethodPost).Path("/posts").
+			Handler(NewFileServer(http.Dir("."), nil)).Disable()
+	})
+	rtr.Get("/first", listUsers).Priority(1)
+
+	assert.Equal(t, `/
+├── GET /first  github.com/sharpvik/mux.listUsers
+├── GET /users  [users]  github.com/sharpvik/mux.listUsers
+└── /api
+    ├── GET /api/users/{id:int}  github.com/sharpvik/mux.listUsers
+    └── GET,POST /api/posts  *mux.FileServer  (disabled)
+`, rtr.String())
+}
+
+func TestHandlerName(t *testing.T) {
+	assert.Equal(t, "github.com/sharpvik/mux.listUsers",
+		HandlerName(View(listUsers)))
+	assert.Equal(t, "github.com/sharpvik/mux.listUsers",
+		HandlerName(http.HandlerFunc(listUsers)))
+	assert.Equal(t, "*http.ServeMux", HandlerName(http.NewServeMux()))
+}
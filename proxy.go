@@ -0,0 +1,614 @@
+package mux
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// BalanceStrategy tells Proxy which backend to send each request to.
+type BalanceStrategy int
+
+const (
+	// RoundRobin is the zero value of BalanceStrategy. Proxy with this
+	// strategy sends requests to the backends in turn.
+	RoundRobin BalanceStrategy = iota
+
+	// LeastConnections strategy sends the request to the backend with the
+	// fewest requests in flight, so that slow backends get less traffic.
+	LeastConnections
+
+	// WeightedRoundRobin strategy sends requests to the backends in turn,
+	// in proportion to their weights (see Backend.Weight). Requests to the
+	// same backend are interleaved with the others rather than sent in a
+	// row.
+	WeightedRoundRobin
+)
+
+// Backend is an upstream server that Proxy forwards requests to.
+type Backend struct {
+	// URL is the base URL of the backend, e.g. "http://10.0.0.1:8080/api".
+	// Request paths are appended to its path.
+	URL string
+
+	// Weight is the share of requests the backend gets with
+	// WeightedRoundRobin strategy relative to the other backends. Zero
+	// means 1.
+	Weight int
+
+	// Pool configures the connections to the backend. Nil means
+	// ProxyOptions.Pool.
+	Pool *PoolOptions
+}
+
+// PoolOptions configures the pool of connections to a backend. Each backend
+// has a pool of its own.
+type PoolOptions struct {
+	// MaxIdleConns is the number of idle connections kept open for reuse.
+	// Zero means 100.
+	MaxIdleConns int
+
+	// MaxConns limits the number of connections, including those in use.
+	// Requests wait for a connection once the limit is reached. Zero means
+	// no limit.
+	MaxConns int
+
+	// IdleConnTimeout is how long idle connections are kept open. Zero
+	// means 90 seconds.
+	IdleConnTimeout time.Duration
+
+	// DialTimeout is how long connecting to the backend may take. Zero
+	// means 30 seconds.
+	DialTimeout time.Duration
+
+	// ResponseHeaderTimeout is how long the backend may take to respond
+	// once the request is sent. Zero means no timeout.
+	ResponseHeaderTimeout time.Duration
+
+	// TLSClientConfig configures connections to backends with "https"
+	// scheme. Nil means the default configuration.
+	TLSClientConfig *tls.Config
+}
+
+// ProxyOptions configures Proxy.
+type ProxyOptions struct {
+	// Backends are the upstream servers requests are forwarded to. If
+	// Resolver is set, they are only used until it resolves the backends.
+	Backends []Backend
+
+	// Resolver, if set, resolves the backends and resolves them again once
+	// their TTL is over, so that the backends may change while the server
+	// runs. Call Proxy.Close to stop it.
+	Resolver UpstreamResolver
+
+	// Strategy tells which backend each request is sent to.
+	Strategy BalanceStrategy
+
+	// Pool configures the connections to the backends that don't have
+	// pool options of their own.
+	Pool PoolOptions
+
+	// PreserveHost keeps the Host header of the request instead of setting
+	// it to the host of the backend.
+	PreserveHost bool
+
+	// Retry, if set, makes Proxy retry idempotent requests that failed
+	// with another backend.
+	Retry *RetryPolicy
+
+	// Breaker, if set, gives every backend a circuit breaker, so that a
+	// failing backend gets no requests until it recovers.
+	Breaker *BreakerOptions
+
+	// ActiveHealth, if set, makes Proxy probe the backends periodically and
+	// send requests only to those that pass. Call Proxy.Close to stop the
+	// probes.
+	ActiveHealth *ActiveHealthCheck
+
+	// PassiveHealth, if set, makes Proxy eject the backends that fail to
+	// serve requests for a while.
+	PassiveHealth *PassiveHealthCheck
+
+	// Logger, if set, logs the events of Proxy that happen outside of
+	// requests, such as failures to resolve the backends, at error level.
+	// Without a logger, these events are silent.
+	Logger *slog.Logger
+}
+
+// RetryPolicy tells Proxy when and how to retry requests. Only idempotent
+// requests are retried: those with GET, HEAD, OPTIONS, TRACE, PUT or DELETE
+// method and those with Idempotency-Key or X-Idempotency-Key header.
+type RetryPolicy struct {
+	// MaxAttempts is the number of attempts, including the first one. Zero
+	// means 3.
+	MaxAttempts int
+
+	// Backoff is the delay before the second attempt. It doubles with each
+	// attempt after that. Zero means 50 milliseconds.
+	Backoff time.Duration
+
+	// MaxBackoff limits the delay between attempts. Zero means 1 second.
+	MaxBackoff time.Duration
+
+	// Statuses are the status codes that make Proxy retry the request with
+	// another backend, unless it is the last attempt. Nil means 502, 503
+	// and 504. Requests are always retried if the backend can't be reached.
+	Statuses []int
+
+	// MaxBodyBytes limits the size of request bodies buffered so that they
+	// can be sent again. Requests with larger bodies or bodies of unknown
+	// size are not retried. Zero means 1 MiB.
+	MaxBodyBytes int64
+}
+
+// Proxy is a reverse proxy that balances requests among several backends.
+// Requests are forwarded with X-Forwarded-For, X-Forwarded-Host and
+// X-Forwarded-Proto headers set. If the backend can't be reached, the client
+// gets "502 Bad Gateway" through Error; if all the backends are cut off by
+// their circuit breakers or health checks, it gets "503 Service Unavailable".
+// Proxy is safe for concurrent use.
+type Proxy struct {
+	opts     ProxyOptions
+	retry    *RetryPolicy
+	active   *ActiveHealthCheck
+	passive  *PassiveHealthCheck
+	backends atomic.Pointer[[]*backend]
+	next     atomic.Uint64
+	mu       sync.Mutex // guards weighted round-robin state
+
+	done      chan struct{} // closed by Close
+	closeOnce sync.Once
+	workers   sync.WaitGroup // health checks and resolution
+}
+
+// backend is a Backend of Proxy with its state.
+type backend struct {
+	url       *url.URL
+	weight    int
+	current   int // guarded by Proxy.mu
+	active    atomic.Int64
+	breaker   *breaker
+	transport *http.Transport
+	proxy     *httputil.ReverseProxy
+
+	healthy      atomic.Bool  // whether active health checks pass
+	ejectedUntil atomic.Int64 // Unix time in nanoseconds
+
+	mu     sync.Mutex
+	passed int // probes passed in a row
+	failed int // probes failed in a row
+	errors int // requests failed in a row
+}
+
+// attempt is the outcome of the attempt to forward the request to a backend.
+type attempt struct {
+	// retry tells whether the request is going to be retried if the backend
+	// responds with one of the retry statuses.
+	retry bool
+
+	// status is the status code the backend responded with.
+	status int
+
+	// err is the error that prevented the response from being sent.
+	err error
+}
+
+// errRetry is the error of the attempt that got one of the retry statuses.
+var errRetry = errors.New("mux: backend responded with retry status")
+
+// NewProxy returns pointer to a new Proxy. If Resolver is set, it resolves
+// the backends before it returns. If active health checks are set, it starts
+// probing the backends. It panics if there are neither backends nor resolver
+// or if URL of some of the backends can't be parsed.
+func NewProxy(opts *ProxyOptions) *Proxy {
+	if len(opts.Backends) == 0 && opts.Resolver == nil {
+		panic("can't proxy without backends")
+	}
+	p := &Proxy{
+		opts:    *opts,
+		retry:   opts.Retry.withDefaults(),
+		active:  opts.ActiveHealth.withDefaults(),
+		passive: opts.PassiveHealth.withDefaults(),
+		done:    make(chan struct{}),
+	}
+	p.backends.Store(new([]*backend))
+	if err := p.update(opts.Backends); err != nil {
+		panic(err.Error())
+	}
+	if opts.Resolver != nil {
+		ttl := p.resolve()
+		p.workers.Add(1)
+		go p.refresh(ttl)
+	}
+	if p.active != nil {
+		p.workers.Add(1)
+		go p.check()
+	}
+	return p
+}
+
+// Proxy method mounts Proxy under the path prefix (see Mount), so that
+// requests under it are forwarded to the backends with the prefix cut:
+//
+//	rtr.Proxy("/api", &mux.ProxyOptions{
+//	    Backends: []mux.Backend{
+//	        {URL: "http://10.0.0.1:8080"},
+//	        {URL: "http://10.0.0.2:8080"},
+//	    },
+//	    Strategy: mux.LeastConnections,
+//	})
+//
+// It returns the new sub-router.
+func (rtr *Router) Proxy(prefix string, opts *ProxyOptions) *Router {
+	return rtr.Mount(prefix, NewProxy(opts))
+}
+
+// upstreams method returns the current backends.
+func (p *Proxy) upstreams() []*backend {
+	return *p.backends.Load()
+}
+
+// update method replaces the backends. Backends with the same URL as the
+// current ones keep their state and pool of connections; idle connections to
+// the removed ones are closed. It returns an error if URL of some of the
+// backends can't be parsed, in which case nothing is replaced.
+func (p *Proxy) update(backends []Backend) error {
+	urls := make([]*url.URL, len(backends))
+	for i, b := range backends {
+		u, err := url.Parse(b.URL)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("can't parse backend URL %s", b.URL)
+		}
+		urls[i] = u
+	}
+
+	old := make(map[string]*backend)
+	for _, be := range p.upstreams() {
+		old[be.url.String()] = be
+	}
+	next := make([]*backend, 0, len(backends))
+	for i, b := range backends {
+		if be, ok := old[urls[i].String()]; ok {
+			delete(old, urls[i].String())
+			p.mu.Lock()
+			be.weight = weightOf(b)
+			p.mu.Unlock()
+			next = append(next, be)
+			continue
+		}
+		next = append(next, p.newBackend(urls[i], b))
+	}
+	p.backends.Store(&next)
+	for _, be := range old {
+		be.transport.CloseIdleConnections()
+	}
+	return nil
+}
+
+// weightOf returns the weight of the backend.
+func weightOf(b Backend) int {
+	if b.Weight <= 0 {
+		return 1
+	}
+	return b.Weight
+}
+
+// newBackend method sets up the backend with the URL and its pool of
+// connections.
+func (p *Proxy) newBackend(u *url.URL, b Backend) *backend {
+	opts := &p.opts
+	pool := b.Pool
+	if pool == nil {
+		pool = &opts.Pool
+	}
+
+	be := &backend{
+		url:       u,
+		weight:    weightOf(b),
+		breaker:   newBreaker(opts.Breaker),
+		transport: pool.transport(),
+	}
+	be.healthy.Store(true)
+	be.proxy = &httputil.ReverseProxy{
+		Rewrite: func(pr *httputil.ProxyRequest) {
+			pr.SetURL(u)
+			pr.SetXForwarded()
+			if opts.PreserveHost {
+				pr.Out.Host = pr.In.Host
+			}
+		},
+		Transport: be.transport,
+		ModifyResponse: func(res *http.Response) error {
+			a := res.Request.Context().Value(attemptKey).(*attempt)
+			a.status = res.StatusCode
+			if a.retry && p.retry.retries(res.StatusCode) {
+				return errRetry
+			}
+			return nil
+		},
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			// Leave the response to the Proxy, which may retry.
+			r.Context().Value(attemptKey).(*attempt).err = err
+		},
+	}
+	return be
+}
+
+// transport method returns a new http.Transport configured by PoolOptions.
+func (opts *PoolOptions) transport() *http.Transport {
+	idle := opts.MaxIdleConns
+	if idle == 0 {
+		idle = 100
+	}
+	idleTimeout := opts.IdleConnTimeout
+	if idleTimeout == 0 {
+		idleTimeout = 90 * time.Second
+	}
+	dialTimeout := opts.DialTimeout
+	if dialTimeout == 0 {
+		dialTimeout = 30 * time.Second
+	}
+
+	dialer := &net.Dialer{Timeout: dialTimeout, KeepAlive: 30 * time.Second}
+	return &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          idle,
+		MaxIdleConnsPerHost:   idle,
+		MaxConnsPerHost:       opts.MaxConns,
+		IdleConnTimeout:       idleTimeout,
+		ResponseHeaderTimeout: opts.ResponseHeaderTimeout,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: time.Second,
+		TLSClientConfig:       opts.TLSClientConfig,
+	}
+}
+
+// ServeHTTP method forwards the request to one of the backends, retrying it
+// with others if needed. It ensures that Proxy implements the http.Handler
+// interface.
+func (p *Proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	attempts, body, err := p.attempts(r)
+	if err != nil {
+		Error(w, r, err)
+		return
+	}
+
+	var tried []*backend
+	for i := 1; ; i++ {
+		b := p.acquire(tried)
+		if b == nil {
+			Error(w, r, NewHTTPError(http.StatusServiceUnavailable,
+				"no backend available"))
+			return
+		}
+		tried = append(tried, b)
+
+		a := &attempt{retry: i < attempts}
+		req := r.WithContext(context.WithValue(r.Context(), attemptKey, a))
+		if body != nil {
+			req.Body = io.NopCloser(bytes.NewReader(body))
+		}
+		b.active.Add(1)
+		b.proxy.ServeHTTP(w, req)
+		b.active.Add(-1)
+
+		switch {
+		case r.Context().Err() != nil:
+			b.breaker.release()
+		case a.err != nil || unavailable(a.status):
+			b.breaker.failure()
+			b.served(p.passive, true)
+		default:
+			b.breaker.success()
+			b.served(p.passive, false)
+		}
+		if a.err == nil {
+			return
+		}
+		// Sleep is cut short if the client goes away.
+		if !a.retry || !sleep(r.Context(), p.retry.backoff(i)) {
+			Error(w, r, &HTTPError{http.StatusBadGateway, a.err})
+			return
+		}
+	}
+}
+
+// attempts method returns the number of attempts the request may take. If the
+// request is retried, its body is read, so that it can be sent again.
+func (p *Proxy) attempts(r *http.Request) (n int, body []byte, err error) {
+	if p.retry == nil || !idempotent(r) {
+		return 1, nil, nil
+	}
+	if r.Body == nil || r.Body == http.NoBody {
+		return p.retry.MaxAttempts, nil, nil
+	}
+	if r.ContentLength < 0 || r.ContentLength > p.retry.MaxBodyBytes {
+		return 1, nil, nil
+	}
+	if body, err = readBody(r, p.retry.MaxBodyBytes); err != nil {
+		return 0, nil, err
+	}
+	return p.retry.MaxAttempts, body, nil
+}
+
+// acquire method picks the backend for the next attempt and acquires its
+// circuit breaker. Backends that were tried already are avoided unless there
+// are no others. It returns nil if none of the backends is available.
+func (p *Proxy) acquire(tried []*backend) *backend {
+	now := time.Now()
+	backends := p.upstreams()
+	for range backends {
+		var ready, fresh []*backend
+		for _, b := range backends {
+			if !b.available(now) {
+				continue
+			}
+			ready = append(ready, b)
+			if !containsBackend(tried, b) {
+				fresh = append(fresh, b)
+			}
+		}
+		if len(fresh) > 0 {
+			ready = fresh
+		}
+		if len(ready) == 0 {
+			return nil
+		}
+		// Breaker may have let another request through in the meantime.
+		if b := p.pick(ready); b.breaker.acquire() {
+			return b
+		}
+	}
+	return nil
+}
+
+// pick method chooses one of the backends according to the strategy.
+func (p *Proxy) pick(backends []*backend) *backend {
+	switch p.opts.Strategy {
+	case LeastConnections:
+		// Start with the next backend in turn, so that ties are broken
+		// evenly.
+		start := int(p.next.Add(1) - 1)
+		var best *backend
+		for i := range backends {
+			b := backends[(start+i)%len(backends)]
+			if best == nil || b.active.Load() < best.active.Load() {
+				best = b
+			}
+		}
+		return best
+
+	case WeightedRoundRobin:
+		// Smooth weighted round-robin: every backend gains its weight, and
+		// the one that gained the most so far is picked and set back by the
+		// total weight.
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		var best *backend
+		total := 0
+		for _, b := range backends {
+			b.current += b.weight
+			total += b.weight
+			if best == nil || b.current > best.current {
+				best = b
+			}
+		}
+		best.current -= total
+		return best
+
+	default:
+		n := p.next.Add(1) - 1
+		return backends[n%uint64(len(backends))]
+	}
+}
+
+// containsBackend tells whether the backend is among the backends.
+func containsBackend(backends []*backend, b *backend) bool {
+	for _, other := range backends {
+		if other == b {
+			return true
+		}
+	}
+	return false
+}
+
+// withDefaults method returns a copy of the policy with zero fields set to
+// their defaults. It returns nil if the policy is nil.
+func (rp *RetryPolicy) withDefaults() *RetryPolicy {
+	if rp == nil {
+		return nil
+	}
+	c := *rp
+	if c.MaxAttempts <= 0 {
+		c.MaxAttempts = 3
+	}
+	if c.Backoff <= 0 {
+		c.Backoff = 50 * time.Millisecond
+	}
+	if c.MaxBackoff <= 0 {
+		c.MaxBackoff = time.Second
+	}
+	if c.Statuses == nil {
+		c.Statuses = []int{
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		}
+	}
+	if c.MaxBodyBytes <= 0 {
+		c.MaxBodyBytes = 1 << 20
+	}
+	return &c
+}
+
+// retries method tells whether responses with the status code are retried.
+func (rp *RetryPolicy) retries(code int) bool {
+	if rp == nil {
+		return false
+	}
+	for _, s := range rp.Statuses {
+		if s == code {
+			return true
+		}
+	}
+	return false
+}
+
+// backoff method returns the delay before the attempt that follows the n-th
+// one. Half of the delay is random, so that clients that failed together
+// don't retry together.
+func (rp *RetryPolicy) backoff(n int) time.Duration {
+	d := rp.Backoff
+	for i := 1; i < n && d < rp.MaxBackoff; i++ {
+		d *= 2
+	}
+	if d > rp.MaxBackoff {
+		d = rp.MaxBackoff
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// unavailable tells whether the status code means that the backend is down.
+func unavailable(code int) bool {
+	return code == http.StatusBadGateway ||
+		code == http.StatusServiceUnavailable ||
+		code == http.StatusGatewayTimeout
+}
+
+// idempotent tells whether the request may be sent more than once.
+func idempotent(r *http.Request) bool {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions,
+		http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return r.Header.Get("Idempotency-Key") != "" ||
+		r.Header.Get("X-Idempotency-Key") != ""
+}
+
+// sleep waits for the duration unless the context is done first. It tells
+// whether the whole duration has passed.
+func sleep(ctx context.Context, d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
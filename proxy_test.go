@@ -0,0 +1,256 @@
+package mux
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// backendServer starts a server that responds with its name and the path it
+// got.
+func backendServer(t *testing.T, name string) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, "%s %s", name, r.URL.RequestURI())
+		},
+	))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// proxied sends the request through the Router and returns the response body.
+func proxied(t *testing.T, rtr http.Handler, path string) string {
+	rec, req, err := request(http.MethodGet, path, nil)
+	assert.NoError(t, err)
+	rtr.ServeHTTP(rec, req)
+	return rec.Body.String()
+}
+
+func TestProxy(t *testing.T) {
+	a, b := backendServer(t, "a"), backendServer(t, "b")
+	rtr := New()
+	rtr.Proxy("/api", &ProxyOptions{Backends: []Backend{
+		{URL: a.URL + "/v1"},
+		{URL: b.URL},
+	}})
+
+	assert.Equal(t, "a /v1/users?page=2", proxied(t, rtr, "/api/users?page=2"))
+	assert.Equal(t, "b /users?page=2", proxied(t, rtr, "/api/users?page=2"))
+	assert.Equal(t, "a /v1/", proxied(t, rtr, "/api"))
+	//-------------------- Another Test Case --------------------
+	var host, forwarded string
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			host, forwarded = r.Host, r.Header.Get("X-Forwarded-Host")
+		},
+	))
+	defer srv.Close()
+	rtr = New()
+	rtr.Proxy("/", &ProxyOptions{
+		Backends:     []Backend{{URL: srv.URL}},
+		PreserveHost: true,
+	})
+	rec, req, _ := request(http.MethodGet, "/", nil)
+	req.Host = "example.com"
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, "example.com", host)
+	assert.Equal(t, "example.com", forwarded)
+	//-------------------- Another Test Case --------------------
+	srv.Close()
+	rtr = New()
+	rtr.Proxy("/", &ProxyOptions{Backends: []Backend{{URL: srv.URL}}})
+	rec, req, _ = request(http.MethodGet, "/", nil)
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadGateway, rec.Code)
+	//-------------------- Another Test Case --------------------
+	assert.Panics(t, func() { NewProxy(&ProxyOptions{}) })
+	assert.Panics(t, func() {
+		NewProxy(&ProxyOptions{Backends: []Backend{{URL: "localhost"}}})
+	})
+}
+
+func TestProxyWeightedRoundRobin(t *testing.T) {
+	a, b := backendServer(t, "a"), backendServer(t, "b")
+	p := NewProxy(&ProxyOptions{
+		Backends: []Backend{{URL: a.URL, Weight: 3}, {URL: b.URL}},
+		Strategy: WeightedRoundRobin,
+	})
+	var got []string
+	for i := 0; i < 8; i++ {
+		got = append(got, proxied(t, p, "/")[:1])
+	}
+	assert.Equal(t, []string{"a", "a", "b", "a", "a", "a", "b", "a"}, got)
+}
+
+func TestProxyLeastConnections(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			started <- struct{}{}
+			<-release
+			io.WriteString(w, "slow")
+		},
+	))
+	defer slow.Close()
+	fast := backendServer(t, "fast")
+	p := NewProxy(&ProxyOptions{
+		Backends: []Backend{{URL: slow.URL}, {URL: fast.URL}},
+		Strategy: LeastConnections,
+	})
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		assert.Equal(t, "slow", proxied(t, p, "/"))
+	}()
+	<-started
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, "fast /", proxied(t, p, "/"))
+	}
+	close(release)
+	wg.Wait()
+}
+
+func TestPoolOptions(t *testing.T) {
+	tr := (&PoolOptions{}).transport()
+	assert.Equal(t, 100, tr.MaxIdleConnsPerHost)
+	assert.Equal(t, 90*time.Second, tr.IdleConnTimeout)
+	assert.Equal(t, 0, tr.MaxConnsPerHost)
+
+	p := NewProxy(&ProxyOptions{
+		Backends: []Backend{
+			{URL: "http://a"},
+			{URL: "http://b", Pool: &PoolOptions{MaxConns: 5}},
+		},
+		Pool: PoolOptions{MaxIdleConns: 10},
+	})
+	a := p.upstreams()[0].proxy.Transport.(*http.Transport)
+	b := p.upstreams()[1].proxy.Transport.(*http.Transport)
+	assert.Equal(t, 10, a.MaxIdleConnsPerHost)
+	assert.Equal(t, 100, b.MaxIdleConnsPerHost)
+	assert.Equal(t, 5, b.MaxConnsPerHost)
+}
+
+func TestProxyRetry(t *testing.T) {
+	var calls atomic.Int32
+	down := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+		},
+	))
+	defer down.Close()
+	up := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			fmt.Fprintf(w, "up %s", body)
+		},
+	))
+	defer up.Close()
+	p := NewProxy(&ProxyOptions{
+		Backends: []Backend{{URL: down.URL}, {URL: up.URL}},
+		Retry:    &RetryPolicy{Backoff: time.Millisecond},
+	})
+
+	cases := []struct {
+		method string
+		key    string
+		code   int
+		body   string
+	}{
+		{http.MethodGet, "", http.StatusOK, "up "},
+		{http.MethodPut, "", http.StatusOK, "up data"},
+		{http.MethodPost, "", http.StatusServiceUnavailable, ""},
+		{http.MethodPost, "42", http.StatusOK, "up data"},
+	}
+	for _, c := range cases {
+		var body io.Reader
+		if c.method != http.MethodGet {
+			body = strings.NewReader("data")
+		}
+		// Make sure the first attempt goes to the backend that is down.
+		p.next.Store(0)
+		rec, req, err := request(c.method, "/", body)
+		assert.NoError(t, err)
+		if c.key != "" {
+			req.Header.Set("Idempotency-Key", c.key)
+		}
+		p.ServeHTTP(rec, req)
+		assert.Equal(t, c.code, rec.Code, c.method)
+		assert.Equal(t, c.body, rec.Body.String(), c.method)
+	}
+	assert.Equal(t, int32(len(cases)), calls.Load())
+	//-------------------- Another Test Case --------------------
+	down.Close()
+	p = NewProxy(&ProxyOptions{
+		Backends: []Backend{{URL: down.URL}, {URL: down.URL}},
+		Retry:    &RetryPolicy{MaxAttempts: 2, Backoff: time.Millisecond},
+	})
+	rec, req, _ := request(http.MethodGet, "/", nil)
+	p.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadGateway, rec.Code)
+}
+
+func TestProxyBreaker(t *testing.T) {
+	var calls atomic.Int32
+	down := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(http.StatusBadGateway)
+		},
+	))
+	defer down.Close()
+	up := backendServer(t, "up")
+	p := NewProxy(&ProxyOptions{
+		Backends: []Backend{{URL: down.URL}, {URL: up.URL}},
+		Breaker:  &BreakerOptions{Failures: 2},
+	})
+
+	codes := map[int]int{}
+	for i := 0; i < 10; i++ {
+		rec, req, _ := request(http.MethodGet, "/", nil)
+		p.ServeHTTP(rec, req)
+		codes[rec.Code]++
+	}
+	assert.Equal(t, map[int]int{http.StatusOK: 8, http.StatusBadGateway: 2},
+		codes)
+	assert.Equal(t, int32(2), calls.Load())
+	//-------------------- Another Test Case --------------------
+	p = NewProxy(&ProxyOptions{
+		Backends: []Backend{{URL: down.URL}},
+		Breaker:  &BreakerOptions{Failures: 1},
+	})
+	for _, code := range []int{
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+	} {
+		rec, req, _ := request(http.MethodGet, "/", nil)
+		p.ServeHTTP(rec, req)
+		assert.Equal(t, code, rec.Code)
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	rp := (&RetryPolicy{
+		Backoff:    100 * time.Millisecond,
+		MaxBackoff: 300 * time.Millisecond,
+	}).withDefaults()
+	for n, max := range map[int]time.Duration{
+		1: 100 * time.Millisecond,
+		2: 200 * time.Millisecond,
+		5: 300 * time.Millisecond,
+	} {
+		d := rp.backoff(n)
+		assert.True(t, d >= max/2 && d <= max, "%d: %s", n, d)
+	}
+}
@@ -0,0 +1,159 @@
+package mux
+
+import (
+	"regexp"
+	"regexp/syntax"
+	"sort"
+	"strings"
+)
+
+// routeIndex is a compiled matching engine for router's sub-routes. Routes
+// are indexed by the literal prefix of the path they accept so that only those
+// routes whose prefix matches the request path need to be checked. This makes
+// the search for candidates take O(path length) time regardless of the number
+// of routes.
+//
+// Routes that can't be indexed (e.g. routes without path filters or with path
+// filters that are not anchored to the start of the path) are stored in the
+// root node and checked for every request.
+type routeIndex struct {
+	root *radixNode
+
+	// routes is the snapshot of the routes the index was compiled for.
+	routes []*Router
+
+	// order lists indices of the routes in the order they must be checked
+	// in. The tree stores positions in this list rather than route indices.
+	order []int
+}
+
+// radixNode is a node of the compressed prefix tree used by routeIndex.
+type radixNode struct {
+	// prefix is the part of the key that this node adds to its parent.
+	prefix string
+
+	// children are the nodes whose keys extend this node's key. No two
+	// children share the first byte of their prefix.
+	children []*radixNode
+
+	// routes contains the indices of the routes whose key ends at this node.
+	routes []int
+}
+
+// newRouteIndex compiles routeIndex for given routes. Routes are ordered by
+// priority and, if specific is true, by specificity; routes that are equal in
+// that regard keep the order of registration.
+func newRouteIndex(routes []*Router, specific bool) *routeIndex {
+	idx := &routeIndex{&radixNode{}, routes, make([]int, len(routes))}
+	ranks := make([][]int, len(routes))
+	for i, route := range routes {
+		idx.order[i] = i
+		if specific {
+			ranks[i] = route.specificity()
+		}
+	}
+	sort.SliceStable(idx.order, func(i, j int) bool {
+		a, b := idx.order[i], idx.order[j]
+		if routes[a].priority != routes[b].priority {
+			return routes[a].priority > routes[b].priority
+		}
+		return specific && moreSpecific(ranks[a], ranks[b])
+	})
+	for pos, i := range idx.order {
+		idx.root.insert(routes[i].literalPrefix(), pos)
+	}
+	return idx
+}
+
+// candidates method returns indices of the routes that may match given path
+// in the order they must be checked in.
+func (idx *routeIndex) candidates(path string) []int {
+	out := idx.root.collect(path, nil)
+	sort.Ints(out)
+	for i, pos := range out {
+		out[i] = idx.order[pos]
+	}
+	return out
+}
+
+// insert method adds route index to the tree under the given key.
+func (n *radixNode) insert(key string, route int) {
+	if key == "" {
+		n.routes = append(n.routes, route)
+		return
+	}
+
+	for _, child := range n.children {
+		l := commonPrefix(child.prefix, key)
+		if l == 0 {
+			continue
+		}
+
+		// Split the child if the key diverges in the middle of its prefix.
+		if l < len(child.prefix) {
+			split := &radixNode{
+				prefix:   child.prefix[l:],
+				children: child.children,
+				routes:   child.routes,
+			}
+			child.prefix = child.prefix[:l]
+			child.children = []*radixNode{split}
+			child.routes = nil
+		}
+
+		child.insert(key[l:], route)
+		return
+	}
+
+	n.children = append(n.children, &radixNode{key, nil, []int{route}})
+}
+
+// collect method appends routes of every node whose key is a prefix of the
+// path to out.
+func (n *radixNode) collect(path string, out []int) []int {
+	out = append(out, n.routes...)
+	for _, child := range n.children {
+		if strings.HasPrefix(path, child.prefix) {
+			return child.collect(path[len(child.prefix):], out)
+		}
+	}
+	return out
+}
+
+// commonPrefix returns the length of the longest common prefix of a and b.
+func commonPrefix(a string, b string) int {
+	i := 0
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i++
+	}
+	return i
+}
+
+// literalPrefix method returns a literal string that every path accepted by
+// the router must begin with. It returns an empty string if there is no such
+// prefix or it can't be determined.
+func (rtr *Router) literalPrefix() string {
+	if rtr.filters.PathPrefix != nil {
+		return string(*rtr.filters.PathPrefix)
+	}
+	if rtr.filters.Path != nil && anchored(rtr.filters.Path.Regexp) {
+		prefix, _ := rtr.filters.Path.Regexp.LiteralPrefix()
+		return prefix
+	}
+	return ""
+}
+
+// anchored tells whether the regular expression can only match at the start
+// of the text.
+func anchored(regex *regexp.Regexp) bool {
+	re, err := syntax.Parse(regex.String(), syntax.Perl)
+	if err != nil {
+		return false
+	}
+	re = re.Simplify()
+	if re.Op == syntax.OpBeginText {
+		return true
+	}
+	return re.Op == syntax.OpConcat && len(re.Sub) > 0 &&
+		re.Sub[0].Op == syntax.OpBeginText
+}
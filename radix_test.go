@@ -0,0 +1,107 @@
+package mux
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRadixNode(t *testing.T) {
+	root := &radixNode{}
+	root.insert("/api", 0)
+	root.insert("/api/users", 1)
+	root.insert("/apiary", 2)
+	root.insert("", 3)
+	root.insert("/static", 4)
+
+	assert.Equal(t, []int{3, 0, 1}, root.collect("/api/users/42", nil))
+	assert.Equal(t, []int{3, 0, 2}, root.collect("/apiary", nil))
+	assert.Equal(t, []int{3, 4}, root.collect("/static/app.js", nil))
+	assert.Equal(t, []int{3}, root.collect("/home", nil))
+}
+
+func TestAnchored(t *testing.T) {
+	assert.True(t, anchored(regexp.MustCompile(`^/users/\d+$`)))
+	assert.True(t, anchored(regexp.MustCompile(`^`)))
+	assert.False(t, anchored(regexp.MustCompile(`/users`)))
+	assert.False(t, anchored(regexp.MustCompile(`^/a|/b`)))
+	assert.False(t, anchored(regexp.MustCompile(`(?m)^/a`)))
+}
+
+func TestIndexedMatch(t *testing.T) {
+	rtr := New()
+	for i := 0; i < 100; i++ {
+		n := i
+		rtr.Subrouter().PathPrefix(fmt.Sprintf("/p%d/", n)).HandleFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprintf(w, "prefix %d", n)
+			},
+		)
+	}
+	rtr.Subrouter().Path("/users").HandleFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, "users")
+		},
+	)
+	rtr.Subrouter().StrictPath(false).Path("/log").HandleFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, "log")
+		},
+	)
+
+	cases := map[string]string{
+		"/p42/x":        "prefix 42",
+		"/p4/x":         "prefix 4",
+		"/users":        "users",
+		"/users/x":      "404 page not found\n",
+		"/x/log/thing":  "log",
+		"/p99/anything": "prefix 99",
+	}
+	for path, body := range cases {
+		rec, req, err := request(http.MethodGet, path, nil)
+		assert.NoError(t, err)
+		rtr.ServeHTTP(rec, req)
+		assert.Equal(t, body, rec.Body.String(), path)
+	}
+	//-------------------- Another Test Case --------------------
+	// Changing path filter after the index was built must be picked up.
+	rtr.routes[0].PathPrefix("/changed")
+	rec, req, err := request(http.MethodGet, "/changed/x", nil)
+	assert.NoError(t, err)
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, "prefix 0", rec.Body.String())
+}
+
+//-------------------- Another Test Case --------------------
+
+func TestPriority(t *testing.T) {
+	text := func(s string) View {
+		return func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(s))
+		}
+	}
+
+	rtr := New()
+	rtr.Subrouter().PathPrefix("/").HandleFunc(text("catch-all")).Priority(-1)
+	rtr.Get("/users/{name:segment}", text("user"))
+	rtr.Get("/users/new", text("new")).Priority(1)
+	rtr.Get("/users/me", text("me"))
+	rtr.Get("/users/admin", text("admin")).Priority(1)
+
+	cases := map[string]string{
+		"/users/new":   "new",
+		"/users/admin": "admin",
+		"/users/me":    "user",
+		"/users/john":  "user",
+		"/about":       "catch-all",
+	}
+	for path, body := range cases {
+		rec, req, err := request(http.MethodGet, path, nil)
+		assert.NoError(t, err)
+		rtr.ServeHTTP(rec, req)
+		assert.Equal(t, body, rec.Body.String(), path)
+	}
+}
@@ -0,0 +1,294 @@
+package mux
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Limit is the rate of a token bucket: the bucket holds up to Burst tokens and
+// gets Rate new tokens per second. Every request takes a token.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// PerSecond returns Limit of n requests per second with a burst of n.
+func PerSecond(n int) Limit {
+	return Limit{float64(n), n}
+}
+
+// PerMinute returns Limit of n requests per minute with a burst of n.
+func PerMinute(n int) Limit {
+	return Limit{float64(n) / 60, n}
+}
+
+// LimitResult is the outcome of taking a token from a bucket.
+type LimitResult struct {
+	// Allowed tells whether a token was taken.
+	Allowed bool
+
+	// Remaining is the number of tokens left in the bucket.
+	Remaining int
+
+	// Reset is the time it takes for the bucket to fill up.
+	Reset time.Duration
+
+	// RetryAfter is the time it takes for the next token to become available
+	// if none was taken.
+	RetryAfter time.Duration
+}
+
+// RateLimitStore keeps token buckets. The default one, MemoryStore, keeps them
+// in memory, so each instance of the server limits requests on its own.
+// Deployments with several instances may share the buckets in a store like
+// Redis by implementing this interface, e.g. with a Lua script that does what
+// MemoryStore.Take does atomically.
+type RateLimitStore interface {
+	// Take takes a token from the bucket identified by the key.
+	Take(ctx context.Context, key string, limit Limit) (LimitResult, error)
+}
+
+// KeyFunc returns the key that identifies the bucket the request takes a token
+// from. Requests with an empty key are not limited.
+type KeyFunc func(r *http.Request) string
+
+// KeyByIP is KeyFunc that gives each client its own bucket (see ClientIP).
+func KeyByIP(r *http.Request) string {
+	return ClientIP(r)
+}
+
+// KeyByHeader returns KeyFunc that gives a bucket to each value of the header,
+// e.g. "X-API-Key". Requests without the header are not limited.
+func KeyByHeader(name string) KeyFunc {
+	return func(r *http.Request) string {
+		if v := r.Header.Get(name); v != "" {
+			return name + ":" + v
+		}
+		return ""
+	}
+}
+
+// KeyByRoute is KeyFunc that gives each route its own bucket, shared by all
+// clients. The route is the one that is going to serve the request among the
+// sub-routers of the Router that the limiter is registered on with Use.
+// Requests that don't match any route are not limited.
+func KeyByRoute(r *http.Request) string {
+	rtr, ok := r.Context().Value(routerKey).(*Router)
+	if !ok {
+		return ""
+	}
+	route := rtr.lookup(r)
+	if route == nil {
+		return ""
+	}
+	info := route.Info()
+	return describe(&RouteInfo{Methods: info.Methods, Prefixes: info.Prefixes,
+		Path: info.Path})
+}
+
+// lookup method returns the route whose handler is going to serve the request,
+// or nil if there is none.
+func (rtr *Router) lookup(r *http.Request) *Router {
+	for {
+		sub, ok := rtr.Match(r)
+		if !ok {
+			break
+		}
+		rtr, r = sub, sub.trim(r)
+	}
+	if rtr.handler == nil {
+		return nil
+	}
+	return rtr
+}
+
+// RateLimitOptions configures RateLimiter.
+type RateLimitOptions struct {
+	// Key identifies the bucket of the request. If nil, KeyByIP is used.
+	Key KeyFunc
+
+	// Store keeps the buckets. If nil, a new MemoryStore is used.
+	Store RateLimitStore
+
+	// Name is prepended to the keys, so that limiters that share the Store
+	// have separate buckets.
+	Name string
+}
+
+// RateLimiter is an http.Handler that limits the rate of requests with token
+// buckets. Register it as middleware with Use:
+//
+//	api := rtr.Subrouter().PathPrefix("/api").
+//	    Use(mux.NewRateLimiter(mux.Limit{Rate: 10, Burst: 20}, nil))
+//
+// Every limited response carries RateLimit-Limit, RateLimit-Remaining and
+// RateLimit-Reset headers, so that clients can slow down before they hit the
+// limit. If several limiters apply, the headers describe the one with the
+// fewest remaining requests. Requests that find their bucket empty get
+// "429 Too Many Requests" with Retry-After header through Error. If the store
+// fails, requests are let through and the error is logged
+// (see Router.Logger), so that an outage of the store doesn't take the server
+// down with it.
+type RateLimiter struct {
+	limit Limit
+	key   KeyFunc
+	store RateLimitStore
+	name  string
+}
+
+// NewRateLimiter returns pointer to a RateLimiter with given limit. If opts is
+// nil, defaults are used.
+func NewRateLimiter(limit Limit, opts *RateLimitOptions) *RateLimiter {
+	rl := &RateLimiter{limit, KeyByIP, nil, ""}
+	if opts != nil {
+		if opts.Key != nil {
+			rl.key = opts.Key
+		}
+		rl.store, rl.name = opts.Store, opts.Name
+	}
+	if rl.store == nil {
+		rl.store = NewMemoryStore()
+	}
+	return rl
+}
+
+// ServeHTTP method ensures that RateLimiter implements the http.Handler
+// interface.
+func (rl *RateLimiter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	key := rl.key(r)
+	if key == "" {
+		return
+	}
+	if rl.name != "" {
+		key = rl.name + ":" + key
+	}
+	res, err := rl.store.Take(r.Context(), key, rl.limit)
+	if err != nil {
+		if l := logger(r); l != nil {
+			l.ErrorContext(r.Context(), "mux: rate limit store failed",
+				"key", key, "err", err)
+		}
+		return
+	}
+	rl.setHeaders(w.Header(), res)
+	if !res.Allowed {
+		w.Header().Set("Retry-After", ceilSeconds(res.RetryAfter))
+		Error(w, r, NewHTTPError(http.StatusTooManyRequests,
+			"rate limit exceeded"))
+	}
+}
+
+// setHeaders method sets RateLimit headers according to the result, unless
+// they describe a limit with fewer remaining requests already.
+func (rl *RateLimiter) setHeaders(h http.Header, res LimitResult) {
+	if v := h.Get("RateLimit-Remaining"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n <= res.Remaining {
+			return
+		}
+	}
+	h.Set("RateLimit-Limit", strconv.Itoa(rl.limit.Burst))
+	h.Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+	h.Set("RateLimit-Reset", ceilSeconds(res.Reset))
+}
+
+// RateLimit method registers a RateLimiter with given limit on the Router, so
+// that different sub-routers can have different limits:
+//
+//	rtr.Subrouter().PathPrefix("/search").RateLimit(mux.PerSecond(5), nil)
+//	rtr.Subrouter().PathPrefix("/users").RateLimit(mux.PerSecond(50), nil)
+//
+// See NewRateLimiter.
+func (rtr *Router) RateLimit(limit Limit, opts *RateLimitOptions) *Router {
+	return rtr.Use(NewRateLimiter(limit, opts))
+}
+
+// MemoryStore is RateLimitStore that keeps token buckets in memory. Buckets
+// that have filled up are dropped from time to time, so that the memory used
+// is proportional to the number of active keys.
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	swept   time.Time
+	now     func() time.Time
+}
+
+// bucket is a token bucket.
+type bucket struct {
+	tokens float64
+	last   time.Time
+	full   time.Time
+}
+
+// NewMemoryStore returns pointer to an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets: make(map[string]*bucket),
+		swept:   time.Now(),
+		now:     time.Now,
+	}
+}
+
+// Take method ensures that MemoryStore implements the RateLimitStore
+// interface.
+func (s *MemoryStore) Take(
+	ctx context.Context, key string, limit Limit,
+) (LimitResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	s.sweep(now)
+
+	burst := float64(limit.Burst)
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: burst, last: now}
+		s.buckets[key] = b
+	}
+	if limit.Rate > 0 {
+		elapsed := now.Sub(b.last).Seconds()
+		b.tokens = math.Min(burst, b.tokens+elapsed*limit.Rate)
+	}
+	b.last = now
+
+	res := LimitResult{}
+	if b.tokens >= 1 {
+		b.tokens--
+		res.Allowed = true
+	} else if limit.Rate > 0 {
+		res.RetryAfter = seconds((1 - b.tokens) / limit.Rate)
+	}
+	res.Remaining = int(b.tokens)
+	if limit.Rate > 0 {
+		res.Reset = seconds((burst - b.tokens) / limit.Rate)
+		b.full = now.Add(res.Reset)
+	}
+	return res, nil
+}
+
+// sweep method drops buckets that have filled up, at most once a minute.
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.swept) < time.Minute {
+		return
+	}
+	s.swept = now
+	for key, b := range s.buckets {
+		// Buckets that never refill have zero full time and are kept.
+		if !b.full.IsZero() && !now.Before(b.full) {
+			delete(s.buckets, key)
+		}
+	}
+}
+
+// ceilSeconds formats the duration as a whole number of seconds, rounded up.
+func ceilSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
+
+// seconds converts seconds to time.Duration.
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
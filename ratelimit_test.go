@@ -0,0 +1,166 @@
+package mux
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryStore(t *testing.T) {
+	now := time.Unix(0, 0)
+	s := NewMemoryStore()
+	s.now = func() time.Time { return now }
+	s.swept = now
+	limit := Limit{Rate: 2, Burst: 3}
+	ctx := context.Background()
+
+	take := func() LimitResult {
+		res, err := s.Take(ctx, "k", limit)
+		assert.NoError(t, err)
+		return res
+	}
+
+	assert.Equal(t, LimitResult{true, 2, 500 * time.Millisecond, 0}, take())
+	assert.Equal(t, LimitResult{true, 1, time.Second, 0}, take())
+	assert.Equal(t, LimitResult{true, 0, 1500 * time.Millisecond, 0}, take())
+	assert.Equal(t, LimitResult{false, 0, 1500 * time.Millisecond,
+		500 * time.Millisecond}, take())
+
+	now = now.Add(500 * time.Millisecond)
+	assert.True(t, take().Allowed)
+	assert.False(t, take().Allowed)
+
+	//-------------------- Another Test Case --------------------
+
+	// Full buckets are dropped.
+	now = now.Add(time.Minute)
+	s.Take(ctx, "other", limit)
+	assert.Len(t, s.buckets, 1)
+}
+
+// failingStore is RateLimitStore that is always down.
+type failingStore struct{}
+
+func (failingStore) Take(
+	ctx context.Context, key string, limit Limit,
+) (LimitResult, error) {
+	return LimitResult{}, errors.New("store is down")
+}
+
+func TestRateLimiter(t *testing.T) {
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+	serve := func(rtr *Router, path, ip, key string) int {
+		rec, req, err := request(http.MethodGet, path, nil)
+		assert.NoError(t, err)
+		req.RemoteAddr = ip + ":1234"
+		req.Header.Set("X-API-Key", key)
+		rtr.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	rtr := New()
+	rtr.Get("/free", noop)
+	rtr.Subrouter().PathPrefix("/api").
+		Use(NewRateLimiter(Limit{Burst: 2}, nil)).
+		Get("/users", noop)
+
+	assert.Equal(t, http.StatusOK, serve(rtr, "/api/users", "1.1.1.1", ""))
+	assert.Equal(t, http.StatusOK, serve(rtr, "/api/users", "1.1.1.1", ""))
+	assert.Equal(t, http.StatusTooManyRequests,
+		serve(rtr, "/api/users", "1.1.1.1", ""))
+	assert.Equal(t, http.StatusOK, serve(rtr, "/api/users", "2.2.2.2", ""))
+	assert.Equal(t, http.StatusOK, serve(rtr, "/free", "1.1.1.1", ""))
+
+	//-------------------- Another Test Case --------------------
+
+	rtr = New().Use(NewRateLimiter(Limit{Burst: 1},
+		&RateLimitOptions{Key: KeyByHeader("X-API-Key")}))
+	rtr.Get("/", noop)
+	assert.Equal(t, http.StatusOK, serve(rtr, "/", "1.1.1.1", "a"))
+	assert.Equal(t, http.StatusTooManyRequests, serve(rtr, "/", "2.2.2.2", "a"))
+	assert.Equal(t, http.StatusOK, serve(rtr, "/", "1.1.1.1", "b"))
+	assert.Equal(t, http.StatusOK, serve(rtr, "/", "1.1.1.1", ""))
+	assert.Equal(t, http.StatusOK, serve(rtr, "/", "1.1.1.1", ""))
+
+	//-------------------- Another Test Case --------------------
+
+	rtr = New().Use(NewRateLimiter(Limit{Burst: 1},
+		&RateLimitOptions{Key: KeyByRoute}))
+	rtr.Get("/a", noop)
+	api := rtr.Subrouter().PathPrefix("/api")
+	api.Get("/users/{id:int}", noop)
+	assert.Equal(t, "GET /api/users/{id:int}", func() string {
+		_, req, _ := request(http.MethodGet, "/api/users/1", nil)
+		return KeyByRoute(req.WithContext(
+			context.WithValue(req.Context(), routerKey, rtr)))
+	}())
+	assert.Equal(t, http.StatusOK, serve(rtr, "/a", "1.1.1.1", ""))
+	assert.Equal(t, http.StatusTooManyRequests, serve(rtr, "/a", "2.2.2.2", ""))
+	assert.Equal(t, http.StatusOK, serve(rtr, "/api/users/1", "1.1.1.1", ""))
+	assert.Equal(t, http.StatusTooManyRequests,
+		serve(rtr, "/api/users/2", "1.1.1.1", ""))
+	assert.Equal(t, http.StatusNotFound, serve(rtr, "/b", "1.1.1.1", ""))
+	assert.Equal(t, http.StatusNotFound, serve(rtr, "/b", "1.1.1.1", ""))
+
+	//-------------------- Another Test Case --------------------
+
+	// Failing stores let requests through.
+	rtr = New().Use(NewRateLimiter(Limit{Burst: 1},
+		&RateLimitOptions{Store: failingStore{}}))
+	rtr.Get("/", noop)
+	assert.Equal(t, http.StatusOK, serve(rtr, "/", "1.1.1.1", ""))
+}
+
+func TestRateLimitHeaders(t *testing.T) {
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+	store := NewMemoryStore()
+	now := time.Unix(0, 0)
+	store.now = func() time.Time { return now }
+
+	rtr := New().RateLimit(Limit{Rate: 1, Burst: 10},
+		&RateLimitOptions{Store: store, Name: "global"})
+	rtr.Get("/", noop)
+	rtr.Subrouter().PathPrefix("/search").
+		RateLimit(Limit{Rate: 0.5, Burst: 2},
+			&RateLimitOptions{Store: store, Name: "search"}).
+		Get("/", noop)
+
+	serve := func(path string) *http.Response {
+		rec, req, err := request(http.MethodGet, path, nil)
+		assert.NoError(t, err)
+		req.RemoteAddr = "1.1.1.1:1234"
+		rtr.ServeHTTP(rec, req)
+		return rec.Result()
+	}
+
+	res := serve("/")
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "10", res.Header.Get("RateLimit-Limit"))
+	assert.Equal(t, "9", res.Header.Get("RateLimit-Remaining"))
+	assert.Equal(t, "1", res.Header.Get("RateLimit-Reset"))
+	assert.Empty(t, res.Header.Get("Retry-After"))
+
+	//-------------------- Another Test Case --------------------
+
+	// The search limit is closer to exhaustion, so it is reported.
+	res = serve("/search/")
+	assert.Equal(t, "2", res.Header.Get("RateLimit-Limit"))
+	assert.Equal(t, "1", res.Header.Get("RateLimit-Remaining"))
+	assert.Equal(t, "2", res.Header.Get("RateLimit-Reset"))
+
+	serve("/search/")
+	res = serve("/search/")
+	assert.Equal(t, http.StatusTooManyRequests, res.StatusCode)
+	assert.Equal(t, "0", res.Header.Get("RateLimit-Remaining"))
+	assert.Equal(t, "4", res.Header.Get("RateLimit-Reset"))
+	assert.Equal(t, "2", res.Header.Get("Retry-After"))
+
+	// The global limit is not exhausted yet.
+	res = serve("/")
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "5", res.Header.Get("RateLimit-Remaining"))
+}
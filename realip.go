@@ -0,0 +1,183 @@
+package mux
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// RealIP returns Middleware that resolves the IP address of the client that
+// sent the request through proxies, so that ClientIP, IPFilter and rate limits
+// see the client rather than the proxy:
+//
+//	rtr.Wrap(mux.RealIP("10.0.0.0/8", "127.0.0.1"))
+//
+// Forwarding headers are trusted only if the request comes from one of the
+// trusted networks, since anyone can set them otherwise. The Forwarded header
+// is preferred, then X-Forwarded-For, then X-Real-IP. Addresses in the chain
+// are examined right to left, so that the first one not in the trusted
+// networks is taken as the client's. It panics if any of the networks is
+// neither a valid CIDR nor a valid IP address.
+func RealIP(trusted ...string) Middleware {
+	nets := parsePrefixes(trusted)
+	return func(next http.Handler) http.Handler {
+		return View(func(w http.ResponseWriter, r *http.Request) {
+			if ip := realIP(r, nets); ip.IsValid() {
+				ctx := context.WithValue(r.Context(), clientIPKey, ip)
+				r = r.WithContext(ctx)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ClientIP returns the IP address of the client resolved by RealIP, or the IP
+// address of the peer if the request didn't pass through RealIP.
+func ClientIP(r *http.Request) string {
+	if ip := clientAddr(r); ip.IsValid() {
+		return ip.String()
+	}
+	return remoteIP(r)
+}
+
+// remoteIP returns the IP address of the peer that sent the request.
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// clientAddr returns the IP address of the client (see ClientIP). It is
+// invalid if the address can't be parsed.
+func clientAddr(r *http.Request) netip.Addr {
+	if ip, ok := r.Context().Value(clientIPKey).(netip.Addr); ok {
+		return ip
+	}
+	return parseIP(r.RemoteAddr)
+}
+
+// realIP resolves the IP address of the client given the trusted networks.
+func realIP(r *http.Request, trusted []netip.Prefix) netip.Addr {
+	peer := parseIP(r.RemoteAddr)
+	if !peer.IsValid() || !contains(trusted, peer) {
+		return peer
+	}
+
+	var chain []string
+	if fwd := r.Header.Values("Forwarded"); len(fwd) > 0 {
+		chain = forwardedFor(strings.Join(fwd, ","))
+	} else if xff := r.Header.Values("X-Forwarded-For"); len(xff) > 0 {
+		chain = strings.Split(strings.Join(xff, ","), ",")
+	} else if xrip := r.Header.Get("X-Real-IP"); xrip != "" {
+		chain = []string{xrip}
+	}
+
+	client := peer
+	for i := len(chain) - 1; i >= 0; i-- {
+		ip := parseIP(chain[i])
+		if !ip.IsValid() {
+			break
+		}
+		client = ip
+		if !contains(trusted, ip) {
+			break
+		}
+	}
+	return client
+}
+
+// forwardedFor returns values of the "for" parameters of the Forwarded header
+// (RFC 7239) in order.
+func forwardedFor(header string) []string {
+	var chain []string
+	for _, elem := range strings.Split(header, ",") {
+		for _, pair := range strings.Split(elem, ";") {
+			key, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+			if ok && strings.EqualFold(key, "for") {
+				chain = append(chain, strings.Trim(value, `"`))
+			}
+		}
+	}
+	return chain
+}
+
+// parseIP parses the IP address that may come with a port and be enclosed in
+// square brackets, e.g. "[2001:db8::1]:4711". It returns an invalid address if
+// s is not an IP address.
+func parseIP(s string) netip.Addr {
+	s = strings.TrimSpace(s)
+	if ap, err := netip.ParseAddrPort(s); err == nil {
+		return ap.Addr().Unmap()
+	}
+	ip, err := netip.ParseAddr(strings.Trim(s, "[]"))
+	if err != nil {
+		return netip.Addr{}
+	}
+	return ip.Unmap()
+}
+
+// parsePrefixes parses CIDRs and IP addresses into network prefixes. It panics
+// if any of them is invalid.
+func parsePrefixes(cidrs []string) []netip.Prefix {
+	prefixes := make([]netip.Prefix, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		if ip, err := netip.ParseAddr(cidr); err == nil {
+			ip = ip.Unmap()
+			prefixes = append(prefixes, netip.PrefixFrom(ip, ip.BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(cidr)
+		if err != nil {
+			panic(fmt.Sprintf("can't parse network %s: %v", cidr, err))
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes
+}
+
+// contains tells whether ip belongs to one of the networks.
+func contains(prefixes []netip.Prefix, ip netip.Addr) bool {
+	for _, prefix := range prefixes {
+		if prefix.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// IPFilter takes care of filtering requests by the IP address of the client
+// (see ClientIP). It matches only those requests that come from one of the
+// networks.
+type IPFilter struct {
+	Networks []netip.Prefix
+}
+
+// NewIPFilter returns pointer to a newly created IPFilter. It panics if any of
+// the networks is neither a valid CIDR nor a valid IP address.
+func NewIPFilter(networks ...string) *IPFilter {
+	return &IPFilter{parsePrefixes(networks)}
+}
+
+// Match method returns boolean value that tells you whether given request
+// passed the filter. Also, *IPFilter implements the Filter interface since it
+// has this method.
+func (fil *IPFilter) Match(r *http.Request) bool {
+	ip := clientAddr(r)
+	return ip.IsValid() && contains(fil.Networks, ip)
+}
+
+// IP method adds an IPFilter to the Router, so that it only accepts requests
+// from clients in the given networks:
+//
+//	admin := rtr.Subrouter().PathPrefix("/admin").IP("10.0.0.0/8")
+//
+// Use RealIP if the server is behind a proxy. It panics if any of the
+// networks is neither a valid CIDR nor a valid IP address.
+func (rtr *Router) IP(networks ...string) *Router {
+	return rtr.Filter(NewIPFilter(networks...))
+}
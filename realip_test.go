@@ -0,0 +1,85 @@
+package mux
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRealIP(t *testing.T) {
+	var ip string
+	rtr := New().Wrap(RealIP("10.0.0.0/8", "::1"))
+	rtr.HandleFunc(func(w http.ResponseWriter, r *http.Request) {
+		ip = ClientIP(r)
+	})
+
+	cases := []struct {
+		remote string
+		header map[string]string
+		ip     string
+	}{
+		{"203.0.113.7:1234", nil, "203.0.113.7"},
+		// Headers from untrusted peers are ignored.
+		{"203.0.113.7:1234",
+			map[string]string{"X-Forwarded-For": "1.2.3.4"}, "203.0.113.7"},
+		{"10.0.0.1:1234",
+			map[string]string{"X-Forwarded-For": "1.2.3.4"}, "1.2.3.4"},
+		// Spoofed entries to the left of the client are skipped.
+		{"10.0.0.1:1234", map[string]string{
+			"X-Forwarded-For": "6.6.6.6, 1.2.3.4, 10.0.0.2"}, "1.2.3.4"},
+		{"10.0.0.1:1234",
+			map[string]string{"X-Forwarded-For": "10.0.0.3, 10.0.0.2"},
+			"10.0.0.3"},
+		{"[::1]:1234", map[string]string{"X-Real-IP": "1.2.3.4"}, "1.2.3.4"},
+		{"10.0.0.1:1234", map[string]string{
+			"Forwarded":       `for="[2001:db8::1]:4711";proto=https`,
+			"X-Forwarded-For": "7.7.7.7",
+		}, "2001:db8::1"},
+		{"10.0.0.1:1234", map[string]string{
+			"Forwarded": "for=unknown, for=10.0.0.2"}, "10.0.0.2"},
+	}
+	for _, c := range cases {
+		rec, req, err := request(http.MethodGet, "/", nil)
+		assert.NoError(t, err)
+		req.RemoteAddr = c.remote
+		for k, v := range c.header {
+			req.Header.Set(k, v)
+		}
+		rtr.ServeHTTP(rec, req)
+		assert.Equal(t, c.ip, ip, c.header)
+	}
+
+	//-------------------- Another Test Case --------------------
+
+	assert.Panics(t, func() { RealIP("10.0.0.0/33") })
+}
+
+func TestIPFilter(t *testing.T) {
+	rtr := New().Wrap(RealIP("127.0.0.1"))
+	rtr.Subrouter().IP("10.0.0.0/8", "192.168.1.1").
+		HandleFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	cases := []struct {
+		remote string
+		xff    string
+		code   int
+	}{
+		{"10.1.2.3:1234", "", http.StatusOK},
+		{"192.168.1.1:1234", "", http.StatusOK},
+		{"192.168.1.2:1234", "", http.StatusNotFound},
+		{"127.0.0.1:1234", "10.1.2.3", http.StatusOK},
+		{"127.0.0.1:1234", "8.8.8.8", http.StatusNotFound},
+		{"8.8.8.8:1234", "10.1.2.3", http.StatusNotFound},
+	}
+	for _, c := range cases {
+		rec, req, err := request(http.MethodGet, "/", nil)
+		assert.NoError(t, err)
+		req.RemoteAddr = c.remote
+		if c.xff != "" {
+			req.Header.Set("X-Forwarded-For", c.xff)
+		}
+		rtr.ServeHTTP(rec, req)
+		assert.Equal(t, c.code, rec.Code, c.remote+" "+c.xff)
+	}
+}
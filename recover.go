@@ -0,0 +1,84 @@
+package mux
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"runtime/debug"
+)
+
+// PanicError is the error reported through Error when Recover catches a panic.
+type PanicError struct {
+	// Value is the value passed to panic.
+	Value interface{}
+
+	// Stack is the stack trace of the goroutine that panicked.
+	Stack []byte
+}
+
+// Error method ensures that PanicError implements the error interface.
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Unwrap method returns the value passed to panic if it is an error.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// PanicHook is a function that Recover calls for every panic it catches, e.g.
+// to log it or report it to an error tracker.
+type PanicHook func(r *http.Request, err *PanicError)
+
+// DefaultPanicHook logs the panic and its stack trace with the logger of the
+// closest router that has one (see Router.Logger) or the standard logger.
+func DefaultPanicHook(r *http.Request, err *PanicError) {
+	if l := logger(r); l != nil {
+		l.ErrorContext(r.Context(), "mux: panic",
+			"method", r.Method, "path", r.URL.Path, "err", err,
+			"stack", string(err.Stack))
+		return
+	}
+	log.Printf("mux: %s %s: %v\n%s", r.Method, r.URL.Path, err, err.Stack)
+}
+
+// Recover returns Middleware that catches panics in the handlers of the
+// subtree, passes them to the hook (DefaultPanicHook if nil) and responds with
+// "500 Internal Server Error" through Error, so that the status handlers and
+// the error handler apply. If the response was already started, it is left as
+// is:
+//
+//	rtr.Wrap(mux.Recover(func(r *http.Request, err *mux.PanicError) {
+//	    logger.Error("handler panicked", "err", err, "stack", err.Stack)
+//	}))
+//
+// Panics with http.ErrAbortHandler are not recovered, since they are meant to
+// abort the response.
+func Recover(hook PanicHook) Middleware {
+	if hook == nil {
+		hook = DefaultPanicHook
+	}
+	return func(next http.Handler) http.Handler {
+		return View(func(w http.ResponseWriter, r *http.Request) {
+			rw := NewResponseWriter(w)
+			defer func() {
+				v := recover()
+				if v == nil {
+					return
+				}
+				if err, ok := v.(error); ok &&
+					errors.Is(err, http.ErrAbortHandler) {
+					panic(v)
+				}
+				err := &PanicError{v, debug.Stack()}
+				hook(r, err)
+				if !rw.Written() {
+					Error(rw, r, err)
+				}
+			}()
+			next.ServeHTTP(rw, r)
+		})
+	}
+}
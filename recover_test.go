@@ -0,0 +1,62 @@
+package mux
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecover(t *testing.T) {
+	var caught *PanicError
+	hook := func(r *http.Request, err *PanicError) {
+		caught = err
+	}
+	errDB := errors.New("db is down")
+
+	rtr := New().Wrap(Recover(hook))
+	rtr.Get("/value", func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})
+	rtr.Get("/error", func(w http.ResponseWriter, r *http.Request) {
+		panic(errDB)
+	})
+	rtr.Get("/abort", func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	})
+
+	rec, req, err := request(http.MethodGet, "/value", nil)
+	assert.NoError(t, err)
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Equal(t, "Internal Server Error\n", rec.Body.String())
+	if assert.NotNil(t, caught) {
+		assert.Equal(t, "boom", caught.Value)
+		assert.Equal(t, "panic: boom", caught.Error())
+		assert.Contains(t, string(caught.Stack), "recover_test.go")
+	}
+
+	//-------------------- Another Test Case --------------------
+
+	// Status handlers render the panic.
+	rtr.StatusHandlerFunc(http.StatusInternalServerError,
+		func(w http.ResponseWriter, r *http.Request) {
+			if errors.Is(ErrorOf(r), errDB) {
+				w.Write([]byte("database is unavailable"))
+			}
+		})
+	rec, req, err = request(http.MethodGet, "/error", nil)
+	assert.NoError(t, err)
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Equal(t, "database is unavailable", rec.Body.String())
+
+	//-------------------- Another Test Case --------------------
+
+	rec, req, err = request(http.MethodGet, "/abort", nil)
+	assert.NoError(t, err)
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		rtr.ServeHTTP(rec, req)
+	})
+}
@@ -0,0 +1,208 @@
+package mux
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// RedirectMap maps old paths to the URLs their clients are redirected to, e.g.
+// after a site migration. Lookups take constant time no matter how many
+// redirects there are. Paths are compared without trailing slashes.
+type RedirectMap struct {
+	targets map[string]redirectTarget
+}
+
+// redirectTarget is where clients are redirected to, and how.
+type redirectTarget struct {
+	url  string
+	code int
+}
+
+// LoadRedirects reads RedirectMap from the file, which is either CSV or JSON
+// depending on its extension (see ParseRedirectsCSV and ParseRedirectsJSON).
+func LoadRedirects(file string) (*RedirectMap, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	switch ext := strings.ToLower(filepath.Ext(file)); ext {
+	case ".csv":
+		return ParseRedirectsCSV(f)
+	case ".json":
+		return ParseRedirectsJSON(f)
+	default:
+		return nil, fmt.Errorf("redirects: unsupported file type %q", ext)
+	}
+}
+
+// ParseRedirectsCSV reads RedirectMap from CSV records of the old path, the
+// new URL and, optionally, the status code of the redirect, which defaults to
+// 301:
+//
+//	from,to,code
+//	/about-us,/about
+//	/blog/2019/hello,https://blog.example.com/hello,308
+//
+// Lines starting with # are comments. The header ("from,to" or
+// "from,to,code") is optional. The status code must be 301, 302, 303, 307 or
+// 308. Malformed records and paths listed twice are reported as errors.
+func ParseRedirectsCSV(r io.Reader) (*RedirectMap, error) {
+	m := &RedirectMap{make(map[string]redirectTarget)}
+	rd := csv.NewReader(r)
+	rd.Comment = '#'
+	rd.FieldsPerRecord = -1
+	rd.TrimLeadingSpace = true
+	for first := true; ; first = false {
+		rec, err := rd.Read()
+		if err == io.EOF {
+			return m, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("redirects: %v", err)
+		}
+		if first && isRedirectsHeader(rec) {
+			continue
+		}
+		line, _ := rd.FieldPos(0)
+		if len(rec) < 2 || len(rec) > 3 {
+			return nil, fmt.Errorf("redirects: line %d: expected 2 or 3 "+
+				"fields, got %d", line, len(rec))
+		}
+		code := http.StatusMovedPermanently
+		if len(rec) == 3 {
+			if code, err = parseRedirectCode(rec[2]); err != nil {
+				return nil, fmt.Errorf("redirects: line %d: %v", line, err)
+			}
+		}
+		if err := m.add(rec[0], rec[1], code); err != nil {
+			return nil, fmt.Errorf("redirects: line %d: %v", line, err)
+		}
+	}
+}
+
+// ParseRedirectsJSON reads RedirectMap from JSON object that maps old paths to
+// new URLs. Clients are redirected with "301 Moved Permanently":
+//
+//	{
+//	    "/about-us": "/about",
+//	    "/blog/2019/hello": "https://blog.example.com/hello"
+//	}
+func ParseRedirectsJSON(r io.Reader) (*RedirectMap, error) {
+	var redirects map[string]string
+	if err := json.NewDecoder(r).Decode(&redirects); err != nil {
+		return nil, fmt.Errorf("redirects: %v", err)
+	}
+	m := &RedirectMap{make(map[string]redirectTarget, len(redirects))}
+	for from, to := range redirects {
+		err := m.add(from, to, http.StatusMovedPermanently)
+		if err != nil {
+			return nil, fmt.Errorf("redirects: %v", err)
+		}
+	}
+	return m, nil
+}
+
+// add method adds the redirect to the map unless it's malformed or the path
+// is already there.
+func (m *RedirectMap) add(from, to string, code int) error {
+	if !strings.HasPrefix(from, "/") {
+		return fmt.Errorf("path %q must start with a slash", from)
+	}
+	if to == "" {
+		return fmt.Errorf("path %q has no target", from)
+	}
+	key := redirectKey(from)
+	if _, ok := m.targets[key]; ok {
+		return fmt.Errorf("path %q is listed twice", from)
+	}
+	m.targets[key] = redirectTarget{to, code}
+	return nil
+}
+
+// Lookup method returns the URL the path is redirected to and the status code
+// of the redirect. It returns false if the path isn't redirected.
+func (m *RedirectMap) Lookup(path string) (string, int, bool) {
+	t, ok := m.targets[redirectKey(path)]
+	return t.url, t.code, ok
+}
+
+// Len method returns the number of redirects in the map.
+func (m *RedirectMap) Len() int {
+	return len(m.targets)
+}
+
+// Redirects returns Middleware that redirects requests for the paths in the
+// map, so that old URLs keep working after a site migration. The query string
+// of the request is kept, unless the new URL has one. Other requests are
+// routed as usual:
+//
+//	redirects, err := mux.LoadRedirects("redirects.csv")
+//	...
+//	rtr.Redirects(redirects)
+func Redirects(m *RedirectMap) Middleware {
+	return func(next http.Handler) http.Handler {
+		return View(func(w http.ResponseWriter, r *http.Request) {
+			to, code, ok := m.Lookup(r.URL.Path)
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if !strings.Contains(to, "?") && r.URL.RawQuery != "" {
+				to += "?" + r.URL.RawQuery
+			}
+			http.Redirect(w, r, to, code)
+		})
+	}
+}
+
+// Redirects method registers Redirects middleware on the Router. See
+// Redirects.
+func (rtr *Router) Redirects(m *RedirectMap) *Router {
+	return rtr.Wrap(Redirects(m))
+}
+
+// redirectKey returns the path without trailing slashes, which is how paths
+// are kept in RedirectMap.
+func redirectKey(path string) string {
+	if key := strings.TrimRight(path, "/"); key != "" {
+		return key
+	}
+	return "/"
+}
+
+// isRedirectsHeader tells whether the CSV record is the header of redirects.
+func isRedirectsHeader(rec []string) bool {
+	header := []string{"from", "to", "code"}
+	if len(rec) < 2 || len(rec) > len(header) {
+		return false
+	}
+	for i, field := range rec {
+		if !strings.EqualFold(strings.TrimSpace(field), header[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// parseRedirectCode parses the status code of a redirect. Only the codes that
+// make clients follow Location header are accepted.
+func parseRedirectCode(s string) (int, error) {
+	code, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("invalid redirect status code %q", s)
+	}
+	switch code {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusSeeOther,
+		http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		return code, nil
+	}
+	return 0, fmt.Errorf("invalid redirect status code %q", s)
+}
@@ -0,0 +1,105 @@
+package mux
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedirects(t *testing.T) {
+	m, err := ParseRedirectsCSV(strings.NewReader(`from,to,code
+# Pages that moved during the migration.
+/about-us/,/about
+/blog/2019/hello, https://blog.example.com/hello?ref=old, 308
+"/a,b",/ab,302
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, m.Len())
+
+	rtr := New().Redirects(m)
+	rtr.Get("/about", func(w http.ResponseWriter, r *http.Request) {
+		Text(w, http.StatusOK, "about")
+	})
+	cases := []struct {
+		url, location string
+		code          int
+	}{
+		{"/about-us", "/about", http.StatusMovedPermanently},
+		{"/about-us/?lang=de", "/about?lang=de", http.StatusMovedPermanently},
+		{"/blog/2019/hello?x=1", "https://blog.example.com/hello?ref=old",
+			http.StatusPermanentRedirect},
+		{"/a,b", "/ab", http.StatusFound},
+		{"/about", "", http.StatusOK},
+		{"/About-us", "", http.StatusNotFound},
+	}
+	for _, c := range cases {
+		rec, req, err := request(http.MethodGet, c.url, nil)
+		assert.NoError(t, err)
+		rtr.ServeHTTP(rec, req)
+		assert.Equal(t, c.code, rec.Code, c.url)
+		assert.Equal(t, c.location, rec.Header().Get("Location"), c.url)
+	}
+}
+
+func TestParseRedirects(t *testing.T) {
+	m, err := ParseRedirectsJSON(strings.NewReader(`{
+		"/": "/home",
+		"/old": "https://example.com/new"
+	}`))
+	assert.NoError(t, err)
+	to, code, ok := m.Lookup("/old/")
+	assert.True(t, ok)
+	assert.Equal(t, "https://example.com/new", to)
+	assert.Equal(t, http.StatusMovedPermanently, code)
+	to, _, ok = m.Lookup("/")
+	assert.True(t, ok)
+	assert.Equal(t, "/home", to)
+	_, _, ok = m.Lookup("/new")
+	assert.False(t, ok)
+	//-------------------- Another Test Case --------------------
+	for _, doc := range []string{
+		"/a,/b\n/a/,/c",
+		"/a,/b,200",
+		"/a,/b,3xx",
+		"/a,/b,300",
+		"/a,/b,304",
+		"/a,/b,305",
+		"a,/b\n/c,/d",
+		"From,To,Code,Extra\n/c,/d",
+		"/a\n",
+		"/a,/b\nb,/c",
+		"/a,",
+	} {
+		_, err := ParseRedirectsCSV(strings.NewReader(doc))
+		assert.Error(t, err, doc)
+	}
+	_, err = ParseRedirectsCSV(strings.NewReader("/a,/b\nb,/c"))
+	assert.EqualError(t, err,
+		`redirects: line 2: path "b" must start with a slash`)
+	_, err = ParseRedirectsCSV(strings.NewReader("about-us,/about\n/a,/b"))
+	assert.EqualError(t, err,
+		`redirects: line 1: path "about-us" must start with a slash`)
+	m, err = ParseRedirectsCSV(strings.NewReader("From, To\n/a,/b,303"))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, m.Len())
+	_, err = ParseRedirectsJSON(strings.NewReader(`["/a", "/b"]`))
+	assert.Error(t, err)
+	//-------------------- Another Test Case --------------------
+	dir := t.TempDir()
+	csv := filepath.Join(dir, "redirects.csv")
+	assert.NoError(t, os.WriteFile(csv, []byte("/a,/b\n"), 0o644))
+	m, err = LoadRedirects(csv)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, m.Len())
+	json := filepath.Join(dir, "redirects.JSON")
+	assert.NoError(t, os.WriteFile(json, []byte(`{"/a": "/b"}`), 0o644))
+	m, err = LoadRedirects(json)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, m.Len())
+	_, err = LoadRedirects(filepath.Join(dir, "redirects.txt"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,75 @@
+package mux
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// RefererFilter takes care of filtering requests by the host of their Referer
+// header, so that images and other assets can't be hotlinked from other
+// sites. It matches requests whose referring host matches one of Patterns:
+//
+//   - "example.com" matches that host only;
+//   - "*.example.com" matches its subdomains, but not the host itself;
+//   - "*" matches any host;
+//   - "" matches requests without Referer header, which browsers omit when
+//     asked to by Referrer-Policy or privacy settings.
+//
+// Hosts are compared case-insensitively and without port.
+type RefererFilter struct {
+	Patterns []string
+}
+
+// NewRefererFilter returns pointer to a new RefererFilter of the patterns.
+func NewRefererFilter(patterns ...string) *RefererFilter {
+	fil := &RefererFilter{make([]string, 0, len(patterns))}
+	for _, p := range patterns {
+		fil.Patterns = append(fil.Patterns, strings.ToLower(p))
+	}
+	return fil
+}
+
+// Match method returns boolean value that tells you whether given request
+// passed the filter. Also, *RefererFilter implements the Filter interface
+// since it has this method.
+func (fil *RefererFilter) Match(r *http.Request) bool {
+	host := ""
+	if ref := r.Referer(); ref != "" {
+		u, err := url.Parse(ref)
+		if err != nil || u.Hostname() == "" {
+			return false
+		}
+		host = strings.ToLower(u.Hostname())
+	}
+	for _, p := range fil.Patterns {
+		if matchHost(p, host) {
+			return true
+		}
+	}
+	return false
+}
+
+// Referer method adds a RefererFilter to the Router, so that it only accepts
+// requests referred by the hosts that match the patterns (see RefererFilter):
+//
+//	rtr.Subrouter().PathPrefix("/images").
+//	    Referer("", "example.com", "*.example.com").
+//	    Handler(images)
+//	rtr.Subrouter().PathPrefix("/images").Handler(placeholder)
+func (rtr *Router) Referer(patterns ...string) *Router {
+	return rtr.Filter(NewRefererFilter(patterns...))
+}
+
+// matchHost tells whether the host matches the pattern of RefererFilter.
+func matchHost(pattern, host string) bool {
+	switch {
+	case pattern == "" || host == "":
+		return pattern == host
+	case pattern == "*":
+		return true
+	case strings.HasPrefix(pattern, "*."):
+		return strings.HasSuffix(host, pattern[1:])
+	}
+	return pattern == host
+}
@@ -0,0 +1,60 @@
+package mux
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRefererFilter(t *testing.T) {
+	fil := NewRefererFilter("", "Example.com", "*.example.com")
+	cases := []struct {
+		referer string
+		match   bool
+	}{
+		{"", true},
+		{"https://example.com/gallery", true},
+		{"http://EXAMPLE.com:8080/", true},
+		{"https://cdn.example.com/page", true},
+		{"https://a.b.example.com/page", true},
+		{"https://notexample.com/", false},
+		{"https://example.com.evil.net/", false},
+		{"https://evil.net/?https://example.com", false},
+		{"example.com", false},
+		{"::", false},
+	}
+	for _, c := range cases {
+		_, req, err := request(http.MethodGet, "/logo.png", nil)
+		assert.NoError(t, err)
+		if c.referer != "" {
+			req.Header.Set("Referer", c.referer)
+		}
+		assert.Equal(t, c.match, fil.Match(req), c.referer)
+	}
+	//-------------------- Another Test Case --------------------
+	text := func(s string) View {
+		return func(w http.ResponseWriter, r *http.Request) {
+			Text(w, http.StatusOK, s)
+		}
+	}
+	rtr := New()
+	rtr.Get("/logo.png", text("logo")).Referer("example.com")
+	rtr.Get("/logo.png", text("placeholder"))
+	for referer, body := range map[string]string{
+		"":                    "placeholder",
+		"https://example.com": "logo",
+		"https://evil.net":    "placeholder",
+	} {
+		rec, req, err := request(http.MethodGet, "/logo.png", nil)
+		assert.NoError(t, err)
+		req.Header.Set("Referer", referer)
+		rtr.ServeHTTP(rec, req)
+		assert.Equal(t, body, rec.Body.String(), referer)
+	}
+	//-------------------- Another Test Case --------------------
+	_, req, err := request(http.MethodGet, "/logo.png", nil)
+	assert.NoError(t, err)
+	req.Header.Set("Referer", "https://anything.net/")
+	assert.True(t, NewRefererFilter("*").Match(req))
+}
@@ -0,0 +1,135 @@
+package mux
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Reloader is an http.Handler that serves requests with a routing tree built
+// from a YAML or JSON configuration file (see FromConfig) and rebuilds it when
+// the file changes. The new tree replaces the old one atomically: requests in
+// flight finish with the tree they started with, and no request is ever served
+// by a partially built tree. If the new configuration is invalid, the old tree
+// stays in place.
+//
+//	rl, err := mux.NewReloader("routes.yaml", handlers)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	go rl.Watch(ctx, time.Second, func(err error) {
+//	    if err != nil {
+//	        log.Println("routes not reloaded:", err)
+//	    }
+//	})
+//	http.ListenAndServe(":8080", rl)
+type Reloader struct {
+	file     string
+	handlers map[string]http.Handler
+	current  atomic.Pointer[Router]
+
+	// mu serializes reloads; stat is the file info of the last attempt.
+	mu   sync.Mutex
+	stat os.FileInfo
+}
+
+// NewReloader returns pointer to a Reloader that builds routes from the file
+// with handlers resolved by their names. It returns an error if the initial
+// configuration can't be loaded.
+func NewReloader(
+	file string, handlers map[string]http.Handler,
+) (*Reloader, error) {
+	rl := &Reloader{file: file, handlers: handlers}
+	if err := rl.Reload(); err != nil {
+		return nil, err
+	}
+	return rl, nil
+}
+
+// ServeHTTP method ensures that Reloader implements the http.Handler
+// interface.
+func (rl *Reloader) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rl.current.Load().ServeHTTP(w, r)
+}
+
+// Router method returns the routing tree that is currently in use.
+func (rl *Reloader) Router() *Router {
+	return rl.current.Load()
+}
+
+// Reload method rebuilds the routing tree from the configuration file and
+// swaps it in. It returns an error and keeps the old tree if the configuration
+// can't be loaded.
+func (rl *Reloader) Reload() error {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	return rl.reload()
+}
+
+// reload method is Reload that expects rl.mu to be held.
+func (rl *Reloader) reload() error {
+	f, err := os.Open(rl.file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if rl.stat, err = f.Stat(); err != nil {
+		return err
+	}
+
+	conf, err := parseConfig(f)
+	if err != nil {
+		return err
+	}
+	rtr, err := conf.Build(rl.handlers)
+	if err != nil {
+		return err
+	}
+	if err := rtr.Compile(); err != nil {
+		return err
+	}
+	rl.current.Store(rtr)
+	return nil
+}
+
+// Watch method checks the configuration file for changes (by modification
+// time and size) every interval and reloads it when it changes, until the
+// context is done. The result of every reload is passed to report, if it is
+// not nil; a nil error means that the new tree is in use.
+func (rl *Reloader) Watch(
+	ctx context.Context, interval time.Duration, report func(error),
+) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if changed, err := rl.reloadIfChanged(); changed && report != nil {
+			report(err)
+		}
+	}
+}
+
+// reloadIfChanged method reloads the configuration if the file has changed
+// since the last attempt. The flag tells whether a reload was attempted. The
+// file that went missing is reported once.
+func (rl *Reloader) reloadIfChanged() (changed bool, err error) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	stat, err := os.Stat(rl.file)
+	if err != nil {
+		changed, rl.stat = rl.stat != nil, nil
+		return changed, err
+	}
+	if rl.stat != nil && stat.ModTime().Equal(rl.stat.ModTime()) &&
+		stat.Size() == rl.stat.Size() {
+		return false, nil
+	}
+	return true, rl.reload()
+}
@@ -0,0 +1,79 @@
+package mux
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReloader(t *testing.T) {
+	dir := staticDir(t, map[string]string{"routes.json": `{
+		"routes": [
+			{"methods": ["GET"], "path": "/users", "handler": "users"}
+		]
+	}`})
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "routes.json")
+
+	text := func(s string) http.Handler {
+		return View(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(s))
+		})
+	}
+	handlers := map[string]http.Handler{
+		"users":  text("users"),
+		"health": text("ok"),
+	}
+
+	rl, err := NewReloader(file, handlers)
+	assert.NoError(t, err)
+
+	serve := func(path string) (int, string) {
+		rec, req, err := request(http.MethodGet, path, nil)
+		assert.NoError(t, err)
+		rl.ServeHTTP(rec, req)
+		return rec.Code, rec.Body.String()
+	}
+	update := func(content string, at time.Time) {
+		assert.NoError(t, ioutil.WriteFile(file, []byte(content), 0644))
+		assert.NoError(t, os.Chtimes(file, at, at))
+	}
+
+	_, body := serve("/users")
+	assert.Equal(t, "users", body)
+	changed, err := rl.reloadIfChanged()
+	assert.False(t, changed)
+	assert.NoError(t, err)
+
+	// Invalid configuration keeps the old tree.
+	update(`{"routes": [{"path": "/health", "handler": "missing"}]}`,
+		time.Now().Add(time.Minute))
+	changed, err = rl.reloadIfChanged()
+	assert.True(t, changed)
+	assert.EqualError(t, err, `config: route /health: unknown handler "missing"`)
+	_, body = serve("/users")
+	assert.Equal(t, "users", body)
+
+	update(`{"routes": [{"prefix": "/api", "routes": [
+		{"path": "/health", "handler": "health"}
+	]}]}`, time.Now().Add(2*time.Minute))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	reloaded := make(chan error)
+	go rl.Watch(ctx, time.Millisecond, func(err error) {
+		reloaded <- err
+		cancel()
+	})
+	assert.NoError(t, <-reloaded)
+
+	_, body = serve("/api/health")
+	assert.Equal(t, "ok", body)
+	code, _ := serve("/users")
+	assert.Equal(t, http.StatusNotFound, code)
+}
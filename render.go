@@ -0,0 +1,64 @@
+package mux
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"strconv"
+)
+
+// Content types set by the rendering helpers.
+const (
+	ContentTypeJSON = "application/json; charset=utf-8"
+	ContentTypeXML  = "application/xml; charset=utf-8"
+	ContentTypeText = "text/plain; charset=utf-8"
+	ContentTypeHTML = "text/html; charset=utf-8"
+)
+
+// JSON writes v encoded as JSON with given status code. The value is encoded
+// before anything is written, so if encoding fails, the error is returned and
+// the response is left intact for the caller to report it (e.g. with Error).
+func JSON(w http.ResponseWriter, code int, v interface{}) error {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(v); err != nil {
+		return err
+	}
+	return write(w, code, ContentTypeJSON, buf.Bytes())
+}
+
+// XML writes v encoded as XML (with the standard XML header) with given status
+// code. Encoding errors are handled the same way as in JSON.
+func XML(w http.ResponseWriter, code int, v interface{}) error {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	if err := xml.NewEncoder(&buf).Encode(v); err != nil {
+		return err
+	}
+	return write(w, code, ContentTypeXML, buf.Bytes())
+}
+
+// Text writes s as plain text with given status code.
+func Text(w http.ResponseWriter, code int, s string) error {
+	return write(w, code, ContentTypeText, []byte(s))
+}
+
+// NoContent responds with "204 No Content".
+func NoContent(w http.ResponseWriter) {
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// write sets Content-Type and Content-Length headers, writes status code and
+// body. Content-Type that was already set by the handler is kept.
+func write(
+	w http.ResponseWriter, code int, contentType string, body []byte,
+) error {
+	h := w.Header()
+	if h.Get("Content-Type") == "" {
+		h.Set("Content-Type", contentType)
+	}
+	h.Set("Content-Length", strconv.Itoa(len(body)))
+	w.WriteHeader(code)
+	_, err := w.Write(body)
+	return err
+}
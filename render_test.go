@@ -0,0 +1,43 @@
+package mux
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRender(t *testing.T) {
+	type song struct {
+		ID    int    `json:"id" xml:"id,attr"`
+		Title string `json:"title" xml:"title"`
+	}
+
+	rec := httptest.NewRecorder()
+	assert.NoError(t, JSON(rec, http.StatusCreated, song{42, "Numb"}))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, ContentTypeJSON, rec.Header().Get("Content-Type"))
+	assert.Equal(t, "{\"id\":42,\"title\":\"Numb\"}\n", rec.Body.String())
+	assert.Equal(t, "25", rec.Header().Get("Content-Length"))
+	//-------------------- Another Test Case --------------------
+	rec = httptest.NewRecorder()
+	assert.NoError(t, XML(rec, http.StatusOK, song{42, "Numb"}))
+	assert.Equal(t, ContentTypeXML, rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Body.String(),
+		`<song id="42"><title>Numb</title></song>`)
+	//-------------------- Another Test Case --------------------
+	rec = httptest.NewRecorder()
+	rec.Header().Set("Content-Type", "text/csv")
+	assert.NoError(t, Text(rec, http.StatusOK, "a,b"))
+	assert.Equal(t, "text/csv", rec.Header().Get("Content-Type"))
+	//-------------------- Another Test Case --------------------
+	rec = httptest.NewRecorder()
+	assert.Error(t, JSON(rec, http.StatusOK, make(chan int)))
+	assert.Empty(t, rec.Header().Get("Content-Type"))
+	assert.Empty(t, rec.Body.String())
+	//-------------------- Another Test Case --------------------
+	rec = httptest.NewRecorder()
+	NoContent(rec)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+}
@@ -0,0 +1,163 @@
+package mux
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// resolveTimeout is how long resolution of the backends may take.
+	resolveTimeout = 10 * time.Second
+
+	// resolveRetry is the delay before the backends are resolved again
+	// after resolution failed.
+	resolveRetry = 5 * time.Second
+)
+
+// UpstreamResolver resolves the backends of Proxy, e.g. by querying DNS or a
+// service registry like Consul.
+type UpstreamResolver interface {
+	// Resolve returns the backends and the time they are valid for, after
+	// which Proxy resolves them again. Zero TTL means that the backends
+	// never change. Resolve should give up once the context is done.
+	Resolve(ctx context.Context) (backends []Backend, ttl time.Duration,
+		err error)
+}
+
+// ResolverFunc is an adapter that allows the use of ordinary functions as
+// UpstreamResolver.
+type ResolverFunc func(ctx context.Context) ([]Backend, time.Duration, error)
+
+// Resolve method calls the function itself. It ensures that ResolverFunc
+// implements the UpstreamResolver interface.
+func (f ResolverFunc) Resolve(
+	ctx context.Context,
+) ([]Backend, time.Duration, error) {
+	return f(ctx)
+}
+
+// StaticResolver is UpstreamResolver that always resolves the same backends.
+type StaticResolver []Backend
+
+// Resolve method returns the backends with zero TTL. It ensures that
+// StaticResolver implements the UpstreamResolver interface.
+func (sr StaticResolver) Resolve(
+	ctx context.Context,
+) ([]Backend, time.Duration, error) {
+	return append([]Backend(nil), sr...), 0, nil
+}
+
+// SRVResolver is UpstreamResolver that looks up DNS SRV records of the
+// backends, e.g. "_http._tcp.api.service.consul". Only the targets with the
+// lowest priority are used; their weights become the weights of the backends
+// (see WeightedRoundRobin).
+type SRVResolver struct {
+	// Service, Proto and Name are the parts of the record name passed to
+	// net.Resolver.LookupSRV. If Service and Proto are empty, Name is
+	// looked up directly.
+	Service string
+	Proto   string
+	Name    string
+
+	// Scheme is the scheme of the backend URLs. Empty means "http".
+	Scheme string
+
+	// TTL is how often the records are looked up again, since Go doesn't
+	// expose TTL of DNS records. Zero means 30 seconds.
+	TTL time.Duration
+
+	// Resolver is the DNS resolver. Nil means net.DefaultResolver.
+	Resolver *net.Resolver
+}
+
+// Resolve method looks up the SRV records. It ensures that SRVResolver
+// implements the UpstreamResolver interface.
+func (sr *SRVResolver) Resolve(
+	ctx context.Context,
+) ([]Backend, time.Duration, error) {
+	resolver := sr.Resolver
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+	_, addrs, err := resolver.LookupSRV(ctx, sr.Service, sr.Proto, sr.Name)
+	if err != nil {
+		return nil, 0, err
+	}
+	ttl := sr.TTL
+	if ttl <= 0 {
+		ttl = 30 * time.Second
+	}
+	return srvBackends(sr.Scheme, addrs), ttl, nil
+}
+
+// srvBackends converts the SRV records sorted by priority to the backends
+// with the lowest priority.
+func srvBackends(scheme string, addrs []*net.SRV) []Backend {
+	if scheme == "" {
+		scheme = "http"
+	}
+	var backends []Backend
+	for _, addr := range addrs {
+		if addr.Priority != addrs[0].Priority {
+			break
+		}
+		host := strings.TrimSuffix(addr.Target, ".")
+		port := strconv.Itoa(int(addr.Port))
+		backends = append(backends, Backend{
+			URL:    scheme + "://" + net.JoinHostPort(host, port),
+			Weight: int(addr.Weight),
+		})
+	}
+	return backends
+}
+
+// resolve method replaces the backends with those resolved by Resolver. It
+// returns the time after which they have to be resolved again; zero means
+// never. Errors are logged (see ProxyOptions.Logger) and the current backends
+// are kept.
+func (p *Proxy) resolve() time.Duration {
+	ctx, cancel := context.WithTimeout(context.Background(), resolveTimeout)
+	defer cancel()
+	go func() {
+		select {
+		case <-p.done:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	backends, ttl, err := p.opts.Resolver.Resolve(ctx)
+	if err == nil && len(backends) == 0 {
+		err = errors.New("no backends")
+	}
+	if err == nil {
+		err = p.update(backends)
+	}
+	if err != nil {
+		if l := p.opts.Logger; l != nil {
+			l.Error("mux: can't resolve backends", "err", err)
+		}
+		return resolveRetry
+	}
+	return ttl
+}
+
+// refresh method resolves the backends again once their TTL is over, until
+// Proxy is closed or the TTL is zero.
+func (p *Proxy) refresh(ttl time.Duration) {
+	defer p.workers.Done()
+	for ttl > 0 {
+		t := time.NewTimer(ttl)
+		select {
+		case <-t.C:
+		case <-p.done:
+			t.Stop()
+			return
+		}
+		ttl = p.resolve()
+	}
+}
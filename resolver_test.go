@@ -0,0 +1,92 @@
+package mux
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProxyResolver(t *testing.T) {
+	a, b := backendServer(t, "a"), backendServer(t, "b")
+	var calls atomic.Int32
+	p := NewProxy(&ProxyOptions{
+		Resolver: ResolverFunc(func(ctx context.Context) (
+			[]Backend, time.Duration, error,
+		) {
+			if calls.Add(1) == 1 {
+				return []Backend{{URL: a.URL}}, 5 * time.Millisecond, nil
+			}
+			return []Backend{{URL: b.URL}, {URL: a.URL}}, 0, nil
+		}),
+	})
+	defer p.Close()
+	first := p.upstreams()[0]
+	assert.Equal(t, "a /", proxied(t, p, "/"))
+
+	// Backends with the same URL keep their state.
+	assert.Eventually(t, func() bool { return len(p.Backends()) == 2 },
+		time.Second, time.Millisecond)
+	assert.Equal(t, b.URL, p.Backends()[0].URL)
+	assert.Same(t, first, p.upstreams()[1])
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, int32(2), calls.Load(), "zero TTL stops resolution")
+	//-------------------- Another Test Case --------------------
+	for _, resolve := range []ResolverFunc{
+		func(ctx context.Context) ([]Backend, time.Duration, error) {
+			return nil, 0, errors.New("registry is down")
+		},
+		func(ctx context.Context) ([]Backend, time.Duration, error) {
+			return nil, time.Second, nil
+		},
+		func(ctx context.Context) ([]Backend, time.Duration, error) {
+			return []Backend{{URL: "::"}}, time.Second, nil
+		},
+	} {
+		var buf bytes.Buffer
+		p := NewProxy(&ProxyOptions{
+			Backends: []Backend{{URL: a.URL}},
+			Logger:   slog.New(slog.NewTextHandler(&buf, nil)),
+		})
+		p.opts.Resolver = resolve
+		assert.Equal(t, resolveRetry, p.resolve())
+		assert.Len(t, p.Backends(), 1)
+		assert.Contains(t, buf.String(), "mux: can't resolve backends")
+	}
+	//-------------------- Another Test Case --------------------
+	p = NewProxy(&ProxyOptions{Resolver: StaticResolver{{URL: b.URL}}})
+	assert.Equal(t, "b /", proxied(t, p, "/"))
+	p.Close()
+}
+
+func TestSRVResolver(t *testing.T) {
+	backends := srvBackends("", []*net.SRV{
+		{Target: "a.example.com.", Port: 8080, Priority: 1, Weight: 10},
+		{Target: "b.example.com.", Port: 8081, Priority: 1, Weight: 0},
+		{Target: "c.example.com.", Port: 8082, Priority: 2, Weight: 5},
+	})
+	assert.Equal(t, []Backend{
+		{URL: "http://a.example.com:8080", Weight: 10},
+		{URL: "http://b.example.com:8081", Weight: 0},
+	}, backends)
+	//-------------------- Another Test Case --------------------
+	sr := &SRVResolver{
+		Name: "api.invalid",
+		Resolver: &net.Resolver{
+			PreferGo: true,
+			Dial: func(ctx context.Context, network, address string) (
+				net.Conn, error,
+			) {
+				return nil, errors.New("no network")
+			},
+		},
+	}
+	_, _, err := sr.Resolve(context.Background())
+	assert.Error(t, err)
+}
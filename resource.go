@@ -0,0 +1,96 @@
+package mux
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// IndexController lists the items of a resource.
+type IndexController interface {
+	Index(w http.ResponseWriter, r *http.Request)
+}
+
+// ShowController shows an item of a resource.
+type ShowController interface {
+	Show(w http.ResponseWriter, r *http.Request)
+}
+
+// CreateController creates an item of a resource.
+type CreateController interface {
+	Create(w http.ResponseWriter, r *http.Request)
+}
+
+// UpdateController updates an item of a resource.
+type UpdateController interface {
+	Update(w http.ResponseWriter, r *http.Request)
+}
+
+// DeleteController deletes an item of a resource.
+type DeleteController interface {
+	Delete(w http.ResponseWriter, r *http.Request)
+}
+
+// ResourceController handles all the actions of a resource. Controllers
+// passed to Router.Resource may implement only some of them.
+type ResourceController interface {
+	IndexController
+	ShowController
+	CreateController
+	UpdateController
+	DeleteController
+}
+
+// Resource method creates a group (see Route) under the path that maps the
+// actions the controller implements to conventional routes:
+//
+//	GET       /articles       Index
+//	POST      /articles       Create
+//	GET       /articles/{id}  Show
+//	PUT/PATCH /articles/{id}  Update
+//	DELETE    /articles/{id}  Delete
+//
+// The controller may implement any of IndexController, ShowController,
+// CreateController, UpdateController and DeleteController; only the routes of
+// the actions it implements are added. The item is identified by a segment in
+// "id" path variable:
+//
+//	func (c *articles) Show(w http.ResponseWriter, r *http.Request) {
+//	    id := mux.VarsOf(r).MustString("id")
+//	    ...
+//	}
+//
+//	rtr.Resource("/articles", &articles{db})
+//
+// It panics if the controller implements none of the actions. It returns
+// pointer to the group, so middleware may be added to all the actions at
+// once.
+func (rtr *Router) Resource(path string, ctrl interface{}) *Router {
+	index, isIndex := ctrl.(IndexController)
+	show, isShow := ctrl.(ShowController)
+	create, isCreate := ctrl.(CreateController)
+	update, isUpdate := ctrl.(UpdateController)
+	del, isDelete := ctrl.(DeleteController)
+	if !isIndex && !isShow && !isCreate && !isUpdate && !isDelete {
+		panic(fmt.Sprintf("can't use %T as resource controller", ctrl))
+	}
+
+	const item = "/{id:segment}"
+	return rtr.Route(path, func(r *Router) {
+		if isIndex {
+			r.Get("/", index.Index)
+		}
+		if isCreate {
+			r.Post("/", create.Create)
+		}
+		if isShow {
+			r.Get(item, show.Show)
+		}
+		if isUpdate {
+			r.Put(item, update.Update)
+			r.Patch(item, update.Update)
+		}
+		if isDelete {
+			r.Delete(item, del.Delete)
+		}
+	})
+}
@@ -0,0 +1,78 @@
+package mux
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// articles is a resource controller that reports the action it was called
+// with.
+type articles struct{}
+
+func (articles) Index(w http.ResponseWriter, r *http.Request) {
+	Text(w, http.StatusOK, "index")
+}
+
+func (articles) Show(w http.ResponseWriter, r *http.Request) {
+	Text(w, http.StatusOK, "show "+VarsOf(r).MustString("id"))
+}
+
+func (articles) Create(w http.ResponseWriter, r *http.Request) {
+	Text(w, http.StatusCreated, "create")
+}
+
+func (articles) Update(w http.ResponseWriter, r *http.Request) {
+	Text(w, http.StatusOK, "update "+VarsOf(r).MustString("id"))
+}
+
+func (articles) Delete(w http.ResponseWriter, r *http.Request) {
+	Text(w, http.StatusOK, "delete "+VarsOf(r).MustString("id"))
+}
+
+// comments is a read-only resource controller.
+type comments struct{}
+
+func (comments) Index(w http.ResponseWriter, r *http.Request) {
+	Text(w, http.StatusOK, "comments")
+}
+
+func TestResource(t *testing.T) {
+	var _ ResourceController = articles{}
+	rtr := New()
+	rtr.Resource("/articles", articles{})
+	rtr.Resource("/comments", comments{})
+
+	cases := []struct {
+		method, path string
+		code         int
+		body         string
+	}{
+		{http.MethodGet, "/articles", http.StatusOK, "index"},
+		{http.MethodGet, "/articles/", http.StatusOK, "index"},
+		{http.MethodPost, "/articles", http.StatusCreated, "create"},
+		{http.MethodGet, "/articles/42", http.StatusOK, "show 42"},
+		{http.MethodPut, "/articles/42", http.StatusOK, "update 42"},
+		{http.MethodPatch, "/articles/hello", http.StatusOK, "update hello"},
+		{http.MethodDelete, "/articles/42", http.StatusOK, "delete 42"},
+		{http.MethodDelete, "/articles", http.StatusMethodNotAllowed, ""},
+		{http.MethodGet, "/articles/42/edit", http.StatusNotFound, ""},
+		{http.MethodGet, "/comments", http.StatusOK, "comments"},
+		{http.MethodPost, "/comments", http.StatusMethodNotAllowed, ""},
+		{http.MethodGet, "/comments/1", http.StatusNotFound, ""},
+	}
+	for _, c := range cases {
+		rec, req, err := request(c.method, c.path, nil)
+		assert.NoError(t, err)
+		rtr.ServeHTTP(rec, req)
+		assert.Equal(t, c.code, rec.Code, c.method+" "+c.path)
+		if c.body != "" {
+			assert.Equal(t, c.body, rec.Body.String())
+		}
+	}
+	//-------------------- Another Test Case --------------------
+	assert.Panics(t, func() {
+		rtr.Resource("/nothing", struct{}{})
+	})
+}
@@ -0,0 +1,106 @@
+package mux
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"net/http"
+)
+
+// ResponseWriter is an http.ResponseWriter that records the status code and
+// the size of the response, so that middleware can report them once the
+// request is served:
+//
+//	rw := mux.NewResponseWriter(w)
+//	next.ServeHTTP(rw, r)
+//	log.Println(r.URL.Path, rw.Status(), rw.Size())
+//
+// It passes flushes and hijacks through to the underlying writer and
+// supports http.ResponseController via Unwrap.
+type ResponseWriter struct {
+	http.ResponseWriter
+	status int
+	size   int64
+}
+
+// NewResponseWriter returns pointer to a ResponseWriter that wraps w. If w is
+// a ResponseWriter already, it is returned as is.
+func NewResponseWriter(w http.ResponseWriter) *ResponseWriter {
+	if rw, ok := w.(*ResponseWriter); ok {
+		return rw
+	}
+	return &ResponseWriter{w, 0, 0}
+}
+
+// WriteHeader method records the status code and writes it to the underlying
+// writer. Informational (1xx) codes are passed through without being recorded,
+// since they don't complete the header.
+func (rw *ResponseWriter) WriteHeader(code int) {
+	if rw.status != 0 {
+		return
+	}
+	if code >= 200 || code == http.StatusSwitchingProtocols {
+		rw.status = code
+	}
+	rw.ResponseWriter.WriteHeader(code)
+}
+
+// Write method writes the data to the underlying writer and counts its size.
+func (rw *ResponseWriter) Write(b []byte) (int, error) {
+	if rw.status == 0 {
+		rw.WriteHeader(http.StatusOK)
+	}
+	n, err := rw.ResponseWriter.Write(b)
+	rw.size += int64(n)
+	return n, err
+}
+
+// Status method returns the status code of the response. It is 200 if the
+// handler didn't write anything, since that's what the client gets.
+func (rw *ResponseWriter) Status() int {
+	if rw.status == 0 {
+		return http.StatusOK
+	}
+	return rw.status
+}
+
+// Size method returns the number of bytes of the body written so far.
+func (rw *ResponseWriter) Size() int64 {
+	return rw.size
+}
+
+// Written method tells whether the header was written already.
+func (rw *ResponseWriter) Written() bool {
+	return rw.status != 0
+}
+
+// Flush method ensures that ResponseWriter implements the http.Flusher
+// interface. It does nothing if the underlying writer can't flush.
+func (rw *ResponseWriter) Flush() {
+	if rw.status == 0 {
+		rw.WriteHeader(http.StatusOK)
+	}
+	if f, ok := rw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack method ensures that ResponseWriter implements the http.Hijacker
+// interface. It fails if the underlying writer can't be hijacked.
+func (rw *ResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := rw.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("mux: response can't be hijacked")
+	}
+	conn, buf, err := h.Hijack()
+	if err == nil && rw.status == 0 {
+		rw.status = http.StatusSwitchingProtocols
+	}
+	return conn, buf, err
+}
+
+// Unwrap method returns the underlying writer. It is used by
+// http.ResponseController.
+func (rw *ResponseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
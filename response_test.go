@@ -0,0 +1,36 @@
+package mux
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResponseWriter(t *testing.T) {
+	rec := httptest.NewRecorder()
+	rw := NewResponseWriter(rec)
+	assert.Same(t, rw, NewResponseWriter(rw))
+	assert.False(t, rw.Written())
+	assert.Equal(t, http.StatusOK, rw.Status())
+
+	rw.WriteHeader(http.StatusCreated)
+	rw.WriteHeader(http.StatusConflict)
+	rw.Write([]byte("hello"))
+	rw.Write([]byte(", world"))
+	assert.True(t, rw.Written())
+	assert.Equal(t, http.StatusCreated, rw.Status())
+	assert.Equal(t, int64(12), rw.Size())
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	//-------------------- Another Test Case --------------------
+
+	rec = httptest.NewRecorder()
+	rw = NewResponseWriter(rec)
+	assert.NoError(t, http.NewResponseController(rw).Flush())
+	assert.True(t, rec.Flushed)
+	assert.Equal(t, http.StatusOK, rw.Status())
+	_, _, err := rw.Hijack()
+	assert.Error(t, err)
+}
@@ -0,0 +1,111 @@
+package mux
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// rewriteRule is a rule added by Rewrite or RewriteRedirect.
+type rewriteRule struct {
+	pattern     *regexp.Regexp
+	replacement string
+
+	// redirect tells whether clients are redirected to the replacement with
+	// the code, or the path is rewritten internally.
+	redirect bool
+	code     int
+}
+
+// Rewrite method adds a rule that rewrites request paths matching the regular
+// expression before routing, so that legacy URLs are served by current
+// routes:
+//
+//	rtr.Rewrite("^/old/(.*)", "/new/$1")
+//	rtr.Rewrite(`^/blog/(\d+)\.html$`, "/posts/$1")
+//
+// The whole path is replaced with the replacement, in which $1, ${name} and
+// the like stand for the submatches (see regexp.Regexp.Expand). Rules are
+// tried in the order they were added, and only the first one that matches
+// applies. The client doesn't notice the rewrite; use RewriteRedirect to send
+// it to the new URL instead.
+//
+// Add rules to the root Router, so that they apply before any routes are
+// matched. Rules of a sub-router apply once it matched the request, and see
+// the path relative to its prefix. It panics if the pattern fails to compile.
+func (rtr *Router) Rewrite(pattern, replacement string) *Router {
+	return rtr.addRewrite(&rewriteRule{replacement: replacement}, pattern)
+}
+
+// RewriteRedirect method adds a rule like Rewrite does, but clients are
+// redirected to the replacement URL with the status code. Zero code means
+// "301 Moved Permanently" for GET and HEAD requests and "308 Permanent
+// Redirect" for other methods. The replacement may be a path or an absolute
+// URL:
+//
+//	rtr.RewriteRedirect("^/docs/(.*)", "https://docs.example.com/$1", 0)
+//
+// The query string of the request is kept, unless the replacement has one.
+// It panics if the pattern fails to compile.
+func (rtr *Router) RewriteRedirect(
+	pattern, replacement string, code int,
+) *Router {
+	return rtr.addRewrite(&rewriteRule{
+		replacement: replacement,
+		redirect:    true,
+		code:        code,
+	}, pattern)
+}
+
+// addRewrite method compiles the pattern of the rule and adds the rule to the
+// Router.
+func (rtr *Router) addRewrite(rule *rewriteRule, pattern string) *Router {
+	regex, err := regexp.Compile(pattern)
+	if err != nil {
+		panic(fmt.Sprintf("can't compile regex %s: %v", pattern, err))
+	}
+	rule.pattern = regex
+	rtr.rewrites = append(rtr.rewrites, rule)
+	return rtr
+}
+
+// rewrite method applies the first rewrite rule that matches the request
+// path. If the path is rewritten, a copy of the request is returned, so that
+// the request of the caller stays intact. It returns false if the request was
+// answered with a redirect and must not be routed any further.
+func (rtr *Router) rewrite(
+	w http.ResponseWriter, r *http.Request,
+) (*http.Request, bool) {
+	for _, rule := range rtr.rewrites {
+		match := rule.pattern.FindStringSubmatchIndex(r.URL.Path)
+		if match == nil {
+			continue
+		}
+		target := string(rule.pattern.ExpandString(
+			nil, rule.replacement, r.URL.Path, match))
+		if !rule.redirect {
+			return withPath(r, target, ""), true
+		}
+
+		code := rule.code
+		if code == 0 {
+			code = http.StatusPermanentRedirect
+			if r.Method == http.MethodGet || r.Method == http.MethodHead {
+				code = http.StatusMovedPermanently
+			}
+		}
+		// Parent routers may have cut their prefixes already; restore them.
+		orig := originalPath(r)
+		if strings.HasPrefix(target, "/") &&
+			strings.HasSuffix(orig, r.URL.Path) {
+			target = orig[:len(orig)-len(r.URL.Path)] + target
+		}
+		if !strings.Contains(target, "?") && r.URL.RawQuery != "" {
+			target += "?" + r.URL.RawQuery
+		}
+		http.Redirect(w, r, target, code)
+		return r, false
+	}
+	return r, true
+}
@@ -0,0 +1,72 @@
+package mux
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRewrite(t *testing.T) {
+	path := func(w http.ResponseWriter, r *http.Request) {
+		Text(w, http.StatusOK, r.URL.Path+" "+r.URL.RawQuery)
+	}
+	rtr := New().
+		Rewrite("^/old/(.*)", "/new/$1").
+		Rewrite(`^/blog/(?P<id>\d+)\.html$`, "/posts/${id}").
+		Rewrite("^/old/never$", "/never").
+		RewriteRedirect("^/docs/(.*)", "https://docs.example.com/$1", 0).
+		RewriteRedirect("^/promo$", "/sale?utm=promo", http.StatusFound)
+	rtr.Get("/new/{rest:segment}", path)
+	rtr.Get("/posts/{id:int}", path)
+	rtr.Get("/blog/{slug:segment}", path)
+	rtr.Route("/api", func(r *Router) {
+		r.RewriteRedirect("^/v1/(.*)", "/v2/$1", 0)
+		r.Get("/v1/users", path)
+	})
+
+	cases := []struct {
+		method, url    string
+		code           int
+		body, location string
+	}{
+		{http.MethodGet, "/old/never?q=1", http.StatusOK, "/new/never q=1", ""},
+		{http.MethodGet, "/blog/42.html", http.StatusOK, "/posts/42 ", ""},
+		{http.MethodGet, "/blog/about", http.StatusOK, "/blog/about ", ""},
+		{http.MethodGet, "/docs/intro?v=2", http.StatusMovedPermanently, "",
+			"https://docs.example.com/intro?v=2"},
+		{http.MethodPost, "/docs/intro", http.StatusPermanentRedirect, "",
+			"https://docs.example.com/intro"},
+		{http.MethodGet, "/promo?x=1", http.StatusFound, "",
+			"/sale?utm=promo"},
+		{http.MethodGet, "/api/v1/users?page=2", http.StatusMovedPermanently,
+			"", "/api/v2/users?page=2"},
+	}
+	for _, c := range cases {
+		rec, req, err := request(c.method, c.url, nil)
+		assert.NoError(t, err)
+		rtr.ServeHTTP(rec, req)
+		assert.Equal(t, c.code, rec.Code, c.url)
+		if c.body != "" {
+			assert.Equal(t, c.body, rec.Body.String(), c.url)
+		}
+		assert.Equal(t, c.location, rec.Header().Get("Location"), c.url)
+	}
+	//-------------------- Another Test Case --------------------
+	var logged string
+	logging := func(next http.Handler) http.Handler {
+		return View(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r)
+			logged = r.URL.Path
+		})
+	}
+	inner := New().Rewrite("^/old$", "/new")
+	inner.Get("/new", path)
+	rec, req, err := request(http.MethodGet, "/old", nil)
+	assert.NoError(t, err)
+	logging(inner).ServeHTTP(rec, req)
+	assert.Equal(t, "/new ", rec.Body.String())
+	assert.Equal(t, "/old", logged, "caller's request changed")
+	//-------------------- Another Test Case --------------------
+	assert.Panics(t, func() { New().Rewrite("^/(", "/") })
+}
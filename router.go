@@ -2,49 +2,182 @@ package mux
 
 import (
 	"context"
+	"log/slog"
 	"net/http"
-	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 )
 
 // Router represents the node of a routing tree.
 type Router struct {
+	// name is an optional human-readable name of the route. It is not used
+	// for routing, but is reported by Walk.
+	name string
+
 	handler http.Handler
 
 	// Fail is a failure message written to http.ResponseWriter by the ServeHTTP
 	// method in case current request did not match any routes and the View
 	// handler function was not set.
 	//
-	// Initially its value is set to be DefaultFailMessage, but you can easily
-	// change it if you want.
+	// Initially it is nil, which means that the fail handler of the closest
+	// parent that has one is used, or DefaultFailHandler if there is none.
 	fail http.Handler
 
-	// routes is a slice of sub-routers.
+	// errorHandler is the function used by Error to report errors. See
+	// Router.ErrorHandler.
+	errorHandler ErrorHandlerFunc
+
+	// validator is the function used to validate bound values. See
+	// Router.Validator.
+	validator ValidatorFunc
+
+	// renderer is used by the Render function. See Router.Renderer.
+	renderer *Renderer
+
+	// flags is the provider of feature flags. See Router.Flags.
+	flags FlagProvider
+
+	// logger is used to report internal events. See Router.Logger.
+	logger *slog.Logger
+
+	// methodNotAllowed is a handler used instead of fail when some of the
+	// routes matched the request in everything except its method. By the time
+	// it is invoked, the Allow header is already set. Nil means inherited, as
+	// with fail.
+	methodNotAllowed http.Handler
+
+	// status maps status codes to handlers used by Error to report errors
+	// with those codes. See StatusHandler.
+	status map[int]http.Handler
+
+	// routes is a slice of sub-routers. It is never modified in place, so
+	// requests can use it while new routes are added. Guarded by mu.
 	routes []*Router
 
+	// index holds *routeIndex compiled from routes. It is built lazily by the
+	// Match method and reset whenever routes or their path filters change.
+	index atomic.Value
+
+	// mu guards routes and serializes index compilation.
+	mu sync.Mutex
+
+	// parent is the Router this one was created by with Subrouter (if any).
+	parent *Router
+
 	// filters is a set of filters that are used to check whether this Router
 	// instance should be used for the request at hand.
 	filters *Filters
 
+	// strictPath tells whether path filters created by the Path method should
+	// only match full paths. See StrictPath.
+	strictPath bool
+
+	// encoded is the encoded slashes policy. See EncodedSlashes.
+	encoded EncodedSlashes
+
+	// clean is the path cleaning mode. See CleanPath.
+	clean int
+
+	// rewrites are the rules applied to request paths before routing. See
+	// Rewrite.
+	rewrites []*rewriteRule
+
+	// slash is the trailing slash policy. See TrailingSlash.
+	slash TrailingSlash
+
+	// headFallback tells whether HEAD requests that did not match any route
+	// should be served by the matching GET route. See HeadFallback.
+	headFallback bool
+
+	// source is the location (file:line) of the code that registered this
+	// router, reported by Validate.
+	source string
+
+	// priority is used to order sibling routes for matching. See Priority.
+	priority int
+
+	// specific tells whether routes are ordered by specificity. See
+	// SortBySpecificity.
+	specific bool
+
+	// disabled tells whether the route is switched off. See Disable.
+	disabled atomic.Bool
+
+	// group tells whether the router only matches requests that its handler or
+	// one of its routes can serve. See Group.
+	group bool
+
 	// middleware is just a list of handlers that are applied to the request
 	// before it is passed to the final Router's handler or a subroute.
 	middleware []http.Handler
+
+	// wrappers is a list of middleware that wrap the rest of request handling.
+	// See Wrap.
+	wrappers []Middleware
+
+	// wrapped is the serve method wrapped in wrappers, or nil if there are
+	// none.
+	wrapped http.Handler
+
+	// collectStats tells whether the routes in the subtree collect
+	// statistics. See CollectStats.
+	collectStats bool
+
+	// stats holds the statistics of the route, or nil if it has none.
+	stats atomic.Pointer[routeStats]
 }
 
-// DefaultFailHandler is a default handler attached to every Router. Use
-// Router.Fail to specify a custom one.
+// DefaultFailHandler is a default handler used by routers that neither have a
+// fail handler nor inherit one. Use Router.Fail to specify a custom one.
 var DefaultFailHandler = http.NotFoundHandler()
 
+// DefaultMethodNotAllowedHandler is a default handler used to respond with
+// "405 Method Not Allowed". Use Router.MethodNotAllowed to specify a custom
+// one.
+var DefaultMethodNotAllowedHandler = View(
+	func(w http.ResponseWriter, r *http.Request) {
+		http.Error(
+			w,
+			http.StatusText(http.StatusMethodNotAllowed),
+			http.StatusMethodNotAllowed,
+		)
+	},
+)
+
 // New is a constructor used to create the root of a routing tree. Root doesn't
 // need any filters as it is invoked automatically by the server anyway.
 // The routes will be added later, using Router's methods.
 func New() *Router {
 	return &Router{
-		handler:    nil,
-		fail:       DefaultFailHandler,
-		routes:     nil,
-		filters:    NewFilters(),
-		middleware: make([]http.Handler, 0),
+		name:             "",
+		handler:          nil,
+		fail:             nil,
+		errorHandler:     nil,
+		validator:        nil,
+		renderer:         nil,
+		flags:            nil,
+		logger:           nil,
+		methodNotAllowed: nil,
+		status:           nil,
+		routes:           nil,
+		filters:          NewFilters(),
+		parent:           nil,
+		strictPath:       true,
+		encoded:          DecodedPaths,
+		clean:            cleanNone,
+		rewrites:         nil,
+		slash:            InheritSlash,
+		headFallback:     false,
+		source:           "",
+		priority:         0,
+		specific:         false,
+		group:            false,
+		middleware:       make([]http.Handler, 0),
+		wrappers:         nil,
+		wrapped:          nil,
+		collectStats:     false,
 	}
 }
 
@@ -54,45 +187,130 @@ func New() *Router {
 // but a sub-router instead, its ServeHTTP method will be invoked by the parent
 // Router whenever some request passes all its filters upon checkup.
 func (rtr *Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// Switch to escaped path if required. Stop if request was rejected.
+	r, ok := rtr.escapePath(w, r)
+	if !ok {
+		return
+	}
+
+	// Clean the path (if enabled). Stop if client was redirected.
+	r, ok = rtr.cleanPath(w, r)
+	if !ok {
+		return
+	}
+
 	// Cut path prefix (if set) from the reuqest URL path.
-	if rtr.filters.PathPrefix != nil {
-		r.URL.Path = strings.TrimPrefix(
-			r.URL.Path, string(*rtr.filters.PathPrefix),
-		)
+	r = rtr.trim(r)
+
+	// Apply rewrite rules (if any). Stop if client was redirected.
+	r, ok = rtr.rewrite(w, r)
+	if !ok {
+		return
 	}
 
-	// Parse path variables and alter http.Request.Context.
-	r = rtr.vars(r)
+	// Parse path variables and alter http.Request.Context. Stop if they are
+	// invalid.
+	r, ok = rtr.vars(w, r)
+	if !ok {
+		return
+	}
 
-	// Apply middleware.
-	for _, mw := range rtr.middleware {
-		mw.ServeHTTP(w, r)
+	// Let sub-routers know that HEAD fallback is enabled for them.
+	if rtr.headFallback {
+		r = r.WithContext(context.WithValue(r.Context(), headFallbackKey, true))
+	}
+
+	// Let handlers know which error handler, validator, renderer, logger and
+	// flag provider to use.
+	r = rtr.withErrorHandler(r)
+	r = rtr.withValidator(r)
+	r = rtr.withRenderer(r)
+	r = rtr.withLogger(r)
+	r = rtr.withFlags(r)
+
+	// Let sub-routers inherit fail and status handlers.
+	r = rtr.withFailHandlers(r)
+	r = rtr.withStatusHandlers(r)
+
+	// Let sub-routers know whether they should collect statistics.
+	r = rtr.withStats(r)
+
+	// Let sub-routers know about the trailing slash policy.
+	if rtr.slash != InheritSlash {
+		r = r.WithContext(context.WithValue(r.Context(), slashKey, rtr.slash))
+	}
+
+	// Let wrapping middleware (if any) take over the rest of the request.
+	if rtr.wrapped != nil {
+		rtr.wrapped.ServeHTTP(w, r)
+		return
+	}
+	rtr.serve(w, r)
+}
+
+// serve method applies middleware handlers and dispatches the request to the
+// matching route, the handler, or the fail handler.
+func (rtr *Router) serve(w http.ResponseWriter, r *http.Request) {
+	// Apply middleware. Stop if one of them responded.
+	if len(rtr.middleware) > 0 {
+		r = r.WithContext(context.WithValue(r.Context(), routerKey, rtr))
+		rw := NewResponseWriter(w)
+		for _, mw := range rtr.middleware {
+			mw.ServeHTTP(rw, unescaped(r))
+			if rw.Written() {
+				return
+			}
+		}
 	}
 
 	// 1. Check if there are routes with matching filters.
-	// 2. If not, use handler if present.
-	// 3. If everything else failed, respond with a fail message.
+	// 2. If not, try serving HEAD request with a GET route (if enabled).
+	// 3. If not, try toggling trailing slash (if policy allows).
+	// 4. If not, use handler if present.
+	// 5. If some routes differ only by method, respond with 405.
+	// 6. If everything else failed, respond with a fail message.
 	if sub, match := rtr.Match(r); match {
 		sub.ServeHTTP(w, r)
+	} else if sub, get, match := rtr.matchHead(r); match {
+		hw := newHeadWriter(w)
+		sub.ServeHTTP(hw, get)
+		hw.flush()
+	} else if sub, alt, match := rtr.matchSlash(r); match {
+		rtr.serveSlash(w, r, sub, alt)
 	} else if rtr.handler != nil {
-		rtr.handler.ServeHTTP(w, r)
+		matched(r, rtr)
+		rtr.serveHandler(w, unescaped(r))
+	} else if allow := rtr.allowed(r); len(allow) > 0 {
+		w.Header().Set("Allow", strings.Join(allow, ", "))
+		logMismatch(r, "mux: method not allowed")
+		rtr.methodNotAllowedHandler(r).ServeHTTP(w, unescaped(r))
 	} else {
-		rtr.fail.ServeHTTP(w, r)
+		logMismatch(r, "mux: no route matched")
+		rtr.failHandler(r).ServeHTTP(w, unescaped(r))
 	}
 }
 
-// Use registers a middleware handler on the Router.
+// Use registers a middleware handler on the Router. Middleware handlers run
+// one after another before the request is routed. If one of them writes the
+// response (e.g. rejects the request), the request goes no further.
 func (rtr *Router) Use(h http.Handler) *Router {
 	rtr.middleware = append(rtr.middleware, h)
 	return rtr
 }
 
-// Use registers a middleware View handler on the Router.
+// UseFunc registers a middleware View handler on the Router. See Use.
 func (rtr *Router) UseFunc(v View) *Router {
 	rtr.middleware = append(rtr.middleware, v)
 	return rtr
 }
 
+// Name method sets router's name. Names are not used for routing, but they
+// make it easier to identify routes reported by Walk.
+func (rtr *Router) Name(name string) *Router {
+	rtr.name = name
+	return rtr
+}
+
 // Handler method sets router's handler.
 func (rtr *Router) Handler(h http.Handler) *Router {
 	rtr.handler = h
@@ -105,30 +323,129 @@ func (rtr *Router) HandleFunc(v View) *Router {
 	return rtr
 }
 
-// Fail method sets router's fail message.
+// Fail method sets router's fail message. It is used for the whole subtree of
+// the Router, unless sub-routers set their own; pass DefaultFailHandler to
+// restore the default one in a subtree, or nil to inherit the parent's one
+// again.
 func (rtr *Router) Fail(handler http.Handler) *Router {
 	rtr.fail = handler
 	return rtr
 }
 
-// FailFunc method sets router's fail message.
+// FailFunc method sets router's fail message. See Fail.
 func (rtr *Router) FailFunc(v View) *Router {
-	rtr.fail = v
+	if v == nil {
+		return rtr.Fail(nil)
+	}
+	return rtr.Fail(v)
+}
+
+// MethodNotAllowed method sets the handler used to respond when request path
+// matched some routes but its method did not. The Allow header is set before
+// the handler is invoked. Like Fail, it is inherited by sub-routers.
+func (rtr *Router) MethodNotAllowed(handler http.Handler) *Router {
+	rtr.methodNotAllowed = handler
+	return rtr
+}
+
+// MethodNotAllowedFunc method sets the handler used to respond when request
+// path matched some routes but its method did not. See MethodNotAllowed.
+func (rtr *Router) MethodNotAllowedFunc(v View) *Router {
+	if v == nil {
+		return rtr.MethodNotAllowed(nil)
+	}
+	return rtr.MethodNotAllowed(v)
+}
+
+// HeadFallback method enables or disables HEAD fallback mode for this Router
+// and all of its sub-routers. In this mode, HEAD requests that did not match
+// any route are served by the route that would match the same request with
+// GET method. The response body is discarded, but Content-Length is set
+// according to its size.
+//
+// NOTICE: Handlers invoked this way receive a copy of the request with its
+// method set to GET, so that nested methods filters match as well.
+func (rtr *Router) HeadFallback(enabled bool) *Router {
+	rtr.headFallback = enabled
 	return rtr
 }
 
 // Subrouter method returns pointer to a new sub-router instance that inherits
 // context from its parent.
+//
+// The sub-router is matched against requests right away, so configuring it
+// while the server is running is not safe. Add routes at runtime with the
+// shortcuts (Get, Post, etc.) or with Group and Route, which attach routes
+// only once they are fully configured.
 func (rtr *Router) Subrouter() *Router {
+	return rtr.attach(rtr.detached())
+}
+
+// detached method returns a new sub-router that is not yet added to the
+// routes of this Router. See attach.
+func (rtr *Router) detached() *Router {
 	// Create new Router that inherits its parent's Context.
 	sub := New()
+	sub.parent = rtr
+	sub.specific = rtr.specific
+	sub.source = caller()
+	return sub
+}
 
-	// Add it to parent's routes.
-	rtr.routes = append(rtr.routes, sub)
-
+// attach method adds the sub-router to the routes of this Router. It is safe
+// to call while requests are served: the list of routes is copied on write.
+func (rtr *Router) attach(sub *Router) *Router {
+	rtr.mu.Lock()
+	defer rtr.mu.Unlock()
+	rtr.routes = append(rtr.routes[:len(rtr.routes):len(rtr.routes)], sub)
+	rtr.index.Store((*routeIndex)(nil))
 	return sub
 }
 
+// Get is a shortcut that creates a sub-router matching GET requests to the
+// given path and sets its handler. It returns pointer to the sub-router so you
+// can keep configuring it:
+//
+//	rtr.Get("/users/{id:int}", showUser)
+//
+// is equivalent to
+//
+//	rtr.Subrouter().Methods(http.MethodGet).Path("/users/{id:int}").
+//	    HandleFunc(showUser)
+func (rtr *Router) Get(path string, v View) *Router {
+	return rtr.route(http.MethodGet, path, v)
+}
+
+// Post is a shortcut that creates a sub-router matching POST requests to the
+// given path and sets its handler. See Get.
+func (rtr *Router) Post(path string, v View) *Router {
+	return rtr.route(http.MethodPost, path, v)
+}
+
+// Put is a shortcut that creates a sub-router matching PUT requests to the
+// given path and sets its handler. See Get.
+func (rtr *Router) Put(path string, v View) *Router {
+	return rtr.route(http.MethodPut, path, v)
+}
+
+// Delete is a shortcut that creates a sub-router matching DELETE requests to
+// the given path and sets its handler. See Get.
+func (rtr *Router) Delete(path string, v View) *Router {
+	return rtr.route(http.MethodDelete, path, v)
+}
+
+// Patch is a shortcut that creates a sub-router matching PATCH requests to the
+// given path and sets its handler. See Get.
+func (rtr *Router) Patch(path string, v View) *Router {
+	return rtr.route(http.MethodPatch, path, v)
+}
+
+// route creates a sub-router with methods and path filters set and assigns
+// the View as its handler.
+func (rtr *Router) route(method string, path string, v View) *Router {
+	return rtr.attach(rtr.detached().Methods(method).Path(path).HandleFunc(v))
+}
+
 // Methods returns pointer to the same Router instance while altering its
 // methods filter.
 //
@@ -142,11 +459,29 @@ func (rtr *Router) Methods(methods ...string) *Router {
 // Path returns pointer to the same Router instance while altering its path
 // filter.
 //
+// The path filter is strict by default, meaning that the whole request path
+// must match the template. Use StrictPath(false) to opt out.
+//
 // NOTICE: This method replaces router's PathFilter with a newly created
 // instance while setting PathPrefix to nil.
 func (rtr *Router) Path(path string) *Router {
-	rtr.filters.Path = NewPathFilter(path)
+	rtr.filters.Path = newPathFilter(path, rtr.strictPath)
 	rtr.filters.PathPrefix = nil
+	rtr.invalidate()
+	return rtr
+}
+
+// StrictPath returns pointer to the same Router instance while setting the
+// strictness of its path filter. Strict path filters (the default) only match
+// full request paths; loose ones match any path that contains a match of the
+// template, e.g. loose "/user" also matches "/log/user/thing".
+//
+// If the path filter is already set, it is rebuilt with the new strictness.
+func (rtr *Router) StrictPath(strict bool) *Router {
+	rtr.strictPath = strict
+	if rtr.filters.Path != nil {
+		rtr.Path(rtr.filters.Path.Path)
+	}
 	return rtr
 }
 
@@ -158,6 +493,7 @@ func (rtr *Router) Path(path string) *Router {
 func (rtr *Router) PathPrefix(prefix string) *Router {
 	rtr.filters.PathPrefix = NewPathPrefixFilter(prefix)
 	rtr.filters.Path = nil
+	rtr.invalidate()
 	return rtr
 }
 
@@ -174,78 +510,208 @@ func (rtr *Router) Schemes(schemes ...string) *Router {
 	return rtr
 }
 
+// Host returns pointer to the same Router instance while altering its host
+// filter. The request matches if its host, without port, is one of the given
+// hosts. ServeTLS obtains certificates for these hosts with autocert.
+//
+// NOTICE: This method replaces router's HostFilter with a newly created
+// instance.
+func (rtr *Router) Host(hosts ...string) *Router {
+	rtr.filters.Host = NewHostFilter(hosts...)
+	return rtr
+}
+
+// UserAgent returns pointer to the same Router instance while altering its
+// User-Agent filter. The request matches if any of the patterns matches its
+// User-Agent header.
+//
+// NOTICE: This method replaces router's UserAgentFilter with a newly created
+// instance.
+func (rtr *Router) UserAgent(patterns ...string) *Router {
+	rtr.filters.UserAgent = NewUserAgentFilter(patterns...)
+	return rtr
+}
+
+// ClientCert returns pointer to the same Router instance while altering its
+// client certificate filter. Only requests with a verified TLS client
+// certificate will match; patterns, if given, are checked against the subject
+// common name and alternative names of the certificate.
+//
+// NOTICE: This method replaces router's ClientCertFilter with a newly created
+// instance.
+func (rtr *Router) ClientCert(patterns ...string) *Router {
+	rtr.filters.ClientCert = NewClientCertFilter(patterns...)
+	return rtr
+}
+
+// Filter returns pointer to the same Router instance while adding a custom
+// filter to it. Unlike other filter methods, Filter does not replace anything:
+// each call appends another filter that requests must pass.
+func (rtr *Router) Filter(f Filter) *Router {
+	rtr.filters.Custom = append(rtr.filters.Custom, f)
+	return rtr
+}
+
+// Priority method sets the priority of the router among its siblings. Routes
+// with higher priority are checked first, so they win over the routes that
+// were registered before them; routes with equal priority (zero by default)
+// are checked in order of registration:
+//
+//	rtr.Get("/users/{name:segment}", showUser)
+//	rtr.Get("/users/new", newUserForm).Priority(1)
+//
+// Negative priorities move routes to the end of the list, which is handy for
+// catch-alls. It returns pointer to the same Router instance.
+func (rtr *Router) Priority(n int) *Router {
+	rtr.priority = n
+	rtr.invalidate()
+	return rtr
+}
+
+// MatcherFunc returns pointer to the same Router instance while adding a
+// function as its custom filter. See Filter.
+func (rtr *Router) MatcherFunc(f func(*http.Request) bool) *Router {
+	return rtr.Filter(MatcherFunc(f))
+}
+
 // Match method must go through all registered routes one by one and check if
 // their filters match the request. It returns the first sub-router where
 // filters matched and a boolean value indicating that there was a match.
+// Routes are checked in order of their priority, then in order of
+// registration (see Priority).
 // If there was no match, it returns nil as the sub-router while setting the
 // second value to false.
+//
+// Routes are looked up in a compiled prefix tree, so only those whose static
+// path prefix fits the request are actually checked.
 func (rtr *Router) Match(r *http.Request) (sub *Router, match bool) {
-	for _, route := range rtr.routes {
-		if route.filters.Match(r) {
+	idx := rtr.compiled()
+	for _, i := range idx.candidates(r.URL.Path) {
+		if route := idx.routes[i]; route.accepts(r) {
 			return route, true
 		}
 	}
 	return nil, false
 }
 
+// compiled method returns the route index, compiling it if needed.
+func (rtr *Router) compiled() *routeIndex {
+	if idx, _ := rtr.index.Load().(*routeIndex); idx != nil {
+		return idx
+	}
+	rtr.mu.Lock()
+	defer rtr.mu.Unlock()
+	idx, _ := rtr.index.Load().(*routeIndex)
+	if idx == nil {
+		idx = newRouteIndex(rtr.routes, rtr.specific)
+		rtr.index.Store(idx)
+		rtr.logShadowed(idx)
+	}
+	return idx
+}
+
+// ordered method returns sub-routers in the order they are matched in.
+func (rtr *Router) ordered() []*Router {
+	idx := rtr.compiled()
+	routes := make([]*Router, len(idx.order))
+	for i, j := range idx.order {
+		routes[i] = idx.routes[j]
+	}
+	return routes
+}
+
+// invalidate method resets parent's route index after path filters of this
+// Router were changed.
+func (rtr *Router) invalidate() {
+	if rtr.parent != nil {
+		rtr.parent.index.Store((*routeIndex)(nil))
+	}
+}
+
+// matchHead method checks whether the HEAD request can be served by a GET
+// route in case HEAD fallback is enabled. It returns the matched sub-router and
+// a GET copy of the request to serve it with.
+func (rtr *Router) matchHead(r *http.Request) (*Router, *http.Request, bool) {
+	if r.Method != http.MethodHead {
+		return nil, nil, false
+	}
+	if enabled, _ := r.Context().Value(headFallbackKey).(bool); !enabled {
+		return nil, nil, false
+	}
+	get := r.Clone(r.Context())
+	get.Method = http.MethodGet
+	if sub, match := rtr.Match(get); match {
+		return sub, get, true
+	}
+	return nil, nil, false
+}
+
+// allowed method returns a sorted list of methods accepted by those routes
+// that match the request in everything except its method. It is only useful
+// after Match failed: any route without a methods filter that matches the rest
+// would have been matched already.
+func (rtr *Router) allowed(r *http.Request) []string {
+	allow := newSet()
+	for _, route := range rtr.compiled().routes {
+		if !route.Enabled() {
+			continue
+		}
+		if route.group && route.filters.Match(r) {
+			for _, m := range route.allowed(route.trim(r)) {
+				allow.Add(m)
+			}
+			continue
+		}
+		if route.filters.Methods == nil {
+			continue
+		}
+		fils := *route.filters
+		fils.Methods = nil
+		if fils.Match(r) {
+			for _, m := range route.filters.Methods.Methods.Items() {
+				allow.Add(m)
+			}
+		}
+	}
+	return allow.Items()
+}
+
 // vars method parses variables from request using the PathFilter.Path and
 // stores them in http.Request.Context.
 //
 // This is a non-exported method that's only triggered by Router's ServeHTTP
-// method. Therefore, we can assume that the Request given to us matches all
-// Router's filters including the PathFilter (if present).
-func (rtr *Router) vars(r *http.Request) *http.Request {
+// method. The Request given to us matches all Router's filters including the
+// PathFilter (if present), unless the Router is the root one, whose filters
+// aren't checked. If the variables can't be parsed, the client gets "404 Not
+// Found" and false is returned.
+func (rtr *Router) vars(
+	w http.ResponseWriter, r *http.Request,
+) (*http.Request, bool) {
 	pathfil := rtr.filters.Path
 
 	// Check if PathFilter is present.
 	if pathfil == nil {
-		return r
+		return r, true
 	}
 
 	// Check if PathFilter has variables.
 	if !pathfil.hasVars {
-		return r
+		return r, true
 	}
 
 	// At this point, we know that rtr has a PathFilter with vars.
-	vars := make(map[string]interface{})
-	path := pathfil.Path
-
-	// Slicing the first element away because it is always going to be an empty
-	// string since the first character is always a slash.
-	fsplit := strings.Split(path, "/")[1:]
-	rsplit := strings.Split(r.URL.Path, "/")[1:]
-
-	// Linear pattern matching. The pat here is a field from the filter path,
-	// exp is a request path field we want to match towards. Both are strings.
-	// For example, pat = "{n:int}"; exp = "42".
-	for i, pat := range fsplit {
-		exp := rsplit[i]
-
-		// Skip all patterns that are not variables. No need to validate them.
-		if !isVar(pat) {
-			continue
-		}
-
-		name, typ := varData(pat)
-
-		// Discarding all conversion errors in switch because we know
-		// for sure that exp passed regex test for number.
-		switch typ {
-		case "int":
-			vars[name], _ = strconv.Atoi(exp)
-
-		case "nat":
-			n, _ := strconv.ParseUint(exp, 10, 0)
-			vars[name] = uint(n)
+	vars, raw, err := pathfil.parse(r)
+	if err != nil {
+		Error(w, r, NewHTTPError(http.StatusNotFound, "%v", err))
+		return r, false
+	}
 
-		case "str":
-			vars[name] = exp
+	r = r.WithContext(context.WithValue(r.Context(), varsKey, vars))
 
-		default: // regex type
-			vars[name] = exp
-		}
+	// Expose raw values through http.Request.PathValue as well, so that
+	// handlers written for http.ServeMux work unchanged.
+	for name, value := range raw {
+		r.SetPathValue(name, value)
 	}
-
-	return r.WithContext(context.WithValue(r.Context(), varsKey, vars))
+	return r, true
 }
@@ -108,6 +108,91 @@ func TestRouterMiddleware(t *testing.T) {
 	assert.NoError(t, err, "middleware failed:", err)
 }
 
+func TestMethodShortcuts(t *testing.T) {
+	rtr := New()
+	rtr.Get("/users/{id:int}", func(w http.ResponseWriter, r *http.Request) {
+		vars, _ := Vars(r)
+		fmt.Fprintf(w, "get %d", vars["id"])
+	})
+	rtr.Post("/users", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "post")
+	})
+	rtr.Delete("/users/{id:int}", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "delete")
+	})
+
+	cases := []struct {
+		method, path, body string
+	}{
+		{http.MethodGet, "/users/42", "get 42"},
+		{http.MethodPost, "/users", "post"},
+		{http.MethodDelete, "/users/42", "delete"},
+	}
+	for _, c := range cases {
+		rec, req, err := request(c.method, c.path, nil)
+		assert.NoError(t, err)
+		rtr.ServeHTTP(rec, req)
+		assert.Equal(t, c.body, rec.Body.String(), c.method+" "+c.path)
+	}
+
+	rec, req, err := request(http.MethodPut, "/users/42", nil)
+	assert.NoError(t, err)
+	rtr.ServeHTTP(rec, req)
+	assert.NotEqual(t, http.StatusOK, rec.Code)
+}
+
+func TestMethodNotAllowed(t *testing.T) {
+	rtr := New()
+	rtr.Get("/users", func(w http.ResponseWriter, r *http.Request) {})
+	rtr.Post("/users", func(w http.ResponseWriter, r *http.Request) {})
+	rtr.Get("/songs", func(w http.ResponseWriter, r *http.Request) {})
+
+	rec, req, err := request(http.MethodDelete, "/users", nil)
+	assert.NoError(t, err)
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	assert.Equal(t, "GET, POST", rec.Header().Get("Allow"))
+	//-------------------- Another Test Case --------------------
+	rec, req, err = request(http.MethodDelete, "/nothing", nil)
+	assert.NoError(t, err)
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Empty(t, rec.Header().Get("Allow"))
+	//-------------------- Another Test Case --------------------
+	rtr.MethodNotAllowedFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		fmt.Fprintf(w, "use %s", w.Header().Get("Allow"))
+	})
+	rec, req, err = request(http.MethodPut, "/songs", nil)
+	assert.NoError(t, err)
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	assert.Equal(t, "use GET", rec.Body.String())
+}
+
+func TestHeadFallback(t *testing.T) {
+	rtr := New().HeadFallback(true)
+	api := rtr.Subrouter().PathPrefix("/api")
+	api.Get("/hello", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Method", r.Method)
+		fmt.Fprint(w, "hello, world")
+	})
+
+	rec, req, err := request(http.MethodHead, "/api/hello", nil)
+	assert.NoError(t, err)
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "12", rec.Header().Get("Content-Length"))
+	assert.Equal(t, http.MethodGet, rec.Header().Get("X-Method"))
+	assert.Empty(t, rec.Body.String())
+	//-------------------- Another Test Case --------------------
+	rtr.HeadFallback(false)
+	rec, req, err = request(http.MethodHead, "/api/hello", nil)
+	assert.NoError(t, err)
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
+
 func request(method string, addr string, body io.Reader) (
 	w *httptest.ResponseRecorder, r *http.Request, err error,
 ) {
@@ -0,0 +1,186 @@
+package mux
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// TimeWindow is a period of time that repeats every week.
+type TimeWindow struct {
+	// Days are the days the window starts on. Empty means every day.
+	Days []time.Weekday
+
+	// Start and End are the times of day the window starts and ends at, as
+	// durations since midnight. Windows whose End isn't after their Start
+	// end on the next day, e.g. night shifts from 22:00 to 06:00.
+	Start, End time.Duration
+}
+
+// startsOn method tells whether the window starts on the day.
+func (win *TimeWindow) startsOn(day time.Weekday) bool {
+	if len(win.Days) == 0 {
+		return true
+	}
+	for _, d := range win.Days {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
+
+// contains method tells whether the window contains the time of day clock of
+// the day.
+func (win *TimeWindow) contains(day time.Weekday, clock time.Duration) bool {
+	if win.Start < win.End {
+		return win.startsOn(day) && clock >= win.Start && clock < win.End
+	}
+	yesterday := (day + 6) % 7
+	return win.startsOn(day) && clock >= win.Start ||
+		win.startsOn(yesterday) && clock < win.End
+}
+
+// TimeFilter takes care of filtering requests that arrive within schedules,
+// e.g. trading hours or the working hours of a support desk. Times of day are
+// those of Location, so daylight saving time is taken care of.
+type TimeFilter struct {
+	Location *time.Location
+	Windows  []TimeWindow
+	now      func() time.Time
+}
+
+// NewTimeFilter returns pointer to a new TimeFilter of the schedules in the
+// location. If loc is nil, local time is used. Schedules consist of optional
+// days followed by the times of day, e.g. "Mon-Fri 09:30-16:00",
+// "Sat,Sun 10:00-14:00" or "22:00-06:00" (every day, till the next morning).
+// It panics if any of the schedules is malformed.
+func NewTimeFilter(loc *time.Location, schedules ...string) *TimeFilter {
+	if loc == nil {
+		loc = time.Local
+	}
+	fil := &TimeFilter{loc, make([]TimeWindow, 0, len(schedules)), time.Now}
+	for _, s := range schedules {
+		win, err := parseSchedule(s)
+		if err != nil {
+			panic(fmt.Sprintf("can't parse schedule %s: %v", s, err))
+		}
+		fil.Windows = append(fil.Windows, win)
+	}
+	return fil
+}
+
+// Match method returns boolean value that tells you whether given request
+// passed the filter. Also, *TimeFilter implements the Filter interface since
+// it has this method.
+func (fil *TimeFilter) Match(r *http.Request) bool {
+	now := time.Now
+	if fil.now != nil {
+		now = fil.now
+	}
+	loc := fil.Location
+	if loc == nil {
+		loc = time.Local
+	}
+	t := now().In(loc)
+	clock := time.Duration(t.Hour())*time.Hour +
+		time.Duration(t.Minute())*time.Minute +
+		time.Duration(t.Second())*time.Second
+	for i := range fil.Windows {
+		if fil.Windows[i].contains(t.Weekday(), clock) {
+			return true
+		}
+	}
+	return false
+}
+
+// During method adds a TimeFilter to the Router, so that it only accepts
+// requests that arrive within the schedules (see NewTimeFilter). Register
+// the route that serves the requests at other times after it:
+//
+//	nyse, _ := time.LoadLocation("America/New_York")
+//	rtr.Post("/orders", placeOrder).During(nyse, "Mon-Fri 09:30-16:00")
+//	rtr.Post("/orders", marketClosed)
+//
+// It panics if any of the schedules is malformed.
+func (rtr *Router) During(loc *time.Location, schedules ...string) *Router {
+	return rtr.Filter(NewTimeFilter(loc, schedules...))
+}
+
+// parseSchedule parses the schedule of the form "[days] hh:mm-hh:mm", where
+// days are comma-separated days of week or their ranges, e.g. "Mon-Fri,Sun".
+func parseSchedule(s string) (win TimeWindow, err error) {
+	fields := strings.Fields(s)
+	switch len(fields) {
+	case 1:
+	case 2:
+		if win.Days, err = parseDays(fields[0]); err != nil {
+			return
+		}
+	default:
+		return win, errors.New("expected [days] hh:mm-hh:mm")
+	}
+	start, end, ok := strings.Cut(fields[len(fields)-1], "-")
+	if !ok {
+		return win, errors.New("expected times of day as hh:mm-hh:mm")
+	}
+	if win.Start, err = parseClock(start); err != nil {
+		return
+	}
+	win.End, err = parseClock(end)
+	return
+}
+
+// parseDays parses comma-separated days of week and their ranges. Ranges may
+// wrap around the end of the week, e.g. "Fri-Mon".
+func parseDays(s string) ([]time.Weekday, error) {
+	var days []time.Weekday
+	for _, part := range strings.Split(s, ",") {
+		from, to, isRange := strings.Cut(part, "-")
+		first, err := parseWeekday(from)
+		if err != nil {
+			return nil, err
+		}
+		last := first
+		if isRange {
+			if last, err = parseWeekday(to); err != nil {
+				return nil, err
+			}
+		}
+		for d := first; ; d = (d + 1) % 7 {
+			days = append(days, d)
+			if d == last {
+				break
+			}
+		}
+	}
+	return days, nil
+}
+
+// parseWeekday parses the name of the day of week, full or abbreviated to
+// three letters, in any case.
+func parseWeekday(s string) (time.Weekday, error) {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		name := d.String()
+		if strings.EqualFold(s, name) || strings.EqualFold(s, name[:3]) {
+			return d, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown day %q", s)
+}
+
+// parseClock parses the time of day of the form "hh:mm" into duration since
+// midnight. "24:00" stands for the end of the day.
+func parseClock(s string) (time.Duration, error) {
+	if s == "24:00" {
+		return 24 * time.Hour, nil
+	}
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q", s)
+	}
+	return time.Duration(t.Hour())*time.Hour +
+		time.Duration(t.Minute())*time.Minute, nil
+}
@@ -0,0 +1,74 @@
+package mux
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeFilter(t *testing.T) {
+	loc := time.FixedZone("EST", -5*60*60)
+	fil := NewTimeFilter(loc, "Mon-Fri 09:30-16:00", "Sat 22:00-02:00")
+	now := time.Date(2024, time.January, 1, 9, 30, 0, 0, loc) // Monday
+	fil.now = func() time.Time { return now }
+
+	text := func(s string) View {
+		return func(w http.ResponseWriter, r *http.Request) {
+			Text(w, http.StatusOK, s)
+		}
+	}
+	rtr := New()
+	rtr.Post("/orders", text("placed")).Filter(fil)
+	rtr.Post("/orders", text("closed"))
+
+	cases := []struct {
+		at   time.Time
+		body string
+	}{
+		{time.Date(2024, time.January, 1, 9, 30, 0, 0, loc), "placed"},
+		{time.Date(2024, time.January, 1, 9, 29, 59, 0, loc), "closed"},
+		{time.Date(2024, time.January, 5, 15, 59, 0, 0, loc), "placed"},
+		{time.Date(2024, time.January, 5, 16, 0, 0, 0, loc), "closed"},
+		{time.Date(2024, time.January, 1, 14, 30, 0, 0, time.UTC), "placed"},
+		{time.Date(2024, time.January, 1, 21, 30, 0, 0, time.UTC), "closed"},
+		{time.Date(2024, time.January, 6, 12, 0, 0, 0, loc), "closed"},
+		{time.Date(2024, time.January, 6, 23, 0, 0, 0, loc), "placed"},
+		{time.Date(2024, time.January, 7, 1, 0, 0, 0, loc), "placed"},
+		{time.Date(2024, time.January, 7, 2, 0, 0, 0, loc), "closed"},
+		{time.Date(2024, time.January, 7, 23, 0, 0, 0, loc), "closed"},
+	}
+	for _, c := range cases {
+		now = c.at
+		rec, req, err := request(http.MethodPost, "/orders", nil)
+		assert.NoError(t, err)
+		rtr.ServeHTTP(rec, req)
+		assert.Equal(t, c.body, rec.Body.String(), c.at)
+	}
+}
+
+func TestParseSchedule(t *testing.T) {
+	win, err := parseSchedule("fri-MON,wednesday 22:00-24:00")
+	assert.NoError(t, err)
+	assert.Equal(t, []time.Weekday{
+		time.Friday, time.Saturday, time.Sunday, time.Monday, time.Wednesday,
+	}, win.Days)
+	assert.Equal(t, 22*time.Hour, win.Start)
+	assert.Equal(t, 24*time.Hour, win.End)
+	//-------------------- Another Test Case --------------------
+	win, err = parseSchedule("08:15-17:45")
+	assert.NoError(t, err)
+	assert.Empty(t, win.Days)
+	assert.Equal(t, 8*time.Hour+15*time.Minute, win.Start)
+	assert.Equal(t, 17*time.Hour+45*time.Minute, win.End)
+	//-------------------- Another Test Case --------------------
+	for _, s := range []string{
+		"", "Mon-Fri", "Mon 9:00", "Mon 25:00-26:00", "Funday 09:00-10:00",
+		"Mon Tue 09:00-10:00",
+	} {
+		_, err := parseSchedule(s)
+		assert.Error(t, err, s)
+	}
+	assert.Panics(t, func() { NewTimeFilter(nil, "whenever") })
+}
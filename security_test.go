@@ -0,0 +1,75 @@
+package mux
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCSP(t *testing.T) {
+	csp := NewCSP().
+		Add("default-src", CSPSelf).
+		Add("img-src", CSPSelf).
+		Add("img-src", "https://cdn.example.com").
+		Add("object-src", CSPNone)
+	assert.Equal(t, "default-src 'self'; "+
+		"img-src 'self' https://cdn.example.com; object-src 'none'",
+		csp.String())
+
+	csp.Set("img-src", "data:").Remove("object-src").Remove("script-src")
+	assert.Equal(t, "default-src 'self'; img-src data:", csp.String())
+}
+
+func TestSecurityHeaders(t *testing.T) {
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+	report := NewSecurityHeaders("strict")
+	report.CSPReportOnly = true
+	report.FrameOptions = ""
+
+	rtr := New().Use(NewSecurityHeaders("web"))
+	rtr.Get("/", noop)
+	rtr.Subrouter().PathPrefix("/api").
+		Use(NewSecurityHeaders("api")).
+		Get("/users", noop)
+	rtr.Subrouter().PathPrefix("/beta").Use(report).Get("/", noop)
+
+	rec, req, err := request(http.MethodGet, "/", nil)
+	assert.NoError(t, err)
+	rtr.ServeHTTP(rec, req)
+	h := rec.Header()
+	assert.Equal(t, "nosniff", h.Get("X-Content-Type-Options"))
+	assert.Equal(t, "SAMEORIGIN", h.Get("X-Frame-Options"))
+	assert.Equal(t, "strict-origin-when-cross-origin", h.Get("Referrer-Policy"))
+	assert.Equal(t, "camera=(), microphone=(), geolocation=()",
+		h.Get("Permissions-Policy"))
+	assert.Equal(t, "default-src 'self'; base-uri 'self'; object-src 'none'; "+
+		"frame-ancestors 'self'", h.Get("Content-Security-Policy"))
+
+	//-------------------- Another Test Case --------------------
+
+	rec, req, err = request(http.MethodGet, "/api/users", nil)
+	assert.NoError(t, err)
+	rtr.ServeHTTP(rec, req)
+	h = rec.Header()
+	assert.Equal(t, "DENY", h.Get("X-Frame-Options"))
+	assert.Equal(t, "no-referrer", h.Get("Referrer-Policy"))
+	assert.Empty(t, h.Values("Permissions-Policy"))
+	assert.Equal(t, "default-src 'none'; frame-ancestors 'none'",
+		h.Get("Content-Security-Policy"))
+
+	//-------------------- Another Test Case --------------------
+
+	rec, req, err = request(http.MethodGet, "/beta/", nil)
+	assert.NoError(t, err)
+	rtr.ServeHTTP(rec, req)
+	h = rec.Header()
+	assert.Empty(t, h.Values("X-Frame-Options"))
+	assert.Empty(t, h.Values("Content-Security-Policy"))
+	assert.Contains(t, h.Get("Content-Security-Policy-Report-Only"),
+		"script-src 'self'")
+
+	//-------------------- Another Test Case --------------------
+
+	assert.Panics(t, func() { NewSecurityHeaders("lax") })
+}
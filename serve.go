@@ -0,0 +1,144 @@
+package mux
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// DefaultGracePeriod is how long Serve waits for requests in flight to
+// complete on shutdown unless specified otherwise.
+const DefaultGracePeriod = 30 * time.Second
+
+// ServeOptions configures Serve.
+type ServeOptions struct {
+	// GracePeriod is how long requests in flight have to complete once
+	// shutdown begins. Zero means DefaultGracePeriod.
+	GracePeriod time.Duration
+
+	// Signals are the signals that trigger shutdown. If nil, they are
+	// SIGINT and SIGTERM.
+	Signals []os.Signal
+
+	// Context triggers shutdown once it is done, e.g. in tests. Nil means
+	// context.Background().
+	Context context.Context
+
+	// Health, if set, is drained (see Health.Drain) as soon as shutdown
+	// begins, so that load balancers stop sending new requests.
+	Health *Health
+
+	// DrainDelay is how long to wait after draining Health before the
+	// server stops accepting connections, so that load balancers have time
+	// to notice.
+	DrainDelay time.Duration
+
+	// ReadHeaderTimeout, ReadTimeout, WriteTimeout and IdleTimeout are the
+	// timeouts of the http.Server. Zero ReadHeaderTimeout means 10 seconds,
+	// so that slow clients can't hold connections forever; zero values of
+	// the others mean no timeout, as in http.Server.
+	ReadHeaderTimeout time.Duration
+	ReadTimeout       time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+
+	// Server, if set, is called with the http.Server before it starts, so
+	// that the rest of its fields can be set, e.g. by h2c.Enable.
+	Server func(srv *http.Server)
+}
+
+// Serve listens on the TCP address and serves requests with h until it gets
+// SIGINT or SIGTERM. Then it stops accepting connections and waits for the
+// requests in flight to complete, so that deployments don't cut them off:
+//
+//	if err := mux.Serve(":8080", rtr, nil); err != nil {
+//	    log.Fatal(err)
+//	}
+//
+// Serve returns nil once the server has shut down gracefully. It returns an
+// error if it can't listen, or if requests are still in flight when the grace
+// period is over; in the latter case their connections are closed. If opts is
+// nil, defaults are used.
+func Serve(addr string, h http.Handler, opts *ServeOptions) error {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return serveListener(ln, h, opts, nil)
+}
+
+// serveListener serves requests accepted by the listener until shutdown. The
+// start function, if not nil, starts the server instead of Serve method, e.g.
+// to serve TLS.
+func serveListener(
+	ln net.Listener, h http.Handler, opts *ServeOptions,
+	start func(srv *http.Server, ln net.Listener) error,
+) error {
+	if opts == nil {
+		opts = &ServeOptions{}
+	}
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	signals := opts.Signals
+	if signals == nil {
+		signals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+	}
+	grace := opts.GracePeriod
+	if grace == 0 {
+		grace = DefaultGracePeriod
+	}
+	readHeaderTimeout := opts.ReadHeaderTimeout
+	if readHeaderTimeout == 0 {
+		readHeaderTimeout = 10 * time.Second
+	}
+	if start == nil {
+		start = (*http.Server).Serve
+	}
+
+	srv := &http.Server{
+		Handler:           h,
+		ReadHeaderTimeout: readHeaderTimeout,
+		ReadTimeout:       opts.ReadTimeout,
+		WriteTimeout:      opts.WriteTimeout,
+		IdleTimeout:       opts.IdleTimeout,
+	}
+	if opts.Server != nil {
+		opts.Server(srv)
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, signals...)
+	defer stop()
+	errc := make(chan error, 1)
+	go func() {
+		errc <- start(srv, ln)
+	}()
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+	stop()
+
+	if opts.Health != nil {
+		opts.Health.Drain()
+		time.Sleep(opts.DrainDelay)
+	}
+	shutdown, cancel := context.WithTimeout(context.Background(), grace)
+	defer cancel()
+	if err := srv.Shutdown(shutdown); err != nil {
+		srv.Close()
+		return fmt.Errorf("mux: graceful shutdown failed: %w", err)
+	}
+	if err := <-errc; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package mux
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServe(t *testing.T) {
+	for _, c := range []struct {
+		grace time.Duration
+		err   string
+	}{
+		{time.Second, ""},
+		{10 * time.Millisecond,
+			"mux: graceful shutdown failed: context deadline exceeded"},
+	} {
+		started := make(chan struct{})
+		release := make(chan struct{})
+		rtr := New()
+		rtr.Get("/slow", func(w http.ResponseWriter, r *http.Request) {
+			close(started)
+			<-release
+			w.Write([]byte("done"))
+		})
+
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		assert.NoError(t, err)
+		ctx, cancel := context.WithCancel(context.Background())
+		health := NewHealth()
+		served := make(chan error)
+		go func() {
+			served <- serveListener(ln, rtr, &ServeOptions{
+				Context:     ctx,
+				GracePeriod: c.grace,
+				Health:      health,
+			}, nil)
+		}()
+
+		type response struct {
+			body string
+			err  error
+		}
+		responses := make(chan response)
+		go func() {
+			res, err := http.Get("http://" + ln.Addr().String() + "/slow")
+			if err != nil {
+				responses <- response{"", err}
+				return
+			}
+			defer res.Body.Close()
+			body, err := io.ReadAll(res.Body)
+			responses <- response{string(body), err}
+		}()
+
+		// Shutdown waits for the request in flight.
+		<-started
+		cancel()
+		time.Sleep(50 * time.Millisecond)
+		assert.False(t, health.CheckReadiness(ctx).OK())
+		close(release)
+
+		err = <-served
+		res := <-responses
+		if c.err == "" {
+			assert.NoError(t, err)
+			assert.NoError(t, res.err)
+			assert.Equal(t, "done", res.body)
+		} else {
+			assert.EqualError(t, err, c.err)
+			assert.Error(t, res.err)
+		}
+	}
+}
+
+func TestServeListenError(t *testing.T) {
+	err := Serve("127.0.0.1:-1", New(), nil)
+	assert.Error(t, err)
+}
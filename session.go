@@ -0,0 +1,561 @@
+package mux
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/gob"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DefaultSessionIdleTimeout is how long sessions last without requests unless
+// specified otherwise.
+const DefaultSessionIdleTimeout = 30 * time.Minute
+
+// DefaultSessionLifetime is how long sessions last at most unless specified
+// otherwise.
+const DefaultSessionLifetime = 24 * time.Hour
+
+// SessionStore keeps sessions on the server side, so that the session cookie
+// carries only the session ID. Implement it to keep sessions in a database or
+// a cache like Redis shared by several instances of the server; use
+// MemorySessionStore for a single one.
+type SessionStore interface {
+	// Load returns the data of the session, or nil if there is no such
+	// session or it has expired.
+	Load(ctx context.Context, id string) ([]byte, error)
+
+	// Save stores the data of the session until it expires. Zero expiry
+	// time means the session doesn't expire.
+	Save(ctx context.Context, id string, data []byte, expires time.Time) error
+
+	// Delete removes the session.
+	Delete(ctx context.Context, id string) error
+}
+
+// SessionOptions configures Sessions.
+type SessionOptions struct {
+	// Secret is the key the session cookie is encrypted with. It should be
+	// at least 32 random bytes. It is required unless Cookies are given.
+	Secret []byte
+
+	// Cookies protect the session cookie. If nil, the cookie is encrypted
+	// with the Secret; set it to rotate keys. Sessions kept in cookies that
+	// are signed but not encrypted can be read by the client.
+	Cookies *Cookies
+
+	// Store keeps the sessions. If nil, the whole session is kept in the
+	// encrypted cookie, which must not be larger than 4KB.
+	Store SessionStore
+
+	// CookieName is the name of the session cookie. Empty means "session".
+	CookieName string
+
+	// Path and Domain are the attributes of the session cookie. Empty Path
+	// means "/".
+	Path   string
+	Domain string
+
+	// Secure makes the session cookie HTTPS only.
+	Secure bool
+
+	// SameSite is the SameSite attribute of the session cookie. Zero means
+	// http.SameSiteLaxMode.
+	SameSite http.SameSite
+
+	// IdleTimeout is how long sessions last without requests. Zero means
+	// DefaultSessionIdleTimeout; negative values disable the timeout.
+	IdleTimeout time.Duration
+
+	// Lifetime is how long sessions last since they were created, no matter
+	// how active they are. Zero means DefaultSessionLifetime; negative values
+	// disable the limit.
+	Lifetime time.Duration
+}
+
+// SessionData is the session of the client. Handlers get it with Session.
+// It is safe for concurrent use.
+//
+// Values are encoded with encoding/gob, so values of custom types must be
+// registered with gob.Register.
+type SessionData struct {
+	mu  sync.Mutex
+	rec sessionRecord
+
+	// old is the ID of the session replaced by this one, which has to be
+	// deleted from the store.
+	old string
+
+	// isNew tells whether the session was created by this request.
+	isNew bool
+
+	// cookie tells whether the request came with a session cookie.
+	cookie bool
+}
+
+// sessionRecord is the encoded form of a session.
+type sessionRecord struct {
+	ID       string
+	Created  time.Time
+	Accessed time.Time
+	Values   map[string]interface{}
+}
+
+// Session returns the session of the client, or nil if the request isn't
+// served under Sessions middleware.
+func Session(r *http.Request) *SessionData {
+	s, _ := r.Context().Value(sessionKey).(*SessionData)
+	return s
+}
+
+// ID method returns the ID of the session.
+func (s *SessionData) ID() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rec.ID
+}
+
+// IsNew method tells whether the session was started by this request.
+func (s *SessionData) IsNew() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.isNew
+}
+
+// Get method returns the value stored under the key, or nil.
+func (s *SessionData) Get(key string) interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rec.Values[key]
+}
+
+// Set method stores the value under the key.
+func (s *SessionData) Set(key string, value interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rec.Values[key] = value
+}
+
+// Delete method removes the value stored under the key.
+func (s *SessionData) Delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.rec.Values, key)
+}
+
+// Clear method removes all values from the session.
+func (s *SessionData) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rec.Values = make(map[string]interface{})
+}
+
+// Renew method gives the session a new ID while keeping its values. Call it
+// when the user logs in, so that an ID planted by an attacker before that
+// becomes useless (session fixation).
+func (s *SessionData) Renew() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.old == "" && !s.isNew {
+		s.old = s.rec.ID
+	}
+	s.rec.ID = newSessionID()
+}
+
+// Destroy method ends the session, e.g. when the user logs out. Values set
+// afterwards go to a new session.
+func (s *SessionData) Destroy() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.old == "" && !s.isNew {
+		s.old = s.rec.ID
+	}
+	s.rec = newSessionRecord(time.Now())
+	s.isNew = true
+}
+
+// Sessions returns Middleware that gives every client a session kept
+// between requests with a cookie:
+//
+//	rtr.Wrap(mux.Sessions(&mux.SessionOptions{
+//	    Secret: key,
+//	    Secure: true,
+//	}))
+//
+//	func login(w http.ResponseWriter, r *http.Request) {
+//	    ...
+//	    s := mux.Session(r)
+//	    s.Renew()
+//	    s.Set("user", user.ID)
+//	}
+//
+// New sessions are only saved once a value is set, so clients that never need
+// one don't get the cookie. The cookie is updated before the response is
+// written; failures to save the session are logged (see Router.Logger). It
+// panics if neither Secret nor Cookies are given.
+func Sessions(opts *SessionOptions) Middleware {
+	return newSessions(opts).wrap
+}
+
+// Sessions method registers Sessions middleware on the Router.
+func (rtr *Router) Sessions(opts *SessionOptions) *Router {
+	return rtr.Wrap(Sessions(opts))
+}
+
+// sessions implements Sessions middleware.
+type sessions struct {
+	opts SessionOptions
+	now  func() time.Time
+}
+
+// newSessions returns pointer to sessions configured by opts with defaults
+// filled in.
+func newSessions(opts *SessionOptions) *sessions {
+	if opts == nil || len(opts.Secret) == 0 && opts.Cookies == nil {
+		panic("can't use sessions without a secret")
+	}
+	m := &sessions{*opts, time.Now}
+	if m.opts.Cookies == nil {
+		m.opts.Cookies = SecureCookies(opts.Secret).Encrypted()
+	}
+	if m.opts.CookieName == "" {
+		m.opts.CookieName = "session"
+	}
+	if m.opts.Path == "" {
+		m.opts.Path = "/"
+	}
+	if m.opts.SameSite == 0 {
+		m.opts.SameSite = http.SameSiteLaxMode
+	}
+	if m.opts.IdleTimeout == 0 {
+		m.opts.IdleTimeout = DefaultSessionIdleTimeout
+	}
+	if m.opts.Lifetime == 0 {
+		m.opts.Lifetime = DefaultSessionLifetime
+	}
+	return m
+}
+
+// wrap method is the Middleware returned by Sessions.
+func (m *sessions) wrap(next http.Handler) http.Handler {
+	return View(func(w http.ResponseWriter, r *http.Request) {
+		s, err := m.load(r)
+		if err != nil {
+			Error(w, r, err)
+			return
+		}
+		r = r.WithContext(context.WithValue(r.Context(), sessionKey, s))
+		sw := &sessionWriter{w, func() { m.save(w, r, s) }, false}
+		next.ServeHTTP(sw, r)
+		sw.commit()
+	})
+}
+
+// load method returns the session of the client, or a new one if the client
+// has none or it has expired.
+func (m *sessions) load(r *http.Request) (*SessionData, error) {
+	now := m.now()
+	c, err := r.Cookie(m.opts.CookieName)
+	if err != nil {
+		return &SessionData{rec: newSessionRecord(now), isNew: true}, nil
+	}
+
+	s := &SessionData{cookie: true}
+	data, err := m.opts.Cookies.Decode(m.opts.CookieName, c.Value)
+	ok := err == nil
+	if ok && m.opts.Store != nil {
+		id := string(data)
+		if data, err = m.opts.Store.Load(r.Context(), id); err != nil {
+			return nil, err
+		}
+		s.old = id
+	}
+	if ok && data != nil && decodeSession(data, &s.rec) == nil &&
+		!m.expired(&s.rec, now) {
+		s.old = ""
+		return s, nil
+	}
+	s.rec = newSessionRecord(now)
+	s.isNew = true
+	return s, nil
+}
+
+// save method saves the session and sets the cookie. New sessions without
+// values are not saved; the cookie is deleted instead if the client had one.
+func (m *sessions) save(
+	w http.ResponseWriter, r *http.Request, s *SessionData,
+) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ctx := r.Context()
+	store := m.opts.Store
+	if s.old != "" && store != nil {
+		if err := store.Delete(ctx, s.old); err != nil {
+			m.logError(r, "mux: can't delete session", err)
+		}
+	}
+	if s.isNew && len(s.rec.Values) == 0 {
+		if s.cookie {
+			http.SetCookie(w, m.cookie("", -1))
+		}
+		return
+	}
+
+	now := m.now()
+	if s.isNew {
+		s.rec.Created = now
+	}
+	s.rec.Accessed = now
+	data, err := encodeSession(&s.rec)
+	if err != nil {
+		m.logError(r, "mux: can't encode session", err)
+		return
+	}
+	expires := m.expires(&s.rec)
+	if store != nil {
+		if err := store.Save(ctx, s.rec.ID, data, expires); err != nil {
+			m.logError(r, "mux: can't save session", err)
+			return
+		}
+		data = []byte(s.rec.ID)
+	}
+	value := m.opts.Cookies.Encode(m.opts.CookieName, data)
+	if len(value) > maxCookieSize {
+		m.logError(r, "mux: can't save session",
+			errors.New("session cookie is too large, use a SessionStore"))
+		return
+	}
+	maxAge := 0
+	if !expires.IsZero() {
+		maxAge = int(expires.Sub(now).Seconds()) + 1
+	}
+	http.SetCookie(w, m.cookie(value, maxAge))
+}
+
+// cookie method returns the session cookie with the value.
+func (m *sessions) cookie(value string, maxAge int) *http.Cookie {
+	return &http.Cookie{
+		Name:     m.opts.CookieName,
+		Value:    value,
+		Path:     m.opts.Path,
+		Domain:   m.opts.Domain,
+		MaxAge:   maxAge,
+		Secure:   m.opts.Secure,
+		HttpOnly: true,
+		SameSite: m.opts.SameSite,
+	}
+}
+
+// expires method returns the time the session expires at unless it is used
+// again, or zero time if it never expires.
+func (m *sessions) expires(rec *sessionRecord) (t time.Time) {
+	if m.opts.IdleTimeout > 0 {
+		t = rec.Accessed.Add(m.opts.IdleTimeout)
+	}
+	if m.opts.Lifetime > 0 {
+		end := rec.Created.Add(m.opts.Lifetime)
+		if t.IsZero() || end.Before(t) {
+			t = end
+		}
+	}
+	return t
+}
+
+// expired method tells whether the session has expired by now.
+func (m *sessions) expired(rec *sessionRecord, now time.Time) bool {
+	t := m.expires(rec)
+	return !t.IsZero() && !now.Before(t)
+}
+
+// logError method logs the error of saving the session.
+func (m *sessions) logError(r *http.Request, msg string, err error) {
+	if l := logger(r); l != nil {
+		l.ErrorContext(r.Context(), msg, "err", err)
+	}
+}
+
+// newSessionRecord returns a record of a new empty session.
+func newSessionRecord(now time.Time) sessionRecord {
+	return sessionRecord{
+		ID:       newSessionID(),
+		Created:  now,
+		Accessed: now,
+		Values:   make(map[string]interface{}),
+	}
+}
+
+// newSessionID returns a new random session ID.
+func newSessionID() string {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// encodeSession encodes the session record with gob.
+func encodeSession(rec *sessionRecord) ([]byte, error) {
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(rec)
+	return buf.Bytes(), err
+}
+
+// decodeSession decodes the session record encoded by encodeSession.
+func decodeSession(data []byte, rec *sessionRecord) error {
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(rec); err != nil {
+		return err
+	}
+	if rec.Values == nil {
+		rec.Values = make(map[string]interface{})
+	}
+	return nil
+}
+
+// sessionWriter is an http.ResponseWriter that saves the session before the
+// header is written.
+type sessionWriter struct {
+	http.ResponseWriter
+	save func()
+	done bool
+}
+
+// commit method saves the session unless it was done already.
+func (sw *sessionWriter) commit() {
+	if !sw.done {
+		sw.done = true
+		sw.save()
+	}
+}
+
+// WriteHeader method saves the session before the header is written.
+// Informational (1xx) codes don't complete the header, so they are passed
+// through as they are.
+func (sw *sessionWriter) WriteHeader(code int) {
+	if code >= 200 || code == http.StatusSwitchingProtocols {
+		sw.commit()
+	}
+	sw.ResponseWriter.WriteHeader(code)
+}
+
+// Write method saves the session before the first chunk of data.
+func (sw *sessionWriter) Write(b []byte) (int, error) {
+	sw.commit()
+	return sw.ResponseWriter.Write(b)
+}
+
+// Flush method ensures that sessionWriter implements the http.Flusher
+// interface.
+func (sw *sessionWriter) Flush() {
+	sw.commit()
+	if f, ok := sw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack method ensures that sessionWriter implements the http.Hijacker
+// interface.
+func (sw *sessionWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := sw.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("mux: response can't be hijacked")
+	}
+	sw.done = true
+	return h.Hijack()
+}
+
+// Unwrap method returns the underlying writer. It is used by
+// http.ResponseController.
+func (sw *sessionWriter) Unwrap() http.ResponseWriter {
+	return sw.ResponseWriter
+}
+
+// MemorySessionStore is SessionStore that keeps sessions in memory. Expired
+// sessions are dropped from time to time.
+type MemorySessionStore struct {
+	mu       sync.Mutex
+	sessions map[string]memorySession
+	swept    time.Time
+	now      func() time.Time
+}
+
+// memorySession is a session kept by MemorySessionStore.
+type memorySession struct {
+	data    []byte
+	expires time.Time
+}
+
+// NewMemorySessionStore returns pointer to an empty MemorySessionStore.
+func NewMemorySessionStore() *MemorySessionStore {
+	return &MemorySessionStore{
+		sessions: make(map[string]memorySession),
+		swept:    time.Now(),
+		now:      time.Now,
+	}
+}
+
+// Load method ensures that MemorySessionStore implements the SessionStore
+// interface.
+func (s *MemorySessionStore) Load(
+	ctx context.Context, id string,
+) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sess, ok := s.sessions[id]
+	if !ok || sess.expired(s.now()) {
+		return nil, nil
+	}
+	return sess.data, nil
+}
+
+// Save method ensures that MemorySessionStore implements the SessionStore
+// interface.
+func (s *MemorySessionStore) Save(
+	ctx context.Context, id string, data []byte, expires time.Time,
+) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sweep(s.now())
+	s.sessions[id] = memorySession{data, expires}
+	return nil
+}
+
+// Delete method ensures that MemorySessionStore implements the SessionStore
+// interface.
+func (s *MemorySessionStore) Delete(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sessions, id)
+	return nil
+}
+
+// Len method returns the number of sessions in the store, including the
+// expired ones that weren't dropped yet.
+func (s *MemorySessionStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.sessions)
+}
+
+// sweep method drops expired sessions, at most once a minute.
+func (s *MemorySessionStore) sweep(now time.Time) {
+	if now.Sub(s.swept) < time.Minute {
+		return
+	}
+	s.swept = now
+	for id, sess := range s.sessions {
+		if sess.expired(now) {
+			delete(s.sessions, id)
+		}
+	}
+}
+
+// expired method tells whether the session has expired by now.
+func (sess memorySession) expired(now time.Time) bool {
+	return !sess.expires.IsZero() && !now.Before(sess.expires)
+}
@@ -0,0 +1,160 @@
+package mux
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// sessionClient is a client that keeps the session cookie between requests.
+type sessionClient struct {
+	handler http.Handler
+	cookie  *http.Cookie
+}
+
+func (c *sessionClient) get(path string) *httptest.ResponseRecorder {
+	rec, req, _ := request(http.MethodGet, path, nil)
+	if c.cookie != nil {
+		req.AddCookie(c.cookie)
+	}
+	c.handler.ServeHTTP(rec, req)
+	for _, cookie := range rec.Result().Cookies() {
+		c.cookie = cookie
+		if cookie.MaxAge < 0 {
+			c.cookie = nil
+		}
+	}
+	return rec
+}
+
+func sessionRouter(m *sessions) *Router {
+	rtr := New().Wrap(m.wrap)
+	rtr.Get("/visit", func(w http.ResponseWriter, r *http.Request) {
+		s := Session(r)
+		n, _ := s.Get("visits").(int)
+		s.Set("visits", n+1)
+		w.Write([]byte(strconv.Itoa(n + 1)))
+	})
+	rtr.Get("/peek", func(w http.ResponseWriter, r *http.Request) {
+		n, _ := Session(r).Get("visits").(int)
+		w.Write([]byte(strconv.Itoa(n)))
+	})
+	rtr.Get("/login", func(w http.ResponseWriter, r *http.Request) {
+		Session(r).Renew()
+		w.Write([]byte(Session(r).ID()))
+	})
+	rtr.Get("/logout", func(w http.ResponseWriter, r *http.Request) {
+		Session(r).Destroy()
+	})
+	return rtr
+}
+
+func TestSessionsCookie(t *testing.T) {
+	m := newSessions(&SessionOptions{Secret: []byte("secret")})
+	c := &sessionClient{handler: sessionRouter(m)}
+
+	// Sessions without values don't get a cookie.
+	assert.Equal(t, "0", c.get("/peek").Body.String())
+	assert.Nil(t, c.cookie)
+
+	assert.Equal(t, "1", c.get("/visit").Body.String())
+	if assert.NotNil(t, c.cookie) {
+		assert.Equal(t, "session", c.cookie.Name)
+		assert.True(t, c.cookie.HttpOnly)
+		assert.Equal(t, http.SameSiteLaxMode, c.cookie.SameSite)
+		assert.Equal(t, int(DefaultSessionIdleTimeout.Seconds())+1,
+			c.cookie.MaxAge)
+	}
+	assert.Equal(t, "2", c.get("/visit").Body.String())
+	assert.Equal(t, "2", c.get("/peek").Body.String())
+
+	// Tampered cookies start a new session.
+	value := []byte(c.cookie.Value)
+	value[10] ^= 1
+	c.cookie.Value = string(value)
+	assert.Equal(t, "0", c.get("/peek").Body.String())
+	assert.Nil(t, c.cookie)
+
+	//-------------------- Another Test Case --------------------
+
+	// The cookie of another app can't be used.
+	c.get("/visit")
+	other := newSessions(&SessionOptions{Secret: []byte("other")})
+	c.handler = sessionRouter(other)
+	assert.Equal(t, "0", c.get("/peek").Body.String())
+
+	//-------------------- Another Test Case --------------------
+
+	// Sessions survive rotation of the secret.
+	c.get("/visit")
+	rotated := newSessions(&SessionOptions{
+		Cookies: SecureCookies([]byte("new"), []byte("other")).Encrypted(),
+	})
+	c.handler = sessionRouter(rotated)
+	assert.Equal(t, "2", c.get("/visit").Body.String())
+}
+
+func TestSessionsStore(t *testing.T) {
+	store := NewMemorySessionStore()
+	m := newSessions(&SessionOptions{
+		Secret:     []byte("secret"),
+		Store:      store,
+		CookieName: "sid",
+		Secure:     true,
+	})
+	c := &sessionClient{handler: sessionRouter(m)}
+
+	c.get("/visit")
+	c.get("/visit")
+	assert.Equal(t, 1, store.Len())
+	assert.True(t, c.cookie.Secure)
+
+	// Renewed sessions keep their values under a new ID.
+	id := c.get("/login").Body.String()
+	assert.Equal(t, "2", c.get("/peek").Body.String())
+	assert.Equal(t, 1, store.Len())
+	data, _ := store.Load(context.Background(), id)
+	assert.NotNil(t, data)
+
+	c.get("/logout")
+	assert.Nil(t, c.cookie)
+	assert.Equal(t, 0, store.Len())
+	assert.Equal(t, "0", c.get("/peek").Body.String())
+}
+
+func TestSessionsExpiry(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	m := newSessions(&SessionOptions{
+		Secret:      []byte("secret"),
+		IdleTimeout: time.Hour,
+		Lifetime:    3 * time.Hour,
+	})
+	m.now = func() time.Time { return now }
+	c := &sessionClient{handler: sessionRouter(m)}
+
+	c.get("/visit")
+	now = now.Add(59 * time.Minute)
+	assert.Equal(t, "2", c.get("/visit").Body.String())
+	now = now.Add(61 * time.Minute)
+	assert.Equal(t, "1", c.get("/visit").Body.String())
+
+	// Active sessions end when their lifetime is over.
+	for i := 0; i < 5; i++ {
+		now = now.Add(30 * time.Minute)
+		c.get("/visit")
+	}
+	assert.Equal(t, 1800, c.cookie.MaxAge-1)
+	now = now.Add(30 * time.Minute)
+	assert.Equal(t, "0", c.get("/peek").Body.String())
+}
+
+func TestSessionsNoSecret(t *testing.T) {
+	assert.PanicsWithValue(t, "can't use sessions without a secret", func() {
+		Sessions(nil)
+	})
+}
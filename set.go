@@ -1,5 +1,7 @@
 package mux
 
+import "sort"
+
 // set is a map-based data structure that allows us to add and check presence
 // of any string in constant time.
 type set map[string]bool
@@ -24,3 +26,13 @@ func (s set) Has(item string) bool {
 	_, ok := s[item]
 	return ok
 }
+
+// Items method returns all items stored in the set in ascending order.
+func (s set) Items() []string {
+	items := make([]string, 0, len(s))
+	for i := range s {
+		items = append(items, i)
+	}
+	sort.Strings(items)
+	return items
+}
@@ -12,3 +12,12 @@ func TestSet(t *testing.T) {
 		t.Errorf("set claims to have item that hasn't been added")
 	}
 }
+
+func TestSetItems(t *testing.T) {
+	s := newSet("POST", "GET", "DELETE", "GET")
+	items := s.Items()
+	if len(items) != 3 || items[0] != "DELETE" || items[1] != "GET" ||
+		items[2] != "POST" {
+		t.Errorf("got %v; expected [DELETE GET POST]", items)
+	}
+}
@@ -0,0 +1,156 @@
+package mux
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// ShadowOptions configures Shadow.
+type ShadowOptions struct {
+	// Percent is the share of requests that are mirrored, up to 100. Zero
+	// means 100; negative values disable mirroring.
+	Percent float64
+
+	// MaxBodyBytes is the maximum size of the request body that is mirrored.
+	// Requests with larger bodies aren't mirrored. Zero means 1MB.
+	MaxBodyBytes int64
+
+	// Timeout is how long the shadow handler may take. Zero means 30
+	// seconds.
+	Timeout time.Duration
+
+	// MaxInFlight is the maximum number of mirrored requests served at once.
+	// Requests above the limit aren't mirrored, so that a slow shadow
+	// doesn't pile up goroutines. Zero means 100.
+	MaxInFlight int
+}
+
+// withDefaults method returns a copy of the options with zero fields set to
+// their defaults.
+func (opts *ShadowOptions) withDefaults() *ShadowOptions {
+	o := ShadowOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	if o.Percent == 0 {
+		o.Percent = 100
+	}
+	if o.MaxBodyBytes == 0 {
+		o.MaxBodyBytes = 1 << 20
+	}
+	if o.Timeout == 0 {
+		o.Timeout = 30 * time.Second
+	}
+	if o.MaxInFlight == 0 {
+		o.MaxInFlight = 100
+	}
+	return &o
+}
+
+// Shadow returns Middleware that mirrors requests to the shadow handler once
+// they were served, e.g. to test a new implementation against production
+// traffic:
+//
+//	rtr.Shadow(newSearch, nil)
+//	rtr.Shadow(mux.NewProxy(&mux.ProxyOptions{
+//	    Backends: []mux.Backend{{URL: "http://search-v2:8080"}},
+//	}), &mux.ShadowOptions{Percent: 10})
+//
+// The copy of the request, with its body, is served in another goroutine
+// after the primary handler returned, and its response is discarded. The copy
+// isn't canceled along with the original request, but it's canceled after
+// the timeout. Panics of the shadow handler are recovered. If opts is nil,
+// defaults are used.
+func Shadow(h http.Handler, opts *ShadowOptions) Middleware {
+	opts = opts.withDefaults()
+	inFlight := make(chan struct{}, opts.MaxInFlight)
+
+	return func(next http.Handler) http.Handler {
+		return View(func(w http.ResponseWriter, r *http.Request) {
+			if opts.Percent < 100 && rand.Float64()*100 >= opts.Percent {
+				next.ServeHTTP(w, r)
+				return
+			}
+			body, ok := bufferBody(r, opts.MaxBodyBytes)
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+			// Copy the request before the handler gets a chance to
+			// change it.
+			shadow := r.Clone(context.WithValue(
+				context.WithoutCancel(r.Context()), routeKey, nil))
+			next.ServeHTTP(w, r)
+
+			select {
+			case inFlight <- struct{}{}:
+			default:
+				return
+			}
+			go func() {
+				defer func() {
+					recover()
+					<-inFlight
+				}()
+				ctx, cancel := context.WithTimeout(shadow.Context(),
+					opts.Timeout)
+				defer cancel()
+				shadow = shadow.WithContext(ctx)
+				if body != nil {
+					shadow.Body = io.NopCloser(bytes.NewReader(body))
+				}
+				h.ServeHTTP(discardWriter{make(http.Header)}, shadow)
+			}()
+		})
+	}
+}
+
+// Shadow method registers Shadow middleware on the Router. See Shadow.
+func (rtr *Router) Shadow(h http.Handler, opts *ShadowOptions) *Router {
+	return rtr.Wrap(Shadow(h, opts))
+}
+
+// bufferBody reads the request body, unless it's larger than limit, and
+// replaces it with a reader of what was read, so that the request may be
+// served again. It returns nil if the request has no body, and false if the
+// body is too large or can't be read; the body is still left readable as
+// long as possible.
+func bufferBody(r *http.Request, limit int64) ([]byte, bool) {
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil, true
+	}
+	if r.ContentLength > limit {
+		return nil, false
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, limit+1))
+	r.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(body), r.Body), r.Body}
+	if err != nil || int64(len(body)) > limit {
+		return nil, false
+	}
+	return body, true
+}
+
+// discardWriter is http.ResponseWriter that discards the response.
+type discardWriter struct {
+	header http.Header
+}
+
+// Header method ensures that discardWriter implements http.ResponseWriter.
+func (dw discardWriter) Header() http.Header {
+	return dw.header
+}
+
+// Write method discards the data.
+func (dw discardWriter) Write(p []byte) (int, error) {
+	return len(p), nil
+}
+
+// WriteHeader method discards the status code.
+func (dw discardWriter) WriteHeader(int) {}
@@ -0,0 +1,70 @@
+package mux
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShadow(t *testing.T) {
+	type mirrored struct {
+		method, path, body string
+		canceled           bool
+	}
+	shadowed := make(chan mirrored, 10)
+	var served atomic.Bool
+	shadow := View(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		shadowed <- mirrored{r.Method, r.URL.Path, string(body),
+			r.Context().Err() != nil}
+		assert.True(t, served.Load(), "shadow must run after primary")
+		w.WriteHeader(http.StatusTeapot)
+		panic("shadow must not break anything")
+	})
+
+	rtr := New().Shadow(shadow, &ShadowOptions{MaxBodyBytes: 8})
+	rtr.Post("/orders", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		r.URL.Path = "/changed"
+		Text(w, http.StatusCreated, string(body))
+		served.Store(true)
+	})
+
+	rec, req, _ := request(http.MethodPost, "/orders",
+		strings.NewReader("order"))
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, "order", rec.Body.String())
+	select {
+	case m := <-shadowed:
+		assert.Equal(t, mirrored{http.MethodPost, "/orders", "order", false},
+			m)
+	case <-time.After(time.Second):
+		t.Fatal("request was not mirrored")
+	}
+	//-------------------- Another Test Case --------------------
+	rec, req, _ = request(http.MethodPost, "/orders",
+		strings.NewReader("large order"))
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, "large order", rec.Body.String())
+	select {
+	case <-shadowed:
+		t.Fatal("large request must not be mirrored")
+	case <-time.After(50 * time.Millisecond):
+	}
+	//-------------------- Another Test Case --------------------
+	rtr = New().Shadow(shadow, &ShadowOptions{Percent: -1})
+	rtr.Get("/", func(w http.ResponseWriter, r *http.Request) {})
+	rec, req, _ = request(http.MethodGet, "/", nil)
+	rtr.ServeHTTP(rec, req)
+	select {
+	case <-shadowed:
+		t.Fatal("request must not be sampled")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
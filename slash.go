@@ -0,0 +1,112 @@
+package mux
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// TrailingSlash is a policy that tells Router how to treat requests whose path
+// differs from a route only by a trailing slash (e.g. "/users/" vs "/users").
+type TrailingSlash int
+
+const (
+	// InheritSlash is the zero value of TrailingSlash. Routers with this
+	// policy use the one set on the closest parent, or StrictSlash if none of
+	// the parents have it set.
+	InheritSlash TrailingSlash = iota
+
+	// StrictSlash policy treats "/users" and "/users/" as different paths.
+	StrictSlash
+
+	// IgnoreSlash policy serves the request with the route that matches the
+	// path with the trailing slash added or removed.
+	IgnoreSlash
+
+	// RedirectSlash policy redirects the client to the canonical form of the
+	// path, i.e. the one that has a matching route. GET and HEAD requests are
+	// redirected with "301 Moved Permanently"; other methods get "308
+	// Permanent Redirect" so that the method and body are preserved.
+	RedirectSlash
+)
+
+// TrailingSlash method sets trailing slash policy of the Router. The policy
+// applies to this Router and all of its sub-routers that don't have their own
+// policy set.
+func (rtr *Router) TrailingSlash(policy TrailingSlash) *Router {
+	rtr.slash = policy
+	return rtr
+}
+
+// slashPolicy method returns the trailing slash policy effective for request.
+func (rtr *Router) slashPolicy(r *http.Request) TrailingSlash {
+	if rtr.slash != InheritSlash {
+		return rtr.slash
+	}
+	if policy, ok := r.Context().Value(slashKey).(TrailingSlash); ok {
+		return policy
+	}
+	return StrictSlash
+}
+
+// matchSlash method checks whether the request matches one of the routes when
+// its trailing slash is added or removed. It returns the matched sub-router
+// and a copy of the request with the altered path.
+func (rtr *Router) matchSlash(r *http.Request) (*Router, *http.Request, bool) {
+	if rtr.slashPolicy(r) == StrictSlash || r.URL.Path == "/" ||
+		r.URL.Path == "" {
+		return nil, nil, false
+	}
+	alt := r.Clone(r.Context())
+	alt.URL.Path = toggleSlash(r.URL.Path)
+	alt.URL.RawPath = ""
+	if sub, match := rtr.Match(alt); match {
+		return sub, alt, true
+	}
+	return nil, nil, false
+}
+
+// serveSlash method handles the request that matched a sub-router only after
+// its trailing slash was toggled, according to the trailing slash policy.
+func (rtr *Router) serveSlash(
+	w http.ResponseWriter, r *http.Request, sub *Router, alt *http.Request,
+) {
+	if rtr.slashPolicy(r) != RedirectSlash {
+		sub.ServeHTTP(w, alt)
+		return
+	}
+
+	code := http.StatusPermanentRedirect
+	if r.Method == http.MethodGet || r.Method == http.MethodHead {
+		code = http.StatusMovedPermanently
+	}
+	path := toggleSlash(originalPath(r))
+	http.Redirect(w, r, requestURI(r, path), code)
+}
+
+// toggleSlash removes trailing slash from path if it's there or adds it if it
+// isn't.
+func toggleSlash(path string) string {
+	if strings.HasSuffix(path, "/") {
+		return strings.TrimSuffix(path, "/")
+	}
+	return path + "/"
+}
+
+// originalPath returns request path as it was before any of the routers cut
+// their prefixes from it.
+func originalPath(r *http.Request) string {
+	if path, ok := r.Context().Value(pathKey).(string); ok {
+		return path
+	}
+	return r.URL.Path
+}
+
+// withOriginalPath returns a copy of request that remembers its current path
+// as the original one, unless it already remembers one.
+func withOriginalPath(r *http.Request) *http.Request {
+	if _, ok := r.Context().Value(pathKey).(string); ok {
+		return r
+	}
+	return r.WithContext(context.WithValue(r.Context(), pathKey, r.URL.Path))
+}
@@ -0,0 +1,52 @@
+package mux
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrailingSlash(t *testing.T) {
+	rtr := New()
+	api := rtr.Subrouter().PathPrefix("/api")
+	api.Get("/users", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "users")
+	})
+	api.Post("/songs/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "songs")
+	})
+
+	// StrictSlash is the default.
+	rec, req, err := request(http.MethodGet, "/api/users/", nil)
+	assert.NoError(t, err)
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	//-------------------- Another Test Case --------------------
+	rtr.TrailingSlash(IgnoreSlash)
+	rec, req, err = request(http.MethodGet, "/api/users/", nil)
+	assert.NoError(t, err)
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, "users", rec.Body.String())
+	//-------------------- Another Test Case --------------------
+	rtr.TrailingSlash(RedirectSlash)
+	rec, req, err = request(http.MethodGet, "/api/users/?page=2", nil)
+	assert.NoError(t, err)
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusMovedPermanently, rec.Code)
+	assert.Equal(t, "/api/users?page=2", rec.Header().Get("Location"))
+
+	rec, req, err = request(http.MethodPost, "/api/songs", nil)
+	assert.NoError(t, err)
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusPermanentRedirect, rec.Code)
+	assert.Equal(t, "/api/songs/", rec.Header().Get("Location"))
+	//-------------------- Another Test Case --------------------
+	// Sub-router's own policy takes precedence.
+	api.TrailingSlash(StrictSlash)
+	rec, req, err = request(http.MethodGet, "/api/users/", nil)
+	assert.NoError(t, err)
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
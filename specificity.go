@@ -0,0 +1,100 @@
+package mux
+
+import "strings"
+
+// Ranks of path segments used to order routes by specificity.
+const (
+	rankRest    = iota + 1 // catch-all variable or the rest of a prefix
+	rankRegex              // variable with regular expression type
+	rankTyped              // variable with named or enum type
+	rankLiteral            // static segment
+)
+
+// SortBySpecificity method enables automatic ordering of the routes of this
+// Router by specificity, so that the most specific route wins regardless of
+// the order of registration:
+//
+//	rtr.SortBySpecificity(true)
+//	rtr.Get("/users/{name:str}", showUser)
+//	rtr.Get("/users/new", newUserForm) // Wins for "/users/new".
+//
+// Paths are compared segment by segment, left to right: static segments beat
+// typed variables ("{id:int}", "{kind:enum(a,b)}"), which beat regular
+// expressions ("{id:[0-9a-f]+}"), which beat catch-all variables and path
+// prefixes. If one path template is a prefix of the other, the shorter one
+// wins. Routes without path filters (including groups) are treated as
+// catch-alls. Explicit priorities (see Priority) take precedence over
+// specificity, and equally specific routes keep the order of registration.
+//
+// Sub-routers created afterwards inherit the setting. It returns pointer to
+// the same Router instance.
+func (rtr *Router) SortBySpecificity(enabled bool) *Router {
+	rtr.specific = enabled
+	rtr.index.Store((*routeIndex)(nil))
+	return rtr
+}
+
+// specificity method returns ranks of the segments of the path accepted by the
+// router.
+func (rtr *Router) specificity() []int {
+	fil := rtr.filters.Path
+	if fil == nil {
+		if rtr.filters.PathPrefix == nil {
+			return []int{rankRest}
+		}
+		return append(literalRanks(string(*rtr.filters.PathPrefix)), rankRest)
+	}
+
+	var ranks []int
+	for _, seg := range splitPath(fil.Path) {
+		if !isVar(seg) {
+			ranks = append(ranks, rankLiteral)
+			continue
+		}
+		_, typ := varData(seg)
+		if typ == "*" {
+			ranks = append(ranks, rankRest)
+		} else if _, ok := lookupVarType(typ); ok {
+			ranks = append(ranks, rankTyped)
+		} else if _, ok := enumValues(typ); ok {
+			ranks = append(ranks, rankTyped)
+		} else {
+			ranks = append(ranks, rankRegex)
+		}
+	}
+	if !fil.Strict {
+		ranks = append(ranks, rankRest)
+	}
+	return ranks
+}
+
+// moreSpecific tells whether path with segment ranks a is more specific than
+// path with segment ranks b.
+func moreSpecific(a []int, b []int) bool {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			return a[i] > b[i]
+		}
+	}
+	return len(a) < len(b)
+}
+
+// literalRanks returns ranks of the segments of a static path.
+func literalRanks(path string) []int {
+	ranks := make([]int, len(splitPath(path)))
+	for i := range ranks {
+		ranks[i] = rankLiteral
+	}
+	return ranks
+}
+
+// splitPath splits path into segments dropping the leading slash.
+func splitPath(path string) []string {
+	if path == "" || path == "/" {
+		return nil
+	}
+	if path[0] == '/' {
+		path = path[1:]
+	}
+	return strings.Split(path, "/")
+}
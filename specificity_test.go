@@ -0,0 +1,60 @@
+package mux
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSortBySpecificity(t *testing.T) {
+	text := func(s string) View {
+		return func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(s))
+		}
+	}
+
+	rtr := New().SortBySpecificity(true)
+	rtr.Subrouter().HandleFunc(text("fallback"))
+	rtr.Get("/users/{rest:*}", text("rest"))
+	rtr.Subrouter().PathPrefix("/users").HandleFunc(text("prefix"))
+	rtr.Get("/users/{hex:[0-9a-f]+}", text("regex"))
+	rtr.Get("/users/{id:int}", text("int"))
+	rtr.Get("/users/{name:str}", text("str"))
+	rtr.Get("/users/new", text("new"))
+	rtr.Get("/users/{name:str}/posts", text("posts"))
+	rtr.Get("/users/me", text("me")).Priority(-1)
+
+	cases := map[string]string{
+		"/users/new":        "new",
+		"/users/me":         "str",
+		"/users/john":       "str",
+		"/users/42":         "int",
+		"/users/beef42":     "regex",
+		"/users/john/posts": "posts",
+		"/users/john/likes": "rest",
+		"/users":            "prefix",
+		"/about":            "fallback",
+	}
+	for path, body := range cases {
+		rec, req, err := request(http.MethodGet, path, nil)
+		assert.NoError(t, err)
+		rtr.ServeHTTP(rec, req)
+		assert.Equal(t, body, rec.Body.String(), path)
+	}
+
+	// Sub-routers inherit the setting.
+	api := rtr.Subrouter().PathPrefix("/api")
+	assert.True(t, api.specific)
+}
+
+func TestMoreSpecific(t *testing.T) {
+	users := []int{rankLiteral}
+	user := []int{rankLiteral, rankTyped}
+	rest := []int{rankLiteral, rankRest}
+
+	assert.True(t, moreSpecific(users, user))
+	assert.True(t, moreSpecific(user, rest))
+	assert.False(t, moreSpecific(rest, user))
+	assert.False(t, moreSpecific(user, user))
+}
@@ -0,0 +1,321 @@
+package mux
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// StaticOptions configures FileServer.
+type StaticOptions struct {
+	// Index is a list of file names served for directory requests, in order
+	// of preference. If nil, it defaults to "index.html"; use an empty
+	// non-nil slice to disable index files. Directories without index files
+	// are never listed.
+	Index []string
+
+	// MaxAge is the max-age of the Cache-Control header. Zero means
+	// "no-cache", which makes browsers revalidate files upon every use.
+	MaxAge time.Duration
+
+	// Immutable adds "immutable" directive to the Cache-Control header. Use
+	// it for fingerprinted assets (e.g. "app.3f2a1c.js") with large MaxAge.
+	Immutable bool
+
+	// StrongETags makes FileServer compute ETags from file contents instead
+	// of modification times. Strong ETags survive deployments that touch
+	// unchanged files, at the cost of reading each file once per change.
+	StrongETags bool
+
+	// Fallback is the name of a file served instead of "404 Not Found" to
+	// browser navigations (see Router.SPA). Empty means no fallback.
+	Fallback string
+
+	// AllowDotFiles allows serving files and directories whose names start
+	// with a dot (e.g. ".env" or ".git"). They are hidden by default.
+	AllowDotFiles bool
+}
+
+// FileServer is an http.Handler that serves files from a file system with
+// hardened defaults: path traversal attempts and dot files are rejected,
+// directories are never listed, and cache headers are set. Every file is
+// served with ETag and, if known, Last-Modified headers, so conditional
+// requests with If-None-Match and If-Modified-Since get "304 Not Modified".
+// Range requests are served with "206 Partial Content".
+//
+// FileServer is meant to be used as the handler of a Router with PathPrefix
+// filter (see Router.Static), which cuts the prefix from the request path
+// before it reaches the handler.
+type FileServer struct {
+	fsys  http.FileSystem
+	opts  StaticOptions
+	etags sync.Map // etagKey -> strong ETag
+}
+
+// NewFileServer returns pointer to a FileServer that serves files from fsys.
+// If opts is nil, defaults are used.
+func NewFileServer(fsys http.FileSystem, opts *StaticOptions) *FileServer {
+	s := &FileServer{fsys: fsys}
+	if opts != nil {
+		s.opts = *opts
+	}
+	if s.opts.Index == nil {
+		s.opts.Index = []string{"index.html"}
+	}
+	return s
+}
+
+// Static method creates a sub-router that serves files from the directory dir
+// under the path prefix with default StaticOptions:
+//
+//	rtr.Static("/assets", "./public")
+//
+// Only GET and HEAD requests are accepted.
+func (rtr *Router) Static(prefix string, dir string) *Router {
+	return rtr.Files(prefix, http.Dir(dir), nil)
+}
+
+// Files method creates a sub-router that serves files from fsys under the path
+// prefix using a FileServer with given options. Only GET and HEAD requests are
+// accepted.
+func (rtr *Router) Files(
+	prefix string, fsys http.FileSystem, opts *StaticOptions,
+) *Router {
+	return rtr.attach(rtr.detached().
+		Methods(http.MethodGet, http.MethodHead).
+		PathPrefix(strings.TrimSuffix(prefix, "/")).
+		Handler(NewFileServer(fsys, opts)))
+}
+
+// StaticFS method creates a sub-router that serves files from fsys under the
+// path prefix. If root is not empty, fsys is rooted at that directory, which is
+// handy for embedded file systems:
+//
+//	//go:embed public
+//	var public embed.FS
+//
+//	rtr.StaticFS("/assets", public, "public", nil)
+//
+// Embedded files have no modification time, so they are served with ETag
+// computed from their content instead of Last-Modified. It panics if root is
+// not a valid path.
+func (rtr *Router) StaticFS(
+	prefix string, fsys fs.FS, root string, opts *StaticOptions,
+) *Router {
+	if root != "" && root != "." {
+		sub, err := fs.Sub(fsys, root)
+		if err != nil {
+			panic(fmt.Sprintf("can't root file system at %s: %v", root, err))
+		}
+		fsys = sub
+	}
+	return rtr.Files(prefix, http.FS(fsys), opts)
+}
+
+// SPA method creates a sub-router that serves a single-page application from
+// fsys under the path prefix. Requests for files that don't exist fall back to
+// the index file, so that client-side routing works for deep links:
+//
+//	rtr.Get("/api/users", users)
+//	rtr.SPA("/", dist, "index.html")
+//
+// Only browser navigations fall back: the request must accept "text/html"
+// explicitly and its last path segment must have no extension. Thus, missing
+// assets and API calls still receive "404 Not Found". Routers are matched in
+// order of registration, so register API routes before the SPA.
+func (rtr *Router) SPA(prefix string, fsys fs.FS, index string) *Router {
+	return rtr.Files(prefix, http.FS(fsys), &StaticOptions{Fallback: index})
+}
+
+// ServeHTTP method ensures that FileServer implements the http.Handler
+// interface.
+func (s *FileServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var (
+		f    http.File
+		info os.FileInfo
+		err  error = os.ErrNotExist
+	)
+	name, ok := s.resolve(r.URL.Path)
+	if ok {
+		f, info, err = s.open(name)
+	}
+
+	if err == nil && info.IsDir() {
+		f.Close()
+		// Redirect to the path with trailing slash, so that relative links
+		// in index files work as expected.
+		if !strings.HasSuffix(r.URL.Path, "/") {
+			localRedirect(w, r, path.Base(r.URL.Path)+"/")
+			return
+		}
+		name, f, info, err = s.index(name)
+	}
+
+	cache := true
+	if err != nil && s.fallback(r) {
+		name = path.Clean("/" + s.opts.Fallback)
+		if f, info, err = s.open(name); err == nil && info.IsDir() {
+			f.Close()
+			err = os.ErrNotExist
+		}
+		// The fallback is served for many URLs and must stay fresh.
+		cache = false
+	}
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	defer f.Close()
+
+	if cache {
+		s.setCacheHeaders(w)
+	} else {
+		w.Header().Set("Cache-Control", "no-cache")
+	}
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	etag, err := s.etag(name, f, info)
+	if err != nil {
+		Error(w, r, err)
+		return
+	}
+	w.Header().Set("ETag", etag)
+	http.ServeContent(w, r, info.Name(), info.ModTime(), f)
+}
+
+// fallback method tells whether the fallback file should be served instead of
+// "404 Not Found" for the request.
+func (s *FileServer) fallback(r *http.Request) bool {
+	if s.opts.Fallback == "" || path.Ext(r.URL.Path) != "" {
+		return false
+	}
+	accept := strings.Join(r.Header.Values("Accept"), ",")
+	for _, rng := range parseAccept(accept) {
+		if rng.typ == "text" && rng.sub == "html" && rng.q > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// resolve method converts the request path into a file name. It returns false
+// if the path is not allowed to be served.
+func (s *FileServer) resolve(p string) (string, bool) {
+	if p == "" {
+		p = "/"
+	}
+	// Prefix filter matches "/assetsfoo" for "/assets" prefix.
+	if !strings.HasPrefix(p, "/") {
+		return "", false
+	}
+	for _, seg := range strings.Split(p, "/") {
+		if seg == ".." || strings.Contains(seg, "\\") ||
+			strings.ContainsRune(seg, 0) {
+			return "", false
+		}
+		if !s.opts.AllowDotFiles && strings.HasPrefix(seg, ".") {
+			return "", false
+		}
+	}
+	return path.Clean(p), true
+}
+
+// open method opens the named file and returns its info.
+func (s *FileServer) open(name string) (http.File, os.FileInfo, error) {
+	f, err := s.fsys.Open(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, nil, err
+	}
+	return f, info, nil
+}
+
+// index method opens the first index file that exists in the directory and
+// returns its name.
+func (s *FileServer) index(
+	dir string,
+) (string, http.File, os.FileInfo, error) {
+	for _, index := range s.opts.Index {
+		name := path.Join(dir, index)
+		f, info, err := s.open(name)
+		if err != nil {
+			continue
+		}
+		if info.IsDir() {
+			f.Close()
+			continue
+		}
+		return name, f, info, nil
+	}
+	return "", nil, nil, os.ErrNotExist
+}
+
+// setCacheHeaders method sets Cache-Control header according to the options.
+func (s *FileServer) setCacheHeaders(w http.ResponseWriter) {
+	cc := "no-cache"
+	if s.opts.MaxAge > 0 {
+		cc = "public, max-age=" + strconv.Itoa(int(s.opts.MaxAge.Seconds()))
+		if s.opts.Immutable {
+			cc += ", immutable"
+		}
+	}
+	w.Header().Set("Cache-Control", cc)
+}
+
+// etagKey identifies a version of a file in the ETag cache.
+type etagKey struct {
+	name    string
+	modtime int64
+	size    int64
+}
+
+// etag method returns ETag of the file. Files with modification time get weak
+// ETag derived from it and the size of the file, unless StrongETags option is
+// set. Others get strong ETag computed from their content, which is cached
+// until the file changes.
+func (s *FileServer) etag(
+	name string, f http.File, info os.FileInfo,
+) (string, error) {
+	modtime := info.ModTime()
+	if !modtime.IsZero() && !s.opts.StrongETags {
+		return fmt.Sprintf(`W/"%x-%x"`, modtime.UnixNano(), info.Size()), nil
+	}
+
+	key := etagKey{name, modtime.UnixNano(), info.Size()}
+	if etag, ok := s.etags.Load(key); ok {
+		return etag.(string), nil
+	}
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	etag := `"` + hex.EncodeToString(h.Sum(nil)[:16]) + `"`
+	s.etags.Store(key, etag)
+	return etag, nil
+}
+
+// localRedirect redirects the client to the path relative to the current
+// directory, keeping the query, the way net/http does for directories. The
+// Location is never absolute, so that paths like "//evil.com" can't send
+// clients to other hosts.
+func localRedirect(w http.ResponseWriter, r *http.Request, name string) {
+	u := &url.URL{Path: name, RawQuery: r.URL.RawQuery}
+	w.Header().Set("Location", u.String())
+	w.WriteHeader(http.StatusMovedPermanently)
+}
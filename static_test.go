@@ -0,0 +1,292 @@
+package mux
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// staticDir creates a temporary directory with given files and returns its
+// path.
+func staticDir(t *testing.T, files map[string]string) string {
+	dir, err := ioutil.TempDir("", "mux-static")
+	assert.NoError(t, err)
+	for name, content := range files {
+		file := filepath.Join(dir, filepath.FromSlash(name))
+		assert.NoError(t, os.MkdirAll(filepath.Dir(file), 0755))
+		assert.NoError(t, ioutil.WriteFile(file, []byte(content), 0644))
+	}
+	return dir
+}
+
+func TestStatic(t *testing.T) {
+	dir := staticDir(t, map[string]string{
+		"app.js":          "console.log(42)",
+		"docs/index.html": "<h1>Docs</h1>",
+		"empty/file.txt":  "",
+		".env":            "SECRET=1",
+	})
+	defer os.RemoveAll(dir)
+
+	rtr := New()
+	rtr.Static("/assets", dir)
+
+	cases := []struct {
+		path string
+		code int
+		body string
+	}{
+		{"/assets/app.js", http.StatusOK, "console.log(42)"},
+		{"/assets/docs/", http.StatusOK, "<h1>Docs</h1>"},
+		{"/assets/docs", http.StatusMovedPermanently, ""},
+		{"/assets/empty/", http.StatusNotFound, ""},
+		{"/assets/.env", http.StatusNotFound, ""},
+		{"/assets/../static_test.go", http.StatusNotFound, ""},
+		{"/assetsapp.js", http.StatusNotFound, ""},
+		{"/assets/missing.js", http.StatusNotFound, ""},
+	}
+	for _, c := range cases {
+		rec, req, err := request(http.MethodGet, "/", nil)
+		assert.NoError(t, err)
+		req.URL.Path = c.path
+		rtr.ServeHTTP(rec, req)
+		assert.Equal(t, c.code, rec.Code, c.path)
+		if c.body != "" {
+			assert.Equal(t, c.body, rec.Body.String(), c.path)
+		}
+	}
+
+	rec, req, err := request(http.MethodGet, "/assets/docs", nil)
+	assert.NoError(t, err)
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, "docs/", rec.Header().Get("Location"))
+
+	rec, req, err = request(http.MethodPost, "/assets/app.js", nil)
+	assert.NoError(t, err)
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
+
+func TestStaticCacheHeaders(t *testing.T) {
+	dir := staticDir(t, map[string]string{"app.js": "42"})
+	defer os.RemoveAll(dir)
+
+	rtr := New()
+	rtr.Static("/a", dir)
+	rtr.Files("/b", http.Dir(dir), &StaticOptions{
+		MaxAge:    365 * 24 * time.Hour,
+		Immutable: true,
+	})
+
+	rec, req, err := request(http.MethodGet, "/a/app.js", nil)
+	assert.NoError(t, err)
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, "no-cache", rec.Header().Get("Cache-Control"))
+	assert.Equal(t, "nosniff", rec.Header().Get("X-Content-Type-Options"))
+
+	rec, req, err = request(http.MethodGet, "/b/app.js", nil)
+	assert.NoError(t, err)
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, "public, max-age=31536000, immutable",
+		rec.Header().Get("Cache-Control"))
+}
+
+func TestStaticFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"public/app.js":     {Data: []byte("console.log(42)")},
+		"public/index.html": {Data: []byte("<h1>Home</h1>")},
+		"secret.txt":        {Data: []byte("secret")},
+	}
+
+	rtr := New()
+	rtr.StaticFS("/assets", fsys, "public", nil)
+
+	rec, req, err := request(http.MethodGet, "/assets/app.js", nil)
+	assert.NoError(t, err)
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "console.log(42)", rec.Body.String())
+	assert.Empty(t, rec.Header().Get("Last-Modified"))
+	etag := rec.Header().Get("ETag")
+	assert.Regexp(t, `^"[0-9a-f]{32}"$`, etag)
+
+	rec, req, err = request(http.MethodGet, "/assets/app.js", nil)
+	assert.NoError(t, err)
+	req.Header.Set("If-None-Match", etag)
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+
+	rec, req, err = request(http.MethodGet, "/assets/", nil)
+	assert.NoError(t, err)
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, "<h1>Home</h1>", rec.Body.String())
+	assert.NotEqual(t, etag, rec.Header().Get("ETag"))
+
+	rec, req, err = request(http.MethodGet, "/assets/secret.txt", nil)
+	assert.NoError(t, err)
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	assert.Panics(t, func() {
+		New().StaticFS("/assets", fsys, "../public", nil)
+	})
+}
+
+func TestSPA(t *testing.T) {
+	dist := fstest.MapFS{
+		"index.html":            {Data: []byte("<div id=app></div>")},
+		"assets/app.js":         {Data: []byte("mount()")},
+		"evil.com/index.html":   {Data: []byte("evil")},
+		"docs/guide/index.html": {Data: []byte("guide")},
+	}
+
+	rtr := New()
+	rtr.Get("/api/users", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("users"))
+	})
+	rtr.SPA("/", dist, "index.html")
+
+	const html = "text/html,application/xhtml+xml,*/*;q=0.8"
+	cases := []struct {
+		path   string
+		accept string
+		code   int
+		body   string
+	}{
+		{"/", html, http.StatusOK, "<div id=app></div>"},
+		{"/users/42/edit", html, http.StatusOK, "<div id=app></div>"},
+		{"/assets/app.js", "*/*", http.StatusOK, "mount()"},
+		{"/assets/missing.js", html, http.StatusNotFound, ""},
+		{"/api/users", "*/*", http.StatusOK, "users"},
+		{"/api/missing", "*/*", http.StatusNotFound, ""},
+		{"/api/missing", "application/json", http.StatusNotFound, ""},
+	}
+	for _, c := range cases {
+		rec, req, err := request(http.MethodGet, c.path, nil)
+		assert.NoError(t, err)
+		req.Header.Set("Accept", c.accept)
+		rtr.ServeHTTP(rec, req)
+		assert.Equal(t, c.code, rec.Code, c.path)
+		if c.body != "" {
+			assert.Equal(t, c.body, rec.Body.String(), c.path)
+		}
+	}
+
+	rec, req, err := request(http.MethodGet, "/users/42", nil)
+	assert.NoError(t, err)
+	req.Header.Set("Accept", html)
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, "no-cache", rec.Header().Get("Cache-Control"))
+	assert.Equal(t, "text/html; charset=utf-8", rec.Header().Get("Content-Type"))
+	//-------------------- Another Test Case --------------------
+	// The path of "GET //evil.com" must not become a protocol-relative URL.
+	for _, c := range []struct{ path, query, location string }{
+		{"//evil.com", "", "evil.com/"},
+		{"/docs/guide", "page=2", "guide/?page=2"},
+	} {
+		rec, req, err := request(http.MethodGet, "/", nil)
+		assert.NoError(t, err)
+		req.URL.Path, req.URL.RawQuery = c.path, c.query
+		rtr.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusMovedPermanently, rec.Code, c.path)
+		assert.Equal(t, c.location, rec.Header().Get("Location"), c.path)
+	}
+}
+
+func TestStaticConditional(t *testing.T) {
+	dir := staticDir(t, map[string]string{"app.js": "console.log(42)"})
+	defer os.RemoveAll(dir)
+
+	rtr := New()
+	rtr.Static("/weak", dir)
+	rtr.Files("/strong", http.Dir(dir), &StaticOptions{StrongETags: true})
+
+	rec, req, err := request(http.MethodGet, "/weak/app.js", nil)
+	assert.NoError(t, err)
+	rtr.ServeHTTP(rec, req)
+	weak := rec.Header().Get("ETag")
+	modified := rec.Header().Get("Last-Modified")
+	assert.Regexp(t, `^W/"[0-9a-f]+-f"$`, weak)
+	assert.NotEmpty(t, modified)
+
+	rec, req, err = request(http.MethodGet, "/strong/app.js", nil)
+	assert.NoError(t, err)
+	rtr.ServeHTTP(rec, req)
+	strong := rec.Header().Get("ETag")
+	assert.Regexp(t, `^"[0-9a-f]{32}"$`, strong)
+
+	cases := []struct {
+		path   string
+		header string
+		value  string
+		code   int
+	}{
+		{"/weak/app.js", "If-None-Match", weak, http.StatusNotModified},
+		{"/weak/app.js", "If-None-Match", `"other"`, http.StatusOK},
+		{"/weak/app.js", "If-Modified-Since", modified, http.StatusNotModified},
+		{"/strong/app.js", "If-None-Match", strong, http.StatusNotModified},
+		{"/strong/app.js", "If-None-Match", "W/" + strong, http.StatusNotModified},
+		{"/strong/app.js", "If-None-Match", "*", http.StatusNotModified},
+	}
+	for _, c := range cases {
+		rec, req, err := request(http.MethodGet, c.path, nil)
+		assert.NoError(t, err)
+		req.Header.Set(c.header, c.value)
+		rtr.ServeHTTP(rec, req)
+		assert.Equal(t, c.code, rec.Code, c.header+": "+c.value)
+	}
+
+	// ETags change with the file.
+	later := time.Now().Add(time.Hour)
+	file := filepath.Join(dir, "app.js")
+	assert.NoError(t, ioutil.WriteFile(file, []byte("console.log(43)"), 0644))
+	assert.NoError(t, os.Chtimes(file, later, later))
+	for _, c := range []struct{ path, etag string }{
+		{"/weak/app.js", weak},
+		{"/strong/app.js", strong},
+	} {
+		rec, req, err := request(http.MethodGet, c.path, nil)
+		assert.NoError(t, err)
+		req.Header.Set("If-None-Match", c.etag)
+		rtr.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code, c.path)
+		assert.Equal(t, "console.log(43)", rec.Body.String())
+	}
+}
+
+func TestStaticRange(t *testing.T) {
+	dir := staticDir(t, map[string]string{"audio.ogg": "0123456789"})
+	defer os.RemoveAll(dir)
+
+	rtr := New()
+	rtr.Static("/media", dir)
+
+	rec, req, err := request(http.MethodGet, "/media/audio.ogg", nil)
+	assert.NoError(t, err)
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, "bytes", rec.Header().Get("Accept-Ranges"))
+	etag := rec.Header().Get("ETag")
+
+	rec, req, err = request(http.MethodGet, "/media/audio.ogg", nil)
+	assert.NoError(t, err)
+	req.Header.Set("Range", "bytes=2-5")
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusPartialContent, rec.Code)
+	assert.Equal(t, "bytes 2-5/10", rec.Header().Get("Content-Range"))
+	assert.Equal(t, "2345", rec.Body.String())
+
+	// Weak ETags can't be used with If-Range, so the whole file is sent.
+	rec, req, err = request(http.MethodGet, "/media/audio.ogg", nil)
+	assert.NoError(t, err)
+	req.Header.Set("Range", "bytes=2-5")
+	req.Header.Set("If-Range", etag)
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "0123456789", rec.Body.String())
+}
@@ -0,0 +1,228 @@
+package mux
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// statsBuckets is the number of buckets of the latency histograms. Bucket i
+// counts requests served within statsBase << i; the last one counts the rest.
+const statsBuckets = 24
+
+// statsBase is the upper bound of the first bucket of the latency histograms.
+const statsBase = 50 * time.Microsecond
+
+// RouteStats is a snapshot of the statistics of a route collected since the
+// server started (see CollectStats). Latency percentiles are estimated with
+// a histogram whose buckets grow twofold, so they are accurate within a
+// factor of two.
+type RouteStats struct {
+	// Route describes the route the statistics belong to.
+	Route *RouteInfo
+
+	// Requests is the number of requests served by the route.
+	Requests uint64
+
+	// ClientErrors and ServerErrors are the numbers of requests that got
+	// 4xx and 5xx status codes respectively.
+	ClientErrors uint64
+	ServerErrors uint64
+
+	// Mean and Max are the mean and maximum latency of the route.
+	Mean time.Duration
+	Max  time.Duration
+
+	// P50, P90 and P99 are the percentiles of the latency of the route.
+	P50 time.Duration
+	P90 time.Duration
+	P99 time.Duration
+}
+
+// routeStats collects the statistics of a route. It is updated with atomic
+// operations, so that concurrent requests don't wait for each other.
+type routeStats struct {
+	requests     atomic.Uint64
+	clientErrors atomic.Uint64
+	serverErrors atomic.Uint64
+	total        atomic.Int64
+	max          atomic.Int64
+	buckets      [statsBuckets]atomic.Uint64
+}
+
+// CollectStats method makes the Router collect statistics of the routes in
+// its subtree: request counts, error counts and latency of their handlers.
+// They are reported by Stats and StatsHandler:
+//
+//	rtr := mux.New().CollectStats()
+//	rtr.Get("/debug/stats", rtr.StatsHandler().ServeHTTP)
+//
+// Latency is measured from the moment the handler of the route is called, so
+// it doesn't include middleware and routing.
+func (rtr *Router) CollectStats() *Router {
+	rtr.collectStats = true
+	return rtr
+}
+
+// Stats method returns the statistics of the routes in the subtree of the
+// Router that served requests, in the order in which they are matched.
+func (rtr *Router) Stats() []RouteStats {
+	var stats []RouteStats
+	rtr.Walk(func(route *RouteInfo) error {
+		if s := route.Router.stats.Load(); s != nil {
+			stats = append(stats, s.snapshot(route))
+		}
+		return nil
+	})
+	return stats
+}
+
+// statsJSON is a JSON-friendly form of RouteStats used by the StatsHandler.
+type statsJSON struct {
+	Name         string   `json:"name,omitempty"`
+	Methods      []string `json:"methods,omitempty"`
+	Template     string   `json:"template"`
+	Requests     uint64   `json:"requests"`
+	ClientErrors uint64   `json:"clientErrors"`
+	ServerErrors uint64   `json:"serverErrors"`
+	Mean         string   `json:"mean"`
+	P50          string   `json:"p50"`
+	P90          string   `json:"p90"`
+	P99          string   `json:"p99"`
+	Max          string   `json:"max"`
+}
+
+// StatsHandler method returns an http.Handler that responds with the
+// statistics of the routes in the subtree of this Router encoded as JSON (see
+// Stats). Like the DebugHandler, it reveals the routes of the app, so mount
+// it where only operators can reach it.
+func (rtr *Router) StatsHandler() http.Handler {
+	return View(func(w http.ResponseWriter, r *http.Request) {
+		stats := make([]statsJSON, 0)
+		for _, s := range rtr.Stats() {
+			stats = append(stats, statsJSON{
+				Name:         s.Route.Name,
+				Methods:      s.Route.Methods,
+				Template:     s.Route.Template(),
+				Requests:     s.Requests,
+				ClientErrors: s.ClientErrors,
+				ServerErrors: s.ServerErrors,
+				Mean:         s.Mean.String(),
+				P50:          s.P50.String(),
+				P90:          s.P90.String(),
+				P99:          s.P99.String(),
+				Max:          s.Max.String(),
+			})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		enc.Encode(stats)
+	})
+}
+
+// withStats returns a copy of request that tells the routes to collect
+// statistics in case this Router does that.
+func (rtr *Router) withStats(r *http.Request) *http.Request {
+	if !rtr.collectStats {
+		return r
+	}
+	return r.WithContext(context.WithValue(r.Context(), statsKey, true))
+}
+
+// serveHandler method calls the handler of the Router and updates its
+// statistics if they are collected.
+func (rtr *Router) serveHandler(w http.ResponseWriter, r *http.Request) {
+	if collect, _ := r.Context().Value(statsKey).(bool); !collect {
+		rtr.handler.ServeHTTP(w, r)
+		return
+	}
+	rw := NewResponseWriter(w)
+	start := time.Now()
+	defer func() {
+		rtr.routeStats().observe(rw.Status(), time.Since(start))
+	}()
+	rtr.handler.ServeHTTP(rw, r)
+}
+
+// routeStats method returns the statistics of the Router, creating them on
+// first use.
+func (rtr *Router) routeStats() *routeStats {
+	if s := rtr.stats.Load(); s != nil {
+		return s
+	}
+	rtr.stats.CompareAndSwap(nil, new(routeStats))
+	return rtr.stats.Load()
+}
+
+// observe method records a request served with the status in d.
+func (s *routeStats) observe(status int, d time.Duration) {
+	s.requests.Add(1)
+	switch {
+	case status >= 500:
+		s.serverErrors.Add(1)
+	case status >= 400:
+		s.clientErrors.Add(1)
+	}
+	s.total.Add(int64(d))
+	for {
+		max := s.max.Load()
+		if int64(d) <= max || s.max.CompareAndSwap(max, int64(d)) {
+			break
+		}
+	}
+	i := 0
+	for i < statsBuckets-1 && d > statsBase<<i {
+		i++
+	}
+	s.buckets[i].Add(1)
+}
+
+// snapshot method returns RouteStats of the route.
+func (s *routeStats) snapshot(route *RouteInfo) RouteStats {
+	var counts [statsBuckets]uint64
+	var total uint64
+	for i := range s.buckets {
+		counts[i] = s.buckets[i].Load()
+		total += counts[i]
+	}
+	stats := RouteStats{
+		Route:        route,
+		Requests:     s.requests.Load(),
+		ClientErrors: s.clientErrors.Load(),
+		ServerErrors: s.serverErrors.Load(),
+		Max:          time.Duration(s.max.Load()),
+	}
+	if stats.Requests > 0 {
+		stats.Mean = time.Duration(s.total.Load() / int64(stats.Requests))
+	}
+	stats.P50 = percentile(counts[:], total, 0.50, stats.Max)
+	stats.P90 = percentile(counts[:], total, 0.90, stats.Max)
+	stats.P99 = percentile(counts[:], total, 0.99, stats.Max)
+	return stats
+}
+
+// percentile estimates the percentile p of the latency histogram as the upper
+// bound of the bucket it falls in, capped by the maximum latency.
+func percentile(
+	counts []uint64, total uint64, p float64, max time.Duration,
+) time.Duration {
+	if total == 0 {
+		return 0
+	}
+	rank := uint64(p*float64(total-1)) + 1
+	var seen uint64
+	for i, n := range counts {
+		seen += n
+		if seen < rank {
+			continue
+		}
+		if bound := statsBase << i; i < len(counts)-1 && bound < max {
+			return bound
+		}
+		break
+	}
+	return max
+}
@@ -0,0 +1,84 @@
+package mux
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStats(t *testing.T) {
+	rtr := New().CollectStats()
+	api := rtr.Subrouter().PathPrefix("/api")
+	api.Get("/users/{id:int}", func(w http.ResponseWriter, r *http.Request) {
+		if VarsOf(r).MustInt("id") == 0 {
+			Error(w, r, NewHTTPError(http.StatusNotFound, "no such user"))
+		}
+	}).Name("user")
+	api.Post("/users", func(w http.ResponseWriter, r *http.Request) {
+		Error(w, r, errors.New("database is down"))
+	})
+	api.Get("/idle", func(w http.ResponseWriter, r *http.Request) {})
+	untracked := New()
+	untracked.Get("/", func(w http.ResponseWriter, r *http.Request) {})
+
+	for _, c := range []struct{ method, path string }{
+		{http.MethodGet, "/api/users/1"},
+		{http.MethodGet, "/api/users/2"},
+		{http.MethodGet, "/api/users/0"},
+		{http.MethodPost, "/api/users"},
+		{http.MethodGet, "/nope"},
+	} {
+		rec, req, err := request(c.method, c.path, nil)
+		assert.NoError(t, err)
+		rtr.ServeHTTP(rec, req)
+	}
+	rec, req, _ := request(http.MethodGet, "/", nil)
+	untracked.ServeHTTP(rec, req)
+	assert.Empty(t, untracked.Stats())
+
+	stats := rtr.Stats()
+	if !assert.Len(t, stats, 2) {
+		return
+	}
+	assert.Equal(t, "/api/users/{id:int}", stats[0].Route.Template())
+	assert.Equal(t, uint64(3), stats[0].Requests)
+	assert.Equal(t, uint64(1), stats[0].ClientErrors)
+	assert.Equal(t, uint64(0), stats[0].ServerErrors)
+	assert.True(t, stats[0].P99 <= stats[0].Max)
+	assert.Equal(t, uint64(1), stats[1].ServerErrors)
+
+	rec, req, _ = request(http.MethodGet, "/", nil)
+	rtr.StatsHandler().ServeHTTP(rec, req)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	var body []map[string]interface{}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	if assert.Len(t, body, 2) {
+		assert.Equal(t, "user", body[0]["name"])
+		assert.Equal(t, float64(3), body[0]["requests"])
+		assert.Equal(t, "/api/users", body[1]["template"])
+	}
+}
+
+func TestRouteStatsPercentiles(t *testing.T) {
+	s := new(routeStats)
+	for i := 0; i < 98; i++ {
+		s.observe(http.StatusOK, 30*time.Microsecond)
+	}
+	s.observe(http.StatusOK, 3*time.Millisecond)
+	s.observe(http.StatusOK, time.Second)
+
+	stats := s.snapshot(nil)
+	assert.Equal(t, uint64(100), stats.Requests)
+	assert.Equal(t, statsBase, stats.P50)
+	assert.Equal(t, statsBase, stats.P90)
+	assert.Equal(t, statsBase<<6, stats.P99)
+	assert.Equal(t, time.Second, stats.Max)
+	assert.Equal(t, (98*30*time.Microsecond+3*time.Millisecond+time.Second)/
+		100, stats.Mean)
+
+	assert.Equal(t, RouteStats{}, new(routeStats).snapshot(nil))
+}
@@ -0,0 +1,99 @@
+package mux
+
+import (
+	"context"
+	"net/http"
+)
+
+// StatusHandler method registers the handler used to respond with given status
+// code, so that error pages look the same across the app:
+//
+//	rtr.StatusHandler(http.StatusNotFound, pages.NotFound).
+//	    StatusHandler(http.StatusInternalServerError, pages.Oops)
+//
+// The handler is used by Error whenever it reports an error with that code
+// (see StatusOf) and can get the error with ErrorOf. Handlers registered for
+// 404 and 405 also become the fail and "405 Method Not Allowed" handlers of
+// the Router, in which case ErrorOf returns nil. If the handler writes the
+// body without calling WriteHeader, the response gets the registered code.
+//
+// Like Fail, status handlers are inherited by sub-routers, unless they set
+// their own. Pass nil to inherit the parent's one again.
+func (rtr *Router) StatusHandler(code int, h http.Handler) *Router {
+	if h != nil {
+		h = &statusResponder{code, h}
+	}
+	switch code {
+	case http.StatusNotFound:
+		rtr.Fail(h)
+	case http.StatusMethodNotAllowed:
+		rtr.MethodNotAllowed(h)
+	}
+	if h == nil {
+		delete(rtr.status, code)
+		return rtr
+	}
+	if rtr.status == nil {
+		rtr.status = make(map[int]http.Handler)
+	}
+	rtr.status[code] = h
+	return rtr
+}
+
+// StatusHandlerFunc method registers the handler function used to respond with
+// given status code. See StatusHandler.
+func (rtr *Router) StatusHandlerFunc(code int, v View) *Router {
+	if v == nil {
+		return rtr.StatusHandler(code, nil)
+	}
+	return rtr.StatusHandler(code, v)
+}
+
+// ErrorOf returns the error that is being reported by a status handler, or nil
+// if the request is not served by one.
+func ErrorOf(r *http.Request) error {
+	err, _ := r.Context().Value(errorKey).(error)
+	return err
+}
+
+// withStatusHandlers method returns a copy of request that carries the status
+// handlers of this Router merged with the inherited ones.
+func (rtr *Router) withStatusHandlers(r *http.Request) *http.Request {
+	if len(rtr.status) == 0 {
+		return r
+	}
+	inherited, _ := r.Context().Value(statusKey).(map[int]http.Handler)
+	status := make(map[int]http.Handler, len(inherited)+len(rtr.status))
+	for code, h := range inherited {
+		status[code] = h
+	}
+	for code, h := range rtr.status {
+		status[code] = h
+	}
+	return r.WithContext(context.WithValue(r.Context(), statusKey, status))
+}
+
+// statusHandler returns the status handler registered for the code by the
+// closest router that has one. Errors reported from within status handlers are
+// not passed to status handlers again, so that they can't loop.
+func statusHandler(r *http.Request, code int) (http.Handler, bool) {
+	if ErrorOf(r) != nil {
+		return nil, false
+	}
+	status, _ := r.Context().Value(statusKey).(map[int]http.Handler)
+	h, ok := status[code]
+	return h, ok
+}
+
+// statusResponder is an http.Handler that makes sure the response has the
+// status code it was registered for.
+type statusResponder struct {
+	code    int
+	handler http.Handler
+}
+
+// ServeHTTP method ensures that statusResponder implements the http.Handler
+// interface.
+func (s *statusResponder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.handler.ServeHTTP(&statusWriter{w, s.code, false}, r)
+}
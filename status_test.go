@@ -0,0 +1,89 @@
+package mux
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusHandler(t *testing.T) {
+	page := func(s string) View {
+		return func(w http.ResponseWriter, r *http.Request) {
+			msg := s
+			if err := ErrorOf(r); err != nil {
+				msg += ": " + err.Error()
+			}
+			w.Write([]byte(msg))
+		}
+	}
+	fail := func(err error) View {
+		return func(w http.ResponseWriter, r *http.Request) {
+			Error(w, r, err)
+		}
+	}
+
+	rtr := New().
+		StatusHandlerFunc(http.StatusNotFound, page("not found")).
+		StatusHandlerFunc(http.StatusMethodNotAllowed, page("not allowed")).
+		StatusHandlerFunc(http.StatusInternalServerError, page("oops"))
+	rtr.Get("/boom", fail(errors.New("db is down")))
+	rtr.Get("/gone", fail(NewHTTPError(http.StatusNotFound, "no user")))
+	rtr.Get("/bad", fail(NewHTTPError(http.StatusBadRequest, "bad id")))
+	api := rtr.Subrouter().PathPrefix("/api").
+		StatusHandlerFunc(http.StatusInternalServerError, page("api oops"))
+	api.Get("/boom", fail(errors.New("db is down")))
+	api.Get("/gone", fail(NewHTTPError(http.StatusNotFound, "no user")))
+
+	cases := []struct {
+		method string
+		path   string
+		code   int
+		body   string
+	}{
+		{http.MethodGet, "/missing", http.StatusNotFound, "not found"},
+		{http.MethodPost, "/boom", http.StatusMethodNotAllowed, "not allowed"},
+		{http.MethodGet, "/boom", http.StatusInternalServerError,
+			"oops: db is down"},
+		{http.MethodGet, "/gone", http.StatusNotFound, "not found: no user"},
+		{http.MethodGet, "/bad", http.StatusBadRequest, "bad id\n"},
+		{http.MethodGet, "/api/boom", http.StatusInternalServerError,
+			"api oops: db is down"},
+		{http.MethodGet, "/api/gone", http.StatusNotFound,
+			"not found: no user"},
+		{http.MethodGet, "/api/missing", http.StatusNotFound, "not found"},
+	}
+	for _, c := range cases {
+		rec, req, err := request(c.method, c.path, nil)
+		assert.NoError(t, err)
+		rtr.ServeHTTP(rec, req)
+		assert.Equal(t, c.code, rec.Code, c.path)
+		assert.Equal(t, c.body, rec.Body.String(), c.path)
+	}
+
+	//-------------------- Another Test Case --------------------
+
+	// Nil restores inheritance.
+	api.StatusHandler(http.StatusInternalServerError, nil)
+	rec, req, err := request(http.MethodGet, "/api/boom", nil)
+	assert.NoError(t, err)
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, "oops: db is down", rec.Body.String())
+}
+
+func TestStatusHandlerLoop(t *testing.T) {
+	rtr := New().StatusHandlerFunc(http.StatusInternalServerError,
+		func(w http.ResponseWriter, r *http.Request) {
+			Error(w, r, errors.New("template is broken"))
+		})
+	rtr.Get("/", func(w http.ResponseWriter, r *http.Request) {
+		Error(w, r, errors.New("db is down"))
+	})
+
+	rec, req, err := request(http.MethodGet, "/", nil)
+	assert.NoError(t, err)
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Equal(t, "Internal Server Error\n", rec.Body.String())
+}
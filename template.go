@@ -0,0 +1,203 @@
+package mux
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// errNoRenderer is returned when no Renderer is attached to the router.
+var errNoRenderer = errors.New("no renderer attached to the router")
+
+// RendererOptions configures Renderer.
+type RendererOptions struct {
+	// Dir is the root directory of the templates.
+	Dir string
+
+	// Extension is the extension of template files. Defaults to ".html".
+	Extension string
+
+	// Layouts is a list of glob patterns (relative to Dir) of the files that
+	// are shared by all pages, e.g. "layouts/*.html" or "partials/*.html".
+	// Every page is parsed together with these files.
+	Layouts []string
+
+	// Layout is the name of the template executed to render a page, e.g.
+	// "layouts/base.html" for a layout file that calls
+	// {{template "content" .}}, while pages {{define "content"}}. Templates
+	// parsed from files are named by their paths relative to Dir. If empty,
+	// the page template itself is executed.
+	Layout string
+
+	// Funcs is a map of functions available to all templates.
+	Funcs template.FuncMap
+
+	// Reload makes the Renderer re-parse templates upon every call, so that
+	// changes are visible without restart. Meant for development only.
+	Reload bool
+}
+
+// Renderer renders html/template pages. Every file under the template root
+// that is not a layout is a page, named by its path relative to the root with
+// forward slashes (e.g. "users/show.html").
+type Renderer struct {
+	opts  RendererOptions
+	mu    sync.RWMutex
+	pages map[string]*template.Template
+}
+
+// NewRenderer returns pointer to a Renderer with all the templates parsed. It
+// returns an error if some of them can't be parsed.
+func NewRenderer(opts RendererOptions) (*Renderer, error) {
+	if opts.Extension == "" {
+		opts.Extension = ".html"
+	}
+	rnd := &Renderer{opts: opts}
+	if err := rnd.Load(); err != nil {
+		return nil, err
+	}
+	return rnd, nil
+}
+
+// Load method (re-)parses all templates.
+func (rnd *Renderer) Load() error {
+	base := template.New("").Funcs(rnd.opts.Funcs)
+	layouts := make(map[string]bool)
+	for _, pattern := range rnd.opts.Layouts {
+		files, err := filepath.Glob(filepath.Join(rnd.opts.Dir, pattern))
+		if err != nil {
+			return err
+		}
+		for _, file := range files {
+			if err := parseFile(base, rnd.opts.Dir, file); err != nil {
+				return err
+			}
+			layouts[file] = true
+		}
+	}
+
+	pages := make(map[string]*template.Template)
+	err := filepath.Walk(rnd.opts.Dir,
+		func(file string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() || layouts[file] ||
+				filepath.Ext(file) != rnd.opts.Extension {
+				return nil
+			}
+			t, err := base.Clone()
+			if err != nil {
+				return err
+			}
+			if err := parseFile(t, rnd.opts.Dir, file); err != nil {
+				return err
+			}
+			name, _ := filepath.Rel(rnd.opts.Dir, file)
+			pages[filepath.ToSlash(name)] = t
+			return nil
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	rnd.mu.Lock()
+	rnd.pages = pages
+	rnd.mu.Unlock()
+	return nil
+}
+
+// Render method renders the page with "200 OK" status code.
+func (rnd *Renderer) Render(
+	w http.ResponseWriter, name string, data interface{},
+) error {
+	return rnd.HTML(w, http.StatusOK, name, data)
+}
+
+// HTML method renders the page with given status code. The page is rendered
+// into a buffer first, so if execution fails, the error is returned and the
+// response is left intact.
+func (rnd *Renderer) HTML(
+	w http.ResponseWriter, code int, name string, data interface{},
+) error {
+	if rnd.opts.Reload {
+		if err := rnd.Load(); err != nil {
+			return err
+		}
+	}
+
+	rnd.mu.RLock()
+	t, ok := rnd.pages[name]
+	rnd.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("template %s not found", name)
+	}
+
+	exec := name
+	if rnd.opts.Layout != "" {
+		exec = rnd.opts.Layout
+	}
+	var buf bytes.Buffer
+	if err := t.ExecuteTemplate(&buf, exec, data); err != nil {
+		return err
+	}
+	return write(w, code, ContentTypeHTML, buf.Bytes())
+}
+
+// Renderer method attaches the Renderer to this Router and its sub-routers
+// (unless they have their own renderer attached), so that handlers can use
+// the Render function.
+func (rtr *Router) Renderer(rnd *Renderer) *Router {
+	rtr.renderer = rnd
+	return rtr
+}
+
+// Render renders the page using the Renderer attached to the closest router
+// with "200 OK" status code:
+//
+//	err := mux.Render(w, r, "users/show.html", user)
+func Render(
+	w http.ResponseWriter, r *http.Request, name string, data interface{},
+) error {
+	rnd, ok := r.Context().Value(rendererKey).(*Renderer)
+	if !ok {
+		return errNoRenderer
+	}
+	return rnd.Render(w, name, data)
+}
+
+// withRenderer returns a copy of request that carries Router's renderer in
+// case it is set.
+func (rtr *Router) withRenderer(r *http.Request) *http.Request {
+	if rtr.renderer == nil {
+		return r
+	}
+	return r.WithContext(
+		context.WithValue(r.Context(), rendererKey, rtr.renderer),
+	)
+}
+
+// parseFile parses the file into a new template associated with t and named
+// by the file path relative to the root.
+func parseFile(t *template.Template, root string, file string) error {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	name, err := filepath.Rel(root, file)
+	if err != nil {
+		return err
+	}
+	name = path.Clean(strings.ReplaceAll(name, string(filepath.Separator), "/"))
+	_, err = t.New(name).Parse(string(b))
+	return err
+}
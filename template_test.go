@@ -0,0 +1,72 @@
+package mux
+
+import (
+	"html/template"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderer(t *testing.T) {
+	dir, err := os.MkdirTemp("", "mux-templates")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"layouts/base.html": `<main>{{template "content" .}}</main>`,
+		"index.html":        `{{define "content"}}Hi, {{upper .}}!{{end}}`,
+		"users/show.html":   `{{define "content"}}User {{.}}{{end}}`,
+	}
+	for name, content := range files {
+		file := filepath.Join(dir, filepath.FromSlash(name))
+		assert.NoError(t, os.MkdirAll(filepath.Dir(file), 0755))
+		assert.NoError(t, os.WriteFile(file, []byte(content), 0644))
+	}
+
+	rnd, err := NewRenderer(RendererOptions{
+		Dir:     dir,
+		Layouts: []string{"layouts/*.html"},
+		Layout:  "layouts/base.html",
+		Funcs:   template.FuncMap{"upper": strings.ToUpper},
+		Reload:  true,
+	})
+	assert.NoError(t, err)
+
+	rtr := New().Renderer(rnd)
+	rtr.Get("/", func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, Render(w, r, "index.html", "viktor"))
+	})
+	rtr.Get("/users", func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, Render(w, r, "users/show.html", 42))
+	})
+
+	rec, req, err := request(http.MethodGet, "/", nil)
+	assert.NoError(t, err)
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, ContentTypeHTML, rec.Header().Get("Content-Type"))
+	assert.Equal(t, "<main>Hi, VIKTOR!</main>", rec.Body.String())
+
+	rec, req, err = request(http.MethodGet, "/users", nil)
+	assert.NoError(t, err)
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, "<main>User 42</main>", rec.Body.String())
+	//-------------------- Another Test Case --------------------
+	// Hot reload picks up changes.
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "index.html"),
+		[]byte(`{{define "content"}}Bye{{end}}`), 0644))
+	rec, req, err = request(http.MethodGet, "/", nil)
+	assert.NoError(t, err)
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, "<main>Bye</main>", rec.Body.String())
+	//-------------------- Another Test Case --------------------
+	assert.Error(t, rnd.Render(httptestRecorder(), "missing.html", nil))
+}
+
+func httptestRecorder() http.ResponseWriter {
+	rec, _, _ := request(http.MethodGet, "/", nil)
+	return rec
+}
@@ -0,0 +1,173 @@
+package mux
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Timeout returns Middleware that gives the handlers of the subtree d to
+// serve each request. The request context gets the deadline, so handlers that
+// respect it stop their work in time. If the deadline passes before the
+// handler starts writing the response, the client gets "504 Gateway Timeout"
+// through Error right away, and further writes of the handler fail with
+// http.ErrHandlerTimeout. If the handler has started writing already, the
+// status can't be changed anymore, so it is allowed to finish the response
+// that it is now expected to cut short.
+//
+// Unlike http.TimeoutHandler, it doesn't buffer the response, so it works with
+// streamed responses. Handlers are run in a separate goroutine; their panics
+// are passed on to the goroutine that serves the request.
+func Timeout(d time.Duration) Middleware {
+	return func(next http.Handler) http.Handler {
+		return View(func(w http.ResponseWriter, r *http.Request) {
+			deadline := time.Now().Add(d)
+			ctx, cancel := context.WithCancelCause(r.Context())
+			defer cancel(nil)
+			r = r.WithContext(&timeoutContext{ctx, deadline})
+			timer := time.NewTimer(d)
+			defer timer.Stop()
+
+			tw := &timeoutWriter{w: w, h: make(http.Header)}
+			done := make(chan struct{})
+			panicked := make(chan interface{}, 1)
+			go func() {
+				defer func() {
+					if v := recover(); v != nil {
+						panicked <- v
+					}
+				}()
+				next.ServeHTTP(tw, r)
+				close(done)
+			}()
+
+			select {
+			case v := <-panicked:
+				panic(v)
+			case <-done:
+				return
+			case <-ctx.Done():
+				// The client went away or the parent context expired.
+			case <-timer.C:
+			}
+
+			// Mark the writer before the handler learns about the timeout,
+			// so that it can't start the response in between.
+			tw.mu.Lock()
+			started := tw.wrote
+			tw.timedOut = !started
+			tw.mu.Unlock()
+			cancel(context.DeadlineExceeded)
+			if started {
+				// The response is on its way; let the handler finish it.
+				select {
+				case v := <-panicked:
+					panic(v)
+				case <-done:
+				}
+				return
+			}
+			Error(w, r, NewHTTPError(http.StatusGatewayTimeout,
+				"request timed out after %s", d))
+		})
+	}
+}
+
+// Timeout method registers Timeout middleware on the Router, so that its
+// subtree has d to serve each request:
+//
+//	rtr.Subrouter().PathPrefix("/reports").Timeout(5 * time.Second)
+func (rtr *Router) Timeout(d time.Duration) *Router {
+	return rtr.Wrap(Timeout(d))
+}
+
+// timeoutContext is the context of requests served by Timeout. It reports
+// the deadline, but is cancelled by Timeout itself, once the writer is marked.
+type timeoutContext struct {
+	context.Context
+	deadline time.Time
+}
+
+// Deadline method returns the deadline set by Timeout or the one of the parent
+// context, whichever is earlier.
+func (c *timeoutContext) Deadline() (time.Time, bool) {
+	if d, ok := c.Context.Deadline(); ok && d.Before(c.deadline) {
+		return d, true
+	}
+	return c.deadline, true
+}
+
+// Err method reports context.DeadlineExceeded once Timeout cancels the
+// context.
+func (c *timeoutContext) Err() error {
+	err := c.Context.Err()
+	if err != nil && context.Cause(c.Context) == context.DeadlineExceeded {
+		return context.DeadlineExceeded
+	}
+	return err
+}
+
+// timeoutWriter is an http.ResponseWriter that handlers write to while
+// Timeout waits for them. It keeps its own header map, so that the handler
+// can't touch the headers of the response once it timed out.
+type timeoutWriter struct {
+	w        http.ResponseWriter
+	h        http.Header
+	mu       sync.Mutex
+	wrote    bool
+	timedOut bool
+}
+
+// Header method returns the header map of the handler.
+func (tw *timeoutWriter) Header() http.Header {
+	return tw.h
+}
+
+// WriteHeader method copies the headers to the response and writes the
+// status code, unless the request timed out.
+func (tw *timeoutWriter) WriteHeader(code int) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	tw.writeHeader(code)
+}
+
+// writeHeader method is WriteHeader that expects the lock to be held.
+func (tw *timeoutWriter) writeHeader(code int) {
+	if tw.timedOut || tw.wrote {
+		return
+	}
+	dst := tw.w.Header()
+	for k, v := range tw.h {
+		dst[k] = v
+	}
+	tw.w.WriteHeader(code)
+	if code >= 200 || code == http.StatusSwitchingProtocols {
+		tw.wrote = true
+	}
+}
+
+// Write method writes the data to the response unless the request timed out.
+func (tw *timeoutWriter) Write(b []byte) (int, error) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	tw.writeHeader(http.StatusOK)
+	return tw.w.Write(b)
+}
+
+// Flush method ensures that timeoutWriter implements the http.Flusher
+// interface.
+func (tw *timeoutWriter) Flush() {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut {
+		return
+	}
+	tw.writeHeader(http.StatusOK)
+	if f, ok := tw.w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
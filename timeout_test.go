@@ -0,0 +1,65 @@
+package mux
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeout(t *testing.T) {
+	wrote := make(chan error, 1)
+
+	rtr := New().Timeout(20 * time.Millisecond)
+	rtr.Get("/fast", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Fast", "yes")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte("done"))
+	})
+	rtr.Get("/slow", func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+		w.Header().Set("X-Slow", "yes")
+		_, err := w.Write([]byte("too late"))
+		wrote <- err
+	})
+	rtr.Get("/stream", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("partial"))
+		<-r.Context().Done()
+		w.Write([]byte(" response"))
+	})
+	rtr.Get("/panic", func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})
+
+	rec, req, err := request(http.MethodGet, "/fast", nil)
+	assert.NoError(t, err)
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, "yes", rec.Header().Get("X-Fast"))
+	assert.Equal(t, "done", rec.Body.String())
+
+	//-------------------- Another Test Case --------------------
+
+	rec, req, err = request(http.MethodGet, "/slow", nil)
+	assert.NoError(t, err)
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusGatewayTimeout, rec.Code)
+	assert.Equal(t, "Gateway Timeout\n", rec.Body.String())
+	assert.Equal(t, http.ErrHandlerTimeout, <-wrote)
+	assert.Empty(t, rec.Header().Get("X-Slow"))
+
+	//-------------------- Another Test Case --------------------
+
+	rec, req, err = request(http.MethodGet, "/stream", nil)
+	assert.NoError(t, err)
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "partial response", rec.Body.String())
+
+	//-------------------- Another Test Case --------------------
+
+	rec, req, err = request(http.MethodGet, "/panic", nil)
+	assert.NoError(t, err)
+	assert.PanicsWithValue(t, "boom", func() { rtr.ServeHTTP(rec, req) })
+}
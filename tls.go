@@ -0,0 +1,100 @@
+package mux
+
+import (
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/http"
+)
+
+// TLSOptions configures ServeTLS.
+type TLSOptions struct {
+	// ServeOptions configure the server and its graceful shutdown just as
+	// they do for Serve.
+	ServeOptions
+
+	// CertFile and KeyFile are the paths to the PEM-encoded certificate,
+	// optionally followed by the intermediates, and its private key.
+	CertFile string
+	KeyFile  string
+
+	// Config, if set, is called with the tls.Config before the server
+	// starts, e.g. to require client certificates, to add certificates for
+	// other hosts or to obtain them with package autocert.
+	Config func(cfg *tls.Config)
+}
+
+// ServeTLS listens on the TCP address and serves requests with h over HTTPS
+// until it gets SIGINT or SIGTERM, then shuts down gracefully just like
+// Serve. Certificates are either read from files
+//
+//	mux.ServeTLS(":443", rtr, &mux.TLSOptions{
+//	    CertFile: "cert.pem",
+//	    KeyFile:  "key.pem",
+//	})
+//
+// or set up by TLSOptions.Config, e.g. to obtain them from Let's Encrypt with
+// package autocert. Connections older than TLS 1.2 are refused. It returns an
+// error if the certificates can't be set up or if the server fails (see
+// Serve).
+func ServeTLS(addr string, h http.Handler, opts *TLSOptions) error {
+	if opts == nil {
+		opts = &TLSOptions{}
+	}
+	cfg, err := opts.tlsConfig()
+	if err != nil {
+		return err
+	}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return serveTLSListener(ln, h, opts, cfg)
+}
+
+// serveTLSListener serves requests accepted by the listener over TLS with the
+// configuration until shutdown.
+func serveTLSListener(
+	ln net.Listener, h http.Handler, opts *TLSOptions, cfg *tls.Config,
+) error {
+	start := func(srv *http.Server, ln net.Listener) error {
+		srv.TLSConfig = cfg
+		return srv.ServeTLS(ln, "", "")
+	}
+	return serveListener(ln, h, &opts.ServeOptions, start)
+}
+
+// tlsConfig method builds the TLS configuration of the server. It returns an
+// error if there are no certificates.
+func (opts *TLSOptions) tlsConfig() (*tls.Config, error) {
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+	if opts.CertFile != "" || opts.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(opts.CertFile, opts.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	if opts.Config != nil {
+		opts.Config(cfg)
+	}
+	if len(cfg.Certificates) == 0 && cfg.GetCertificate == nil &&
+		cfg.GetConfigForClient == nil {
+		return nil, errors.New("mux: no certificates to serve TLS with")
+	}
+	return cfg, nil
+}
+
+// Hosts method returns the sorted hosts accepted by the Host filters of this
+// Router and its sub-routers.
+func (rtr *Router) Hosts() []string {
+	hosts := newSet()
+	rtr.Walk(func(route *RouteInfo) error {
+		for _, h := range route.Hosts {
+			hosts.Add(h)
+		}
+		return nil
+	})
+	return hosts.Items()
+}
@@ -0,0 +1,112 @@
+package mux
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// writeCert writes a self-signed certificate for localhost and its key to the
+// directory and returns their paths.
+func writeCert(t *testing.T, dir string) (certFile, keyFile string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey,
+		key)
+	assert.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	assert.NoError(t, err)
+
+	certFile = filepath.Join(dir, "cert.pem")
+	keyFile = filepath.Join(dir, "key.pem")
+	assert.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(
+		&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	assert.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(
+		&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600))
+	return certFile, keyFile
+}
+
+func TestServeTLS(t *testing.T) {
+	certFile, keyFile := writeCert(t, t.TempDir())
+	rtr := New()
+	rtr.Get("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Proto))
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	configured := false
+	opts := &TLSOptions{
+		ServeOptions: ServeOptions{Context: ctx},
+		CertFile:     certFile,
+		KeyFile:      keyFile,
+		Config: func(cfg *tls.Config) {
+			configured = len(cfg.Certificates) == 1
+		},
+	}
+	cfg, err := opts.tlsConfig()
+	assert.NoError(t, err)
+	assert.True(t, configured)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	served := make(chan error)
+	go func() {
+		served <- serveTLSListener(ln, rtr, opts, cfg)
+	}()
+
+	client := &http.Client{Transport: &http.Transport{
+		TLSClientConfig:   &tls.Config{InsecureSkipVerify: true},
+		ForceAttemptHTTP2: true,
+	}}
+	res, err := client.Get("https://" + ln.Addr().String() + "/")
+	if assert.NoError(t, err) {
+		body, _ := io.ReadAll(res.Body)
+		res.Body.Close()
+		assert.Equal(t, "HTTP/2.0", string(body))
+	}
+	client.CloseIdleConnections()
+
+	cancel()
+	assert.NoError(t, <-served)
+}
+
+func TestTLSConfigErrors(t *testing.T) {
+	certFile, _ := writeCert(t, t.TempDir())
+	_, err := (&TLSOptions{}).tlsConfig()
+	assert.EqualError(t, err, "mux: no certificates to serve TLS with")
+	//-------------------- Another Test Case --------------------
+	_, err = (&TLSOptions{CertFile: certFile}).tlsConfig()
+	assert.Error(t, err)
+}
+
+func TestHosts(t *testing.T) {
+	rtr := New()
+	rtr.Subrouter().Host("Example.com", "www.example.com")
+	rtr.Pattern("GET api.example.com/users/{id}")
+	assert.Equal(t,
+		[]string{"api.example.com", "example.com", "www.example.com"},
+		rtr.Hosts())
+}
@@ -0,0 +1,42 @@
+package mux
+
+// Remove method removes the route from the routes of this Router, so that it
+// is no longer matched. It is safe to call while requests are served. It
+// returns false if the route is not a direct sub-router of this Router.
+func (rtr *Router) Remove(route *Router) bool {
+	rtr.mu.Lock()
+	defer rtr.mu.Unlock()
+	for i, sub := range rtr.routes {
+		if sub != route {
+			continue
+		}
+		routes := make([]*Router, 0, len(rtr.routes)-1)
+		routes = append(routes, rtr.routes[:i]...)
+		rtr.routes = append(routes, rtr.routes[i+1:]...)
+		rtr.index.Store((*routeIndex)(nil))
+		return true
+	}
+	return false
+}
+
+// Disable method switches the route off: it is skipped during matching as if
+// its filters did not match, so requests fall through to the following
+// siblings or get "404 Not Found". Use it as a kill switch for endpoints that
+// misbehave. It is safe to call while requests are served and has no effect
+// on the root Router. It returns pointer to the same Router instance.
+func (rtr *Router) Disable() *Router {
+	rtr.disabled.Store(true)
+	return rtr
+}
+
+// Enable method switches the route back on after Disable. It returns pointer
+// to the same Router instance.
+func (rtr *Router) Enable() *Router {
+	rtr.disabled.Store(false)
+	return rtr
+}
+
+// Enabled method tells whether the route is enabled.
+func (rtr *Router) Enabled() bool {
+	return !rtr.disabled.Load()
+}
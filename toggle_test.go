@@ -0,0 +1,82 @@
+package mux
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDisable(t *testing.T) {
+	text := func(s string) View {
+		return func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(s))
+		}
+	}
+
+	rtr := New()
+	checkout := rtr.Post("/checkout", text("checkout"))
+	rtr.Get("/users/new", text("new"))
+	users := rtr.Get("/users/{name:str}", text("user"))
+	rtr.Get("/users/{rest:*}", text("rest"))
+
+	serve := func(method, path string) (int, string) {
+		rec, req, err := request(method, path, nil)
+		assert.NoError(t, err)
+		rtr.ServeHTTP(rec, req)
+		return rec.Code, rec.Body.String()
+	}
+
+	checkout.Disable()
+	assert.False(t, checkout.Enabled())
+	code, _ := serve(http.MethodPost, "/checkout")
+	assert.Equal(t, http.StatusNotFound, code)
+	code, _ = serve(http.MethodGet, "/checkout")
+	assert.Equal(t, http.StatusNotFound, code)
+
+	checkout.Enable()
+	_, body := serve(http.MethodPost, "/checkout")
+	assert.Equal(t, "checkout", body)
+
+	users.Disable()
+	_, body = serve(http.MethodGet, "/users/john")
+	assert.Equal(t, "rest", body)
+
+	var disabled []string
+	rtr.Walk(func(route *RouteInfo) error {
+		if route.Disabled {
+			disabled = append(disabled, route.Template())
+		}
+		return nil
+	})
+	assert.Equal(t, []string{"/users/{name:str}"}, disabled)
+}
+
+//-------------------- Another Test Case --------------------
+
+func TestRemove(t *testing.T) {
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+
+	rtr := New()
+	first := rtr.Get("/first", noop)
+	second := rtr.Get("/second", noop)
+	api := rtr.Subrouter().PathPrefix("/api")
+	nested := api.Get("/nested", noop)
+
+	assert.True(t, rtr.Remove(first))
+	assert.False(t, rtr.Remove(first))
+	assert.False(t, rtr.Remove(nested))
+	assert.True(t, api.Remove(nested))
+
+	for path, code := range map[string]int{
+		"/first":      http.StatusNotFound,
+		"/second":     http.StatusOK,
+		"/api/nested": http.StatusNotFound,
+	} {
+		rec, req, err := request(http.MethodGet, path, nil)
+		assert.NoError(t, err)
+		rtr.ServeHTTP(rec, req)
+		assert.Equal(t, code, rec.Code, path)
+	}
+	assert.Equal(t, []*Router{second, api}, rtr.ordered())
+}
@@ -0,0 +1,147 @@
+// Use of this source code is governed by the Mozilla Public License Version 2.0
+// that can be found in the LICENSE file.
+
+/*
+Package tracing instruments mux routing trees with OpenTelemetry.
+
+The middleware starts a server span for every request, continuing the trace
+the client started if the request carries W3C traceparent header. Spans are
+named after the method and the template of the route that served the request
+(e.g. "GET /users/{id:int}"), and record the status code and the error
+reported with mux.Error, if any:
+
+	rtr := mux.New().Wrap(tracing.Middleware(nil))
+
+The span is placed in the request context, so handlers that pass the context
+on to outgoing calls continue the trace:
+
+	req, _ := http.NewRequestWithContext(r.Context(), "GET", url, nil)
+	propagation.TraceContext{}.Inject(r.Context(),
+	    propagation.HeaderCarrier(req.Header))
+*/
+package tracing
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/sharpvik/mux"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentation is the name of the tracer used by the middleware.
+const instrumentation = "github.com/sharpvik/mux/tracing"
+
+// Options configures Middleware.
+type Options struct {
+	// TracerProvider creates the tracer. If nil, the global one is used
+	// (see otel.SetTracerProvider).
+	TracerProvider trace.TracerProvider
+
+	// Propagator extracts the trace context from the request. If nil, W3C
+	// Trace Context and Baggage are extracted. The global propagator is not
+	// used by default, since it does nothing unless set with
+	// otel.SetTextMapPropagator.
+	Propagator propagation.TextMapPropagator
+
+	// Skip tells which requests are not traced, e.g. health checks. If nil,
+	// all requests are traced.
+	Skip func(r *http.Request) bool
+}
+
+// Middleware returns mux.Middleware that traces requests. Register it on the
+// root router, so that spans cover all the middleware and the routing. If
+// opts is nil, defaults are used.
+func Middleware(opts *Options) mux.Middleware {
+	if opts == nil {
+		opts = &Options{}
+	}
+	provider := opts.TracerProvider
+	if provider == nil {
+		provider = otel.GetTracerProvider()
+	}
+	propagator := opts.Propagator
+	if propagator == nil {
+		propagator = propagation.NewCompositeTextMapPropagator(
+			propagation.TraceContext{}, propagation.Baggage{})
+	}
+	tracer := provider.Tracer(instrumentation,
+		trace.WithSchemaURL(semconv.SchemaURL))
+
+	return func(next http.Handler) http.Handler {
+		return mux.View(func(w http.ResponseWriter, r *http.Request) {
+			if opts.Skip != nil && opts.Skip(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx := propagator.Extract(r.Context(),
+				propagation.HeaderCarrier(r.Header))
+			ctx, span := tracer.Start(ctx, r.Method,
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(requestAttributes(r)...))
+			defer span.End()
+
+			rw := mux.NewResponseWriter(w)
+			r = mux.RecordRoute(r.WithContext(ctx))
+			next.ServeHTTP(rw, r)
+
+			if info := mux.MatchedRoute(r); info != nil {
+				route := info.Template()
+				span.SetName(r.Method + " " + route)
+				span.SetAttributes(semconv.HTTPRoute(route))
+			}
+			status := rw.Status()
+			span.SetAttributes(
+				semconv.HTTPResponseStatusCode(status),
+				semconv.HTTPResponseBodySize(int(rw.Size())),
+			)
+			err := mux.ReportedError(r)
+			if err != nil {
+				span.RecordError(err)
+			}
+			// Client errors are not errors of the server.
+			if status >= 500 {
+				msg := http.StatusText(status)
+				if err != nil {
+					msg = err.Error()
+				}
+				span.SetStatus(codes.Error, msg)
+			}
+		})
+	}
+}
+
+// requestAttributes returns the attributes of the span that describe the
+// request.
+func requestAttributes(r *http.Request) []attribute.KeyValue {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	attrs := []attribute.KeyValue{
+		semconv.HTTPRequestMethodKey.String(r.Method),
+		semconv.URLScheme(scheme),
+		semconv.URLPath(mux.OriginalPath(r)),
+		semconv.ServerAddress(r.Host),
+		semconv.ClientAddress(mux.ClientIP(r)),
+		semconv.NetworkProtocolVersion(
+			fmt.Sprintf("%d.%d", r.ProtoMajor, r.ProtoMinor)),
+	}
+	if r.URL.RawQuery != "" {
+		attrs = append(attrs, semconv.URLQuery(r.URL.RawQuery))
+	}
+	if ua := r.UserAgent(); ua != "" {
+		attrs = append(attrs, semconv.UserAgentOriginal(ua))
+	}
+	if r.ContentLength > 0 {
+		attrs = append(attrs,
+			semconv.HTTPRequestBodySize(int(r.ContentLength)))
+	}
+	return attrs
+}
@@ -0,0 +1,125 @@
+package tracing
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sharpvik/mux"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestMiddleware(t *testing.T) {
+	spans := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))
+
+	var handlerSpan trace.SpanContext
+	rtr := mux.New().Wrap(Middleware(&Options{
+		TracerProvider: provider,
+		Propagator:     propagation.TraceContext{},
+		Skip: func(r *http.Request) bool {
+			return r.URL.Path == "/health"
+		},
+	}))
+	api := rtr.Subrouter().PathPrefix("/api")
+	api.Get("/users/{id:int}", func(w http.ResponseWriter, r *http.Request) {
+		handlerSpan = trace.SpanContextFromContext(r.Context())
+		w.Write([]byte("user"))
+	})
+	api.Get("/fail", func(w http.ResponseWriter, r *http.Request) {
+		mux.Error(w, r, errors.New("database is down"))
+	})
+	rtr.Get("/health", func(w http.ResponseWriter, r *http.Request) {})
+
+	serve := func(path string, header http.Header) {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		rtr.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	serve("/api/users/42", http.Header{"Traceparent": {
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+	}})
+	serve("/api/fail", nil)
+	serve("/nope", nil)
+	serve("/health", nil)
+
+	ended := spans.Ended()
+	if !assert.Len(t, ended, 3) {
+		return
+	}
+
+	// The trace started by the client is continued.
+	user := ended[0]
+	assert.Equal(t, "GET /api/users/{id:int}", user.Name())
+	assert.Equal(t, trace.SpanKindServer, user.SpanKind())
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736",
+		user.SpanContext().TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", user.Parent().SpanID().String())
+	assert.Equal(t, user.SpanContext(), handlerSpan)
+	attrs := attributes(user)
+	assert.Equal(t, "/api/users/{id:int}", attrs["http.route"].AsString())
+	assert.Equal(t, "/api/users/42", attrs["url.path"].AsString())
+	assert.Equal(t, int64(200), attrs["http.response.status_code"].AsInt64())
+	assert.Equal(t, codes.Unset, user.Status().Code)
+
+	fail := ended[1]
+	assert.Equal(t, "GET /api/fail", fail.Name())
+	assert.Equal(t, codes.Error, fail.Status().Code)
+	assert.Equal(t, "database is down", fail.Status().Description)
+	if assert.Len(t, fail.Events(), 1) {
+		assert.Equal(t, "exception", fail.Events()[0].Name)
+	}
+
+	nope := ended[2]
+	assert.Equal(t, "GET", nope.Name())
+	assert.Equal(t, int64(404),
+		attributes(nope)["http.response.status_code"].AsInt64())
+	assert.Equal(t, codes.Unset, nope.Status().Code)
+}
+
+func TestMiddlewareDefaults(t *testing.T) {
+	spans := tracetest.NewSpanRecorder()
+	global := otel.GetTracerProvider()
+	otel.SetTracerProvider(
+		sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans)))
+	defer otel.SetTracerProvider(global)
+
+	var member string
+	rtr := mux.New().Wrap(Middleware(nil))
+	rtr.Get("/", func(w http.ResponseWriter, r *http.Request) {
+		member = baggage.FromContext(r.Context()).Member("user").Value()
+	})
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Traceparent",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	req.Header.Set("Baggage", "user=alice")
+	rtr.ServeHTTP(httptest.NewRecorder(), req)
+
+	if !assert.Len(t, spans.Ended(), 1) {
+		return
+	}
+	span := spans.Ended()[0]
+	assert.Equal(t, "GET /", span.Name())
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736",
+		span.SpanContext().TraceID().String())
+	assert.Equal(t, "alice", member)
+}
+
+func attributes(span sdktrace.ReadOnlySpan) map[string]attribute.Value {
+	attrs := make(map[string]attribute.Value)
+	for _, kv := range span.Attributes() {
+		attrs[string(kv.Key)] = kv.Value
+	}
+	return attrs
+}
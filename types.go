@@ -14,5 +14,98 @@ func (v View) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 // context key.
 type contextKey int
 
-// varsKey is a context key for request variables.
-const varsKey contextKey = iota
+const (
+	// varsKey is a context key for request variables.
+	varsKey contextKey = iota
+
+	// headFallbackKey is a context key for the flag that tells sub-routers
+	// that HEAD fallback was enabled by one of their parents.
+	headFallbackKey
+
+	// slashKey is a context key for the trailing slash policy set by one of
+	// the parent routers.
+	slashKey
+
+	// pathKey is a context key for the request path as it was before routers
+	// started cutting their prefixes from it.
+	pathKey
+
+	// encodedKey is a context key for the encoded slashes policy in case
+	// request is routed by its escaped path.
+	encodedKey
+
+	// errorHandlerKey is a context key for the error handler of the closest
+	// router that has one.
+	errorHandlerKey
+
+	// validatorKey is a context key for the validator function of the
+	// closest router that has one.
+	validatorKey
+
+	// rendererKey is a context key for the Renderer attached to the closest
+	// router that has one.
+	rendererKey
+
+	// failKey is a context key for the fail handler of the closest router
+	// that has one.
+	failKey
+
+	// methodNotAllowedKey is a context key for the "405 Method Not Allowed"
+	// handler of the closest router that has one.
+	methodNotAllowedKey
+
+	// statusKey is a context key for the status handlers inherited from the
+	// parent routers.
+	statusKey
+
+	// errorKey is a context key for the error that is being reported by a
+	// status handler.
+	errorKey
+
+	// routeKey is a context key for the record of the route that served the
+	// request. See MatchedRoute.
+	routeKey
+
+	// loggerKey is a context key for the logger of the closest router that
+	// has one.
+	loggerKey
+
+	// clientIPKey is a context key for the IP address of the client resolved
+	// by RealIP.
+	clientIPKey
+
+	// routerKey is a context key for the router whose middleware handlers
+	// are being applied.
+	routerKey
+
+	// identityKey is a context key for the identity of the authenticated
+	// client.
+	identityKey
+
+	// sessionKey is a context key for the session of the client.
+	sessionKey
+
+	// statsKey is a context key for the flag that tells routes to collect
+	// statistics.
+	statsKey
+
+	// attemptKey is a context key for the outcome of the attempt to forward
+	// the request to a backend of Proxy.
+	attemptKey
+
+	// experimentKey is a context key for the variants of experiments that
+	// the request was assigned to.
+	experimentKey
+
+	// flagsKey is a context key for the flag provider of the closest router
+	// that has one.
+	flagsKey
+
+	// localeKey is a context key for the locale negotiated for the request or
+	// set by its path.
+	localeKey
+
+	// noETagKey is a context key for the flag that routes set to opt out of
+	// ETag middleware.
+	noETagKey
+)
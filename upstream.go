@@ -0,0 +1,254 @@
+package mux
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ActiveHealthCheck configures periodic probes that Proxy sends to its
+// backends. A backend that fails a number of probes in a row gets no requests
+// until it passes a number of probes in a row again.
+type ActiveHealthCheck struct {
+	// Path, if set, is the path of the backend probed with GET request. The
+	// probe passes if the backend responds with 2xx or 3xx status code. If
+	// empty, the probe only connects to the backend over TCP.
+	Path string
+
+	// Interval is the time between probes. Zero means 10 seconds.
+	Interval time.Duration
+
+	// Timeout is how long a probe may take. Zero means 2 seconds.
+	Timeout time.Duration
+
+	// Unhealthy is the number of failed probes in a row that take the
+	// backend out of the pool. Zero means 2.
+	Unhealthy int
+
+	// Healthy is the number of passed probes in a row that bring the
+	// backend back to the pool. Zero means 2.
+	Healthy int
+}
+
+// PassiveHealthCheck configures the ejection of backends that fail to serve
+// requests. A backend that fails a number of requests in a row is ejected
+// from the pool for a while; then it gets requests again.
+//
+// The backend fails if it can't be reached or if it responds with "502 Bad
+// Gateway", "503 Service Unavailable" or "504 Gateway Timeout".
+type PassiveHealthCheck struct {
+	// Failures is the number of failed requests in a row that eject the
+	// backend. Zero means 3.
+	Failures int
+
+	// EjectFor is how long the backend stays ejected. Zero means 30
+	// seconds.
+	EjectFor time.Duration
+}
+
+// BackendStatus describes the state of a backend of Proxy.
+type BackendStatus struct {
+	// URL is the URL of the backend.
+	URL string
+
+	// Healthy tells whether the backend passes active health checks.
+	Healthy bool
+
+	// Ejected tells whether the backend is ejected by passive health
+	// checks.
+	Ejected bool
+
+	// Active is the number of requests in flight.
+	Active int64
+}
+
+// Backends method describes the state of the backends.
+func (p *Proxy) Backends() []BackendStatus {
+	now := time.Now()
+	backends := p.upstreams()
+	status := make([]BackendStatus, len(backends))
+	for i, b := range backends {
+		status[i] = BackendStatus{
+			URL:     b.url.String(),
+			Healthy: b.healthy.Load(),
+			Ejected: b.ejected(now),
+			Active:  b.active.Load(),
+		}
+	}
+	return status
+}
+
+// Close method stops active health checks and resolution of the backends, and
+// closes idle connections to them. Proxy may still be used after that.
+func (p *Proxy) Close() error {
+	p.closeOnce.Do(func() {
+		close(p.done)
+	})
+	p.workers.Wait()
+	for _, b := range p.upstreams() {
+		b.transport.CloseIdleConnections()
+	}
+	return nil
+}
+
+// withDefaults method returns a copy of the health check with zero fields set
+// to their defaults. It returns nil if the health check is nil.
+func (hc *ActiveHealthCheck) withDefaults() *ActiveHealthCheck {
+	if hc == nil {
+		return nil
+	}
+	c := *hc
+	if c.Interval <= 0 {
+		c.Interval = 10 * time.Second
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = 2 * time.Second
+	}
+	if c.Unhealthy <= 0 {
+		c.Unhealthy = 2
+	}
+	if c.Healthy <= 0 {
+		c.Healthy = 2
+	}
+	return &c
+}
+
+// withDefaults method returns a copy of the health check with zero fields set
+// to their defaults. It returns nil if the health check is nil.
+func (hc *PassiveHealthCheck) withDefaults() *PassiveHealthCheck {
+	if hc == nil {
+		return nil
+	}
+	c := *hc
+	if c.Failures <= 0 {
+		c.Failures = 3
+	}
+	if c.EjectFor <= 0 {
+		c.EjectFor = 30 * time.Second
+	}
+	return &c
+}
+
+// check method probes the backends every interval until Proxy is closed.
+func (p *Proxy) check() {
+	defer p.workers.Done()
+	t := time.NewTicker(p.active.Interval)
+	defer t.Stop()
+	for {
+		var wg sync.WaitGroup
+		for _, b := range p.upstreams() {
+			wg.Add(1)
+			go func(b *backend) {
+				defer wg.Done()
+				b.probed(p.active, p.probe(b))
+			}(b)
+		}
+		wg.Wait()
+
+		select {
+		case <-t.C:
+		case <-p.done:
+			return
+		}
+	}
+}
+
+// probe method tells whether the backend passes the probe.
+func (p *Proxy) probe(b *backend) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), p.active.Timeout)
+	defer cancel()
+	go func() {
+		select {
+		case <-p.done:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	if p.active.Path == "" {
+		var d net.Dialer
+		addr := hostPort(b.url.Scheme, b.url.Host)
+		conn, err := d.DialContext(ctx, "tcp", addr)
+		if err != nil {
+			return false
+		}
+		conn.Close()
+		return true
+	}
+
+	u := *b.url
+	u.Path, u.RawPath, u.RawQuery = p.active.Path, "", ""
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(),
+		nil)
+	if err != nil {
+		return false
+	}
+	res, err := b.transport.RoundTrip(req)
+	if err != nil {
+		return false
+	}
+	res.Body.Close()
+	return res.StatusCode >= 200 && res.StatusCode < 400
+}
+
+// probed method records the outcome of the probe and changes the health of
+// the backend once there were enough probes with the same outcome in a row.
+func (b *backend) probed(hc *ActiveHealthCheck, ok bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if ok {
+		b.passed, b.failed = b.passed+1, 0
+		if b.passed >= hc.Healthy {
+			b.healthy.Store(true)
+		}
+	} else {
+		b.passed, b.failed = 0, b.failed+1
+		if b.failed >= hc.Unhealthy {
+			b.healthy.Store(false)
+		}
+	}
+}
+
+// served method records the outcome of the request for passive health
+// checks and ejects the backend if it failed too many requests in a row.
+func (b *backend) served(hc *PassiveHealthCheck, failed bool) {
+	if hc == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !failed {
+		b.errors = 0
+		return
+	}
+	b.errors++
+	if b.errors >= hc.Failures {
+		b.errors = 0
+		b.ejectedUntil.Store(time.Now().Add(hc.EjectFor).UnixNano())
+	}
+}
+
+// ejected method tells whether the backend is ejected at the moment.
+func (b *backend) ejected(now time.Time) bool {
+	return now.UnixNano() < b.ejectedUntil.Load()
+}
+
+// available method tells whether the backend may get requests according to
+// its health checks and circuit breaker.
+func (b *backend) available(now time.Time) bool {
+	return b.healthy.Load() && !b.ejected(now) && b.breaker.ready()
+}
+
+// hostPort returns host with port, adding the default port of the scheme if
+// the host has none.
+func hostPort(scheme, host string) string {
+	if _, _, err := net.SplitHostPort(host); err == nil {
+		return host
+	}
+	if scheme == "https" {
+		return net.JoinHostPort(host, "443")
+	}
+	return net.JoinHostPort(host, "80")
+}
@@ -0,0 +1,100 @@
+package mux
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActiveHealthCheck(t *testing.T) {
+	var sick atomic.Bool
+	flaky := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/health" && sick.Load() {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			w.Write([]byte("flaky"))
+		},
+	))
+	defer flaky.Close()
+	stable := backendServer(t, "stable")
+	p := NewProxy(&ProxyOptions{
+		Backends: []Backend{{URL: flaky.URL + "/app"}, {URL: stable.URL}},
+		ActiveHealth: &ActiveHealthCheck{
+			Path:      "/health",
+			Interval:  5 * time.Millisecond,
+			Unhealthy: 1,
+			Healthy:   1,
+		},
+	})
+	defer p.Close()
+
+	healthy := func() bool { return p.Backends()[0].Healthy }
+	sick.Store(true)
+	assert.Eventually(t, func() bool { return !healthy() },
+		time.Second, time.Millisecond)
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, "stable /", proxied(t, p, "/"))
+	}
+	sick.Store(false)
+	assert.Eventually(t, healthy, time.Second, time.Millisecond)
+	//-------------------- Another Test Case --------------------
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+	p = NewProxy(&ProxyOptions{
+		Backends:     []Backend{{URL: down.URL}},
+		ActiveHealth: &ActiveHealthCheck{Interval: time.Hour, Unhealthy: 1},
+	})
+	assert.Eventually(t, func() bool { return !p.Backends()[0].Healthy },
+		time.Second, time.Millisecond)
+	rec, req, _ := request(http.MethodGet, "/", nil)
+	p.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.NoError(t, p.Close())
+	assert.NoError(t, p.Close())
+}
+
+func TestPassiveHealthCheck(t *testing.T) {
+	down := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusGatewayTimeout)
+		},
+	))
+	defer down.Close()
+	up := backendServer(t, "up")
+	p := NewProxy(&ProxyOptions{
+		Backends:      []Backend{{URL: down.URL}, {URL: up.URL}},
+		PassiveHealth: &PassiveHealthCheck{Failures: 2},
+	})
+
+	codes := map[int]int{}
+	for i := 0; i < 10; i++ {
+		rec, req, _ := request(http.MethodGet, "/", nil)
+		p.ServeHTTP(rec, req)
+		codes[rec.Code]++
+	}
+	assert.Equal(t, map[int]int{
+		http.StatusOK:             8,
+		http.StatusGatewayTimeout: 2,
+	}, codes)
+	status := p.Backends()
+	assert.True(t, status[0].Ejected)
+	assert.False(t, status[1].Ejected)
+	assert.True(t, status[0].Healthy)
+	//-------------------- Another Test Case --------------------
+	b := p.upstreams()[0]
+	b.ejectedUntil.Store(0)
+	hc := (&PassiveHealthCheck{Failures: 2}).withDefaults()
+	b.served(hc, true)
+	b.served(hc, false)
+	b.served(hc, true)
+	assert.False(t, b.ejected(time.Now()), "success resets failures")
+	b.served(hc, true)
+	assert.True(t, b.ejected(time.Now()))
+	assert.False(t, b.ejected(time.Now().Add(hc.EjectFor)))
+}
@@ -18,30 +18,62 @@ func Vars(r *http.Request) (varsmap map[string]interface{}, ok bool) {
 	return
 }
 
+// varPattern matches path segment patterns of "{varname:vartype}" form.
+var varPattern = regexp.MustCompile(`\{\w+:.+\}`)
+
 // isVar tells you whether this path segment pattern was intended as a variable.
 // The pattern is either an arbitrary string or of "{varname:vartype}" form.
 func isVar(pattern string) bool {
-	return regexp.MustCompile(`\{\w+:.+\}`).MatchString(pattern)
+	return varPattern.MatchString(pattern)
 }
 
 // varData returns path var's name and type from given pattern where pattern is
-// something like "{id:int}".
+// something like "{id:int}". It panics if the type is invalid.
 func varData(pattern string) (name string, typ string) {
+	name, typ, err := parseVar(pattern)
+	if err != nil {
+		panic(err.Error())
+	}
+	return
+}
+
+// parseVar returns path var's name and type from given pattern where pattern
+// is something like "{id:int}". It returns an error if the type is invalid.
+func parseVar(pattern string) (name string, typ string, err error) {
 	trim := string([]rune(pattern)[1 : len(pattern)-1])
-	split := strings.Split(trim, ":")
+	split := strings.SplitN(trim, ":", 2)
 	name = split[0]
 	typ = split[1]
 
 	switch typ {
-	case "int", "str", "nat": // NOP case just to catch regex in typ.
+	case "*": // NOP case just to catch regex in typ.
 	default:
+		if _, ok := lookupVarType(typ); ok {
+			break
+		}
+		if _, ok := enumValues(typ); ok {
+			break
+		}
+
 		// At this point we assume that it's either a regex expression that can
-		// be compiled, or an invalid type (in which case we should panic).
-		_, err := regexp.Compile(typ)
-		if err != nil {
-			panic(fmt.Sprintf("invalid type/regex in path %s", pattern))
+		// be compiled, or an invalid type.
+		if _, err := regexp.Compile(typ); err != nil {
+			return "", "", fmt.Errorf("invalid type/regex in path %s", pattern)
 		}
 	}
 
-	return
+	return name, typ, nil
+}
+
+// enumValues parses enum variable type of "enum(a,b,c)" form and returns its
+// values. The second return value is false if typ is not an enum.
+func enumValues(typ string) (values []string, ok bool) {
+	if !strings.HasPrefix(typ, "enum(") || !strings.HasSuffix(typ, ")") {
+		return nil, false
+	}
+	values = strings.Split(typ[len("enum("):len(typ)-1], ",")
+	for i, v := range values {
+		values[i] = strings.TrimSpace(v)
+	}
+	return values, true
 }
@@ -0,0 +1,69 @@
+package mux
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// Validator is implemented by the values that can validate themselves. Bind,
+// BindForm and DecodeJSON call Validate after successful binding.
+type Validator interface {
+	Validate() error
+}
+
+// ValidatorFunc is a function that validates values after binding. It allows
+// plugging in third-party validation libraries, e.g.
+//
+//	rtr.Validator(func(v interface{}) error {
+//	    return validate.Struct(v)
+//	})
+type ValidatorFunc func(v interface{}) error
+
+// Validator method sets the function that validates values bound by Bind,
+// BindForm and DecodeJSON in handlers of this Router and its sub-routers
+// (unless they have their own validator set). It runs after the value's own
+// Validate method, if it has one.
+func (rtr *Router) Validator(fn ValidatorFunc) *Router {
+	rtr.validator = fn
+	return rtr
+}
+
+// validate runs validation of the bound value: its own Validate method (if
+// any) and the validator function of the closest router. Failures are
+// returned as *HTTPError with "422 Unprocessable Entity" status code unless
+// the validator returned *HTTPError itself.
+func validate(r *http.Request, v interface{}) error {
+	if val, ok := v.(Validator); ok {
+		if err := val.Validate(); err != nil {
+			return validationError(err)
+		}
+	}
+	if fn, ok := r.Context().Value(validatorKey).(ValidatorFunc); ok {
+		if err := fn(v); err != nil {
+			return validationError(err)
+		}
+	}
+	return nil
+}
+
+// validationError wraps err into *HTTPError with "422 Unprocessable Entity"
+// status code unless it already is an *HTTPError.
+func validationError(err error) error {
+	var herr *HTTPError
+	if errors.As(err, &herr) {
+		return err
+	}
+	return &HTTPError{http.StatusUnprocessableEntity, err}
+}
+
+// withValidator returns a copy of request that carries Router's validator in
+// case it is set.
+func (rtr *Router) withValidator(r *http.Request) *http.Request {
+	if rtr.validator == nil {
+		return r
+	}
+	return r.WithContext(
+		context.WithValue(r.Context(), validatorKey, rtr.validator),
+	)
+}
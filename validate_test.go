@@ -0,0 +1,62 @@
+package mux
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type signup struct {
+	Email string `json:"email" mux:"email"`
+	Age   int    `json:"age" mux:"age"`
+}
+
+func (s *signup) Validate() error {
+	if !strings.Contains(s.Email, "@") {
+		return errors.New("email is invalid")
+	}
+	return nil
+}
+
+func TestValidation(t *testing.T) {
+	var bindErr error
+	rtr := New().Validator(func(v interface{}) error {
+		if s, ok := v.(*signup); ok && s.Age < 18 {
+			return errors.New("too young")
+		}
+		return nil
+	})
+	rtr.Post("/json", func(w http.ResponseWriter, r *http.Request) {
+		bindErr = DecodeJSON(r, &signup{}, nil)
+	})
+	rtr.Get("/query", func(w http.ResponseWriter, r *http.Request) {
+		bindErr = Bind(r, &signup{})
+	})
+
+	cases := []struct {
+		method, path, body string
+		err                string
+	}{
+		{http.MethodPost, "/json", `{"email":"a@b.c","age":30}`, ""},
+		{http.MethodPost, "/json", `{"email":"nope","age":30}`,
+			"email is invalid"},
+		{http.MethodPost, "/json", `{"email":"a@b.c","age":12}`, "too young"},
+		{http.MethodGet, "/query?email=a@b.c&age=12", "", "too young"},
+		{http.MethodGet, "/query?email=a@b.c&age=42", "", ""},
+	}
+	for _, c := range cases {
+		rec, req, err := request(c.method, c.path, strings.NewReader(c.body))
+		assert.NoError(t, err)
+		bindErr = nil
+		rtr.ServeHTTP(rec, req)
+		if c.err == "" {
+			assert.NoError(t, bindErr, c.path)
+			continue
+		}
+		assert.EqualError(t, bindErr, c.err)
+		assert.Equal(t, http.StatusUnprocessableEntity, StatusOf(bindErr))
+	}
+}
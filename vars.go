@@ -0,0 +1,206 @@
+package mux
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ErrNoVar is returned by TypedVars accessors when the requested path variable
+// is not present.
+var ErrNoVar = errors.New("no such path variable")
+
+// TypedVars wraps path variables of the request and provides typed accessors
+// for them, so that handlers don't have to do type assertions by hand:
+//
+//	id, err := mux.VarsOf(r).Int("id")
+//
+// Accessors also convert string values (e.g. those captured by regex
+// variables) to the requested type.
+type TypedVars map[string]interface{}
+
+// VarsOf returns path variables of the request as TypedVars. If the request
+// has no path variables, the returned TypedVars is empty.
+func VarsOf(r *http.Request) TypedVars {
+	vars, _ := Vars(r)
+	return TypedVars(vars)
+}
+
+// Has method tells whether the variable is present.
+func (vars TypedVars) Has(name string) bool {
+	_, ok := vars[name]
+	return ok
+}
+
+// String method returns the variable as string. Non-string values are
+// formatted with fmt.Sprint.
+func (vars TypedVars) String(name string) (string, error) {
+	v, ok := vars[name]
+	if !ok {
+		return "", varError(name, ErrNoVar)
+	}
+	if s, ok := v.(string); ok {
+		return s, nil
+	}
+	return fmt.Sprint(v), nil
+}
+
+// Int method returns the variable as int.
+func (vars TypedVars) Int(name string) (int, error) {
+	v, ok := vars[name]
+	if !ok {
+		return 0, varError(name, ErrNoVar)
+	}
+	switch v := v.(type) {
+	case int:
+		return v, nil
+	case uint:
+		if v > math.MaxInt {
+			return 0, varError(name, strconv.ErrRange)
+		}
+		return int(v), nil
+	case string:
+		n, err := strconv.Atoi(v)
+		return n, varError(name, err)
+	}
+	return 0, typeError(name, v, "int")
+}
+
+// Uint method returns the variable as uint.
+func (vars TypedVars) Uint(name string) (uint, error) {
+	v, ok := vars[name]
+	if !ok {
+		return 0, varError(name, ErrNoVar)
+	}
+	switch v := v.(type) {
+	case uint:
+		return v, nil
+	case int:
+		if v >= 0 {
+			return uint(v), nil
+		}
+	case string:
+		n, err := strconv.ParseUint(v, 10, 0)
+		return uint(n), varError(name, err)
+	}
+	return 0, typeError(name, v, "uint")
+}
+
+// Float method returns the variable as float64.
+func (vars TypedVars) Float(name string) (float64, error) {
+	v, ok := vars[name]
+	if !ok {
+		return 0, varError(name, ErrNoVar)
+	}
+	switch v := v.(type) {
+	case float64:
+		return v, nil
+	case int:
+		return float64(v), nil
+	case uint:
+		return float64(v), nil
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		return f, varError(name, err)
+	}
+	return 0, typeError(name, v, "float64")
+}
+
+// Bool method returns the variable as bool.
+func (vars TypedVars) Bool(name string) (bool, error) {
+	v, ok := vars[name]
+	if !ok {
+		return false, varError(name, ErrNoVar)
+	}
+	switch v := v.(type) {
+	case bool:
+		return v, nil
+	case string:
+		b, err := strconv.ParseBool(v)
+		return b, varError(name, err)
+	}
+	return false, typeError(name, v, "bool")
+}
+
+// Time method returns the variable as time.Time. String values are parsed as
+// RFC 3339 timestamps or dates.
+func (vars TypedVars) Time(name string) (time.Time, error) {
+	v, ok := vars[name]
+	if !ok {
+		return time.Time{}, varError(name, ErrNoVar)
+	}
+	switch v := v.(type) {
+	case time.Time:
+		return v, nil
+	case string:
+		if t, err := time.Parse(time.RFC3339, v); err == nil {
+			return t, nil
+		}
+		t, err := time.Parse("2006-01-02", v)
+		return t, varError(name, err)
+	}
+	return time.Time{}, typeError(name, v, "time.Time")
+}
+
+// MustString method is like String but panics on error.
+func (vars TypedVars) MustString(name string) string {
+	s, err := vars.String(name)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// MustInt method is like Int but panics on error.
+func (vars TypedVars) MustInt(name string) int {
+	n, err := vars.Int(name)
+	if err != nil {
+		panic(err)
+	}
+	return n
+}
+
+// MustUint method is like Uint but panics on error.
+func (vars TypedVars) MustUint(name string) uint {
+	n, err := vars.Uint(name)
+	if err != nil {
+		panic(err)
+	}
+	return n
+}
+
+// MustFloat method is like Float but panics on error.
+func (vars TypedVars) MustFloat(name string) float64 {
+	f, err := vars.Float(name)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+
+// MustTime method is like Time but panics on error.
+func (vars TypedVars) MustTime(name string) time.Time {
+	t, err := vars.Time(name)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// varError wraps err with the name of the variable. It returns nil if err is
+// nil.
+func varError(name string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return fmt.Errorf("path variable %s: %w", name, err)
+}
+
+// typeError returns an error that tells that the variable can't be converted
+// to the requested type.
+func typeError(name string, v interface{}, typ string) error {
+	return fmt.Errorf("path variable %s: can't convert %T to %s", name, v, typ)
+}
@@ -0,0 +1,61 @@
+package mux
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTypedVars(t *testing.T) {
+	var vars TypedVars
+	rtr := New()
+	rtr.Get(`/{id:int}/{n:nat}/{price:float}/{day:date}/{code:\d+}`,
+		func(w http.ResponseWriter, r *http.Request) {
+			vars = VarsOf(r)
+		})
+
+	rec, req, err := request(http.MethodGet, "/-7/42/9.5/2021-03-14/0123", nil)
+	assert.NoError(t, err)
+	rtr.ServeHTTP(rec, req)
+
+	assert.Equal(t, -7, vars.MustInt("id"))
+	assert.Equal(t, uint(42), vars.MustUint("n"))
+	assert.Equal(t, 9.5, vars.MustFloat("price"))
+	assert.Equal(t, time.Date(2021, 3, 14, 0, 0, 0, 0, time.UTC),
+		vars.MustTime("day"))
+	assert.Equal(t, "0123", vars.MustString("code"))
+	assert.Equal(t, 123, vars.MustInt("code"))
+	assert.Equal(t, "42", vars.MustString("n"))
+
+	_, err = vars.Int("missing")
+	assert.True(t, errors.Is(err, ErrNoVar))
+	_, err = vars.Uint("id")
+	assert.Error(t, err)
+	_, err = vars.Time("price")
+	assert.Error(t, err)
+	assert.Panics(t, func() { vars.MustInt("price") })
+	//-------------------- Another Test Case --------------------
+	rtr.Get("/big/{n:nat}", func(w http.ResponseWriter, r *http.Request) {
+		vars = VarsOf(r)
+	})
+	rec, req, err = request(http.MethodGet, "/big/18446744073709551615", nil)
+	assert.NoError(t, err)
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, uint(math.MaxUint), vars.MustUint("n"))
+	_, err = vars.Int("n")
+	assert.True(t, errors.Is(err, strconv.ErrRange))
+}
+
+func TestVarsOfWithoutVars(t *testing.T) {
+	req, err := http.NewRequest(http.MethodGet, "/", nil)
+	assert.NoError(t, err)
+	vars := VarsOf(req)
+	assert.False(t, vars.Has("id"))
+	_, err = vars.String("id")
+	assert.True(t, errors.Is(err, ErrNoVar))
+}
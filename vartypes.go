@@ -0,0 +1,110 @@
+package mux
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// VarConverter converts the raw value of a path variable into the value stored
+// in Vars. If it returns an error, the request does not match the route.
+type VarConverter func(value string) (interface{}, error)
+
+// varType describes a named path variable type such as "int" or "date".
+type varType struct {
+	// pattern is a regular expression that matches the values of the type.
+	pattern string
+
+	// convert converts the values of the type; nil means that values are
+	// stored as strings.
+	convert VarConverter
+
+	// validate tells whether convert may fail for values that matched the
+	// pattern, so it must be invoked in order to match the route.
+	validate bool
+}
+
+var (
+	// varTypesMu guards varTypes.
+	varTypesMu sync.RWMutex
+
+	// varTypes is the registry of named path variable types.
+	varTypes = map[string]varType{
+		"int": {`-?[1-9]\d*|0`, func(v string) (interface{}, error) {
+			return strconv.Atoi(v)
+		}, true},
+
+		"nat": {`[1-9]\d*|0`, func(v string) (interface{}, error) {
+			n, err := strconv.ParseUint(v, 10, 0)
+			return uint(n), err
+		}, true},
+
+		"float": {`-?\d+(\.\d+)?`, func(v string) (interface{}, error) {
+			return strconv.ParseFloat(v, 64)
+		}, true},
+
+		"bool": {`true|false|1|0`, func(v string) (interface{}, error) {
+			return strconv.ParseBool(v)
+		}, false},
+
+		"str": {`[a-zA-Z_]+`, nil, false},
+
+		"segment": {`[^/]+`, nil, false},
+
+		"date": {`\d{4}-\d{2}-\d{2}`, func(v string) (interface{}, error) {
+			return time.Parse("2006-01-02", v)
+		}, true},
+
+		"rfc3339": {
+			`\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:\d{2})`,
+			func(v string) (interface{}, error) {
+				return time.Parse(time.RFC3339, v)
+			},
+			true,
+		},
+	}
+)
+
+// RegisterVarType registers a named path variable type that can be used in
+// any path template registered afterwards. The pattern is a regular expression
+// that matches the values of the type (it must not match slashes); convert,
+// if not nil, converts the values that are then stored in Vars. Values that
+// convert fails on do not match the route. For example:
+//
+//	mux.RegisterVarType("slug", `[a-z0-9]+(-[a-z0-9]+)*`, nil)
+//	mux.RegisterVarType("semver", `\d+\.\d+\.\d+`, parseSemver)
+//
+//	rtr.Get("/posts/{post:slug}", showPost)
+//
+// RegisterVarType panics if the name is not a word, if the pattern can't be
+// compiled, or if the name is reserved ("*" or "enum").
+func RegisterVarType(name string, pattern string, convert VarConverter) {
+	if !regexp.MustCompile(`^\w+$`).MatchString(name) || name == "enum" {
+		panic(fmt.Sprintf("invalid variable type name %q", name))
+	}
+	if _, err := regexp.Compile(pattern); err != nil {
+		panic(fmt.Sprintf("can't compile regex %s: %v", pattern, err))
+	}
+
+	varTypesMu.Lock()
+	defer varTypesMu.Unlock()
+	varTypes[name] = varType{pattern, convert, convert != nil}
+}
+
+// VarTypePattern returns the regular expression that matches the values of the
+// named path variable type and a boolean flag that tells whether such type is
+// registered.
+func VarTypePattern(name string) (pattern string, ok bool) {
+	typ, ok := lookupVarType(name)
+	return typ.pattern, ok
+}
+
+// lookupVarType returns the named path variable type from the registry.
+func lookupVarType(name string) (varType, bool) {
+	varTypesMu.RLock()
+	defer varTypesMu.RUnlock()
+	typ, ok := varTypes[name]
+	return typ, ok
+}
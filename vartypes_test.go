@@ -0,0 +1,82 @@
+package mux
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterVarType(t *testing.T) {
+	RegisterVarType("slug", `[a-z0-9]+(-[a-z0-9]+)*`, nil)
+	RegisterVarType("upper", `[a-zA-Z]+`, func(v string) (interface{}, error) {
+		if v == "forbidden" {
+			return nil, errors.New("forbidden value")
+		}
+		return strings.ToUpper(v), nil
+	})
+
+	var vars map[string]interface{}
+	rtr := New()
+	rtr.Get("/posts/{post:slug}/{tag:upper}",
+		func(w http.ResponseWriter, r *http.Request) {
+			vars, _ = Vars(r)
+		})
+
+	rec, req, err := request(http.MethodGet, "/posts/hello-world/go", nil)
+	assert.NoError(t, err)
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "hello-world", vars["post"])
+	assert.Equal(t, "GO", vars["tag"])
+
+	for _, path := range []string{
+		"/posts/Hello_World/go",
+		"/posts/hello-world/forbidden",
+	} {
+		rec, req, err = request(http.MethodGet, path, nil)
+		assert.NoError(t, err)
+		rtr.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusNotFound, rec.Code, path)
+	}
+	//-------------------- Another Test Case --------------------
+	vars = nil
+	rtr.Get("/u/{id:int}/{name:str}",
+		func(w http.ResponseWriter, r *http.Request) {
+			vars, _ = Vars(r)
+		})
+	rec, req, err = request(http.MethodGet, "/u/99999999999999999999999/bob",
+		nil)
+	assert.NoError(t, err)
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Nil(t, vars)
+	for _, typ := range []string{"int", "nat", "float"} {
+		fil := NewPathFilter("/{v:" + typ + "}")
+		_, req, err = request(http.MethodGet, "/1"+strings.Repeat("0", 400),
+			nil)
+		assert.NoError(t, err)
+		assert.False(t, fil.Match(req), typ)
+	}
+	//-------------------- Another Test Case --------------------
+	root := New().Path("/u/{id:int}").HandleFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			vars, _ = Vars(r)
+		})
+	vars = nil
+	rec, req, err = request(http.MethodGet, "/u/99999999999999999999999",
+		nil)
+	assert.NoError(t, err)
+	root.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Nil(t, vars)
+	//-------------------- Another Test Case --------------------
+	pattern, ok := VarTypePattern("slug")
+	assert.True(t, ok)
+	assert.Equal(t, `[a-z0-9]+(-[a-z0-9]+)*`, pattern)
+
+	assert.Panics(t, func() { RegisterVarType("enum", `.*`, nil) })
+	assert.Panics(t, func() { RegisterVarType("bad", `(`, nil) })
+}
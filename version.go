@@ -0,0 +1,173 @@
+package mux
+
+import (
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// DefaultVersionHeader is the header that carries API version unless
+// specified otherwise.
+const DefaultVersionHeader = "Api-Version"
+
+// VersionOptions configures how API version of the request is determined.
+type VersionOptions struct {
+	// Vendor is the vendor name in media types of the Accept header, e.g.
+	// "myapp" for "application/vnd.myapp.v2+json". Empty means any vendor.
+	Vendor string
+
+	// Header is the header that carries the version, e.g. "X-API-Version".
+	// Empty means DefaultVersionHeader. The header takes precedence over
+	// the Accept header.
+	Header string
+
+	// Default is the version of requests that specify none.
+	Default string
+}
+
+// VersionFilter takes care of filtering requests by the API version they ask
+// for (see RequestVersion). Versions are compared case-insensitively, ignoring
+// the leading "v", so "v2" matches "2".
+type VersionFilter struct {
+	Version string
+	Options VersionOptions
+}
+
+// NewVersionFilter returns pointer to a new VersionFilter that matches
+// requests for the version. If opts is nil, defaults are used.
+func NewVersionFilter(version string, opts *VersionOptions) *VersionFilter {
+	fil := &VersionFilter{Version: normalizeVersion(version)}
+	if opts != nil {
+		fil.Options = *opts
+	}
+	return fil
+}
+
+// Match method returns boolean value that tells you whether given request
+// passed the filter. Also, *VersionFilter implements the Filter interface
+// since it has this method.
+func (fil *VersionFilter) Match(r *http.Request) bool {
+	return RequestVersion(r, &fil.Options) == fil.Version
+}
+
+// APIVersion method creates a sub-router for requests that ask for the API
+// version, so that versions of the API share their paths:
+//
+//	opts := &mux.VersionOptions{Vendor: "myapp", Default: "1"}
+//	v1 := rtr.APIVersion("1", opts)
+//	v1.Get("/users", listUsersV1)
+//	v2 := rtr.APIVersion("2", opts)
+//	v2.Get("/users", listUsersV2)
+//
+// Here "Accept: application/vnd.myapp.v2+json" or "Api-Version: 2" get the
+// second version, and requests without version get the first one. Requests
+// for unknown versions match neither.
+func (rtr *Router) APIVersion(version string, opts *VersionOptions) *Router {
+	return rtr.Subrouter().Filter(NewVersionFilter(version, opts))
+}
+
+// RequestVersion returns API version the request asks for without the leading
+// "v", e.g. "2". The version is taken from the version header, then from
+// vendor media types of the Accept header, either from their name (e.g.
+// "application/vnd.myapp.v2+json") or from their "version" parameter (e.g.
+// "application/vnd.myapp+json; version=2"). If the request has none,
+// the default version is returned. If opts is nil, defaults are used.
+func RequestVersion(r *http.Request, opts *VersionOptions) string {
+	if opts == nil {
+		opts = &VersionOptions{}
+	}
+	header := opts.Header
+	if header == "" {
+		header = DefaultVersionHeader
+	}
+	if v := strings.TrimSpace(r.Header.Get(header)); v != "" {
+		return normalizeVersion(v)
+	}
+	for _, accept := range r.Header.Values("Accept") {
+		for _, typ := range strings.Split(accept, ",") {
+			if v, ok := mediaTypeVersion(typ, opts.Vendor); ok {
+				return normalizeVersion(v)
+			}
+		}
+	}
+	return normalizeVersion(opts.Default)
+}
+
+// mediaTypeVersion returns the version of the vendor media type. It reports
+// false if the type isn't a media type of the vendor or has no version.
+func mediaTypeVersion(typ, vendor string) (string, bool) {
+	typ, params, err := mime.ParseMediaType(typ)
+	if err != nil {
+		return "", false
+	}
+	_, sub, _ := strings.Cut(typ, "/")
+	name, ok := strings.CutPrefix(sub, "vnd.")
+	if !ok {
+		return "", false
+	}
+	name, _, _ = strings.Cut(name, "+")
+
+	// The version is the rest of the name after the vendor, e.g. "v2.1" in
+	// "vnd.myapp.v2.1".
+	version := params["version"]
+	parts := strings.Split(name, ".")
+	for i := 1; i < len(parts); i++ {
+		if isVersion(parts[i]) {
+			name = strings.Join(parts[:i], ".")
+			version = strings.Join(parts[i:], ".")
+			break
+		}
+	}
+	if vendor != "" && !strings.EqualFold(name, vendor) {
+		return "", false
+	}
+	return version, version != ""
+}
+
+// isVersion tells whether the segment of media type name is a version, i.e.
+// "v" followed by a digit.
+func isVersion(s string) bool {
+	return len(s) > 1 && (s[0] == 'v' || s[0] == 'V') &&
+		s[1] >= '0' && s[1] <= '9'
+}
+
+// normalizeVersion returns the version in lower case without the leading "v".
+func normalizeVersion(v string) string {
+	v = strings.ToLower(strings.TrimSpace(v))
+	if isVersion(v) {
+		return v[1:]
+	}
+	return v
+}
+
+// Versions method creates sub-routers for the versions of the API, listed
+// from the oldest to the newest, under path prefixes named after them. Each
+// version serves its own routes first and falls back to the routes of the
+// previous versions, so that unchanged endpoints don't have to be registered
+// again:
+//
+//	vs := rtr.Versions("v1", "v2", "v3")
+//	vs[0].Get("/users", listUsers)
+//	vs[0].Get("/posts", listPosts)
+//	vs[1].Get("/posts", listPostsV2)
+//	vs[2].Get("/comments", listComments)
+//
+// Here "/v3/posts" is served by listPostsV2 and "/v3/users" by listUsers. As a
+// consequence, an endpoint can't be removed in a newer version. Middleware of
+// each sub-router applies to its own routes wherever they are served. Versions
+// are groups (see Route), so requests that none of the versions can serve fall
+// through to the following siblings.
+func (rtr *Router) Versions(versions ...string) []*Router {
+	routers := make([]*Router, len(versions))
+	for i, version := range versions {
+		prefix := "/" + strings.Trim(version, "/")
+		rtr.Route(prefix, func(sub *Router) {
+			routers[i] = sub.detached()
+			routers[i].group = true
+			for j := i; j >= 0; j-- {
+				sub.attach(routers[j])
+			}
+		})
+	}
+	return routers
+}
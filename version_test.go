@@ -0,0 +1,131 @@
+package mux
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestVersion(t *testing.T) {
+	opts := &VersionOptions{Vendor: "myapp", Default: "v1"}
+	cases := []struct {
+		header, accept string
+		version        string
+	}{
+		{"", "", "1"},
+		{"", "application/json", "1"},
+		{"", "application/vnd.myapp.v2+json", "2"},
+		{"", "text/html, application/vnd.myapp.V3+json;q=0.9", "3"},
+		{"", "application/vnd.myapp+json; version=4", "4"},
+		{"", "application/vnd.myapp.v2.1+json", "2.1"},
+		{"", "application/vnd.other.v2+json", "1"},
+		{"", "application/vnd.myapp+json", "1"},
+		{"v5", "application/vnd.myapp.v2+json", "5"},
+	}
+	for _, c := range cases {
+		r, _ := http.NewRequest(http.MethodGet, "/", nil)
+		if c.header != "" {
+			r.Header.Set("Api-Version", c.header)
+		}
+		if c.accept != "" {
+			r.Header.Set("Accept", c.accept)
+		}
+		assert.Equal(t, c.version, RequestVersion(r, opts), c.accept)
+	}
+	//-------------------- Another Test Case --------------------
+	r, _ := http.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Accept", "application/vnd.github.v3+json")
+	r.Header.Set("Api-Version", "9")
+	assert.Equal(t, "3", RequestVersion(r, &VersionOptions{Header: "X-V"}))
+	assert.Equal(t, "9", RequestVersion(r, nil))
+}
+
+func TestAPIVersion(t *testing.T) {
+	rtr := New()
+	opts := &VersionOptions{Vendor: "myapp", Default: "1"}
+	rtr.APIVersion("v1", opts).Get("/users", func(
+		w http.ResponseWriter, r *http.Request,
+	) {
+		Text(w, http.StatusOK, "v1")
+	})
+	rtr.APIVersion("v2", opts).Get("/users", func(
+		w http.ResponseWriter, r *http.Request,
+	) {
+		Text(w, http.StatusOK, "v2")
+	})
+
+	cases := []struct {
+		accept string
+		code   int
+		body   string
+	}{
+		{"", http.StatusOK, "v1"},
+		{"application/vnd.myapp.v1+json", http.StatusOK, "v1"},
+		{"application/vnd.myapp.v2+json", http.StatusOK, "v2"},
+		{"application/vnd.myapp.v3+json", http.StatusNotFound, ""},
+	}
+	for _, c := range cases {
+		rec, req, err := request(http.MethodGet, "/users", nil)
+		assert.NoError(t, err)
+		if c.accept != "" {
+			req.Header.Set("Accept", c.accept)
+		}
+		rtr.ServeHTTP(rec, req)
+		assert.Equal(t, c.code, rec.Code, c.accept)
+		if c.body != "" {
+			assert.Equal(t, c.body, rec.Body.String())
+		}
+	}
+}
+
+func TestVersions(t *testing.T) {
+	text := func(s string) View {
+		return func(w http.ResponseWriter, r *http.Request) {
+			Text(w, http.StatusOK, s)
+		}
+	}
+	rtr := New()
+	vs := rtr.Versions("v1", "v2", "/v3/")
+	vs[0].Get("/users", text("users v1"))
+	vs[0].Get("/posts", text("posts v1"))
+	vs[0].Post("/posts", text("create v1"))
+	vs[1].Get("/posts", text("posts v2"))
+	vs[2].Get("/comments/{id:int}", text("comment v3"))
+	vs[1].UseFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Version", "2")
+	})
+	rtr.Get("/v2/about", text("about"))
+
+	cases := []struct {
+		method, path string
+		code         int
+		body         string
+	}{
+		{http.MethodGet, "/v1/users", http.StatusOK, "users v1"},
+		{http.MethodGet, "/v1/posts", http.StatusOK, "posts v1"},
+		{http.MethodGet, "/v1/comments/1", http.StatusNotFound, ""},
+		{http.MethodGet, "/v2/users", http.StatusOK, "users v1"},
+		{http.MethodGet, "/v2/posts", http.StatusOK, "posts v2"},
+		{http.MethodPost, "/v2/posts", http.StatusOK, "create v1"},
+		{http.MethodGet, "/v2/about", http.StatusOK, "about"},
+		{http.MethodGet, "/v3/users", http.StatusOK, "users v1"},
+		{http.MethodGet, "/v3/posts", http.StatusOK, "posts v2"},
+		{http.MethodGet, "/v3/comments/1", http.StatusOK, "comment v3"},
+		{http.MethodGet, "/v3/comments/x", http.StatusNotFound, ""},
+		{http.MethodGet, "/v4/users", http.StatusNotFound, ""},
+	}
+	for _, c := range cases {
+		rec, req, err := request(c.method, c.path, nil)
+		assert.NoError(t, err)
+		rtr.ServeHTTP(rec, req)
+		assert.Equal(t, c.code, rec.Code, c.path)
+		if c.body != "" {
+			assert.Equal(t, c.body, rec.Body.String(), c.path)
+		}
+	}
+	//-------------------- Another Test Case --------------------
+	rec, req, _ := request(http.MethodGet, "/v3/posts", nil)
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, "2", rec.Header().Get("X-Version"))
+}
@@ -0,0 +1,193 @@
+package mux
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// RouteInfo describes a single node of the routing tree. It is passed to the
+// function given to Router.Walk.
+type RouteInfo struct {
+	// Router is the node that is being described.
+	Router *Router
+
+	// Name is the name set by Router.Name (may be empty).
+	Name string
+
+	// Methods is a sorted list of methods accepted by the router. It is nil
+	// if the router has no methods filter.
+	Methods []string
+
+	// Hosts is a sorted list of hosts accepted by the router. It is nil if
+	// the router has no host filter.
+	Hosts []string
+
+	// Path is the path template of the router's PathFilter (e.g.
+	// "/users/{id:int}"). It is empty if the router has no path filter.
+	Path string
+
+	// Prefixes is the chain of path prefixes set on this router and its
+	// parents, outermost first.
+	Prefixes []string
+
+	// Handler is the router's handler (may be nil).
+	Handler http.Handler
+
+	// Depth is the depth of the router in the tree; root's depth is zero.
+	Depth int
+
+	// Disabled tells whether the router is switched off with Disable.
+	Disabled bool
+
+	// Source is the location (file:line) of the code that created the router
+	// with Subrouter or one of the shortcuts. It is empty for the root.
+	Source string
+}
+
+// Template returns the full path template of the route: the concatenation of
+// all its prefixes and its path.
+func (info *RouteInfo) Template() string {
+	return strings.Join(info.Prefixes, "") + info.Path
+}
+
+// Walk method traverses the routing tree depth-first, starting with the Router
+// it was called on, and calls fn for every node in the order in which the
+// routes are matched. If fn returns an error, Walk stops and returns it.
+//
+// Walk allows applications to generate docs, metrics labels or permission
+// tables from the routing tree:
+//
+//	rtr.Walk(func(route *mux.RouteInfo) error {
+//	    fmt.Println(route.Methods, route.Template())
+//	    return nil
+//	})
+func (rtr *Router) Walk(fn func(route *RouteInfo) error) error {
+	return rtr.walk(fn, nil, 0)
+}
+
+// walk method is a recursive helper for Walk.
+func (rtr *Router) walk(
+	fn func(route *RouteInfo) error, prefixes []string, depth int,
+) error {
+	info := rtr.info(prefixes, depth)
+	if err := fn(info); err != nil {
+		return err
+	}
+	for _, route := range rtr.ordered() {
+		if err := route.walk(fn, info.Prefixes, depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// info method returns RouteInfo describing the Router given its parents'
+// prefixes and its depth.
+func (rtr *Router) info(prefixes []string, depth int) *RouteInfo {
+	info := &RouteInfo{
+		Router:   rtr,
+		Name:     rtr.name,
+		Methods:  nil,
+		Hosts:    nil,
+		Path:     "",
+		Prefixes: append([]string(nil), prefixes...),
+		Handler:  rtr.handler,
+		Depth:    depth,
+		Disabled: !rtr.Enabled(),
+		Source:   rtr.source,
+	}
+	if rtr.filters.Methods != nil {
+		info.Methods = rtr.filters.Methods.Methods.Items()
+	}
+	if rtr.filters.Host != nil {
+		info.Hosts = rtr.filters.Host.Hosts.Items()
+	}
+	if rtr.filters.Path != nil {
+		info.Path = rtr.filters.Path.Path
+	}
+	if rtr.filters.PathPrefix != nil {
+		info.Prefixes = append(info.Prefixes, string(*rtr.filters.PathPrefix))
+	}
+	return info
+}
+
+// Info method returns RouteInfo describing the Router within the tree it was
+// created in.
+func (rtr *Router) Info() *RouteInfo {
+	var parents []*Router
+	for p := rtr.parent; p != nil; p = p.parent {
+		parents = append(parents, p)
+	}
+	var prefixes []string
+	for i := len(parents) - 1; i >= 0; i-- {
+		if prefix := parents[i].filters.PathPrefix; prefix != nil {
+			prefixes = append(prefixes, string(*prefix))
+		}
+	}
+	return rtr.info(prefixes, len(parents))
+}
+
+// routeRecord is a record of the route that served the request and the error
+// it failed with. It is shared by all copies of the request made while it is
+// routed, possibly in other goroutines (see Timeout).
+type routeRecord struct {
+	route atomic.Pointer[Router]
+	err   atomic.Pointer[error]
+
+	// experiments maps experiments to the variants the request was assigned
+	// to. Guarded by mu.
+	mu          sync.Mutex
+	experiments map[string]string
+}
+
+// RecordRoute returns a copy of request that records the route that serves
+// it and the error reported with Error, if any. They are reported by
+// MatchedRoute and ReportedError afterwards. Middleware that reports routes
+// (see AccessLog) should call it before passing the request on.
+func RecordRoute(r *http.Request) *http.Request {
+	if _, ok := r.Context().Value(routeKey).(*routeRecord); ok {
+		return r
+	}
+	return r.WithContext(
+		context.WithValue(r.Context(), routeKey, &routeRecord{}),
+	)
+}
+
+// MatchedRoute returns RouteInfo of the route whose handler served the
+// request returned by RecordRoute, or nil if the request wasn't served by any
+// route (e.g. it got "404 Not Found").
+func MatchedRoute(r *http.Request) *RouteInfo {
+	rec, ok := r.Context().Value(routeKey).(*routeRecord)
+	if !ok || rec.route.Load() == nil {
+		return nil
+	}
+	return rec.route.Load().Info()
+}
+
+// matched records rtr as the route that serves the request, if requested.
+func matched(r *http.Request, rtr *Router) {
+	if rec, ok := r.Context().Value(routeKey).(*routeRecord); ok {
+		rec.route.Store(rtr)
+	}
+}
+
+// ReportedError returns the first error reported with Error while serving the
+// request returned by RecordRoute, or nil if there was none.
+func ReportedError(r *http.Request) error {
+	rec, ok := r.Context().Value(routeKey).(*routeRecord)
+	if !ok || rec.err.Load() == nil {
+		return nil
+	}
+	return *rec.err.Load()
+}
+
+// reported records err as the error the request failed with, if requested
+// and no other error was recorded before.
+func reported(r *http.Request, err error) {
+	if rec, ok := r.Context().Value(routeKey).(*routeRecord); ok {
+		rec.err.CompareAndSwap(nil, &err)
+	}
+}
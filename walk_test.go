@@ -0,0 +1,89 @@
+package mux
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWalk(t *testing.T) {
+	rtr := New()
+	api := rtr.Subrouter().PathPrefix("/api").Name("api")
+	v1 := api.Subrouter().PathPrefix("/v1")
+	v1.Get("/users/{id:int}", func(w http.ResponseWriter, r *http.Request) {}).
+		Name("user")
+	v1.Post("/users", func(w http.ResponseWriter, r *http.Request) {})
+	rtr.Get("/health", func(w http.ResponseWriter, r *http.Request) {})
+
+	var templates []string
+	var names []string
+	err := rtr.Walk(func(route *RouteInfo) error {
+		if route.Handler != nil {
+			templates = append(templates, route.Methods[0]+" "+route.Template())
+		}
+		if route.Name != "" {
+			names = append(names, route.Name)
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"GET /api/v1/users/{id:int}",
+		"POST /api/v1/users",
+		"GET /health",
+	}, templates)
+	assert.Equal(t, []string{"api", "user"}, names)
+	//-------------------- Another Test Case --------------------
+	stop := errors.New("stop")
+	visited := 0
+	err = rtr.Walk(func(route *RouteInfo) error {
+		visited++
+		if route.Depth == 2 {
+			return stop
+		}
+		return nil
+	})
+	assert.Equal(t, stop, err)
+	assert.Equal(t, 3, visited)
+}
+
+func TestInfo(t *testing.T) {
+	rtr := New()
+	api := rtr.Subrouter().PathPrefix("/api")
+	users := api.Get("/users/{id:int}",
+		func(w http.ResponseWriter, r *http.Request) {})
+
+	info := users.Info()
+	assert.Equal(t, "/api/users/{id:int}", info.Template())
+	assert.Equal(t, 2, info.Depth)
+	assert.Equal(t, []string{http.MethodGet}, info.Methods)
+	assert.Equal(t, 0, rtr.Info().Depth)
+}
+
+func TestReportedError(t *testing.T) {
+	var reported error
+	rtr := New().Wrap(func(next http.Handler) http.Handler {
+		return View(func(w http.ResponseWriter, r *http.Request) {
+			r = RecordRoute(r)
+			next.ServeHTTP(w, r)
+			reported = ReportedError(r)
+		})
+	})
+	rtr.Get("/ok", func(w http.ResponseWriter, r *http.Request) {})
+	rtr.Get("/fail", func(w http.ResponseWriter, r *http.Request) {
+		Error(w, r, errors.New("boom"))
+		Error(w, r, errors.New("second"))
+	})
+
+	rec, req, err := request(http.MethodGet, "/fail", nil)
+	assert.NoError(t, err)
+	rtr.ServeHTTP(rec, req)
+	assert.EqualError(t, reported, "boom")
+
+	rec, req, err = request(http.MethodGet, "/ok", nil)
+	assert.NoError(t, err)
+	rtr.ServeHTTP(rec, req)
+	assert.NoError(t, reported)
+}
@@ -0,0 +1,275 @@
+package mux
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultWebhookTolerance is how far the timestamp of a signed webhook request
+// may be from the current time unless specified otherwise.
+const DefaultWebhookTolerance = 5 * time.Minute
+
+// DefaultMaxWebhookBytes is the maximum size of webhook request body unless
+// specified otherwise.
+const DefaultMaxWebhookBytes = 1 << 20
+
+// WebhookSignature is what WebhookScheme finds in a signed request.
+type WebhookSignature struct {
+	// Message is the data that was signed; usually the body, sometimes with
+	// the timestamp prepended to it.
+	Message []byte
+
+	// Signatures are the decoded signatures sent with the request. The
+	// request is valid if any of them matches.
+	Signatures [][]byte
+
+	// Timestamp is the time the request was signed at. It is zero if the
+	// scheme doesn't sign timestamps.
+	Timestamp time.Time
+}
+
+// WebhookScheme describes the way a webhook sender signs its requests.
+type WebhookScheme struct {
+	// Hash is the hash function used with HMAC, e.g. sha256.New.
+	Hash func() hash.Hash
+
+	// Parse extracts the signature from the request given its body. An error
+	// is returned if the request isn't signed or the signature is malformed.
+	Parse func(r *http.Request, body []byte) (*WebhookSignature, error)
+}
+
+// HMACWebhook returns pointer to a WebhookScheme for senders that put the
+// hex-encoded HMAC of the body in the header, after the prefix.
+func HMACWebhook(header, prefix string, h func() hash.Hash) *WebhookScheme {
+	return &WebhookScheme{h, func(r *http.Request, body []byte) (
+		*WebhookSignature, error,
+	) {
+		sig, err := hexSignature(r.Header.Get(header), prefix)
+		if err != nil {
+			return nil, err
+		}
+		return &WebhookSignature{body, [][]byte{sig}, time.Time{}}, nil
+	}}
+}
+
+// GitHubWebhook is the scheme of GitHub webhooks: the "X-Hub-Signature-256"
+// header holds "sha256=" and HMAC-SHA256 of the body.
+var GitHubWebhook = HMACWebhook("X-Hub-Signature-256", "sha256=", sha256.New)
+
+// StripeWebhook is the scheme of Stripe webhooks: the "Stripe-Signature"
+// header holds the timestamp and one or more signatures, e.g.
+// "t=1492774577,v1=5257a869...", of the timestamp and the body joined by dot.
+var StripeWebhook = &WebhookScheme{sha256.New, parseStripe}
+
+// SlackWebhook is the scheme of Slack requests: the "X-Slack-Signature"
+// header holds "v0=" and the signature of "v0:<timestamp>:<body>", where the
+// timestamp is taken from the "X-Slack-Request-Timestamp" header.
+var SlackWebhook = &WebhookScheme{sha256.New, parseSlack}
+
+// WebhookOptions configures Webhook.
+type WebhookOptions struct {
+	// Scheme is the way requests are signed. Nil means GitHubWebhook.
+	Scheme *WebhookScheme
+
+	// Tolerance is how far the signed timestamp may be from the current
+	// time, which protects from replayed requests. Zero means
+	// DefaultWebhookTolerance; negative values disable the check.
+	Tolerance time.Duration
+
+	// MaxBytes is the maximum size of the request body. Zero means
+	// DefaultMaxWebhookBytes; negative values remove the limit.
+	MaxBytes int64
+}
+
+// Webhook returns Middleware that lets through only the requests signed with
+// the secret, e.g.
+//
+//	rtr.Subrouter().Path("/hooks/stripe").
+//	    Wrap(mux.Webhook(secret, &mux.WebhookOptions{
+//	        Scheme: mux.StripeWebhook,
+//	    })).
+//	    HandleFunc(handleStripeEvent)
+//
+// The body is read in full to check the signature and handlers get a copy of
+// it to read again. Requests that aren't signed, or are signed wrong or too
+// long ago, get "401 Unauthorized" through Error; bodies over the limit get
+// "413 Request Entity Too Large". If opts is nil, defaults are used.
+func Webhook(secret string, opts *WebhookOptions) Middleware {
+	if opts == nil {
+		opts = &WebhookOptions{}
+	}
+	scheme := opts.Scheme
+	if scheme == nil {
+		scheme = GitHubWebhook
+	}
+	tolerance := opts.Tolerance
+	if tolerance == 0 {
+		tolerance = DefaultWebhookTolerance
+	}
+	limit := opts.MaxBytes
+	if limit == 0 {
+		limit = DefaultMaxWebhookBytes
+	}
+	key := []byte(secret)
+
+	return func(next http.Handler) http.Handler {
+		return View(func(w http.ResponseWriter, r *http.Request) {
+			body, err := readBody(r, limit)
+			if err != nil {
+				Error(w, r, err)
+				return
+			}
+			sig, err := scheme.Parse(r, body)
+			if err != nil {
+				Error(w, r, &HTTPError{http.StatusUnauthorized, err})
+				return
+			}
+			if !sig.Timestamp.IsZero() && tolerance > 0 &&
+				absDuration(time.Since(sig.Timestamp)) > tolerance {
+				Error(w, r, NewHTTPError(http.StatusUnauthorized,
+					"signature timestamp is out of tolerance"))
+				return
+			}
+			mac := hmac.New(scheme.Hash, key)
+			mac.Write(sig.Message)
+			if !anyEqual(mac.Sum(nil), sig.Signatures) {
+				Error(w, r, NewHTTPError(http.StatusUnauthorized,
+					"invalid signature"))
+				return
+			}
+
+			r = r.WithContext(r.Context())
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			r.ContentLength = int64(len(body))
+			r.GetBody = func() (io.ReadCloser, error) {
+				return io.NopCloser(bytes.NewReader(body)), nil
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// readBody reads the request body that must not be larger than limit, unless
+// limit is negative.
+func readBody(r *http.Request, limit int64) ([]byte, error) {
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil, nil
+	}
+	var body io.Reader = r.Body
+	if limit > 0 {
+		// Read one extra byte to tell whether the limit was exceeded.
+		body = io.LimitReader(r.Body, limit+1)
+	}
+	b, err := io.ReadAll(body)
+	if err != nil {
+		return nil, NewHTTPError(http.StatusBadRequest,
+			"can't read request body: %v", err)
+	}
+	if limit > 0 && int64(len(b)) > limit {
+		return nil, NewHTTPError(http.StatusRequestEntityTooLarge,
+			"request body must not be larger than %d bytes", limit)
+	}
+	return b, nil
+}
+
+// anyEqual tells whether any of the signatures equals mac. Signatures are
+// compared in constant time.
+func anyEqual(mac []byte, signatures [][]byte) bool {
+	ok := false
+	for _, sig := range signatures {
+		if hmac.Equal(mac, sig) {
+			ok = true
+		}
+	}
+	return ok
+}
+
+// hexSignature decodes hex signature that follows the prefix in value.
+func hexSignature(value, prefix string) ([]byte, error) {
+	if value == "" {
+		return nil, errors.New("missing signature")
+	}
+	if !strings.HasPrefix(value, prefix) {
+		return nil, errors.New("malformed signature")
+	}
+	sig, err := hex.DecodeString(value[len(prefix):])
+	if err != nil {
+		return nil, errors.New("malformed signature")
+	}
+	return sig, nil
+}
+
+// unixTime parses the timestamp given in seconds since the epoch.
+func unixTime(value string) (time.Time, error) {
+	sec, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, errors.New("malformed signature timestamp")
+	}
+	return time.Unix(sec, 0), nil
+}
+
+// parseStripe implements WebhookScheme.Parse for StripeWebhook.
+func parseStripe(r *http.Request, body []byte) (*WebhookSignature, error) {
+	header := r.Header.Get("Stripe-Signature")
+	if header == "" {
+		return nil, errors.New("missing signature")
+	}
+	var ts string
+	sig := new(WebhookSignature)
+	for _, part := range strings.Split(header, ",") {
+		k, v, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch k {
+		case "t":
+			ts = v
+		case "v1":
+			if b, err := hex.DecodeString(v); err == nil {
+				sig.Signatures = append(sig.Signatures, b)
+			}
+		}
+	}
+	if ts == "" || sig.Signatures == nil {
+		return nil, errors.New("malformed signature")
+	}
+	t, err := unixTime(ts)
+	if err != nil {
+		return nil, err
+	}
+	sig.Timestamp = t
+	sig.Message = append([]byte(ts+"."), body...)
+	return sig, nil
+}
+
+// parseSlack implements WebhookScheme.Parse for SlackWebhook.
+func parseSlack(r *http.Request, body []byte) (*WebhookSignature, error) {
+	b, err := hexSignature(r.Header.Get("X-Slack-Signature"), "v0=")
+	if err != nil {
+		return nil, err
+	}
+	ts := r.Header.Get("X-Slack-Request-Timestamp")
+	t, err := unixTime(ts)
+	if err != nil {
+		return nil, err
+	}
+	return &WebhookSignature{
+		Message:    append([]byte("v0:"+ts+":"), body...),
+		Signatures: [][]byte{b},
+		Timestamp:  t,
+	}, nil
+}
+
+// absDuration returns the absolute value of d.
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
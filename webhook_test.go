@@ -0,0 +1,121 @@
+package mux
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func sign(secret, message string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(message))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func echo(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	w.Write(body)
+}
+
+func TestWebhookGitHub(t *testing.T) {
+	rtr := New()
+	rtr.Subrouter().Path("/hook").
+		Wrap(Webhook("secret", &WebhookOptions{MaxBytes: 16})).
+		HandleFunc(echo)
+
+	cases := []struct {
+		body string
+		sig  string
+		code int
+		resp string
+	}{
+		{`{"ok":true}`, "sha256=" + sign("secret", `{"ok":true}`),
+			http.StatusOK, `{"ok":true}`},
+		{`{"ok":true}`, "", http.StatusUnauthorized, "missing signature\n"},
+		{`{"ok":true}`, "sha256=zz", http.StatusUnauthorized,
+			"malformed signature\n"},
+		{`{"ok":true}`, "sha256=" + sign("wrong", `{"ok":true}`),
+			http.StatusUnauthorized, "invalid signature\n"},
+		{`{"ok":false}`, "sha256=" + sign("secret", `{"ok":true}`),
+			http.StatusUnauthorized, "invalid signature\n"},
+		{`{"ok":true,"x":123}`,
+			"sha256=" + sign("secret", `{"ok":true,"x":123}`),
+			http.StatusRequestEntityTooLarge,
+			"request body must not be larger than 16 bytes\n"},
+	}
+	for _, c := range cases {
+		rec, req, err := request(http.MethodPost, "/hook",
+			strings.NewReader(c.body))
+		assert.NoError(t, err)
+		if c.sig != "" {
+			req.Header.Set("X-Hub-Signature-256", c.sig)
+		}
+		rtr.ServeHTTP(rec, req)
+		assert.Equal(t, c.code, rec.Code, c.body)
+		assert.Equal(t, c.resp, rec.Body.String(), c.body)
+	}
+}
+
+func TestWebhookStripe(t *testing.T) {
+	rtr := New()
+	rtr.Subrouter().Path("/hook").
+		Wrap(Webhook("whsec", &WebhookOptions{Scheme: StripeWebhook})).
+		HandleFunc(echo)
+
+	body := `{"type":"charge.succeeded"}`
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	old := strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10)
+	cases := []struct {
+		header string
+		code   int
+	}{
+		// Any of the signatures may match, e.g. while secrets are rotated.
+		{"t=" + now + ",v1=" + sign("old", now+"."+body) +
+			",v1=" + sign("whsec", now+"."+body), http.StatusOK},
+		{"t=" + now + ",v1=" + sign("whsec", body), http.StatusUnauthorized},
+		{"t=" + old + ",v1=" + sign("whsec", old+"."+body),
+			http.StatusUnauthorized},
+		{"v1=" + sign("whsec", now+"."+body), http.StatusUnauthorized},
+	}
+	for _, c := range cases {
+		rec, req, err := request(http.MethodPost, "/hook",
+			strings.NewReader(body))
+		assert.NoError(t, err)
+		req.Header.Set("Stripe-Signature", c.header)
+		rtr.ServeHTTP(rec, req)
+		assert.Equal(t, c.code, rec.Code, c.header)
+		if c.code == http.StatusOK {
+			assert.Equal(t, body, rec.Body.String())
+		}
+	}
+}
+
+func TestWebhookSlack(t *testing.T) {
+	rtr := New()
+	rtr.Subrouter().Path("/hook").
+		Wrap(Webhook("slack", &WebhookOptions{
+			Scheme:    SlackWebhook,
+			Tolerance: -1,
+		})).
+		HandleFunc(echo)
+
+	body := "token=x&command=/deploy"
+	ts := strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10)
+	rec, req, err := request(http.MethodPost, "/hook",
+		strings.NewReader(body))
+	assert.NoError(t, err)
+	req.Header.Set("X-Slack-Request-Timestamp", ts)
+	req.Header.Set("X-Slack-Signature",
+		"v0="+sign("slack", "v0:"+ts+":"+body))
+	rtr.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, body, rec.Body.String())
+}
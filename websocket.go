@@ -0,0 +1,198 @@
+package mux
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// webSocketGUID is appended to the key of the client to compute the accept
+// key of the handshake (see RFC 6455, section 1.3).
+const webSocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// WebSocketFilter takes care of filtering WebSocket handshake requests: GET
+// requests with "Upgrade: websocket" and "Connection: upgrade" headers. It
+// lets WebSocket endpoints share their paths with ordinary routes.
+type WebSocketFilter struct{}
+
+// Match method returns boolean value that tells you whether given request
+// passed the filter. Also, WebSocketFilter implements the Filter interface
+// since it has this method.
+func (WebSocketFilter) Match(r *http.Request) bool {
+	return IsWebSocket(r)
+}
+
+// IsWebSocket tells whether the request asks to upgrade the connection to
+// WebSocket.
+func IsWebSocket(r *http.Request) bool {
+	return r.Method == http.MethodGet &&
+		headerHasToken(r.Header, "Connection", "upgrade") &&
+		headerHasToken(r.Header, "Upgrade", "websocket")
+}
+
+// WebSocket method creates a sub-router that serves WebSocket handshake
+// requests to the path with the View. Other requests to the path are left to
+// other routes, so the same URL may serve a page and its live updates:
+//
+//	rtr.WebSocket("/chat", func(w http.ResponseWriter, r *http.Request) {
+//	    ws, err := mux.UpgradeWebSocket(w, r, nil)
+//	    if err != nil {
+//	        return
+//	    }
+//	    defer ws.Conn.Close()
+//	    // Read and write WebSocket frames, e.g. with a library.
+//	})
+//	rtr.Get("/chat", chatPage)
+//
+// The View may do the handshake with UpgradeWebSocket or with a WebSocket
+// library.
+func (rtr *Router) WebSocket(path string, v View) *Router {
+	return rtr.Subrouter().
+		Methods(http.MethodGet).
+		Path(path).
+		Filter(WebSocketFilter{}).
+		HandleFunc(v)
+}
+
+// WebSocketOptions configures the WebSocket handshake.
+type WebSocketOptions struct {
+	// Subprotocols are the application protocols supported by the server
+	// in order of preference. The first one requested by the client is
+	// chosen. If none of them is requested, no subprotocol is chosen.
+	Subprotocols []string
+
+	// CheckOrigin tells whether the request comes from an allowed origin.
+	// Nil means that the host of the Origin header, if present, must be the
+	// host of the request, so that other sites can't connect on behalf of
+	// the user (browsers don't apply CORS to WebSocket).
+	CheckOrigin func(r *http.Request) bool
+}
+
+// WebSocketConn is a connection upgraded to WebSocket by UpgradeWebSocket.
+type WebSocketConn struct {
+	// Conn is the underlying network connection. The handler must close
+	// it when done.
+	Conn net.Conn
+
+	// ReadWriter reads from and writes to Conn. Read from it rather than
+	// from Conn, since it may hold the data received along with the
+	// handshake.
+	ReadWriter *bufio.ReadWriter
+
+	// Subprotocol is the subprotocol chosen from WebSocketOptions (may be
+	// empty).
+	Subprotocol string
+}
+
+// UpgradeWebSocket completes the WebSocket handshake (see RFC 6455) and takes
+// over the connection, so that WebSocket frames may be exchanged over it.
+// Framing is left to the caller.
+//
+// If the handshake is invalid, it responds with "400 Bad Request", "403
+// Forbidden" for unknown origins, or "426 Upgrade Required" for unsupported
+// versions of the protocol through Error, and returns the error. If opts is
+// nil, defaults are used.
+func UpgradeWebSocket(
+	w http.ResponseWriter, r *http.Request, opts *WebSocketOptions,
+) (*WebSocketConn, error) {
+	if opts == nil {
+		opts = &WebSocketOptions{}
+	}
+	checkOrigin := opts.CheckOrigin
+	if checkOrigin == nil {
+		checkOrigin = sameOrigin
+	}
+
+	fail := func(err *HTTPError) (*WebSocketConn, error) {
+		Error(w, r, err)
+		return nil, err
+	}
+	if !IsWebSocket(r) {
+		return fail(NewHTTPError(http.StatusBadRequest,
+			"not a websocket handshake"))
+	}
+	if r.Header.Get("Sec-WebSocket-Version") != "13" {
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		return fail(NewHTTPError(http.StatusUpgradeRequired,
+			"unsupported websocket version"))
+	}
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if nonce, err := base64.StdEncoding.DecodeString(key); err != nil ||
+		len(nonce) != 16 {
+		return fail(NewHTTPError(http.StatusBadRequest,
+			"invalid websocket key"))
+	}
+	if !checkOrigin(r) {
+		return fail(NewHTTPError(http.StatusForbidden,
+			"origin not allowed"))
+	}
+	subprotocol := chooseSubprotocol(r, opts.Subprotocols)
+
+	conn, rw, err := http.NewResponseController(w).Hijack()
+	if err != nil {
+		return fail(&HTTPError{http.StatusInternalServerError, err})
+	}
+	// Timeouts of the server don't apply to the upgraded connection.
+	conn.SetDeadline(time.Time{})
+
+	rw.WriteString("HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + webSocketAccept(key) + "\r\n")
+	if subprotocol != "" {
+		rw.WriteString("Sec-WebSocket-Protocol: " + subprotocol + "\r\n")
+	}
+	rw.WriteString("\r\n")
+	if err := rw.Flush(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return &WebSocketConn{conn, rw, subprotocol}, nil
+}
+
+// webSocketAccept returns the accept key of the handshake for the key of the
+// client.
+func webSocketAccept(key string) string {
+	sum := sha1.Sum([]byte(key + webSocketGUID))
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// sameOrigin tells whether the request has no Origin header or the host of
+// its origin is the host of the request.
+func sameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && strings.EqualFold(u.Host, r.Host)
+}
+
+// chooseSubprotocol returns the first of the supported subprotocols that the
+// client requested, or an empty string if there is none.
+func chooseSubprotocol(r *http.Request, supported []string) string {
+	for _, p := range supported {
+		if headerHasToken(r.Header, "Sec-WebSocket-Protocol", p) {
+			return p
+		}
+	}
+	return ""
+}
+
+// headerHasToken tells whether any of the comma-separated values of the
+// header is the token, compared case-insensitively.
+func headerHasToken(h http.Header, key, token string) bool {
+	for _, v := range h.Values(key) {
+		for _, t := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
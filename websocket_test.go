@@ -0,0 +1,97 @@
+package mux
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// handshake is the handshake request from RFC 6455, section 1.2.
+const handshake = "GET /chat HTTP/1.1\r\n" +
+	"Host: example.com\r\n" +
+	"Upgrade: websocket\r\n" +
+	"Connection: keep-alive, Upgrade\r\n" +
+	"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\n" +
+	"Sec-WebSocket-Protocol: chat, superchat\r\n" +
+	"Sec-WebSocket-Version: 13\r\n" +
+	"Origin: http://example.com\r\n" +
+	"\r\n"
+
+func TestWebSocket(t *testing.T) {
+	rtr := New()
+	rtr.WebSocket("/chat", func(w http.ResponseWriter, r *http.Request) {
+		ws, err := UpgradeWebSocket(w, r, &WebSocketOptions{
+			Subprotocols: []string{"superchat", "chat"},
+		})
+		if err != nil {
+			return
+		}
+		defer ws.Conn.Close()
+		line, _ := ws.ReadWriter.ReadString('\n')
+		ws.ReadWriter.WriteString(ws.Subprotocol + ": " + line)
+		ws.ReadWriter.Flush()
+	})
+	rtr.Get("/chat", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("page"))
+	})
+	srv := httptest.NewServer(rtr)
+	defer srv.Close()
+
+	conn, err := net.Dial("tcp", srv.Listener.Addr().String())
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer conn.Close()
+	conn.Write([]byte(handshake + "hello\n"))
+	br := bufio.NewReader(conn)
+	res, err := http.ReadResponse(br, nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, http.StatusSwitchingProtocols, res.StatusCode)
+	assert.Equal(t, "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=",
+		res.Header.Get("Sec-WebSocket-Accept"))
+	assert.Equal(t, "superchat", res.Header.Get("Sec-WebSocket-Protocol"))
+	line, _ := br.ReadString('\n')
+	assert.Equal(t, "superchat: hello\n", line)
+	//-------------------- Another Test Case --------------------
+	res, err = http.Get(srv.URL + "/chat")
+	if assert.NoError(t, err) {
+		res.Body.Close()
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+	}
+}
+
+func TestUpgradeWebSocket(t *testing.T) {
+	cases := []struct {
+		name   string
+		header map[string]string
+		code   int
+	}{
+		{"not websocket", map[string]string{"Upgrade": ""},
+			http.StatusBadRequest},
+		{"version", map[string]string{"Sec-WebSocket-Version": "8"},
+			http.StatusUpgradeRequired},
+		{"key", map[string]string{"Sec-WebSocket-Key": "c2hvcnQ="},
+			http.StatusBadRequest},
+		{"origin", map[string]string{"Origin": "http://evil.com"},
+			http.StatusForbidden},
+	}
+	for _, c := range cases {
+		req, err := http.ReadRequest(bufio.NewReader(
+			strings.NewReader(handshake)))
+		assert.NoError(t, err)
+		for k, v := range c.header {
+			req.Header.Set(k, v)
+		}
+		rec := httptest.NewRecorder()
+		_, err = UpgradeWebSocket(rec, req, nil)
+		assert.Error(t, err, c.name)
+		assert.Equal(t, c.code, rec.Code, c.name)
+	}
+}
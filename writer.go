@@ -0,0 +1,74 @@
+package mux
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// headWriter is an http.ResponseWriter that is used to serve HEAD requests
+// with GET handlers. It discards the body while counting its length and defers
+// the call to WriteHeader until flush, so that Content-Length can be set
+// according to the body that would have been written.
+type headWriter struct {
+	http.ResponseWriter
+	status int
+	length int
+}
+
+// newHeadWriter returns pointer to a headWriter that wraps w.
+func newHeadWriter(w http.ResponseWriter) *headWriter {
+	return &headWriter{w, 0, 0}
+}
+
+// WriteHeader method only records the status code. It is going to be written
+// by the flush method.
+func (hw *headWriter) WriteHeader(status int) {
+	if hw.status == 0 {
+		hw.status = status
+	}
+}
+
+// Write method discards the data while counting its length.
+func (hw *headWriter) Write(b []byte) (int, error) {
+	if hw.status == 0 {
+		hw.status = http.StatusOK
+	}
+	hw.length += len(b)
+	return len(b), nil
+}
+
+// flush method sets Content-Length header (unless the handler has already set
+// it) and writes the status code to the underlying http.ResponseWriter.
+func (hw *headWriter) flush() {
+	if hw.status == 0 {
+		hw.status = http.StatusOK
+	}
+	h := hw.ResponseWriter.Header()
+	if h.Get("Content-Length") == "" && hw.length > 0 {
+		h.Set("Content-Length", strconv.Itoa(hw.length))
+	}
+	hw.ResponseWriter.WriteHeader(hw.status)
+}
+
+// statusWriter is an http.ResponseWriter that writes the default status code
+// if the handler starts writing the body without calling WriteHeader.
+type statusWriter struct {
+	http.ResponseWriter
+	code  int
+	wrote bool
+}
+
+// WriteHeader method writes the status code unless it was already written.
+func (sw *statusWriter) WriteHeader(code int) {
+	if !sw.wrote {
+		sw.wrote = true
+		sw.ResponseWriter.WriteHeader(code)
+	}
+}
+
+// Write method writes the default status code before the first chunk of data
+// unless the handler did it already.
+func (sw *statusWriter) Write(b []byte) (int, error) {
+	sw.WriteHeader(sw.code)
+	return sw.ResponseWriter.Write(b)
+}